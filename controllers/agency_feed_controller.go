@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// AgencyFeedController Methods:
+// 0. NewAgencyFeedController(service *services.AgencyFeedService) -> 注入 AgencyFeedService
+// 1. GetFeedXML(c *gin.Context) -> 代理公司刊登 Feed（XML）
+// 2. GetFeedJSON(c *gin.Context) -> 代理公司刊登 Feed（JSON）
+// 3. GetFeedSettings(c *gin.Context) -> Feed 设置及 API Key 列表（代理公司）
+// 4. UpdateFeedSettings(c *gin.Context) -> 更新 Feed 设置（代理公司）
+// 5. CreateFeedKey(c *gin.Context) -> 创建 API Key（代理公司）
+// 6. RevokeFeedKey(c *gin.Context) -> 吊销 API Key（代理公司）
+
+type AgencyFeedController struct {
+	feedService *services.AgencyFeedService
+}
+
+// 0. NewAgencyFeedController -> 注入 AgencyFeedService
+func NewAgencyFeedController(feedService *services.AgencyFeedService) *AgencyFeedController {
+	return &AgencyFeedController{
+		feedService: feedService,
+	}
+}
+
+// 1. GetFeedXML -> 代理公司刊登 Feed（XML）
+// GET /api/v1/agencies/:id/feed.xml
+func (ctrl *AgencyFeedController) GetFeedXML(c *gin.Context) {
+	feed, ok := ctrl.prepareFeed(c, "xml")
+	if !ok {
+		return
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
+
+// 2. GetFeedJSON -> 代理公司刊登 Feed（JSON，不使用统一响应包装）
+// GET /api/v1/agencies/:id/feed.json
+func (ctrl *AgencyFeedController) GetFeedJSON(c *gin.Context) {
+	feed, ok := ctrl.prepareFeed(c, "json")
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// 3. GetFeedSettings -> Feed 设置及 API Key 列表
+// GET /api/v1/agencies/me/feed
+func (ctrl *AgencyFeedController) GetFeedSettings(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	result, err := ctrl.feedService.GetSettings(c.Request.Context(), userID.(uint))
+	if err != nil {
+		ctrl.handleSettingsError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 4. UpdateFeedSettings -> 更新 Feed 设置
+// PUT /api/v1/agencies/me/feed
+func (ctrl *AgencyFeedController) UpdateFeedSettings(c *gin.Context) {
+	var req models.UpdateAgencyFeedSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	result, err := ctrl.feedService.UpdateSettings(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		ctrl.handleSettingsError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 5. CreateFeedKey -> 创建 API Key（明文只返回一次）
+// POST /api/v1/agencies/me/feed/keys
+func (ctrl *AgencyFeedController) CreateFeedKey(c *gin.Context) {
+	var req models.CreateAgencyFeedKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	result, err := ctrl.feedService.CreateKey(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		ctrl.handleSettingsError(c, err)
+		return
+	}
+
+	tools.Created(c, result)
+}
+
+// 6. RevokeFeedKey -> 吊销 API Key
+// DELETE /api/v1/agencies/me/feed/keys/:keyId
+func (ctrl *AgencyFeedController) RevokeFeedKey(c *gin.Context) {
+	keyID, err := strconv.ParseUint(c.Param("keyId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid key id")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	if err := ctrl.feedService.RevokeKey(c.Request.Context(), userID.(uint), uint(keyID)); err != nil {
+		if err == tools.ErrNotFound {
+			tools.NotFound(c, "feed key not found")
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, nil)
+}
+
+// prepareFeed 解析请求、校验权限并处理条件请求；返回 false 时已写入响应
+func (ctrl *AgencyFeedController) prepareFeed(c *gin.Context, format string) (*models.AgencyFeed, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid agency id")
+		return nil, false
+	}
+
+	var req models.AgencyFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return nil, false
+	}
+	apiKey := c.GetHeader("X-API-Key")
+	if apiKey == "" {
+		apiKey = req.APIKey
+	}
+
+	version, err := ctrl.feedService.PrepareFeed(c.Request.Context(), uint(id), apiKey, format, req.Since)
+	if err != nil {
+		switch err {
+		case tools.ErrNotFound:
+			tools.NotFound(c, "agency not found")
+		case tools.ErrUnauthorized:
+			tools.Unauthorized(c, "a valid API key is required for this feed")
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return nil, false
+	}
+
+	c.Header("ETag", version.ETag)
+	c.Header("Last-Modified", version.LastModified.Format(http.TimeFormat))
+	if version.Agency.FeedPrivate {
+		c.Header("Cache-Control", "private, no-cache")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300")
+	}
+	if notModified(c, version.ETag, version.LastModified) {
+		c.Status(http.StatusNotModified)
+		return nil, false
+	}
+
+	feed, err := ctrl.feedService.BuildFeed(c.Request.Context(), version, req.Since)
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return nil, false
+	}
+	return feed, true
+}
+
+// handleSettingsError Feed 设置相关错误响应
+func (ctrl *AgencyFeedController) handleSettingsError(c *gin.Context, err error) {
+	if err == tools.ErrNotFound {
+		tools.NotFound(c, "agency not found")
+		return
+	}
+	tools.InternalError(c, err.Error())
+}
+
+// notModified 条件请求判断（If-None-Match 优先于 If-Modified-Since）
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
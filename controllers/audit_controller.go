@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController Methods:
+// 0. NewAuditController(service *services.AuditService) -> 注入 AuditService
+// 1. ListAuditLogs(c *gin.Context) -> 审计日志列表（管理员）
+
+type AuditController struct {
+	auditService *services.AuditService
+}
+
+// 0. NewAuditController -> 注入 AuditService
+func NewAuditController(auditService *services.AuditService) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+	}
+}
+
+// 1. ListAuditLogs -> 审计日志列表
+// GET /api/v1/admin/audit?entity=property&id=123
+func (ctrl *AuditController) ListAuditLogs(c *gin.Context) {
+	var req models.ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	logs, err := ctrl.auditService.ListAuditLogs(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, logs)
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ComparisonController Methods:
+// 0. NewComparisonController(service *services.ComparisonService) -> 注入 ComparisonService
+// 1. CompareProperties(c *gin.Context) -> 房产对比
+// 2. CompareEstates(c *gin.Context) -> 屋苑对比
+
+type ComparisonController struct {
+	comparisonService *services.ComparisonService
+}
+
+// 0. NewComparisonController -> 注入 ComparisonService
+func NewComparisonController(comparisonService *services.ComparisonService) *ComparisonController {
+	return &ComparisonController{
+		comparisonService: comparisonService,
+	}
+}
+
+// 1. CompareProperties -> 房产对比
+// POST /api/v1/properties/compare
+func (ctrl *ComparisonController) CompareProperties(c *gin.Context) {
+	var req models.CompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.comparisonService.CompareProperties(c.Request.Context(), req.IDs)
+	if err != nil {
+		ctrl.handleError(c, err, "property not found")
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 2. CompareEstates -> 屋苑对比
+// POST /api/v1/estates/compare
+func (ctrl *ComparisonController) CompareEstates(c *gin.Context) {
+	var req models.CompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.comparisonService.CompareEstates(c.Request.Context(), req.IDs)
+	if err != nil {
+		ctrl.handleError(c, err, "estate not found")
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// handleError 对比错误响应
+func (ctrl *ComparisonController) handleError(c *gin.Context, err error, notFoundMessage string) {
+	switch {
+	case err == tools.ErrNotFound:
+		tools.NotFound(c, notFoundMessage)
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// DuplicateController Methods:
+// 0. NewDuplicateController(service *services.DuplicateService) -> 注入 DuplicateService
+// 1. GetPropertyDuplicates(c *gin.Context) -> 同一单位的其他刊登
+// 2. ListClusters(c *gin.Context) -> 重复组列表（管理员）
+// 3. GetCluster(c *gin.Context) -> 重复组详情（管理员）
+// 4. MergeCluster(c *gin.Context) -> 合并重复组（管理员）
+// 5. DismissCluster(c *gin.Context) -> 标记为非重复（管理员）
+
+type DuplicateController struct {
+	duplicateService *services.DuplicateService
+}
+
+// 0. NewDuplicateController -> 注入 DuplicateService
+func NewDuplicateController(duplicateService *services.DuplicateService) *DuplicateController {
+	return &DuplicateController{
+		duplicateService: duplicateService,
+	}
+}
+
+// 1. GetPropertyDuplicates -> 同一单位的其他刊登
+// GET /api/v1/properties/:id/duplicates
+func (ctrl *DuplicateController) GetPropertyDuplicates(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	duplicates, err := ctrl.duplicateService.GetPropertyDuplicates(c.Request.Context(), uint(id))
+	if err != nil {
+		if err.Error() == "property not found" {
+			tools.NotFound(c, "property not found")
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, duplicates)
+}
+
+// 2. ListClusters -> 重复组列表
+// GET /api/v1/admin/duplicates
+func (ctrl *DuplicateController) ListClusters(c *gin.Context) {
+	var req models.ListDuplicateClustersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	clusters, err := ctrl.duplicateService.ListClusters(c.Request.Context(), &req)
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, clusters)
+}
+
+// 3. GetCluster -> 重复组详情
+// GET /api/v1/admin/duplicates/:fingerprint
+func (ctrl *DuplicateController) GetCluster(c *gin.Context) {
+	cluster, err := ctrl.duplicateService.GetCluster(c.Request.Context(), c.Param("fingerprint"))
+	if err != nil {
+		respondDuplicateError(c, err)
+		return
+	}
+
+	tools.Success(c, cluster)
+}
+
+// 4. MergeCluster -> 合并重复组
+// POST /api/v1/admin/duplicates/:fingerprint/merge
+func (ctrl *DuplicateController) MergeCluster(c *gin.Context) {
+	var req models.MergeDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	cluster, err := ctrl.duplicateService.MergeCluster(c.Request.Context(), c.Param("fingerprint"), userID.(uint), &req)
+	if err != nil {
+		respondDuplicateError(c, err)
+		return
+	}
+
+	tools.Success(c, cluster)
+}
+
+// 5. DismissCluster -> 标记为非重复
+// POST /api/v1/admin/duplicates/:fingerprint/dismiss
+func (ctrl *DuplicateController) DismissCluster(c *gin.Context) {
+	var req models.DismissDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	cluster, err := ctrl.duplicateService.DismissCluster(c.Request.Context(), c.Param("fingerprint"), userID.(uint), &req)
+	if err != nil {
+		respondDuplicateError(c, err)
+		return
+	}
+
+	tools.Success(c, cluster)
+}
+
+// respondDuplicateError 重复组操作错误响应
+func respondDuplicateError(c *gin.Context, err error) {
+	switch {
+	case err == tools.ErrNotFound:
+		tools.NotFound(c, "duplicate cluster not found")
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
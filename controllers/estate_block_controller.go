@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// EstateBlockController Methods:
+// 0. NewEstateBlockController(service *services.EstateBlockService) -> 注入 EstateBlockService
+// 1. ListBlocks(c *gin.Context) -> 屋苑座数列表（可含单位）
+// 2. ListUnits(c *gin.Context) -> 座数单位列表
+// 3. ImportUnits(c *gin.Context) -> 上传 CSV/XLSX 单位目录（管理员）
+// 4. DownloadTemplate(c *gin.Context) -> 下载单位目录导入模板
+
+type EstateBlockController struct {
+	estateBlockService *services.EstateBlockService
+}
+
+// 0. NewEstateBlockController -> 注入 EstateBlockService
+func NewEstateBlockController(estateBlockService *services.EstateBlockService) *EstateBlockController {
+	return &EstateBlockController{
+		estateBlockService: estateBlockService,
+	}
+}
+
+// 1. ListBlocks -> 屋苑座数列表（楼层数、每层单位数及单位总数）
+// GET /api/v1/estates/:id/blocks?include_units=
+func (ctrl *EstateBlockController) ListBlocks(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid estate id")
+	if !ok {
+		return
+	}
+
+	var req models.ListEstateBlocksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	blocks, err := ctrl.estateBlockService.ListBlocks(c.Request.Context(), id, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, blocks)
+}
+
+// 2. ListUnits -> 座数单位列表（按楼层、单位排序，供地址输入选择）
+// GET /api/v1/estates/:id/blocks/:blockId/units?floor=
+func (ctrl *EstateBlockController) ListUnits(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid estate id")
+	if !ok {
+		return
+	}
+	blockID, ok := ctrl.parseID(c, "blockId", "invalid block id")
+	if !ok {
+		return
+	}
+
+	var req models.ListEstateUnitsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	units, err := ctrl.estateBlockService.ListUnits(c.Request.Context(), id, blockID, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, units)
+}
+
+// 3. ImportUnits -> 上传单位目录文件（整份校验通过才写入，座数按名称自动新建）
+// POST /api/v1/estates/:id/blocks/import（multipart/form-data，字段 file）
+func (ctrl *EstateBlockController) ImportUnits(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid estate id")
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		tools.BadRequest(c, "file is required")
+		return
+	}
+	if fileHeader.Size > ctrl.estateBlockService.MaxSize() {
+		tools.BadRequest(c, tools.ErrFileTooLarge.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 多读 1 字节用于判断是否超出大小上限
+	data, err := io.ReadAll(io.LimitReader(file, ctrl.estateBlockService.MaxSize()+1))
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.estateBlockService.ImportUnits(c.Request.Context(), id, fileHeader.Filename, data)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 4. DownloadTemplate -> 下载单位目录导入模板（CSV）
+// GET /api/v1/estates/blocks/template
+func (ctrl *EstateBlockController) DownloadTemplate(c *gin.Context) {
+	data, err := ctrl.estateBlockService.Template()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="estate_unit_template.csv"`)
+	c.Data(200, "text/csv; charset=utf-8", data)
+}
+
+// parseID 解析路径中的ID参数
+func (ctrl *EstateBlockController) parseID(c *gin.Context, param, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError 错误响应
+func (ctrl *EstateBlockController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tools.ErrNotFound):
+		tools.NotFound(c, "estate not found")
+	case err.Error() == "estate block not found":
+		tools.NotFound(c, err.Error())
+	case errors.Is(err, tools.ErrFileTooLarge),
+		errors.Is(err, tools.ErrUnsupportedSpreadsheet),
+		errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
@@ -22,6 +23,12 @@ import (
 // 9. CreateEstate(c *gin.Context) -> 创建屋苑（需认证）
 // 10. UpdateEstate(c *gin.Context) -> 更新屋苑（需认证）
 // 11. DeleteEstate(c *gin.Context) -> 删除屋苑（需认证）
+// 12. AddEstateImage(c *gin.Context) -> 添加屋苑图片（管理员）
+// 13. UpdateEstateImage(c *gin.Context) -> 更新屋苑图片信息（管理员）
+// 14. DeleteEstateImage(c *gin.Context) -> 删除屋苑图片（管理员）
+// 15. ReorderEstateImages(c *gin.Context) -> 批量调整屋苑图片顺序（管理员）
+// 16. SetEstateCoverImage(c *gin.Context) -> 设置屋苑封面图（管理员）
+// 17. UpdateEstateFacilities(c *gin.Context) -> 覆盖更新屋苑设施（管理员）
 
 type EstateController struct {
 	estateService *services.EstateService
@@ -289,3 +296,162 @@ func (ctrl *EstateController) DeleteEstate(c *gin.Context) {
 
 	tools.Success(c, gin.H{"message": "estate deleted successfully"})
 }
+
+// 12. AddEstateImage -> 添加屋苑图片（管理员）
+func (ctrl *EstateController) AddEstateImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid estate id")
+		return
+	}
+
+	var req models.AddImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.estateService.AddEstateImage(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondEstateImageError(c, err)
+		return
+	}
+
+	tools.Created(c, image)
+}
+
+// 13. UpdateEstateImage -> 更新屋苑图片信息（管理员）
+func (ctrl *EstateController) UpdateEstateImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid estate id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	var req models.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.estateService.UpdateEstateImage(c.Request.Context(), uint(id), uint(imageID), &req)
+	if err != nil {
+		respondEstateImageError(c, err)
+		return
+	}
+
+	tools.Success(c, image)
+}
+
+// 14. DeleteEstateImage -> 删除屋苑图片（管理员）
+func (ctrl *EstateController) DeleteEstateImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid estate id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	if err := ctrl.estateService.DeleteEstateImage(c.Request.Context(), uint(id), uint(imageID)); err != nil {
+		respondEstateImageError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "image deleted successfully"})
+}
+
+// 15. ReorderEstateImages -> 批量调整屋苑图片顺序（管理员）
+func (ctrl *EstateController) ReorderEstateImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid estate id")
+		return
+	}
+
+	var req models.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	images, err := ctrl.estateService.ReorderEstateImages(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondEstateImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// 16. SetEstateCoverImage -> 设置屋苑封面图（管理员）
+func (ctrl *EstateController) SetEstateCoverImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid estate id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	images, err := ctrl.estateService.SetEstateCoverImage(c.Request.Context(), uint(id), uint(imageID))
+	if err != nil {
+		respondEstateImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// 17. UpdateEstateFacilities -> 覆盖更新屋苑设施（管理员）
+func (ctrl *EstateController) UpdateEstateFacilities(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid estate id")
+		return
+	}
+
+	var req models.UpdateEstateFacilitiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	facilities, err := ctrl.estateService.UpdateEstateFacilities(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		switch {
+		case err == tools.ErrNotFound:
+			tools.NotFound(c, "estate not found")
+		case errors.Is(err, tools.ErrInvalidInput):
+			tools.BadRequest(c, err.Error())
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	tools.Success(c, facilities)
+}
+
+// respondEstateImageError 图片管理错误响应
+func respondEstateImageError(c *gin.Context, err error) {
+	switch {
+	case err == tools.ErrNotFound:
+		tools.NotFound(c, "estate or image not found")
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// EstateImportController Methods:
+// 0. NewEstateImportController(service *services.EstateImportService) -> 注入 EstateImportService
+// 1. ImportEstates(c *gin.Context) -> 上传 CSV/XLSX 屋苑及设施（管理员）
+// 2. DownloadTemplate(c *gin.Context) -> 下载屋苑导入模板
+
+type EstateImportController struct {
+	estateImportService *services.EstateImportService
+}
+
+// 0. NewEstateImportController -> 注入 EstateImportService
+func NewEstateImportController(estateImportService *services.EstateImportService) *EstateImportController {
+	return &EstateImportController{
+		estateImportService: estateImportService,
+	}
+}
+
+// 1. ImportEstates -> 上传屋苑文件（整份校验通过才写入，按名称及地区匹配已有屋苑）
+// POST /api/v1/estates/import（multipart/form-data，字段 file）
+func (ctrl *EstateImportController) ImportEstates(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		tools.BadRequest(c, "file is required")
+		return
+	}
+	if fileHeader.Size > ctrl.estateImportService.MaxSize() {
+		tools.BadRequest(c, tools.ErrFileTooLarge.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 多读 1 字节用于判断是否超出大小上限
+	data, err := io.ReadAll(io.LimitReader(file, ctrl.estateImportService.MaxSize()+1))
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.estateImportService.ImportEstates(c.Request.Context(), fileHeader.Filename, data)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 2. DownloadTemplate -> 下载屋苑导入模板（CSV）
+// GET /api/v1/estates/import/template
+func (ctrl *EstateImportController) DownloadTemplate(c *gin.Context) {
+	data, err := ctrl.estateImportService.Template()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="estate_import_template.csv"`)
+	c.Data(200, "text/csv; charset=utf-8", data)
+}
+
+// handleError 错误响应
+func (ctrl *EstateImportController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tools.ErrFileTooLarge),
+		errors.Is(err, tools.ErrUnsupportedSpreadsheet),
+		errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
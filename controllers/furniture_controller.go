@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
@@ -20,6 +21,12 @@ import (
 // 7. GetFurnitureImages(c *gin.Context) -> 家具图片
 // 8. UpdateFurnitureStatus(c *gin.Context) -> 更新家具状态（需认证）
 // 9. GetFeaturedFurniture(c *gin.Context) -> 精选家具
+// 10. RenewFurniture(c *gin.Context) -> 续期家具（需认证）
+// 11. AddFurnitureImage(c *gin.Context) -> 添加家具图片（需认证）
+// 12. DeleteFurnitureImage(c *gin.Context) -> 删除家具图片（需认证）
+// 13. ReorderFurnitureImages(c *gin.Context) -> 批量调整家具图片顺序（需认证）
+// 14. SetFurnitureCoverImage(c *gin.Context) -> 设置家具封面图（需认证）
+// 15. SubmitFurniture(c *gin.Context) -> 提交家具审核（需认证）
 
 type FurnitureController struct {
 	furnitureService *services.FurnitureService
@@ -76,7 +83,13 @@ func (ctrl *FurnitureController) GetFurniture(c *gin.Context) {
 		return
 	}
 
-	furniture, err := ctrl.furnitureService.GetFurniture(c.Request.Context(), uint(id))
+	// 可选认证：发布者和管理员可查看未发布的家具
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+	viewerID, _ := userID.(uint)
+	viewerType, _ := userType.(string)
+
+	furniture, err := ctrl.furnitureService.GetFurniture(c.Request.Context(), uint(id), viewerID, viewerType)
 	if err != nil {
 		if err == tools.ErrNotFound {
 			tools.NotFound(c, "furniture not found")
@@ -114,6 +127,10 @@ func (ctrl *FurnitureController) CreateFurniture(c *gin.Context) {
 
 	furniture, err := ctrl.furnitureService.CreateFurniture(c.Request.Context(), userID.(uint), userType, &req)
 	if err != nil {
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.InternalError(c, err.Error())
 		return
 	}
@@ -152,6 +169,10 @@ func (ctrl *FurnitureController) UpdateFurniture(c *gin.Context) {
 			tools.Forbidden(c, "you don't have permission to update this furniture")
 			return
 		}
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.InternalError(c, err.Error())
 		return
 	}
@@ -241,6 +262,10 @@ func (ctrl *FurnitureController) UpdateFurnitureStatus(c *gin.Context) {
 			tools.Forbidden(c, "you don't have permission to update this furniture")
 			return
 		}
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.InternalError(c, err.Error())
 		return
 	}
@@ -263,3 +288,190 @@ func (ctrl *FurnitureController) GetFeaturedFurniture(c *gin.Context) {
 
 	tools.Success(c, furniture)
 }
+
+// 10. RenewFurniture -> 续期家具（需认证）
+func (ctrl *FurnitureController) RenewFurniture(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid furniture id")
+		return
+	}
+
+	// 从上下文获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		tools.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	furniture, err := ctrl.furnitureService.RenewFurniture(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		if err == tools.ErrNotFound {
+			tools.NotFound(c, "furniture not found")
+			return
+		}
+		if err == tools.ErrForbidden {
+			tools.Forbidden(c, "you don't have permission to renew this furniture")
+			return
+		}
+		if err == tools.ErrNotRenewable || err == tools.ErrRenewalLimitReached {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, furniture)
+}
+
+// 11. AddFurnitureImage -> 添加家具图片（需认证）
+func (ctrl *FurnitureController) AddFurnitureImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid furniture id")
+		return
+	}
+
+	var req models.AddImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.furnitureService.AddFurnitureImage(c.Request.Context(), uint(id), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondFurnitureImageError(c, err)
+		return
+	}
+
+	tools.Created(c, image)
+}
+
+// 12. DeleteFurnitureImage -> 删除家具图片（需认证）
+func (ctrl *FurnitureController) DeleteFurnitureImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid furniture id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	if err := ctrl.furnitureService.DeleteFurnitureImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string)); err != nil {
+		respondFurnitureImageError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "image deleted successfully"})
+}
+
+// 13. ReorderFurnitureImages -> 批量调整家具图片顺序（需认证）
+func (ctrl *FurnitureController) ReorderFurnitureImages(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid furniture id")
+		return
+	}
+
+	var req models.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	images, err := ctrl.furnitureService.ReorderFurnitureImages(c.Request.Context(), uint(id), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondFurnitureImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// 14. SetFurnitureCoverImage -> 设置家具封面图（需认证）
+func (ctrl *FurnitureController) SetFurnitureCoverImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid furniture id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	images, err := ctrl.furnitureService.SetFurnitureCoverImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string))
+	if err != nil {
+		respondFurnitureImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// respondFurnitureImageError 图片管理错误响应
+func respondFurnitureImageError(c *gin.Context, err error) {
+	switch {
+	case err == tools.ErrNotFound:
+		tools.NotFound(c, "furniture or image not found")
+	case err == tools.ErrForbidden:
+		tools.Forbidden(c, "you don't have permission to manage images of this furniture")
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
+
+// 15. SubmitFurniture -> 提交家具审核（需认证）
+func (ctrl *FurnitureController) SubmitFurniture(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid furniture id")
+		return
+	}
+
+	// 从上下文获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		tools.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	furniture, err := ctrl.furnitureService.SubmitFurniture(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		if err == tools.ErrNotFound {
+			tools.NotFound(c, "furniture not found")
+			return
+		}
+		if err == tools.ErrForbidden {
+			tools.Forbidden(c, "you don't have permission to submit this furniture")
+			return
+		}
+		if err == tools.ErrNotSubmittable {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, furniture)
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// JobController Methods:
+// 0. NewJobController(service *services.JobService) -> 注入 JobService
+// 1. ListJobs(c *gin.Context) -> 定时任务列表（管理员）
+// 2. GetJobRuns(c *gin.Context) -> 任务执行记录（管理员）
+// 3. TriggerJob(c *gin.Context) -> 手动触发任务（管理员）
+
+type JobController struct {
+	jobService *services.JobService
+}
+
+// 0. NewJobController -> 注入 JobService
+func NewJobController(jobService *services.JobService) *JobController {
+	return &JobController{
+		jobService: jobService,
+	}
+}
+
+// 1. ListJobs -> 定时任务列表
+// GET /api/v1/admin/jobs
+func (ctrl *JobController) ListJobs(c *gin.Context) {
+	jobs, err := ctrl.jobService.ListJobs(c.Request.Context())
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, jobs)
+}
+
+// 2. GetJobRuns -> 任务执行记录
+// GET /api/v1/admin/jobs/:name/runs
+func (ctrl *JobController) GetJobRuns(c *gin.Context) {
+	var req models.ListJobRunsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	runs, err := ctrl.jobService.GetJobRuns(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		if err == tools.ErrNotFound {
+			tools.NotFound(c, "job not found")
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, runs)
+}
+
+// 3. TriggerJob -> 手动触发任务
+// POST /api/v1/admin/jobs/:name/run
+func (ctrl *JobController) TriggerJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		tools.Unauthorized(c, "user not authenticated")
+		return
+	}
+
+	name := c.Param("name")
+	if err := ctrl.jobService.TriggerJob(c.Request.Context(), name, userID.(uint)); err != nil {
+		if err == tools.ErrNotFound {
+			tools.NotFound(c, "job not found")
+			return
+		}
+		if err == tools.ErrJobRunning {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "job triggered", "job": name})
+}
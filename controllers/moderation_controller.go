@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationController Methods:
+// 0. NewModerationController(service *services.ModerationService) -> 注入 ModerationService
+// 1. ListReviews(c *gin.Context) -> 审核队列（管理员）
+// 2. ApproveReview(c *gin.Context) -> 审核通过（管理员）
+// 3. RejectReview(c *gin.Context) -> 审核拒绝（管理员）
+// 4. GetPropertyReviews(c *gin.Context) -> 房产审核历史（发布者或管理员）
+// 5. GetFurnitureReviews(c *gin.Context) -> 家具审核历史（发布者或管理员）
+
+type ModerationController struct {
+	moderationService *services.ModerationService
+}
+
+// 0. NewModerationController -> 注入 ModerationService
+func NewModerationController(moderationService *services.ModerationService) *ModerationController {
+	return &ModerationController{
+		moderationService: moderationService,
+	}
+}
+
+// 1. ListReviews -> 审核队列
+// GET /api/v1/admin/moderation/reviews
+func (ctrl *ModerationController) ListReviews(c *gin.Context) {
+	var req models.ListReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	reviews, err := ctrl.moderationService.ListReviews(c.Request.Context(), &req)
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, reviews)
+}
+
+// 2. ApproveReview -> 审核通过
+// POST /api/v1/admin/moderation/reviews/:id/approve
+func (ctrl *ModerationController) ApproveReview(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid review id")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	review, err := ctrl.moderationService.ApproveReview(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	tools.Success(c, review)
+}
+
+// 3. RejectReview -> 审核拒绝
+// POST /api/v1/admin/moderation/reviews/:id/reject
+func (ctrl *ModerationController) RejectReview(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid review id")
+		return
+	}
+
+	var req models.RejectReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	review, err := ctrl.moderationService.RejectReview(c.Request.Context(), uint(id), userID.(uint), req.Reason)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	tools.Success(c, review)
+}
+
+// 4. GetPropertyReviews -> 房产审核历史
+// GET /api/v1/properties/:id/reviews
+func (ctrl *ModerationController) GetPropertyReviews(c *gin.Context) {
+	ctrl.getListingReviews(c, "property")
+}
+
+// 5. GetFurnitureReviews -> 家具审核历史
+// GET /api/v1/furniture/:id/reviews
+func (ctrl *ModerationController) GetFurnitureReviews(c *gin.Context) {
+	ctrl.getListingReviews(c, "furniture")
+}
+
+// getListingReviews 查询刊登审核历史
+func (ctrl *ModerationController) getListingReviews(c *gin.Context, entityType string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid "+entityType+" id")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	reviews, err := ctrl.moderationService.GetListingReviews(c.Request.Context(), entityType, uint(id), userID.(uint), userType.(string))
+	if err != nil {
+		switch err {
+		case tools.ErrNotFound:
+			tools.NotFound(c, entityType+" not found")
+		case tools.ErrForbidden:
+			tools.Forbidden(c, "you don't have permission to view these reviews")
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	tools.Success(c, reviews)
+}
+
+// respondReviewError 审核操作错误响应
+func respondReviewError(c *gin.Context, err error) {
+	switch err {
+	case tools.ErrNotFound:
+		tools.NotFound(c, "review not found")
+	case tools.ErrReviewResolved:
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// MortgageController Methods:
+// 0. NewMortgageController(service *services.MortgageService) -> 注入 MortgageService
+// 1. CalculateMortgage(c *gin.Context) -> 按揭及供款能力计算
+
+type MortgageController struct {
+	mortgageService *services.MortgageService
+}
+
+// 0. NewMortgageController -> 注入 MortgageService
+func NewMortgageController(mortgageService *services.MortgageService) *MortgageController {
+	return &MortgageController{
+		mortgageService: mortgageService,
+	}
+}
+
+// 1. CalculateMortgage -> 按揭及供款能力计算
+// POST /api/v1/tools/mortgage
+func (ctrl *MortgageController) CalculateMortgage(c *gin.Context) {
+	var req models.MortgageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.mortgageService.Calculate(&req)
+	if err != nil {
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, result)
+}
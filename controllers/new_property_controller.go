@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
@@ -14,6 +16,21 @@ import (
 // 1. ListNewDevelopments(c *gin.Context) -> 获取新盘列表
 // 2. GetNewDevelopment(c *gin.Context) -> 获取新盘详情
 // 3. GetDevelopmentLayouts(c *gin.Context) -> 获取新盘户型列表
+// 4. GetNewPropertyImages(c *gin.Context) -> 获取新盘图片列表
+// 5. AddNewPropertyImage(c *gin.Context) -> 添加新盘图片（管理员）
+// 6. UpdateNewPropertyImage(c *gin.Context) -> 更新新盘图片信息（管理员）
+// 7. DeleteNewPropertyImage(c *gin.Context) -> 删除新盘图片（管理员）
+// 8. ReorderNewPropertyImages(c *gin.Context) -> 批量调整新盘图片顺序（管理员）
+// 9. SetNewPropertyCoverImage(c *gin.Context) -> 设置新盘封面图（管理员）
+// 10. CreateNewDevelopment(c *gin.Context) -> 创建新盘（管理员）
+// 11. UpdateNewDevelopment(c *gin.Context) -> 更新新盘（管理员）
+// 12. DeleteNewDevelopment(c *gin.Context) -> 删除新盘（管理员）
+// 13. CreateDevelopmentLayout(c *gin.Context) -> 添加新盘户型（管理员）
+// 14. UpdateDevelopmentLayout(c *gin.Context) -> 更新新盘户型（管理员）
+// 15. DeleteDevelopmentLayout(c *gin.Context) -> 删除新盘户型（管理员）
+// 16. ListDevelopmentTransactions(c *gin.Context) -> 成交纪录册
+// 17. RecordDevelopmentTransaction(c *gin.Context) -> 登记一手成交（管理员）
+// 18. UpdateDevelopmentTransaction(c *gin.Context) -> 更新或终止一手成交（管理员）
 type NewDevelopmentController struct {
 	service *services.NewDevelopmentService
 }
@@ -34,6 +51,14 @@ func NewNewDevelopmentController(service *services.NewDevelopmentService) *NewDe
 // @Param primary_school_net query string false "小学校网"
 // @Param secondary_school_net query string false "中学校网"
 // @Param is_featured query bool false "是否精选"
+// @Param min_price query number false "户型最低售价"
+// @Param max_price query number false "户型最高售价"
+// @Param bedrooms query int false "户型房间数（0 为开放式）"
+// @Param min_saleable_area query number false "户型最小实用面积（平方尺）"
+// @Param max_price_per_sqft query number false "户型最高实用呎价"
+// @Param expected_completion_before query string false "预计落成日期不迟于 (YYYY-MM-DD)"
+// @Param sort_by query string false "排序字段: price, price_per_sqft, saleable_area, expected_completion, created_at"
+// @Param sort_order query string false "排序方向: asc, desc"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20)
 // @Success 200 {object} tools.Response{data=models.PaginatedNewPropertiesResponse}
@@ -47,7 +72,7 @@ func (ctrl *NewDevelopmentController) ListNewDevelopments(c *gin.Context) {
 
 	result, err := ctrl.service.ListNewProperties(c.Request.Context(), &filter)
 	if err != nil {
-		tools.InternalError(c, err.Error())
+		respondNewPropertyError(c, err)
 		return
 	}
 
@@ -72,7 +97,7 @@ func (ctrl *NewDevelopmentController) GetNewDevelopment(c *gin.Context) {
 
 	newProperty, err := ctrl.service.GetNewProperty(c.Request.Context(), uint(id))
 	if err != nil {
-		tools.NotFound(c, err.Error())
+		respondNewPropertyError(c, err)
 		return
 	}
 
@@ -97,9 +122,547 @@ func (ctrl *NewDevelopmentController) GetDevelopmentLayouts(c *gin.Context) {
 
 	layouts, err := ctrl.service.GetNewPropertyLayouts(c.Request.Context(), uint(id))
 	if err != nil {
-		tools.NotFound(c, err.Error())
+		respondNewPropertyError(c, err)
 		return
 	}
 
 	tools.Success(c, layouts)
 }
+
+// GetNewPropertyImages 获取新盘图片
+// @Summary 获取新盘图片
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Param id path int true "新盘ID"
+// @Success 200 {object} tools.Response{data=[]models.NewPropertyImage}
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/images [get]
+func (ctrl *NewDevelopmentController) GetNewPropertyImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	images, err := ctrl.service.GetNewPropertyImages(c.Request.Context(), uint(id))
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// AddNewPropertyImage 添加新盘图片
+// @Summary 添加新盘图片
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param request body models.AddImageRequest true "请求参数"
+// @Success 201 {object} tools.Response{data=models.NewPropertyImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/images [post]
+func (ctrl *NewDevelopmentController) AddNewPropertyImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	var req models.AddImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.service.AddNewPropertyImage(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Created(c, image)
+}
+
+// UpdateNewPropertyImage 更新新盘图片信息
+// @Summary 更新新盘图片信息
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param imageId path int true "图片ID"
+// @Param request body models.UpdateImageRequest true "请求参数"
+// @Success 200 {object} tools.Response{data=models.NewPropertyImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/images/{imageId} [put]
+func (ctrl *NewDevelopmentController) UpdateNewPropertyImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	var req models.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.service.UpdateNewPropertyImage(c.Request.Context(), uint(id), uint(imageID), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, image)
+}
+
+// DeleteNewPropertyImage 删除新盘图片
+// @Summary 删除新盘图片
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param imageId path int true "图片ID"
+// @Success 200 {object} tools.Response
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/images/{imageId} [delete]
+func (ctrl *NewDevelopmentController) DeleteNewPropertyImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	if err := ctrl.service.DeleteNewPropertyImage(c.Request.Context(), uint(id), uint(imageID)); err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "image deleted successfully"})
+}
+
+// ReorderNewPropertyImages 批量调整新盘图片顺序
+// @Summary 批量调整新盘图片顺序
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param request body models.ReorderImagesRequest true "请求参数"
+// @Success 200 {object} tools.Response{data=[]models.NewPropertyImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/images [put]
+func (ctrl *NewDevelopmentController) ReorderNewPropertyImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	var req models.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	images, err := ctrl.service.ReorderNewPropertyImages(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// SetNewPropertyCoverImage 设置新盘封面图
+// @Summary 设置新盘封面图
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param imageId path int true "图片ID"
+// @Success 200 {object} tools.Response{data=[]models.NewPropertyImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/images/{imageId}/cover [put]
+func (ctrl *NewDevelopmentController) SetNewPropertyCoverImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	images, err := ctrl.service.SetNewPropertyCoverImage(c.Request.Context(), uint(id), uint(imageID))
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// CreateNewDevelopment 创建新盘
+// @Summary 创建新盘
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.CreateNewPropertyRequest true "请求参数"
+// @Success 201 {object} tools.Response{data=models.NewPropertyDetailResponse}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Router /api/v1/new-properties [post]
+func (ctrl *NewDevelopmentController) CreateNewDevelopment(c *gin.Context) {
+	var req models.CreateNewPropertyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	newProperty, err := ctrl.service.CreateNewProperty(c.Request.Context(), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Created(c, newProperty)
+}
+
+// UpdateNewDevelopment 更新新盘
+// @Summary 更新新盘（销售状态、售出单位等）
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param request body models.UpdateNewPropertyRequest true "请求参数"
+// @Success 200 {object} tools.Response{data=models.NewPropertyDetailResponse}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id} [put]
+func (ctrl *NewDevelopmentController) UpdateNewDevelopment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	var req models.UpdateNewPropertyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	newProperty, err := ctrl.service.UpdateNewProperty(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, newProperty)
+}
+
+// DeleteNewDevelopment 删除新盘
+// @Summary 删除新盘
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Success 200 {object} tools.Response
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id} [delete]
+func (ctrl *NewDevelopmentController) DeleteNewDevelopment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	if err := ctrl.service.DeleteNewProperty(c.Request.Context(), uint(id)); err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "new property deleted successfully"})
+}
+
+// CreateDevelopmentLayout 添加新盘户型
+// @Summary 添加新盘户型
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param request body models.CreateNewPropertyLayoutRequest true "请求参数"
+// @Success 201 {object} tools.Response{data=models.NewPropertyLayout}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/layouts [post]
+func (ctrl *NewDevelopmentController) CreateDevelopmentLayout(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	var req models.CreateNewPropertyLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	layout, err := ctrl.service.CreateNewPropertyLayout(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Created(c, layout)
+}
+
+// UpdateDevelopmentLayout 更新新盘户型
+// @Summary 更新新盘户型
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param layoutId path int true "户型ID"
+// @Param request body models.UpdateNewPropertyLayoutRequest true "请求参数"
+// @Success 200 {object} tools.Response{data=models.NewPropertyLayout}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/layouts/{layoutId} [put]
+func (ctrl *NewDevelopmentController) UpdateDevelopmentLayout(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+	layoutID, err := strconv.ParseUint(c.Param("layoutId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid layout id")
+		return
+	}
+
+	var req models.UpdateNewPropertyLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	layout, err := ctrl.service.UpdateNewPropertyLayout(c.Request.Context(), uint(id), uint(layoutID), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, layout)
+}
+
+// DeleteDevelopmentLayout 删除新盘户型
+// @Summary 删除新盘户型
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param layoutId path int true "户型ID"
+// @Success 200 {object} tools.Response
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/layouts/{layoutId} [delete]
+func (ctrl *NewDevelopmentController) DeleteDevelopmentLayout(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+	layoutID, err := strconv.ParseUint(c.Param("layoutId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid layout id")
+		return
+	}
+
+	if err := ctrl.service.DeleteNewPropertyLayout(c.Request.Context(), uint(id), uint(layoutID)); err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "layout deleted successfully"})
+}
+
+// ListDevelopmentTransactions 成交纪录册
+// @Summary 新盘成交纪录册
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Param id path int true "新盘ID"
+// @Param status query string false "状态: pasp, asp, terminated"
+// @Param layout_id query int false "户型ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} tools.Response{data=models.PaginatedNewPropertyTransactionsResponse}
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/transactions [get]
+func (ctrl *NewDevelopmentController) ListDevelopmentTransactions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	var req models.ListNewPropertyTransactionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.service.ListTransactions(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// RecordDevelopmentTransaction 登记一手成交
+// @Summary 登记一手成交（单位标记为已售）
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param request body models.RecordNewPropertyTransactionRequest true "请求参数"
+// @Success 201 {object} tools.Response{data=models.NewPropertyTransaction}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/transactions [post]
+func (ctrl *NewDevelopmentController) RecordDevelopmentTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+
+	var req models.RecordNewPropertyTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	transaction, err := ctrl.service.RecordTransaction(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Created(c, transaction)
+}
+
+// UpdateDevelopmentTransaction 更新或终止一手成交
+// @Summary 更新或终止一手成交（终止后单位恢复可售）
+// @Tags 新盘
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "新盘ID"
+// @Param transactionId path int true "成交ID"
+// @Param request body models.UpdateNewPropertyTransactionRequest true "请求参数"
+// @Success 200 {object} tools.Response{data=models.NewPropertyTransaction}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/new-properties/{id}/transactions/{transactionId} [put]
+func (ctrl *NewDevelopmentController) UpdateDevelopmentTransaction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid new property id")
+		return
+	}
+	transactionID, err := strconv.ParseUint(c.Param("transactionId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid transaction id")
+		return
+	}
+
+	var req models.UpdateNewPropertyTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	transaction, err := ctrl.service.UpdateTransaction(c.Request.Context(), uint(id), uint(transactionID), &req)
+	if err != nil {
+		respondNewPropertyError(c, err)
+		return
+	}
+
+	tools.Success(c, transaction)
+}
+
+// respondNewPropertyError 新盘、图片及户型管理错误响应
+func respondNewPropertyError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "new property not found", err.Error() == "image not found", err.Error() == "layout not found",
+		err.Error() == "unit not found", err.Error() == "transaction not found":
+		tools.NotFound(c, err.Error())
+	case errors.Is(err, databases.ErrUnitAlreadySold), errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
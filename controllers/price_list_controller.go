@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// PriceListController Methods:
+// 0. NewPriceListController(service *services.PriceListService) -> 注入 PriceListService
+// 1. ImportPriceList(c *gin.Context) -> 上传 CSV/XLSX 价单（管理员）
+// 2. ListPriceLists(c *gin.Context) -> 价单列表
+// 3. GetPriceList(c *gin.Context) -> 价单详情
+// 4. DownloadTemplate(c *gin.Context) -> 下载价单导入模板
+// 5. ListUnits(c *gin.Context) -> 单位列表及销售状态
+// 6. UpdateUnitStatus(c *gin.Context) -> 批量更新单位销售状态（管理员）
+// 7. ListSalesArrangements(c *gin.Context) -> 销售安排列表
+// 8. CreateSalesArrangement(c *gin.Context) -> 创建销售安排（管理员）
+// 9. UpdateSalesArrangement(c *gin.Context) -> 更新销售安排（管理员）
+// 10. DeleteSalesArrangement(c *gin.Context) -> 删除销售安排（管理员）
+
+type PriceListController struct {
+	priceListService *services.PriceListService
+}
+
+// 0. NewPriceListController -> 注入 PriceListService
+func NewPriceListController(priceListService *services.PriceListService) *PriceListController {
+	return &PriceListController{
+		priceListService: priceListService,
+	}
+}
+
+// 1. ImportPriceList -> 上传价单文件（整份校验通过才写入，单位销售状态自动汇总到新盘及户型）
+// POST /api/v1/new-properties/:id/price-lists（multipart/form-data，字段 file、price_list_no、release_date、remarks）
+func (ctrl *PriceListController) ImportPriceList(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+
+	var req models.ImportPriceListRequest
+	if err := c.ShouldBind(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		tools.BadRequest(c, "file is required")
+		return
+	}
+	if fileHeader.Size > ctrl.priceListService.MaxSize() {
+		tools.BadRequest(c, tools.ErrFileTooLarge.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 多读 1 字节用于判断是否超出大小上限
+	data, err := io.ReadAll(io.LimitReader(file, ctrl.priceListService.MaxSize()+1))
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.priceListService.ImportPriceList(c.Request.Context(), id, &req, fileHeader.Filename, data)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Created(c, result)
+}
+
+// 2. ListPriceLists -> 价单列表
+// GET /api/v1/new-properties/:id/price-lists
+func (ctrl *PriceListController) ListPriceLists(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+
+	priceLists, err := ctrl.priceListService.ListPriceLists(c.Request.Context(), id)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, priceLists)
+}
+
+// 3. GetPriceList -> 价单详情（含单位行）
+// GET /api/v1/new-properties/:id/price-lists/:priceListId
+func (ctrl *PriceListController) GetPriceList(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+	priceListID, ok := ctrl.parseID(c, "priceListId", "invalid price list id")
+	if !ok {
+		return
+	}
+
+	priceList, err := ctrl.priceListService.GetPriceList(c.Request.Context(), id, priceListID)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, priceList)
+}
+
+// 4. DownloadTemplate -> 下载价单导入模板（CSV）
+// GET /api/v1/new-properties/price-lists/template
+func (ctrl *PriceListController) DownloadTemplate(c *gin.Context) {
+	data, err := ctrl.priceListService.Template()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="price_list_template.csv"`)
+	c.Data(200, "text/csv; charset=utf-8", data)
+}
+
+// 5. ListUnits -> 单位列表及销售状态
+// GET /api/v1/new-properties/:id/units
+func (ctrl *PriceListController) ListUnits(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+
+	var req models.ListNewPropertyUnitsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.priceListService.ListUnits(c.Request.Context(), id, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 6. UpdateUnitStatus -> 批量更新单位销售状态
+// PUT /api/v1/new-properties/:id/units/status
+func (ctrl *PriceListController) UpdateUnitStatus(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUnitStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.priceListService.UpdateUnitStatus(c.Request.Context(), id, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 7. ListSalesArrangements -> 销售安排列表
+// GET /api/v1/new-properties/:id/sales-arrangements
+func (ctrl *PriceListController) ListSalesArrangements(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+
+	arrangements, err := ctrl.priceListService.ListSalesArrangements(c.Request.Context(), id)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, arrangements)
+}
+
+// 8. CreateSalesArrangement -> 创建销售安排
+// POST /api/v1/new-properties/:id/sales-arrangements
+func (ctrl *PriceListController) CreateSalesArrangement(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+
+	var req models.SalesArrangementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	arrangement, err := ctrl.priceListService.CreateSalesArrangement(c.Request.Context(), id, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Created(c, arrangement)
+}
+
+// 9. UpdateSalesArrangement -> 更新销售安排
+// PUT /api/v1/new-properties/:id/sales-arrangements/:arrangementId
+func (ctrl *PriceListController) UpdateSalesArrangement(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+	arrangementID, ok := ctrl.parseID(c, "arrangementId", "invalid sales arrangement id")
+	if !ok {
+		return
+	}
+
+	var req models.SalesArrangementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	arrangement, err := ctrl.priceListService.UpdateSalesArrangement(c.Request.Context(), id, arrangementID, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, arrangement)
+}
+
+// 10. DeleteSalesArrangement -> 删除销售安排
+// DELETE /api/v1/new-properties/:id/sales-arrangements/:arrangementId
+func (ctrl *PriceListController) DeleteSalesArrangement(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid new property id")
+	if !ok {
+		return
+	}
+	arrangementID, ok := ctrl.parseID(c, "arrangementId", "invalid sales arrangement id")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.priceListService.DeleteSalesArrangement(c.Request.Context(), id, arrangementID); err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "sales arrangement deleted successfully"})
+}
+
+// parseID 解析路径中的ID参数
+func (ctrl *PriceListController) parseID(c *gin.Context, param, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError 错误响应
+func (ctrl *PriceListController) handleError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "new property not found", err.Error() == "price list not found", err.Error() == "sales arrangement not found":
+		tools.NotFound(c, err.Error())
+	case errors.Is(err, tools.ErrFileTooLarge),
+		errors.Is(err, tools.ErrUnsupportedSpreadsheet),
+		errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
@@ -54,7 +55,13 @@ func (ctrl *PropertyController) GetProperty(c *gin.Context) {
 		return
 	}
 
-	property, err := ctrl.propertyService.GetProperty(c.Request.Context(), uint(id))
+	// 可选认证：发布者和管理员可查看未发布的房产
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+	viewerID, _ := userID.(uint)
+	viewerType, _ := userType.(string)
+
+	property, err := ctrl.propertyService.GetProperty(c.Request.Context(), uint(id), viewerID, viewerType)
 	if err != nil {
 		if err.Error() == "property not found" {
 			tools.NotFound(c, "property not found")
@@ -86,6 +93,10 @@ func (ctrl *PropertyController) CreateProperty(c *gin.Context) {
 		&req,
 	)
 	if err != nil {
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.InternalError(c, err.Error())
 		return
 	}
@@ -121,6 +132,14 @@ func (ctrl *PropertyController) UpdateProperty(c *gin.Context) {
 			tools.NotFound(c, "property not found")
 			return
 		}
+		if err.Error() == "permission denied" {
+			tools.Forbidden(c, "you don't have permission to update this property")
+			return
+		}
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.InternalError(c, err.Error())
 		return
 	}
@@ -152,6 +171,131 @@ func (ctrl *PropertyController) DeleteProperty(c *gin.Context) {
 	tools.Success(c, gin.H{"message": "property deleted successfully"})
 }
 
+// SubmitProperty 提交房产审核
+func (ctrl *PropertyController) SubmitProperty(c *gin.Context) {
+	// 从中间件获取用户ID
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	property, err := ctrl.propertyService.SubmitProperty(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		switch {
+		case err.Error() == "property not found":
+			tools.NotFound(c, "property not found")
+		case err.Error() == "permission denied":
+			tools.Forbidden(c, "you don't have permission to submit this property")
+		case errors.Is(err, tools.ErrNotSubmittable):
+			tools.BadRequest(c, err.Error())
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	tools.Success(c, property)
+}
+
+// PublishProperty 立即发布或定时发布房产
+func (ctrl *PropertyController) PublishProperty(c *gin.Context) {
+	// 从中间件获取用户ID
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	// 请求体可为空（立即发布）
+	var req models.PublishPropertyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	property, err := ctrl.propertyService.PublishProperty(c.Request.Context(), uint(id), userID.(uint), &req)
+	if err != nil {
+		switch {
+		case err.Error() == "property not found":
+			tools.NotFound(c, "property not found")
+		case err.Error() == "permission denied":
+			tools.Forbidden(c, "you don't have permission to publish this property")
+		case errors.Is(err, tools.ErrNotPublishable), errors.Is(err, tools.ErrInvalidInput):
+			tools.BadRequest(c, err.Error())
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	tools.Success(c, property)
+}
+
+// CancelScheduledPublish 取消定时发布
+func (ctrl *PropertyController) CancelScheduledPublish(c *gin.Context) {
+	// 从中间件获取用户ID
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	property, err := ctrl.propertyService.CancelScheduledPublish(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		switch {
+		case err.Error() == "property not found":
+			tools.NotFound(c, "property not found")
+		case err.Error() == "permission denied":
+			tools.Forbidden(c, "you don't have permission to modify this property")
+		case errors.Is(err, tools.ErrNotScheduled):
+			tools.BadRequest(c, err.Error())
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	tools.Success(c, property)
+}
+
+// RenewProperty 续期房产
+func (ctrl *PropertyController) RenewProperty(c *gin.Context) {
+	// 从中间件获取用户ID
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	property, err := ctrl.propertyService.RenewProperty(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		switch {
+		case err.Error() == "property not found":
+			tools.NotFound(c, "property not found")
+		case err.Error() == "permission denied":
+			tools.Forbidden(c, "you don't have permission to renew this property")
+		case errors.Is(err, tools.ErrNotRenewable), errors.Is(err, tools.ErrRenewalLimitReached):
+			tools.BadRequest(c, err.Error())
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	tools.Success(c, property)
+}
+
 // GetSimilarProperties 获取相似房源
 func (ctrl *PropertyController) GetSimilarProperties(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -386,3 +530,168 @@ func (ctrl *PropertyController) ListLongTermRent(c *gin.Context) {
 
 	tools.Success(c, properties)
 }
+
+// ============ 图片管理 ============
+
+// GetPropertyImages 获取房产图片
+func (ctrl *PropertyController) GetPropertyImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	images, err := ctrl.propertyService.GetPropertyImages(c.Request.Context(), uint(id))
+	if err != nil {
+		respondPropertyImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// AddPropertyImage 添加房产图片
+func (ctrl *PropertyController) AddPropertyImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	var req models.AddImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.propertyService.AddPropertyImage(c.Request.Context(), uint(id), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondPropertyImageError(c, err)
+		return
+	}
+
+	tools.Created(c, image)
+}
+
+// UpdatePropertyImage 更新房产图片信息
+func (ctrl *PropertyController) UpdatePropertyImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	var req models.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.propertyService.UpdatePropertyImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondPropertyImageError(c, err)
+		return
+	}
+
+	tools.Success(c, image)
+}
+
+// DeletePropertyImage 删除房产图片
+func (ctrl *PropertyController) DeletePropertyImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	if err := ctrl.propertyService.DeletePropertyImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string)); err != nil {
+		respondPropertyImageError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "image deleted successfully"})
+}
+
+// ReorderPropertyImages 批量调整房产图片顺序
+func (ctrl *PropertyController) ReorderPropertyImages(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+
+	var req models.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	images, err := ctrl.propertyService.ReorderPropertyImages(c.Request.Context(), uint(id), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondPropertyImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// SetPropertyCoverImage 设置房产封面图
+func (ctrl *PropertyController) SetPropertyCoverImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid property id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	images, err := ctrl.propertyService.SetPropertyCoverImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string))
+	if err != nil {
+		respondPropertyImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// respondPropertyImageError 图片管理错误响应
+func respondPropertyImageError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "property not found", err.Error() == "image not found":
+		tools.NotFound(c, err.Error())
+	case err.Error() == "permission denied":
+		tools.Forbidden(c, "you don't have permission to manage images of this property")
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
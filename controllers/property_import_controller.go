@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// PropertyImportController Methods:
+// 0. NewPropertyImportController(service *services.PropertyImportService) -> 注入 PropertyImportService
+// 1. ImportProperties(c *gin.Context) -> 上传 CSV/XLSX 批量导入房产（代理公司）
+// 2. ListImports(c *gin.Context) -> 导入任务列表
+// 3. GetImport(c *gin.Context) -> 导入进度与结果
+// 4. DownloadTemplate(c *gin.Context) -> 下载导入模板
+
+type PropertyImportController struct {
+	importService *services.PropertyImportService
+}
+
+// 0. NewPropertyImportController -> 注入 PropertyImportService
+func NewPropertyImportController(importService *services.PropertyImportService) *PropertyImportController {
+	return &PropertyImportController{
+		importService: importService,
+	}
+}
+
+// 1. ImportProperties -> 上传文件并创建导入任务（后台执行，通过 GetImport 轮询进度）
+// POST /api/v1/properties/import（multipart/form-data，字段 file、dry_run）
+func (ctrl *PropertyImportController) ImportProperties(c *gin.Context) {
+	var req models.ImportPropertiesRequest
+	if err := c.ShouldBind(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		tools.BadRequest(c, "file is required")
+		return
+	}
+	if fileHeader.Size > ctrl.importService.MaxSize() {
+		tools.BadRequest(c, tools.ErrFileTooLarge.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 多读 1 字节用于判断是否超出大小上限
+	data, err := io.ReadAll(io.LimitReader(file, ctrl.importService.MaxSize()+1))
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	result, err := ctrl.importService.StartImport(c.Request.Context(), userID.(uint), userType.(string), fileHeader.Filename, data, req.DryRun)
+	if err != nil {
+		if errors.Is(err, tools.ErrFileTooLarge) ||
+			errors.Is(err, tools.ErrUnsupportedSpreadsheet) ||
+			errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Created(c, result)
+}
+
+// 2. ListImports -> 导入任务列表
+// GET /api/v1/properties/import
+func (ctrl *PropertyImportController) ListImports(c *gin.Context) {
+	var req models.ListPropertyImportsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	imports, err := ctrl.importService.ListImports(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, imports)
+}
+
+// 3. GetImport -> 导入进度与结果
+// GET /api/v1/properties/import/:importId
+func (ctrl *PropertyImportController) GetImport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("importId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid import id")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	result, err := ctrl.importService.GetImport(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		switch err {
+		case tools.ErrNotFound:
+			tools.NotFound(c, "import not found")
+		case tools.ErrForbidden:
+			tools.Forbidden(c, "you don't have permission to view this import")
+		default:
+			tools.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 4. DownloadTemplate -> 下载导入模板（CSV）
+// GET /api/v1/properties/import/template
+func (ctrl *PropertyImportController) DownloadTemplate(c *gin.Context) {
+	data, err := ctrl.importService.Template()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="property_import_template.csv"`)
+	c.Data(200, "text/csv; charset=utf-8", data)
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ServicedApartmentBookingController Methods:
+// 0. NewServicedApartmentBookingController(service *services.ServicedApartmentBookingService) -> 注入 ServicedApartmentBookingService
+// 1. GetAvailability(c *gin.Context) -> 房型房态日历
+// 2. CreateBooking(c *gin.Context) -> 提交预订申请（需要认证）
+// 3. ListBookings(c *gin.Context) -> 住宅预订列表（所属公司或管理员）
+// 4. ListMyBookings(c *gin.Context) -> 我的预订（需要认证）
+// 5. ConfirmBooking(c *gin.Context) -> 确认预订（所属公司或管理员）
+// 6. DeclineBooking(c *gin.Context) -> 拒绝预订（所属公司或管理员）
+// 7. CancelBooking(c *gin.Context) -> 取消预订（申请人、所属公司或管理员）
+
+type ServicedApartmentBookingController struct {
+	bookingService *services.ServicedApartmentBookingService
+}
+
+// 0. NewServicedApartmentBookingController -> 注入 ServicedApartmentBookingService
+func NewServicedApartmentBookingController(bookingService *services.ServicedApartmentBookingService) *ServicedApartmentBookingController {
+	return &ServicedApartmentBookingController{
+		bookingService: bookingService,
+	}
+}
+
+// 1. GetAvailability -> 房型房态日历（start_date 默认今天，end_date 不含当天）
+// GET /api/v1/serviced-apartments/:id/availability?start_date=&end_date=&unit_id=
+func (ctrl *ServicedApartmentBookingController) GetAvailability(c *gin.Context) {
+	id, ok := ctrl.parseID(c, "id", "invalid serviced apartment id")
+	if !ok {
+		return
+	}
+
+	var req models.GetAvailabilityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	calendar, err := ctrl.bookingService.GetAvailability(c.Request.Context(), id, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, calendar)
+}
+
+// 2. CreateBooking -> 提交预订申请（待营运商确认，期间占用房量）
+// POST /api/v1/serviced-apartments/:id/bookings
+func (ctrl *ServicedApartmentBookingController) CreateBooking(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	id, ok := ctrl.parseID(c, "id", "invalid serviced apartment id")
+	if !ok {
+		return
+	}
+
+	var req models.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	booking, err := ctrl.bookingService.CreateBooking(c.Request.Context(), id, userID.(uint), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Created(c, booking)
+}
+
+// 3. ListBookings -> 住宅预订列表
+// GET /api/v1/serviced-apartments/:id/bookings
+func (ctrl *ServicedApartmentBookingController) ListBookings(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, ok := ctrl.parseID(c, "id", "invalid serviced apartment id")
+	if !ok {
+		return
+	}
+
+	var req models.ListBookingsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.bookingService.ListBookings(c.Request.Context(), id, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 4. ListMyBookings -> 我的预订
+// GET /api/v1/users/me/bookings
+func (ctrl *ServicedApartmentBookingController) ListMyBookings(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.ListBookingsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.bookingService.ListMyBookings(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, result)
+}
+
+// 5. ConfirmBooking -> 确认预订
+// PUT /api/v1/serviced-apartments/:id/bookings/:bookingId/confirm
+func (ctrl *ServicedApartmentBookingController) ConfirmBooking(c *gin.Context) {
+	ctrl.respond(c, ctrl.bookingService.ConfirmBooking)
+}
+
+// 6. DeclineBooking -> 拒绝预订
+// PUT /api/v1/serviced-apartments/:id/bookings/:bookingId/decline
+func (ctrl *ServicedApartmentBookingController) DeclineBooking(c *gin.Context) {
+	ctrl.respond(c, ctrl.bookingService.DeclineBooking)
+}
+
+// 7. CancelBooking -> 取消预订
+// PUT /api/v1/serviced-apartments/:id/bookings/:bookingId/cancel
+func (ctrl *ServicedApartmentBookingController) CancelBooking(c *gin.Context) {
+	ctrl.respond(c, ctrl.bookingService.CancelBooking)
+}
+
+// bookingAction 预订状态操作（确认、拒绝、取消）
+type bookingAction func(ctx context.Context, apartmentID uint, bookingID uint, userID uint, userType string, req *models.RespondBookingRequest) (*models.ServicedApartmentBooking, error)
+
+// respond 解析参数并执行预订状态操作
+func (ctrl *ServicedApartmentBookingController) respond(c *gin.Context, action bookingAction) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, ok := ctrl.parseID(c, "id", "invalid serviced apartment id")
+	if !ok {
+		return
+	}
+	bookingID, ok := ctrl.parseID(c, "bookingId", "invalid booking id")
+	if !ok {
+		return
+	}
+
+	// 备注可选，允许空请求体
+	var req models.RespondBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	booking, err := action(c.Request.Context(), id, bookingID, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, booking)
+}
+
+// parseID 解析路径中的ID参数
+func (ctrl *ServicedApartmentBookingController) parseID(c *gin.Context, param, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError 错误响应
+func (ctrl *ServicedApartmentBookingController) handleError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "serviced apartment not found", err.Error() == "unit not found", err.Error() == "booking not found":
+		tools.NotFound(c, err.Error())
+	case err.Error() == "permission denied":
+		tools.Forbidden(c, "you don't have permission to manage this booking")
+	case errors.Is(err, databases.ErrUnitUnavailable), errors.Is(err, databases.ErrBookingStatusChanged),
+		errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
@@ -18,6 +19,11 @@ import (
 // 5. DeleteServicedApartment(c *gin.Context) -> 删除服务式住宅（需要认证）
 // 6. GetServicedApartmentUnits(c *gin.Context) -> 获取房型列表
 // 7. GetServicedApartmentImages(c *gin.Context) -> 获取图片列表
+// 8. AddServicedApartmentImage(c *gin.Context) -> 添加图片（需要认证）
+// 9. UpdateServicedApartmentImage(c *gin.Context) -> 更新图片信息（需要认证）
+// 10. DeleteServicedApartmentImage(c *gin.Context) -> 删除图片（需要认证）
+// 11. ReorderServicedApartmentImages(c *gin.Context) -> 批量调整图片顺序（需要认证）
+// 12. SetServicedApartmentCoverImage(c *gin.Context) -> 设置封面图（需要认证）
 type ServicedApartmentController struct {
 	service *services.ServicedApartmentService
 }
@@ -38,7 +44,17 @@ func NewServicedApartmentController(service *services.ServicedApartmentService)
 // @Param is_featured query bool false "是否精选"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20)
+// @Param check_in query string false "入住日期 (YYYY-MM-DD)，与退房日期同时提供时只列出有房住宅并按报价总额排序"
+// @Param check_out query string false "退房日期 (YYYY-MM-DD)"
+// @Param guests query int false "入住人数"
+// @Param facility_ids query []int false "设施ID（须全部具备）" collectionFormat(multi)
+// @Param min_cleaning query string false "最低清洁频率: monthly, biweekly, weekly, twice_weekly, daily"
+// @Param utilities_included query bool false "包水电煤"
+// @Param internet_included query bool false "包上网"
+// @Param pets_allowed query bool false "可养宠物"
+// @Param smoking_allowed query bool false "可吸烟"
 // @Success 200 {object} tools.Response{data=models.PaginatedServicedApartmentsResponse}
+// @Failure 400 {object} tools.Response
 // @Router /api/v1/serviced-apartments [get]
 func (ctrl *ServicedApartmentController) ListServicedApartments(c *gin.Context) {
 	var filter models.ListServicedApartmentsRequest
@@ -49,6 +65,10 @@ func (ctrl *ServicedApartmentController) ListServicedApartments(c *gin.Context)
 
 	result, err := ctrl.service.ListServicedApartments(c.Request.Context(), &filter)
 	if err != nil {
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.InternalError(c, err.Error())
 		return
 	}
@@ -108,6 +128,10 @@ func (ctrl *ServicedApartmentController) CreateServicedApartment(c *gin.Context)
 
 	apartment, err := ctrl.service.CreateServicedApartment(c.Request.Context(), &req, userID.(uint))
 	if err != nil {
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.InternalError(c, err.Error())
 		return
 	}
@@ -155,6 +179,10 @@ func (ctrl *ServicedApartmentController) UpdateServicedApartment(c *gin.Context)
 			tools.Forbidden(c, "you don't have permission to update this apartment")
 			return
 		}
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
 		tools.NotFound(c, err.Error())
 		return
 	}
@@ -251,3 +279,215 @@ func (ctrl *ServicedApartmentController) GetServicedApartmentImages(c *gin.Conte
 
 	tools.Success(c, images)
 }
+
+// AddServicedApartmentImage 添加服务式住宅图片（需要认证）
+// @Summary 添加服务式住宅图片
+// @Tags 服务式住宅
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务式住宅ID"
+// @Param request body models.AddImageRequest true "请求参数"
+// @Success 201 {object} tools.Response{data=models.ServicedApartmentImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/serviced-apartments/{id}/images [post]
+func (ctrl *ServicedApartmentController) AddServicedApartmentImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid serviced apartment id")
+		return
+	}
+
+	var req models.AddImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.service.AddServicedApartmentImage(c.Request.Context(), uint(id), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondServicedApartmentImageError(c, err)
+		return
+	}
+
+	tools.Created(c, image)
+}
+
+// UpdateServicedApartmentImage 更新服务式住宅图片信息（需要认证）
+// @Summary 更新服务式住宅图片信息
+// @Tags 服务式住宅
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务式住宅ID"
+// @Param imageId path int true "图片ID"
+// @Param request body models.UpdateImageRequest true "请求参数"
+// @Success 200 {object} tools.Response{data=models.ServicedApartmentImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/serviced-apartments/{id}/images/{imageId} [put]
+func (ctrl *ServicedApartmentController) UpdateServicedApartmentImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid serviced apartment id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	var req models.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.service.UpdateServicedApartmentImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondServicedApartmentImageError(c, err)
+		return
+	}
+
+	tools.Success(c, image)
+}
+
+// DeleteServicedApartmentImage 删除服务式住宅图片（需要认证）
+// @Summary 删除服务式住宅图片
+// @Tags 服务式住宅
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务式住宅ID"
+// @Param imageId path int true "图片ID"
+// @Success 200 {object} tools.Response
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/serviced-apartments/{id}/images/{imageId} [delete]
+func (ctrl *ServicedApartmentController) DeleteServicedApartmentImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid serviced apartment id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	if err := ctrl.service.DeleteServicedApartmentImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string)); err != nil {
+		respondServicedApartmentImageError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "image deleted successfully"})
+}
+
+// ReorderServicedApartmentImages 批量调整服务式住宅图片顺序（需要认证）
+// @Summary 批量调整服务式住宅图片顺序
+// @Tags 服务式住宅
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务式住宅ID"
+// @Param request body models.ReorderImagesRequest true "请求参数"
+// @Success 200 {object} tools.Response{data=[]models.ServicedApartmentImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/serviced-apartments/{id}/images [put]
+func (ctrl *ServicedApartmentController) ReorderServicedApartmentImages(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid serviced apartment id")
+		return
+	}
+
+	var req models.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	images, err := ctrl.service.ReorderServicedApartmentImages(c.Request.Context(), uint(id), userID.(uint), userType.(string), &req)
+	if err != nil {
+		respondServicedApartmentImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// SetServicedApartmentCoverImage 设置服务式住宅封面图（需要认证）
+// @Summary 设置服务式住宅封面图
+// @Tags 服务式住宅
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务式住宅ID"
+// @Param imageId path int true "图片ID"
+// @Success 200 {object} tools.Response{data=[]models.ServicedApartmentImage}
+// @Failure 400 {object} tools.Response
+// @Failure 401 {object} tools.Response
+// @Failure 403 {object} tools.Response
+// @Failure 404 {object} tools.Response
+// @Router /api/v1/serviced-apartments/{id}/images/{imageId}/cover [put]
+func (ctrl *ServicedApartmentController) SetServicedApartmentCoverImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid serviced apartment id")
+		return
+	}
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid image id")
+		return
+	}
+
+	images, err := ctrl.service.SetServicedApartmentCoverImage(c.Request.Context(), uint(id), uint(imageID), userID.(uint), userType.(string))
+	if err != nil {
+		respondServicedApartmentImageError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// respondServicedApartmentImageError 图片管理错误响应
+func respondServicedApartmentImageError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "serviced apartment not found", err.Error() == "image not found":
+		tools.NotFound(c, err.Error())
+	case err.Error() == "permission denied":
+		tools.Forbidden(c, "you don't have permission to manage images of this apartment")
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
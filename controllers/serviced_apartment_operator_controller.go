@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ServicedApartmentOperatorController Methods:
+// 0. NewServicedApartmentOperatorController(service *services.ServicedApartmentOperatorService) -> 注入 ServicedApartmentOperatorService
+// 1. CreateUnit(c *gin.Context) -> 新增房型（所属公司或管理员）
+// 2. UpdateUnit(c *gin.Context) -> 更新房型（所属公司或管理员）
+// 3. DeleteUnit(c *gin.Context) -> 删除房型（所属公司或管理员）
+// 4. GetUnitImages(c *gin.Context) -> 房型图片列表
+// 5. AddUnitImage(c *gin.Context) -> 添加房型图片（所属公司或管理员）
+// 6. UpdateUnitImage(c *gin.Context) -> 更新房型图片信息（所属公司或管理员）
+// 7. DeleteUnitImage(c *gin.Context) -> 删除房型图片（所属公司或管理员）
+// 8. ReorderUnitImages(c *gin.Context) -> 批量调整房型图片顺序（所属公司或管理员）
+// 9. GetDashboard(c *gin.Context) -> 营运商概览（需要认证）
+
+type ServicedApartmentOperatorController struct {
+	operatorService *services.ServicedApartmentOperatorService
+}
+
+// 0. NewServicedApartmentOperatorController -> 注入 ServicedApartmentOperatorService
+func NewServicedApartmentOperatorController(operatorService *services.ServicedApartmentOperatorService) *ServicedApartmentOperatorController {
+	return &ServicedApartmentOperatorController{
+		operatorService: operatorService,
+	}
+}
+
+// 1. CreateUnit -> 新增房型
+// POST /api/v1/serviced-apartments/:id/units
+func (ctrl *ServicedApartmentOperatorController) CreateUnit(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, ok := ctrl.parseID(c, "id", "invalid serviced apartment id")
+	if !ok {
+		return
+	}
+
+	var req models.CreateServicedApartmentUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	unit, err := ctrl.operatorService.CreateUnit(c.Request.Context(), id, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Created(c, unit)
+}
+
+// 2. UpdateUnit -> 更新房型（价格、入住人数、单位数、排序等）
+// PUT /api/v1/serviced-apartments/:id/units/:unitId
+func (ctrl *ServicedApartmentOperatorController) UpdateUnit(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, unitID, ok := ctrl.parseUnitPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateServicedApartmentUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	unit, err := ctrl.operatorService.UpdateUnit(c.Request.Context(), id, unitID, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, unit)
+}
+
+// 3. DeleteUnit -> 删除房型
+// DELETE /api/v1/serviced-apartments/:id/units/:unitId
+func (ctrl *ServicedApartmentOperatorController) DeleteUnit(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, unitID, ok := ctrl.parseUnitPath(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.operatorService.DeleteUnit(c.Request.Context(), id, unitID, userID.(uint), userType.(string)); err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "unit deleted successfully"})
+}
+
+// 4. GetUnitImages -> 房型图片列表
+// GET /api/v1/serviced-apartments/:id/units/:unitId/images
+func (ctrl *ServicedApartmentOperatorController) GetUnitImages(c *gin.Context) {
+	id, unitID, ok := ctrl.parseUnitPath(c)
+	if !ok {
+		return
+	}
+
+	images, err := ctrl.operatorService.GetUnitImages(c.Request.Context(), id, unitID)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// 5. AddUnitImage -> 添加房型图片
+// POST /api/v1/serviced-apartments/:id/units/:unitId/images
+func (ctrl *ServicedApartmentOperatorController) AddUnitImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, unitID, ok := ctrl.parseUnitPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.AddImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.operatorService.AddUnitImage(c.Request.Context(), id, unitID, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Created(c, image)
+}
+
+// 6. UpdateUnitImage -> 更新房型图片信息
+// PUT /api/v1/serviced-apartments/:id/units/:unitId/images/:imageId
+func (ctrl *ServicedApartmentOperatorController) UpdateUnitImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, unitID, ok := ctrl.parseUnitPath(c)
+	if !ok {
+		return
+	}
+	imageID, ok := ctrl.parseID(c, "imageId", "invalid image id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := ctrl.operatorService.UpdateUnitImage(c.Request.Context(), id, unitID, imageID, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, image)
+}
+
+// 7. DeleteUnitImage -> 删除房型图片
+// DELETE /api/v1/serviced-apartments/:id/units/:unitId/images/:imageId
+func (ctrl *ServicedApartmentOperatorController) DeleteUnitImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, unitID, ok := ctrl.parseUnitPath(c)
+	if !ok {
+		return
+	}
+	imageID, ok := ctrl.parseID(c, "imageId", "invalid image id")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.operatorService.DeleteUnitImage(c.Request.Context(), id, unitID, imageID, userID.(uint), userType.(string)); err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "image deleted successfully"})
+}
+
+// 8. ReorderUnitImages -> 批量调整房型图片顺序
+// PUT /api/v1/serviced-apartments/:id/units/:unitId/images
+func (ctrl *ServicedApartmentOperatorController) ReorderUnitImages(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, unitID, ok := ctrl.parseUnitPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	images, err := ctrl.operatorService.ReorderUnitImages(c.Request.Context(), id, unitID, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, images)
+}
+
+// 9. GetDashboard -> 营运商概览（管理员可用 company_id 查看指定公司）
+// GET /api/v1/serviced-apartments/dashboard?company_id=
+func (ctrl *ServicedApartmentOperatorController) GetDashboard(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	var req models.GetOperatorDashboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	dashboard, err := ctrl.operatorService.GetDashboard(c.Request.Context(), userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, dashboard)
+}
+
+// parseUnitPath 解析路径中的住宅ID及房型ID
+func (ctrl *ServicedApartmentOperatorController) parseUnitPath(c *gin.Context) (uint, uint, bool) {
+	id, ok := ctrl.parseID(c, "id", "invalid serviced apartment id")
+	if !ok {
+		return 0, 0, false
+	}
+	unitID, ok := ctrl.parseID(c, "unitId", "invalid unit id")
+	if !ok {
+		return 0, 0, false
+	}
+	return id, unitID, true
+}
+
+// parseID 解析路径中的ID参数
+func (ctrl *ServicedApartmentOperatorController) parseID(c *gin.Context, param, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, message)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError 错误响应
+func (ctrl *ServicedApartmentOperatorController) handleError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "serviced apartment not found", err.Error() == "unit not found", err.Error() == "image not found":
+		tools.NotFound(c, err.Error())
+	case err.Error() == "permission denied":
+		tools.Forbidden(c, "you don't have permission to manage this apartment")
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
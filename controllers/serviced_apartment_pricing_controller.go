@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// ServicedApartmentPricingController Methods:
+// 0. NewServicedApartmentPricingController(service *services.ServicedApartmentPricingService) -> 注入 ServicedApartmentPricingService
+// 1. GetQuote(c *gin.Context) -> 入住报价（各房型最低租金组合及明细）
+// 2. GetPricingRules(c *gin.Context) -> 季节性调价及长住折扣
+// 3. UpdatePricingRules(c *gin.Context) -> 整体替换季节性调价及长住折扣（所属公司或管理员）
+
+type ServicedApartmentPricingController struct {
+	pricingService *services.ServicedApartmentPricingService
+}
+
+// 0. NewServicedApartmentPricingController -> 注入 ServicedApartmentPricingService
+func NewServicedApartmentPricingController(pricingService *services.ServicedApartmentPricingService) *ServicedApartmentPricingController {
+	return &ServicedApartmentPricingController{
+		pricingService: pricingService,
+	}
+}
+
+// 1. GetQuote -> 入住报价（check_out 为退房日期，不计当晚）
+// GET /api/v1/serviced-apartments/:id/quote?check_in=&check_out=&unit_id=&guests=
+func (ctrl *ServicedApartmentPricingController) GetQuote(c *gin.Context) {
+	id, ok := ctrl.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req models.GetStayQuoteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	quote, err := ctrl.pricingService.GetQuote(c.Request.Context(), id, &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, quote)
+}
+
+// 2. GetPricingRules -> 季节性调价及长住折扣
+// GET /api/v1/serviced-apartments/:id/pricing-rules
+func (ctrl *ServicedApartmentPricingController) GetPricingRules(c *gin.Context) {
+	id, ok := ctrl.parseID(c)
+	if !ok {
+		return
+	}
+
+	rules, err := ctrl.pricingService.GetPricingRules(c.Request.Context(), id)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, rules)
+}
+
+// 3. UpdatePricingRules -> 整体替换季节性调价及长住折扣
+// PUT /api/v1/serviced-apartments/:id/pricing-rules
+func (ctrl *ServicedApartmentPricingController) UpdatePricingRules(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userType, _ := c.Get("user_type")
+
+	id, ok := ctrl.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePricingRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	rules, err := ctrl.pricingService.UpdatePricingRules(c.Request.Context(), id, userID.(uint), userType.(string), &req)
+	if err != nil {
+		ctrl.handleError(c, err)
+		return
+	}
+
+	tools.Success(c, rules)
+}
+
+// parseID 解析路径中的服务式住宅ID
+func (ctrl *ServicedApartmentPricingController) parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid serviced apartment id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleError 错误响应
+func (ctrl *ServicedApartmentPricingController) handleError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "serviced apartment not found", err.Error() == "unit not found":
+		tools.NotFound(c, err.Error())
+	case err.Error() == "permission denied":
+		tools.Forbidden(c, "you don't have permission to manage pricing of this apartment")
+	case errors.Is(err, tools.ErrInvalidInput):
+		tools.BadRequest(c, err.Error())
+	default:
+		tools.InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// StampDutyController Methods:
+// 0. NewStampDutyController(service *services.StampDutyService) -> 注入 StampDutyService
+// 1. CalculateStampDuty(c *gin.Context) -> 印花税及交易费用估算
+
+type StampDutyController struct {
+	stampDutyService *services.StampDutyService
+}
+
+// 0. NewStampDutyController -> 注入 StampDutyService
+func NewStampDutyController(stampDutyService *services.StampDutyService) *StampDutyController {
+	return &StampDutyController{
+		stampDutyService: stampDutyService,
+	}
+}
+
+// 1. CalculateStampDuty -> 印花税及交易费用估算
+// POST /api/v1/tools/stamp-duty
+func (ctrl *StampDutyController) CalculateStampDuty(c *gin.Context) {
+	var req models.StampDutyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.stampDutyService.Calculate(&req)
+	if err != nil {
+		if errors.Is(err, tools.ErrInvalidInput) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, result)
+}
@@ -97,6 +97,33 @@ func (ctrl *StatisticsController) GetTransactionStatistics(c *gin.Context) {
 	tools.Success(c, stats)
 }
 
+// GetFirstHandStatistics 获取一手成交统计
+// @Summary 获取一手成交统计
+// @Description 按新盘成交纪录册统计一手成交，包括成交宗数、金额、呎价、成交价相对价单售价、地区分布、新盘去化率及月度趋势
+// @Tags 统计分析
+// @Accept json
+// @Produce json
+// @Param start_date query string false "开始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "结束日期 (YYYY-MM-DD)"
+// @Param district_id query int false "地区ID"
+// @Success 200 {object} tools.Response{data=models.FirstHandStatisticsResponse}
+// @Router /api/v1/statistics/first-hand [get]
+func (ctrl *StatisticsController) GetFirstHandStatistics(c *gin.Context) {
+	var req models.GetFirstHandStatisticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	stats, err := ctrl.service.GetFirstHandStatistics(c.Request.Context(), &req)
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, stats)
+}
+
 // GetUserStatistics 获取用户统计
 // @Summary 获取用户统计
 // @Description 获取用户统计数据，包括用户总数、新增用户、活跃度、代理统计等
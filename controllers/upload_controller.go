@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// UploadController Methods:
+// 0. NewUploadController(service *services.MediaService) -> 注入 MediaService
+// 1. UploadImage(c *gin.Context) -> 上传图片（需认证）
+
+type UploadController struct {
+	mediaService *services.MediaService
+}
+
+// 0. NewUploadController -> 注入 MediaService
+func NewUploadController(mediaService *services.MediaService) *UploadController {
+	return &UploadController{
+		mediaService: mediaService,
+	}
+}
+
+// 1. UploadImage -> 上传图片
+// POST /api/v1/uploads/images（multipart/form-data，字段名 file）
+func (ctrl *UploadController) UploadImage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		tools.BadRequest(c, "file is required")
+		return
+	}
+	if fileHeader.Size > ctrl.mediaService.MaxSize() {
+		tools.BadRequest(c, tools.ErrFileTooLarge.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 多读 1 字节用于判断是否超出大小上限
+	data, err := io.ReadAll(io.LimitReader(file, ctrl.mediaService.MaxSize()+1))
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	result, err := ctrl.mediaService.UploadImage(c.Request.Context(), userID.(uint), data)
+	if err != nil {
+		if errors.Is(err, tools.ErrFileTooLarge) ||
+			errors.Is(err, tools.ErrUnsupportedImageType) ||
+			errors.Is(err, tools.ErrInvalidImage) {
+			tools.BadRequest(c, err.Error())
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Created(c, result)
+}
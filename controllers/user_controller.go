@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"strconv"
+
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
@@ -94,3 +96,54 @@ func (ctrl *UserController) GetMyListings(c *gin.Context) {
 
 	tools.Success(c, listings)
 }
+
+// GetMyNotifications 获取我的站内通知
+func (ctrl *UserController) GetMyNotifications(c *gin.Context) {
+	// 从中间件获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		tools.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req models.ListNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	notifications, err := ctrl.userService.GetNotifications(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, notifications)
+}
+
+// MarkNotificationRead 标记通知为已读
+func (ctrl *UserController) MarkNotificationRead(c *gin.Context) {
+	// 从中间件获取用户ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		tools.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		tools.BadRequest(c, "invalid notification id")
+		return
+	}
+
+	if err := ctrl.userService.MarkNotificationRead(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		if err.Error() == "notification not found" {
+			tools.NotFound(c, "notification not found")
+			return
+		}
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, gin.H{"message": "notification marked as read"})
+}
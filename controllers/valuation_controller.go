@@ -15,6 +15,7 @@ import (
 // 2. GetEstateValuation(c *gin.Context) -> 获取指定屋苑估价参考
 // 3. SearchValuations(c *gin.Context) -> 搜索屋苑估价
 // 4. GetDistrictValuations(c *gin.Context) -> 获取地区屋苑估价列表
+// 5. GetYieldRanking(c *gin.Context) -> 屋苑户型租金回报率排行
 
 type ValuationController struct {
 	valuationService *services.ValuationService
@@ -118,3 +119,21 @@ func (ctrl *ValuationController) GetDistrictValuations(c *gin.Context) {
 
 	tools.Success(c, summary)
 }
+
+// 5. GetYieldRanking -> 屋苑户型租金回报率排行
+// GET /api/v1/valuation/yield-ranking
+func (ctrl *ValuationController) GetYieldRanking(c *gin.Context) {
+	var req models.YieldRankingRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tools.BadRequest(c, err.Error())
+		return
+	}
+
+	response, err := ctrl.valuationService.GetYieldRanking(c.Request.Context(), &req)
+	if err != nil {
+		tools.InternalError(c, err.Error())
+		return
+	}
+
+	tools.Success(c, response)
+}
@@ -0,0 +1,131 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// AgencyFeedRepo 代理公司刊登 Feed 仓储
+type AgencyFeedRepo struct {
+	db *gorm.DB
+}
+
+// NewAgencyFeedRepo 创建代理公司刊登 Feed 仓储
+func NewAgencyFeedRepo(db *gorm.DB) *AgencyFeedRepo {
+	return &AgencyFeedRepo{db: db}
+}
+
+// ============ API Key ============
+
+// CreateKey 创建 API Key
+func (r *AgencyFeedRepo) CreateKey(ctx context.Context, key *models.AgencyFeedKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// FindKeysByAgency 查询代理公司的 API Key（含已吊销）
+func (r *AgencyFeedRepo) FindKeysByAgency(ctx context.Context, agencyID uint) ([]models.AgencyFeedKey, error) {
+	var keys []models.AgencyFeedKey
+	err := r.db.WithContext(ctx).
+		Where("agency_id = ?", agencyID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// FindActiveKeyByHash 根据哈希查询未吊销的 API Key（不存在时返回 nil）
+func (r *AgencyFeedRepo) FindActiveKeyByHash(ctx context.Context, keyHash string) (*models.AgencyFeedKey, error) {
+	var key models.AgencyFeedKey
+	err := r.db.WithContext(ctx).
+		Where("key_hash = ? AND revoked_at IS NULL", keyHash).
+		First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindKey 查询代理公司的 API Key（不存在时返回 nil）
+func (r *AgencyFeedRepo) FindKey(ctx context.Context, agencyID, keyID uint) (*models.AgencyFeedKey, error) {
+	var key models.AgencyFeedKey
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND agency_id = ?", keyID, agencyID).
+		First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeKey 吊销 API Key，返回是否找到未吊销的 Key
+func (r *AgencyFeedRepo) RevokeKey(ctx context.Context, agencyID, keyID uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.AgencyFeedKey{}).
+		Where("id = ? AND agency_id = ? AND revoked_at IS NULL", keyID, agencyID).
+		UpdateColumn("revoked_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// TouchKey 记录 API Key 使用时间
+func (r *AgencyFeedRepo) TouchKey(ctx context.Context, keyID uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.AgencyFeedKey{}).
+		Where("id = ?", keyID).
+		UpdateColumn("last_used_at", now).Error
+}
+
+// ============ Feed 数据 ============
+
+// FindStats 统计发布者房产数量及最后修改时间（含已删除，用于 ETag / Last-Modified）
+func (r *AgencyFeedRepo) FindStats(ctx context.Context, publisherID uint) (*models.AgencyFeedStats, error) {
+	var stats models.AgencyFeedStats
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Property{}).
+		Select("COUNT(*) AS count, GREATEST(MAX(updated_at), MAX(deleted_at)) AS last_modified").
+		Where("publisher_id = ?", publisherID).
+		Scan(&stats).Error
+	return &stats, err
+}
+
+// FindListings 查询发布者的在架房源（since 不为空时只返回之后有修改的）
+func (r *AgencyFeedRepo) FindListings(ctx context.Context, publisherID uint, since *time.Time) ([]models.Property, error) {
+	var properties []models.Property
+	query := r.db.WithContext(ctx).
+		Where("publisher_id = ? AND status = ?", publisherID, models.ListingStatusPublished)
+	if since != nil {
+		query = query.Where("updated_at > ?", *since)
+	}
+	err := query.
+		Preload("District").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Order("id ASC").
+		Find(&properties).Error
+	return properties, err
+}
+
+// FindRemovedSince 查询 since 之后下架或删除的房源
+// 只列出曾公开过的房源：当前为发布后的状态（已售、已租、过期等），或曾审核通过 / 提交审核时已发布
+// （发布后改回待审核、被拒的房源也需下架）；从未公开的草稿、待审核等不列出
+func (r *AgencyFeedRepo) FindRemovedSince(ctx context.Context, publisherID uint, since time.Time) ([]models.Property, error) {
+	var properties []models.Property
+	wasPublished := r.db.Model(&models.ListingReview{}).
+		Select("1").
+		Where("listing_reviews.entity_type = ? AND listing_reviews.entity_id = properties.id", "property").
+		Where("listing_reviews.status = ? OR listing_reviews.was_published", models.ReviewStatusApproved)
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("publisher_id = ?", publisherID).
+		Where("status NOT IN ? OR EXISTS (?)", models.UnpublishedListingStatuses, wasPublished).
+		Where("(deleted_at > ?) OR (deleted_at IS NULL AND updated_at > ? AND status <> ?)",
+			since, since, models.ListingStatusPublished).
+		Order("id ASC").
+		Find(&properties).Error
+	return properties, err
+}
@@ -93,13 +93,21 @@ func (r *AgencyRepo) FindByUserID(ctx context.Context, userID uint) (*models.Age
 	return &agency, nil
 }
 
+// UpdateFeedPrivate 更新刊登 Feed 是否私有
+func (r *AgencyRepo) UpdateFeedPrivate(ctx context.Context, id uint, private bool) error {
+	return r.db.WithContext(ctx).Model(&models.AgencyDetail{}).
+		Where("id = ?", id).
+		Update("feed_private", private).Error
+}
+
 // GetAgencyProperties 查询代理公司的房源列表
 func (r *AgencyRepo) GetAgencyProperties(ctx context.Context, userID uint, page, pageSize int) ([]models.Property, int64, error) {
 	var properties []models.Property
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&models.Property{}).
-		Where("publisher_id = ? AND publisher_type = ?", userID, "agency")
+		Where("publisher_id = ? AND publisher_type = ?", userID, "agency").
+		Where("status NOT IN ?", models.UnpublishedListingStatuses)
 
 	// 统计总数
 	if err := query.Count(&total).Error; err != nil {
@@ -175,3 +183,14 @@ func (r *AgencyRepo) DecrementAgentCount(ctx context.Context, userID uint) error
 		Where("agent_count > ?", 0).
 		UpdateColumn("agent_count", gorm.Expr("agent_count - ?", 1)).Error
 }
+
+// RecountAgents 重新统计所有代理公司的在职代理人数量（定时任务调用）
+func (r *AgencyRepo) RecountAgents(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE agency_details SET agent_count = (
+			SELECT COUNT(*) FROM agents
+			WHERE agents.agency_id = agency_details.user_id
+			  AND agents.status = 'active'
+			  AND agents.deleted_at IS NULL
+		)`).Error
+}
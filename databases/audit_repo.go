@@ -0,0 +1,63 @@
+package databases
+
+import (
+	"context"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// AuditRepo 审计日志仓储
+type AuditRepo struct {
+	db *gorm.DB
+}
+
+// NewAuditRepo 创建审计日志仓储
+func NewAuditRepo(db *gorm.DB) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+// Create 写入审计日志
+func (r *AuditRepo) Create(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// FindAll 查询审计日志（按时间倒序）
+func (r *AuditRepo) FindAll(ctx context.Context, req *models.ListAuditLogsRequest) ([]models.AuditLog, int64, error) {
+	var logs []models.AuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+	if req.Entity != "" {
+		query = query.Where("entity_type = ?", req.Entity)
+	}
+	if req.ID != nil {
+		query = query.Where("entity_id = ?", *req.ID)
+	}
+	if req.ActorID != nil {
+		query = query.Where("actor_id = ?", *req.ActorID)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+	if req.RequestID != "" {
+		query = query.Where("request_id = ?", req.RequestID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").
+		Offset((req.Page - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+// DeleteBefore 删除指定时间之前的审计日志
+func (r *AuditRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.AuditLog{})
+	return result.RowsAffected, result.Error
+}
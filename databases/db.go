@@ -70,6 +70,10 @@ func InitDB() error {
 func autoMigrate() error {
 	log.Println("🔄 Running database auto migration...")
 
+	// 住客守则首次加列时，已有住宅需补填为允许访客留宿（与新建住宅一致）
+	backfillOvernightGuests := DB.Migrator().HasTable(&models.ServicedApartment{}) &&
+		!DB.Migrator().HasColumn(&models.ServicedApartment{}, "overnight_guests_allowed")
+
 	// 按依赖顺序迁移表：先创建被引用的表，再创建引用它们的表
 	err := DB.AutoMigrate(
 		// 基础表（无外键依赖）
@@ -101,12 +105,40 @@ func autoMigrate() error {
 		&models.AgencyDetail{},
 		&models.AgencyContact{},
 		&models.SearchHistory{},
+		&models.Notification{},
+		&models.JobRun{},
+		&models.MediaObject{},
+		&models.ListingReview{},
+		&models.DuplicateCluster{},
+		&models.IDSequence{},
+		&models.AuditLog{},
+		&models.PropertyImport{},
+		&models.AgencyFeedKey{},
+		&models.EstateYield{},
+		&models.PriceList{},
+		&models.PriceListItem{},
+		&models.NewPropertyUnit{},
+		&models.SalesArrangement{},
+		&models.NewPropertyTransaction{},
+		&models.ServicedApartmentBooking{},
+		&models.ServicedApartmentInventory{},
+		&models.ServicedApartmentSeasonalRate{},
+		&models.ServicedApartmentStayDiscount{},
+		&models.ServicedApartmentFacility{},
+		&models.EstateBlock{},
+		&models.EstateUnit{},
 	)
 
 	if err != nil {
 		return err
 	}
 
+	if backfillOvernightGuests {
+		if err := DB.Exec("UPDATE serviced_apartments SET overnight_guests_allowed = ?", true).Error; err != nil {
+			return err
+		}
+	}
+
 	log.Println("✅ Database auto migration completed")
 	return nil
 }
@@ -0,0 +1,47 @@
+package databases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// DuplicateClusterRepo 重复组处理记录仓储
+type DuplicateClusterRepo struct {
+	db *gorm.DB
+}
+
+// NewDuplicateClusterRepo 创建重复组处理记录仓储
+func NewDuplicateClusterRepo(db *gorm.DB) *DuplicateClusterRepo {
+	return &DuplicateClusterRepo{db: db}
+}
+
+// FindByFingerprint 根据指纹查询处理记录（不存在时返回 nil）
+func (r *DuplicateClusterRepo) FindByFingerprint(ctx context.Context, fingerprint string) (*models.DuplicateCluster, error) {
+	var cluster models.DuplicateCluster
+	err := r.db.WithContext(ctx).Where("fingerprint = ?", fingerprint).First(&cluster).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cluster, nil
+}
+
+// FindByFingerprints 批量查询处理记录
+func (r *DuplicateClusterRepo) FindByFingerprints(ctx context.Context, fingerprints []string) ([]models.DuplicateCluster, error) {
+	var clusters []models.DuplicateCluster
+	if len(fingerprints) == 0 {
+		return clusters, nil
+	}
+	err := r.db.WithContext(ctx).Where("fingerprint IN ?", fingerprints).Find(&clusters).Error
+	return clusters, err
+}
+
+// Save 创建或更新处理记录
+func (r *DuplicateClusterRepo) Save(ctx context.Context, cluster *models.DuplicateCluster) error {
+	return r.db.WithContext(ctx).Save(cluster).Error
+}
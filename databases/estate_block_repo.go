@@ -0,0 +1,158 @@
+package databases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EstateBlockRepo 屋苑座数及单位目录仓储
+type EstateBlockRepo struct {
+	db *gorm.DB
+}
+
+// NewEstateBlockRepo 创建屋苑座数仓储
+func NewEstateBlockRepo(db *gorm.DB) *EstateBlockRepo {
+	return &EstateBlockRepo{db: db}
+}
+
+// FindBlocks 查询屋苑的座数（includeUnits 时按楼层、单位顺序加载单位）
+func (r *EstateBlockRepo) FindBlocks(ctx context.Context, estateID uint, includeUnits bool) ([]models.EstateBlock, error) {
+	var blocks []models.EstateBlock
+	query := r.db.WithContext(ctx).Where("estate_id = ?", estateID)
+	if includeUnits {
+		query = query.Preload("Units", func(db *gorm.DB) *gorm.DB {
+			return db.Order("floor_level ASC, floor ASC, flat ASC")
+		})
+	}
+	err := query.Order("sort_order ASC, name ASC").Find(&blocks).Error
+	return blocks, err
+}
+
+// FindBlock 查询屋苑的单个座数
+func (r *EstateBlockRepo) FindBlock(ctx context.Context, estateID, blockID uint) (*models.EstateBlock, error) {
+	var block models.EstateBlock
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND estate_id = ?", blockID, estateID).
+		First(&block).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("estate block not found")
+		}
+		return nil, err
+	}
+	return &block, nil
+}
+
+// FindUnits 查询座数的单位（可按楼层筛选）
+func (r *EstateBlockRepo) FindUnits(ctx context.Context, blockID uint, floor string) ([]models.EstateUnit, error) {
+	var units []models.EstateUnit
+	query := r.db.WithContext(ctx).Where("block_id = ?", blockID)
+	if floor != "" {
+		query = query.Where("floor = ?", floor)
+	}
+	err := query.Order("floor_level ASC, floor ASC, flat ASC").Find(&units).Error
+	return units, err
+}
+
+// FindUnit 根据ID查询单位（加载所属座数）
+func (r *EstateBlockRepo) FindUnit(ctx context.Context, unitID uint) (*models.EstateUnit, error) {
+	var unit models.EstateUnit
+	err := r.db.WithContext(ctx).
+		Preload("Block").
+		First(&unit, unitID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("estate unit not found")
+		}
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// FindAllUnits 查询屋苑的全部单位（用于导入时按座数/楼层/单位匹配）
+func (r *EstateBlockRepo) FindAllUnits(ctx context.Context, estateID uint) ([]models.EstateUnit, error) {
+	var units []models.EstateUnit
+	err := r.db.WithContext(ctx).
+		Where("estate_id = ?", estateID).
+		Find(&units).Error
+	return units, err
+}
+
+// ImportUnits 保存单位目录：新增或更新座数（ID 为 0 的为新座数），新增或更新单位（座数ID取自 Block 关联），随后重新汇总各座楼层数及单位数
+func (r *EstateBlockRepo) ImportUnits(ctx context.Context, estateID uint, blocks []*models.EstateBlock, units []*models.EstateUnit) (totalBlocks, totalUnits int, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, block := range blocks {
+			if err := tx.Omit(clause.Associations).Save(block).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, unit := range units {
+			unit.BlockID = unit.Block.ID
+			if err := tx.Omit(clause.Associations).Save(unit).Error; err != nil {
+				return err
+			}
+		}
+
+		totalBlocks, totalUnits, err = rollupEstateBlocks(tx, estateID)
+		return err
+	})
+	return totalBlocks, totalUnits, err
+}
+
+// rollupEstateBlocks 按单位目录重新汇总各座楼层数、每层单位数及单位总数，返回屋苑座数及单位总数
+func rollupEstateBlocks(tx *gorm.DB, estateID uint) (totalBlocks, totalUnits int, err error) {
+	var counts []struct {
+		BlockID uint
+		Floor   string
+		Count   int
+	}
+	if err := tx.Model(&models.EstateUnit{}).
+		Select("block_id, floor, COUNT(*) AS count").
+		Where("estate_id = ?", estateID).
+		Group("block_id, floor").
+		Scan(&counts).Error; err != nil {
+		return 0, 0, err
+	}
+
+	type blockRollup struct {
+		floors, flatsPerFloor, units int
+	}
+	rollups := make(map[uint]*blockRollup)
+	for _, c := range counts {
+		rollup, ok := rollups[c.BlockID]
+		if !ok {
+			rollup = &blockRollup{}
+			rollups[c.BlockID] = rollup
+		}
+		rollup.floors++
+		rollup.units += c.Count
+		if c.Count > rollup.flatsPerFloor {
+			rollup.flatsPerFloor = c.Count
+		}
+		totalUnits += c.Count
+	}
+
+	var blockIDs []uint
+	if err := tx.Model(&models.EstateBlock{}).Where("estate_id = ?", estateID).Pluck("id", &blockIDs).Error; err != nil {
+		return 0, 0, err
+	}
+	for _, blockID := range blockIDs {
+		rollup, ok := rollups[blockID]
+		if !ok {
+			rollup = &blockRollup{}
+		}
+		if err := tx.Model(&models.EstateBlock{}).Where("id = ?", blockID).Updates(map[string]interface{}{
+			"floors":          rollup.floors,
+			"flats_per_floor": rollup.flatsPerFloor,
+			"total_units":     rollup.units,
+		}).Error; err != nil {
+			return 0, 0, err
+		}
+	}
+	return len(blockIDs), totalUnits, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EstateRepo 屋苑仓储
@@ -114,6 +115,38 @@ func (r *EstateRepo) FindByID(ctx context.Context, id uint) (*models.Estate, err
 	return &estate, nil
 }
 
+// FindByIDs 根据ID批量查询屋苑（加载地区、图片及设施）
+func (r *EstateRepo) FindByIDs(ctx context.Context, ids []uint) ([]models.Estate, error) {
+	var estates []models.Estate
+	err := r.db.WithContext(ctx).
+		Preload("District").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Preload("Facilities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Where("id IN ?", ids).
+		Find(&estates).Error
+	return estates, err
+}
+
+// FindByNames 根据名称批量查询屋苑（加载设施，用于按大厦名称关联房产）
+func (r *EstateRepo) FindByNames(ctx context.Context, names []string) ([]models.Estate, error) {
+	var estates []models.Estate
+	if len(names) == 0 {
+		return estates, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Facilities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Where("name IN ?", names).
+		Order("id ASC").
+		Find(&estates).Error
+	return estates, err
+}
+
 // FindFeatured 查询精选屋苑
 func (r *EstateRepo) FindFeatured(ctx context.Context, limit int) ([]models.Estate, error) {
 	var estates []models.Estate
@@ -194,6 +227,52 @@ func (r *EstateRepo) FindImagesByEstateID(ctx context.Context, estateID uint) ([
 	return images, nil
 }
 
+// FindImage 查询单张图片
+func (r *EstateRepo) FindImage(ctx context.Context, estateID, imageID uint) (*models.EstateImage, error) {
+	var image models.EstateImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND estate_id = ?", imageID, estateID).
+		First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage 创建单张图片
+func (r *EstateRepo) CreateImage(ctx context.Context, image *models.EstateImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+// UpdateImage 更新图片信息
+func (r *EstateRepo) UpdateImage(ctx context.Context, image *models.EstateImage) error {
+	return r.db.WithContext(ctx).Save(image).Error
+}
+
+// DeleteImage 删除图片（重排剩余图片，必要时由第一张接替封面）
+func (r *EstateRepo) DeleteImage(ctx context.Context, estateID, imageID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteImage(tx, &models.EstateImage{}, "estate_id", estateID, imageID, "is_cover", true, false)
+	})
+}
+
+// ReorderImages 按给定顺序重排图片
+func (r *EstateRepo) ReorderImages(ctx context.Context, estateID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorderImages(tx, &models.EstateImage{}, "estate_id", estateID, imageIDs)
+	})
+}
+
+// SetCoverImage 设置封面图，并按 imageIDs 重排（封面在首位）
+func (r *EstateRepo) SetCoverImage(ctx context.Context, estateID, imageID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setCoverImage(tx, &models.EstateImage{}, "estate_id", estateID, imageID, "is_cover", true, false); err != nil {
+			return err
+		}
+		return reorderImages(tx, &models.EstateImage{}, "estate_id", estateID, imageIDs)
+	})
+}
+
 // FindFacilitiesByEstateID 查询屋苑设施
 func (r *EstateRepo) FindFacilitiesByEstateID(ctx context.Context, estateID uint) ([]models.Facility, error) {
 	var facilities []models.Facility
@@ -268,6 +347,13 @@ func (r *EstateRepo) IncrementViewCount(ctx context.Context, id uint) error {
 		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
 }
 
+// FindAllIDs 查询所有屋苑ID（定时任务调用）
+func (r *EstateRepo) FindAllIDs(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.Estate{}).Order("id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
 // UpdateCounts 更新屋苑的统计数据（定时任务调用）
 func (r *EstateRepo) UpdateCounts(ctx context.Context, estateID uint) error {
 	var estate models.Estate
@@ -277,52 +363,116 @@ func (r *EstateRepo) UpdateCounts(ctx context.Context, estateID uint) error {
 
 	// 统计放盘数量
 	var forSaleCount, forRentCount int64
-	r.db.WithContext(ctx).
+	if err := r.db.WithContext(ctx).
 		Model(&models.Property{}).
 		Where("building_name = ? AND status = ? AND listing_type = ?", estate.Name, "available", "sale").
-		Count(&forSaleCount)
+		Count(&forSaleCount).Error; err != nil {
+		return err
+	}
 
-	r.db.WithContext(ctx).
+	if err := r.db.WithContext(ctx).
 		Model(&models.Property{}).
 		Where("building_name = ? AND status = ? AND listing_type = ?", estate.Name, "available", "rent").
-		Count(&forRentCount)
+		Count(&forRentCount).Error; err != nil {
+		return err
+	}
 
-	// 更新统计数据
+	// 近3个月成交数量（以 sold/rented 状态模拟成交记录）
 	now := time.Now()
+	var recentTransactions int64
+	if err := r.db.WithContext(ctx).
+		Model(&models.Property{}).
+		Where("building_name = ? AND status IN ? AND updated_at >= ?", estate.Name, []string{"sold", "rented"}, now.AddDate(0, -3, 0)).
+		Count(&recentTransactions).Error; err != nil {
+		return err
+	}
+
+	// 近12个月平均成交呎价（港币/平方尺）
+	var avgPrice struct {
+		AvgPrice float64
+		Count    int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.Property{}).
+		Select("COALESCE(AVG(price / area), 0) as avg_price, COUNT(*) as count").
+		Where("building_name = ? AND listing_type = ? AND status = ? AND area > 0 AND updated_at >= ?", estate.Name, "sale", "sold", now.AddDate(-1, 0, 0)).
+		Scan(&avgPrice).Error; err != nil {
+		return err
+	}
+
+	// 更新统计数据
+	updates := map[string]interface{}{
+		"for_sale_count":            forSaleCount,
+		"for_rent_count":            forRentCount,
+		"recent_transactions_count": recentTransactions,
+		"updated_at":                now,
+	}
+	// 没有成交时保留原平均成交价
+	if avgPrice.Count > 0 {
+		updates["avg_transaction_price"] = avgPrice.AvgPrice
+		updates["avg_transaction_price_updated_at"] = now
+	}
+
 	return r.db.WithContext(ctx).
 		Model(&models.Estate{}).
 		Where("id = ?", estateID).
-		Updates(map[string]interface{}{
-			"for_sale_count": forSaleCount,
-			"for_rent_count": forRentCount,
-			"updated_at":     now,
-		}).Error
+		Updates(updates).Error
 }
 
 // UpdateFacilities 更新屋苑设施
 func (r *EstateRepo) UpdateFacilities(ctx context.Context, estateID uint, facilityIDs []uint) error {
 	// 使用事务
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 删除旧的设施关联
-		if err := tx.Where("estate_id = ?", estateID).Delete(&models.EstateFacility{}).Error; err != nil {
-			return err
-		}
+		return replaceEstateFacilities(tx, estateID, facilityIDs)
+	})
+}
 
-		// 添加新的设施关联
-		if len(facilityIDs) > 0 {
-			facilities := make([]models.EstateFacility, len(facilityIDs))
-			for i, facilityID := range facilityIDs {
-				facilities[i] = models.EstateFacility{
-					EstateID:   estateID,
-					FacilityID: facilityID,
-					CreatedAt:  time.Now(),
-				}
+// CountFacilities 统计设施字典中存在的设施数
+func (r *EstateRepo) CountFacilities(ctx context.Context, facilityIDs []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Facility{}).Where("id IN ?", uniqueIDs(facilityIDs)).Count(&count).Error
+	return count, err
+}
+
+// ImportEstates 批量保存导入的屋苑（ID 为 0 的为新屋苑）；facilityIDs 与 estates 一一对应，为 nil 时保持原设施
+func (r *EstateRepo) ImportEstates(ctx context.Context, estates []*models.Estate, facilityIDs [][]uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, estate := range estates {
+			if err := tx.Omit(clause.Associations).Save(estate).Error; err != nil {
+				return err
+			}
+			if facilityIDs[i] == nil {
+				continue
 			}
-			if err := tx.Create(&facilities).Error; err != nil {
+			if err := replaceEstateFacilities(tx, estate.ID, facilityIDs[i]); err != nil {
 				return err
 			}
 		}
-
 		return nil
 	})
 }
+
+// replaceEstateFacilities 在事务中覆盖屋苑设施关联
+func replaceEstateFacilities(tx *gorm.DB, estateID uint, facilityIDs []uint) error {
+	// 删除旧的设施关联
+	if err := tx.Where("estate_id = ?", estateID).Delete(&models.EstateFacility{}).Error; err != nil {
+		return err
+	}
+
+	// 添加新的设施关联
+	if len(facilityIDs) > 0 {
+		facilities := make([]models.EstateFacility, len(facilityIDs))
+		for i, facilityID := range facilityIDs {
+			facilities[i] = models.EstateFacility{
+				EstateID:   estateID,
+				FacilityID: facilityID,
+				CreatedAt:  time.Now(),
+			}
+		}
+		if err := tx.Create(&facilities).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,21 +2,29 @@ package databases
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
 	"gorm.io/gorm"
 )
 
 // FurnitureRepo 家具仓储
 type FurnitureRepo struct {
-	db *gorm.DB
+	db          *gorm.DB
+	furnitureNo *IDGenerator // 家具编号生成器
 }
 
-// NewFurnitureRepo 创建家具仓储
-func NewFurnitureRepo(db *gorm.DB) *FurnitureRepo {
-	return &FurnitureRepo{db: db}
+// NewFurnitureRepo 创建家具仓储（编号格式无效时返回错误）
+func NewFurnitureRepo(db *gorm.DB) (*FurnitureRepo, error) {
+	format, err := tools.LoadIDFormat("FURNITURE_NO", "FUR", "{prefix}{yyyy}{mm}{dd}{seq:6}")
+	if err != nil {
+		return nil, err
+	}
+	return &FurnitureRepo{
+		db:          db,
+		furnitureNo: NewIDGenerator(db, "furniture_no", "furniture", "furniture_no", format),
+	}, nil
 }
 
 // FindAll 查询家具列表
@@ -186,13 +194,84 @@ func (r *FurnitureRepo) IncrementViewCount(ctx context.Context, id uint) error {
 		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
 }
 
-// GenerateFurnitureNo 生成家具编号
-func (r *FurnitureRepo) GenerateFurnitureNo(ctx context.Context) (string, error) {
+// FindByIDs 根据ID批量查询家具（不加载关联）
+func (r *FurnitureRepo) FindByIDs(ctx context.Context, ids []uint) ([]models.Furniture, error) {
+	var furniture []models.Furniture
+	if len(ids) == 0 {
+		return furniture, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&furniture).Error
+	return furniture, err
+}
+
+// CountDuplicates 统计同一发布者的重复刊登（同标题）
+func (r *FurnitureRepo) CountDuplicates(ctx context.Context, furniture *models.Furniture) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Furniture{}).Count(&count).Error; err != nil {
-		return "", err
+	err := r.db.WithContext(ctx).Model(&models.Furniture{}).
+		Where("publisher_id = ? AND id <> ?", furniture.PublisherID, furniture.ID).
+		Where("status IN ?", []string{models.ListingStatusPublished, models.ListingStatusPendingReview}).
+		Where("LOWER(title) = LOWER(?)", furniture.Title).
+		Count(&count).Error
+	return count, err
+}
+
+// GetDistrictCategoryPrice 查询地区同分类在售家具的平均价格及样本数
+func (r *FurnitureRepo) GetDistrictCategoryPrice(ctx context.Context, districtID uint, categoryID uint, excludeID uint) (float64, int64, error) {
+	var result struct {
+		AvgPrice float64
+		Samples  int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Furniture{}).
+		Select("COALESCE(AVG(price), 0) AS avg_price, COUNT(*) AS samples").
+		Where("delivery_district_id = ? AND category_id = ? AND status = ? AND id <> ?",
+			districtID, categoryID, models.ListingStatusPublished, excludeID).
+		Scan(&result).Error
+	return result.AvgPrice, result.Samples, err
+}
+
+// FindExpired 查找已到期但仍在架的家具（定时任务调用）
+func (r *FurnitureRepo) FindExpired(ctx context.Context, now time.Time) ([]models.Furniture, error) {
+	var furniture []models.Furniture
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "available").
+		Where("expires_at <= ?", now).
+		Find(&furniture).Error
+	return furniture, err
+}
+
+// MarkExpired 批量将家具标记为已过期
+func (r *FurnitureRepo) MarkExpired(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
 	}
-	return fmt.Sprintf("FUR%s%06d", time.Now().Format("20060102"), count+1), nil
+	return r.db.WithContext(ctx).
+		Model(&models.Furniture{}).
+		Where("id IN ? AND status = ?", ids, "available").
+		Update("status", "expired").Error
+}
+
+// FindExpiringSoon 查找即将到期且未发送提醒的家具（定时任务调用）
+func (r *FurnitureRepo) FindExpiringSoon(ctx context.Context, now, deadline time.Time) ([]models.Furniture, error) {
+	var furniture []models.Furniture
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "available").
+		Where("expires_at > ? AND expires_at <= ?", now, deadline).
+		Where("reminder_sent_at IS NULL").
+		Find(&furniture).Error
+	return furniture, err
+}
+
+// MarkReminderSent 记录到期提醒发送时间
+func (r *FurnitureRepo) MarkReminderSent(ctx context.Context, id uint, sentAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Furniture{}).
+		Where("id = ?", id).
+		UpdateColumn("reminder_sent_at", sentAt).Error
+}
+
+// GenerateFurnitureNo 生成家具编号（格式可通过 FURNITURE_NO_PREFIX、FURNITURE_NO_FORMAT 配置）
+func (r *FurnitureRepo) GenerateFurnitureNo(ctx context.Context) (string, error) {
+	return r.furnitureNo.Next(ctx)
 }
 
 // CreateImages 批量创建图片
@@ -211,7 +290,7 @@ func (r *FurnitureRepo) DeleteImages(ctx context.Context, furnitureID uint) erro
 }
 
 // UpdateImages 更新家具图片
-func (r *FurnitureRepo) UpdateImages(ctx context.Context, furnitureID uint, imageURLs []string) error {
+func (r *FurnitureRepo) UpdateImages(ctx context.Context, furnitureID uint, images []models.FurnitureImage) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 删除旧图片
 		if err := tx.Where("furniture_id = ?", furnitureID).Delete(&models.FurnitureImage{}).Error; err != nil {
@@ -219,17 +298,7 @@ func (r *FurnitureRepo) UpdateImages(ctx context.Context, furnitureID uint, imag
 		}
 
 		// 添加新图片
-		if len(imageURLs) > 0 {
-			images := make([]models.FurnitureImage, len(imageURLs))
-			for i, url := range imageURLs {
-				images[i] = models.FurnitureImage{
-					FurnitureID: furnitureID,
-					ImageURL:    url,
-					IsCover:     i == 0, // 第一张为封面
-					SortOrder:   i,
-					CreatedAt:   time.Now(),
-				}
-			}
+		if len(images) > 0 {
 			if err := tx.Create(&images).Error; err != nil {
 				return err
 			}
@@ -239,6 +308,52 @@ func (r *FurnitureRepo) UpdateImages(ctx context.Context, furnitureID uint, imag
 	})
 }
 
+// FindImage 查询单张图片
+func (r *FurnitureRepo) FindImage(ctx context.Context, furnitureID, imageID uint) (*models.FurnitureImage, error) {
+	var image models.FurnitureImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND furniture_id = ?", imageID, furnitureID).
+		First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage 创建单张图片
+func (r *FurnitureRepo) CreateImage(ctx context.Context, image *models.FurnitureImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+// UpdateImage 更新图片信息
+func (r *FurnitureRepo) UpdateImage(ctx context.Context, image *models.FurnitureImage) error {
+	return r.db.WithContext(ctx).Save(image).Error
+}
+
+// DeleteImage 删除图片（重排剩余图片，必要时由第一张接替封面）
+func (r *FurnitureRepo) DeleteImage(ctx context.Context, furnitureID, imageID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteImage(tx, &models.FurnitureImage{}, "furniture_id", furnitureID, imageID, "is_cover", true, false)
+	})
+}
+
+// ReorderImages 按给定顺序重排图片
+func (r *FurnitureRepo) ReorderImages(ctx context.Context, furnitureID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorderImages(tx, &models.FurnitureImage{}, "furniture_id", furnitureID, imageIDs)
+	})
+}
+
+// SetCoverImage 设置封面图，并按 imageIDs 重排（封面在首位）
+func (r *FurnitureRepo) SetCoverImage(ctx context.Context, furnitureID, imageID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setCoverImage(tx, &models.FurnitureImage{}, "furniture_id", furnitureID, imageID, "is_cover", true, false); err != nil {
+			return err
+		}
+		return reorderImages(tx, &models.FurnitureImage{}, "furniture_id", furnitureID, imageIDs)
+	})
+}
+
 // ===== 分类相关 =====
 
 // FindAllCategories 查询所有分类
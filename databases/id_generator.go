@@ -0,0 +1,104 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"gorm.io/gorm"
+)
+
+// IDGenerator 基于 id_sequences 表的业务编号生成器
+// 序号通过单条 UPDATE ... RETURNING / INSERT ... ON CONFLICT 原子递增，并发请求不会拿到相同序号，
+// 软删除的记录也不影响分配。序列首次使用时以业务表中已有编号的最大序号为起点，兼容历史编号。
+type IDGenerator struct {
+	db     *gorm.DB
+	name   string // 序列名
+	table  string // 业务表
+	column string // 编号列
+	format *tools.IDFormat
+}
+
+// NewIDGenerator 创建编号生成器
+func NewIDGenerator(db *gorm.DB, name, table, column string, format *tools.IDFormat) *IDGenerator {
+	return &IDGenerator{db: db, name: name, table: table, column: column, format: format}
+}
+
+// Next 分配下一个编号
+func (g *IDGenerator) Next(ctx context.Context) (string, error) {
+	now := time.Now()
+	key := g.name
+	if period := g.format.Period(now); period != "" {
+		key += ":" + period
+	}
+
+	seq, ok, err := g.increment(ctx, key, now)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		// 序列不存在：以已有编号为起点创建（并发创建时冲突方转为递增）
+		seed, err := g.seed(ctx, now)
+		if err != nil {
+			return "", err
+		}
+		if seq, err = g.create(ctx, key, seed, now); err != nil {
+			return "", err
+		}
+	}
+
+	return g.format.Render(now, seq), nil
+}
+
+// increment 递增已有序列，序列不存在时 ok=false
+func (g *IDGenerator) increment(ctx context.Context, key string, now time.Time) (int64, bool, error) {
+	var values []int64
+	err := g.db.WithContext(ctx).
+		Raw("UPDATE id_sequences SET value = value + 1, updated_at = ? WHERE name = ? RETURNING value", now, key).
+		Scan(&values).Error
+	if err != nil || len(values) == 0 {
+		return 0, false, err
+	}
+	return values[0], true, nil
+}
+
+// create 创建序列并分配第一个序号
+func (g *IDGenerator) create(ctx context.Context, key string, seed int64, now time.Time) (int64, error) {
+	var value int64
+	err := g.db.WithContext(ctx).
+		Raw(`INSERT INTO id_sequences (name, value, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT (name) DO UPDATE SET value = id_sequences.value + 1, updated_at = EXCLUDED.updated_at
+			RETURNING value`, key, seed+1, now).
+		Scan(&value).Error
+	return value, err
+}
+
+// seed 查询当前日期分段内已有编号的最大序号（含软删除记录）
+func (g *IDGenerator) seed(ctx context.Context, now time.Time) (int64, error) {
+	start, width, length := g.format.SeqPosition(now)
+	head := g.format.Render(now, 0)[:start]
+
+	var max sql.NullString
+	err := g.db.WithContext(ctx).
+		Table(g.table).
+		Select("MAX(SUBSTRING("+g.column+" FROM ? FOR ?))", start+1, width).
+		Where(g.column+" LIKE ? AND LENGTH("+g.column+") = ?", escapeLike(head)+"%", length).
+		Scan(&max).Error
+	if err != nil || !max.Valid {
+		return 0, err
+	}
+
+	seq, err := strconv.ParseInt(max.String, 10, 64)
+	if err != nil {
+		return 0, nil // 已有编号与当前格式不符，从头开始
+	}
+	return seq, nil
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,115 @@
+package databases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// idGeneratorTestItem 并发测试用业务表（编号列唯一）
+type idGeneratorTestItem struct {
+	ID   uint   `gorm:"primaryKey"`
+	Code string `gorm:"size:50;not null;uniqueIndex"`
+}
+
+func (idGeneratorTestItem) TableName() string {
+	return "id_generator_test_items"
+}
+
+// openTestDB 连接测试数据库（TEST_DATABASE_DSN 未设置时跳过，通过 make test-db 运行）
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set; run with make test-db")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect test database: %v", err)
+	}
+	return db
+}
+
+func TestIDGeneratorNextConcurrent(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	if err := db.AutoMigrate(&models.IDSequence{}, &idGeneratorTestItem{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	name := fmt.Sprintf("test_seq_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM id_sequences WHERE name LIKE ?", name+"%")
+		db.Migrator().DropTable(&idGeneratorTestItem{})
+	})
+
+	format, err := tools.ParseIDFormat("T", "{prefix}{yyyy}{mm}{dd}{seq:6}")
+	if err != nil {
+		t.Fatalf("ParseIDFormat: %v", err)
+	}
+	gen := NewIDGenerator(db, name, "id_generator_test_items", "code", format)
+
+	// 已有编号作为序列起点
+	existing := format.Render(time.Now(), 5)
+	if err := db.Create(&idGeneratorTestItem{Code: existing}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	const workers, perWorker = 20, 25
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(workers)
+
+	var wg sync.WaitGroup
+	codes := make(chan string, workers*perWorker)
+	errs := make(chan error, workers*perWorker)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				code, err := gen.Next(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if err := db.WithContext(ctx).Create(&idGeneratorTestItem{Code: code}).Error; err != nil {
+					errs <- fmt.Errorf("insert %s: %w", code, err)
+					return
+				}
+				codes <- code
+			}
+		}()
+	}
+	wg.Wait()
+	close(codes)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Next: %v", err)
+	}
+	seen := make(map[string]bool, workers*perWorker)
+	for code := range codes {
+		if seen[code] {
+			t.Errorf("duplicate id %s", code)
+		}
+		if code <= existing {
+			t.Errorf("id %s not after existing %s", code, existing)
+		}
+		seen[code] = true
+	}
+	if len(seen) != workers*perWorker {
+		t.Errorf("generated %d distinct ids, want %d", len(seen), workers*perWorker)
+	}
+}
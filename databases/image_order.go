@@ -0,0 +1,65 @@
+package databases
+
+import (
+	"gorm.io/gorm"
+)
+
+// 图片排序与封面的通用操作，供各实体仓储在事务内调用
+// model 为图片模型指针（如 &models.PropertyImage{}），ownerColumn 为外键列名
+// 封面通过 coverColumn 表示：房产为 image_type='cover'，其余实体为 is_cover=true
+
+// reorderImages 按 imageIDs 顺序重写 sort_order
+func reorderImages(tx *gorm.DB, model interface{}, ownerColumn string, ownerID uint, imageIDs []uint) error {
+	for i, id := range imageIDs {
+		if err := tx.Model(model).
+			Where("id = ? AND "+ownerColumn+" = ?", id, ownerID).
+			Update("sort_order", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCoverImage 将指定图片设为封面（取消原封面）
+func setCoverImage(tx *gorm.DB, model interface{}, ownerColumn string, ownerID, imageID uint, coverColumn string, on, off interface{}) error {
+	if err := tx.Model(model).
+		Where(ownerColumn+" = ? AND "+coverColumn+" = ?", ownerID, on).
+		Update(coverColumn, off).Error; err != nil {
+		return err
+	}
+	return tx.Model(model).
+		Where("id = ? AND "+ownerColumn+" = ?", imageID, ownerID).
+		Update(coverColumn, on).Error
+}
+
+// deleteImage 删除图片，重排剩余图片；若封面被删除则由第一张图片接替
+func deleteImage(tx *gorm.DB, model interface{}, ownerColumn string, ownerID, imageID uint, coverColumn string, on, off interface{}) error {
+	if err := tx.Where("id = ? AND "+ownerColumn+" = ?", imageID, ownerID).Delete(model).Error; err != nil {
+		return err
+	}
+
+	var remaining []uint
+	if err := tx.Model(model).
+		Where(ownerColumn+" = ?", ownerID).
+		Order("sort_order ASC, id ASC").
+		Pluck("id", &remaining).Error; err != nil {
+		return err
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+	if err := reorderImages(tx, model, ownerColumn, ownerID, remaining); err != nil {
+		return err
+	}
+
+	var covers int64
+	if err := tx.Model(model).
+		Where(ownerColumn+" = ? AND "+coverColumn+" = ?", ownerID, on).
+		Count(&covers).Error; err != nil {
+		return err
+	}
+	if covers == 0 {
+		return setCoverImage(tx, model, ownerColumn, ownerID, remaining[0], coverColumn, on, off)
+	}
+	return nil
+}
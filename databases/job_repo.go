@@ -0,0 +1,143 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"os"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// JobRepo 定时任务仓储（实现 tools.JobLocker 与 tools.JobRecorder）
+type JobRepo struct {
+	db *gorm.DB
+}
+
+// NewJobRepo 创建定时任务仓储
+func NewJobRepo(db *gorm.DB) *JobRepo {
+	return &JobRepo{db: db}
+}
+
+// advisoryLockKey 根据任务名称生成 Postgres advisory lock 键
+func advisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("ajoliving:job:" + name))
+	return int64(h.Sum64())
+}
+
+// TryLock 尝试获取 Postgres 会话级 advisory lock
+// advisory lock 绑定在连接上，因此获取与释放必须使用同一个连接
+func (r *JobRepo) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := advisoryLockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+// RecordStart 记录任务开始执行
+func (r *JobRepo) RecordStart(ctx context.Context, name, trigger string, triggeredBy *uint) (uint, error) {
+	hostname, _ := os.Hostname()
+	run := &models.JobRun{
+		JobName:     name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Status:      "running",
+		StartedAt:   time.Now(),
+		Hostname:    hostname,
+	}
+	if err := r.db.WithContext(ctx).Create(run).Error; err != nil {
+		return 0, err
+	}
+	return run.ID, nil
+}
+
+// RecordFinish 记录任务执行结果
+func (r *JobRepo) RecordFinish(ctx context.Context, runID uint, runErr error) error {
+	var run models.JobRun
+	if err := r.db.WithContext(ctx).First(&run, runID).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      "success",
+		"finished_at": now,
+		"duration_ms": now.Sub(run.StartedAt).Milliseconds(),
+	}
+	if runErr != nil {
+		updates["status"] = "failed"
+		updates["error"] = runErr.Error()
+	}
+
+	return r.db.WithContext(ctx).Model(&models.JobRun{}).
+		Where("id = ?", runID).
+		Updates(updates).Error
+}
+
+// FindRuns 查询任务执行记录（分页）
+func (r *JobRepo) FindRuns(ctx context.Context, name string, req *models.ListJobRunsRequest) ([]models.JobRun, int64, error) {
+	var runs []models.JobRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.JobRun{}).Where("job_name = ?", name)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("started_at DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+// FindLatestRun 查询任务最近一次执行记录
+func (r *JobRepo) FindLatestRun(ctx context.Context, name string) (*models.JobRun, error) {
+	var run models.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job_name = ?", name).
+		Order("started_at DESC").
+		First(&run).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
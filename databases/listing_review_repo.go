@@ -0,0 +1,116 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// ListingReviewRepo 刊登审核记录仓储
+type ListingReviewRepo struct {
+	db *gorm.DB
+}
+
+// NewListingReviewRepo 创建刊登审核记录仓储
+func NewListingReviewRepo(db *gorm.DB) *ListingReviewRepo {
+	return &ListingReviewRepo{db: db}
+}
+
+// Create 创建审核记录
+func (r *ListingReviewRepo) Create(ctx context.Context, review *models.ListingReview) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+// Update 更新审核记录
+func (r *ListingReviewRepo) Update(ctx context.Context, review *models.ListingReview) error {
+	return r.db.WithContext(ctx).Save(review).Error
+}
+
+// FindByID 根据ID查询审核记录
+func (r *ListingReviewRepo) FindByID(ctx context.Context, id uint) (*models.ListingReview, error) {
+	var review models.ListingReview
+	if err := r.db.WithContext(ctx).First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// FindAll 查询审核队列（分页，待审核按提交时间先到先审）
+func (r *ListingReviewRepo) FindAll(ctx context.Context, req *models.ListReviewsRequest) ([]models.ListingReview, int64, error) {
+	var reviews []models.ListingReview
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ListingReview{})
+	if req.EntityType != "" {
+		query = query.Where("entity_type = ?", req.EntityType)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "created_at DESC"
+	if req.Status == models.ReviewStatusPending {
+		order = "created_at ASC"
+	}
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order(order).Offset(offset).Limit(req.PageSize).Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+// FindByEntity 查询刊登的审核历史（最新在前）
+func (r *ListingReviewRepo) FindByEntity(ctx context.Context, entityType string, entityID uint) ([]models.ListingReview, error) {
+	var reviews []models.ListingReview
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at DESC").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// FindPendingByEntity 查询刊登当前待审核记录，不存在时返回 nil
+func (r *ListingReviewRepo) FindPendingByEntity(ctx context.Context, entityType string, entityID uint) (*models.ListingReview, error) {
+	var review models.ListingReview
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, models.ReviewStatusPending).
+		Order("created_at DESC").
+		First(&review).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// HasApproved 刊登是否曾审核通过
+func (r *ListingReviewRepo) HasApproved(ctx context.Context, entityType string, entityID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ListingReview{}).
+		Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, models.ReviewStatusApproved).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Resolve 审核待审核记录（仅 pending 状态可审核，返回受影响行数用于并发判断）
+func (r *ListingReviewRepo) Resolve(ctx context.Context, id uint, status string, reviewerID uint, reason string, reviewedAt time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.ListingReview{}).
+		Where("id = ? AND status = ?", id, models.ReviewStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewer_id": reviewerID,
+			"reason":      reason,
+			"reviewed_at": reviewedAt,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,50 @@
+package databases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MediaRepo 媒体文件仓储
+type MediaRepo struct {
+	db *gorm.DB
+}
+
+// NewMediaRepo 创建媒体文件仓储
+func NewMediaRepo(db *gorm.DB) *MediaRepo {
+	return &MediaRepo{db: db}
+}
+
+// FindByHash 根据内容哈希查询媒体文件，不存在时返回 nil
+func (r *MediaRepo) FindByHash(ctx context.Context, hash string) (*models.MediaObject, error) {
+	var media models.MediaObject
+	err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&media).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+// FindByKeys 根据对象键批量查询媒体文件
+func (r *MediaRepo) FindByKeys(ctx context.Context, keys []string) ([]models.MediaObject, error) {
+	var media []models.MediaObject
+	if len(keys) == 0 {
+		return media, nil
+	}
+	err := r.db.WithContext(ctx).Where("object_key IN ?", keys).Find(&media).Error
+	return media, err
+}
+
+// Create 创建媒体文件记录（并发上传同一文件时忽略冲突）
+func (r *MediaRepo) Create(ctx context.Context, media *models.MediaObject) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "hash"}}, DoNothing: true}).
+		Create(media).Error
+}
@@ -3,9 +3,11 @@ package databases
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // NewDevelopmentRepo 新盘仓储
@@ -44,14 +46,55 @@ func (r *NewDevelopmentRepo) FindAll(ctx context.Context, filter *models.ListNew
 	if filter.IsFeatured != nil {
 		query = query.Where("is_featured = ?", *filter.IsFeatured)
 	}
+	if filter.ExpectedCompletionBefore != nil {
+		before, err := time.Parse("2006-01-02", *filter.ExpectedCompletionBefore)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("new_properties.expected_completion < ?", before.AddDate(0, 0, 1))
+	}
+
+	// 户型筛选及排序：按新盘汇总符合条件的户型，有户型筛选时只保留有匹配户型的新盘
+	layoutSort := filter.SortBy == "price" || filter.SortBy == "price_per_sqft" || filter.SortBy == "saleable_area"
+	if filter.HasLayoutFilter() || layoutSort {
+		layouts := layoutFilter(r.db.WithContext(ctx).Model(&models.NewPropertyLayout{}), filter).
+			Select("new_property_id, MIN(min_price) AS layout_min_price, MIN(NULLIF(price_per_sqft, 0)) AS layout_min_price_per_sqft, MAX(saleable_area) AS layout_max_saleable_area").
+			Group("new_property_id")
+		join := "LEFT JOIN (?) AS matched_layouts ON matched_layouts.new_property_id = new_properties.id"
+		if filter.HasLayoutFilter() {
+			join = "JOIN (?) AS matched_layouts ON matched_layouts.new_property_id = new_properties.id"
+		}
+		query = query.Joins(join, layouts)
+	}
 
 	// 统计总数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// 排序：精选优先，然后按创建时间降序
-	query = query.Order("is_featured DESC, sort_order ASC, created_at DESC")
+	// 排序：默认精选优先，然后按创建时间降序；空值始终排在最后
+	sortOrder := "ASC"
+	if filter.SortOrder == "desc" {
+		sortOrder = "DESC"
+	}
+	switch filter.SortBy {
+	case "price":
+		query = query.Order("matched_layouts.layout_min_price IS NULL, matched_layouts.layout_min_price " + sortOrder)
+	case "price_per_sqft":
+		query = query.Order("matched_layouts.layout_min_price_per_sqft IS NULL, matched_layouts.layout_min_price_per_sqft " + sortOrder)
+	case "saleable_area":
+		query = query.Order("matched_layouts.layout_max_saleable_area IS NULL, matched_layouts.layout_max_saleable_area " + sortOrder)
+	case "expected_completion":
+		query = query.Order("new_properties.expected_completion IS NULL, new_properties.expected_completion " + sortOrder)
+	case "created_at":
+		if filter.SortOrder == "" {
+			sortOrder = "DESC"
+		}
+		query = query.Order("new_properties.created_at " + sortOrder)
+	default:
+		query = query.Order("is_featured DESC, sort_order ASC, created_at DESC")
+	}
+	query = query.Order("new_properties.id DESC")
 
 	// 分页
 	offset := (filter.Page - 1) * filter.PageSize
@@ -63,13 +106,40 @@ func (r *NewDevelopmentRepo) FindAll(ctx context.Context, filter *models.ListNew
 			return db.Order("sort_order ASC").Limit(1) // 列表只加载第一张图
 		})
 
-	if err := query.Find(&newProperties).Error; err != nil {
+	// 有户型筛选时同时加载匹配的户型
+	if filter.HasLayoutFilter() {
+		query = query.Preload("Layouts", func(db *gorm.DB) *gorm.DB {
+			return layoutFilter(db, filter).Order("min_price ASC")
+		})
+	}
+
+	if err := query.Select("new_properties.*").Find(&newProperties).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return newProperties, total, nil
 }
 
+// layoutFilter 应用户型筛选条件（价格区间与户型售价范围有交集即视为匹配）
+func layoutFilter(db *gorm.DB, filter *models.ListNewPropertiesRequest) *gorm.DB {
+	if filter.MinPrice != nil {
+		db = db.Where("GREATEST(max_price, min_price) >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		db = db.Where("min_price <= ?", *filter.MaxPrice)
+	}
+	if filter.Bedrooms != nil {
+		db = db.Where("bedrooms = ?", *filter.Bedrooms)
+	}
+	if filter.MinSaleableArea != nil {
+		db = db.Where("saleable_area >= ?", *filter.MinSaleableArea)
+	}
+	if filter.MaxPricePerSqft != nil {
+		db = db.Where("price_per_sqft > 0 AND price_per_sqft <= ?", *filter.MaxPricePerSqft)
+	}
+	return db
+}
+
 // FindByID 根据ID查找新盘
 func (r *NewDevelopmentRepo) FindByID(ctx context.Context, id uint) (*models.NewProperty, error) {
 	var newProperty models.NewProperty
@@ -92,6 +162,30 @@ func (r *NewDevelopmentRepo) FindByID(ctx context.Context, id uint) (*models.New
 	return &newProperty, nil
 }
 
+// Create 创建新盘
+func (r *NewDevelopmentRepo) Create(ctx context.Context, newProperty *models.NewProperty) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(newProperty).Error
+}
+
+// Update 更新新盘（不写入关联的图片和户型）
+func (r *NewDevelopmentRepo) Update(ctx context.Context, newProperty *models.NewProperty) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(newProperty).Error
+}
+
+// Delete 删除新盘（软删除）
+func (r *NewDevelopmentRepo) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.NewProperty{}, id).Error
+}
+
+// HasPriceList 新盘是否已公布价单
+func (r *NewDevelopmentRepo) HasPriceList(ctx context.Context, newPropertyID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PriceList{}).
+		Where("new_property_id = ?", newPropertyID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // IncrementViewCount 增加浏览次数
 func (r *NewDevelopmentRepo) IncrementViewCount(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Model(&models.NewProperty{}).
@@ -108,3 +202,94 @@ func (r *NewDevelopmentRepo) FindLayouts(ctx context.Context, newPropertyID uint
 		Find(&layouts).Error
 	return layouts, err
 }
+
+// FindLayout 查询单个户型
+func (r *NewDevelopmentRepo) FindLayout(ctx context.Context, newPropertyID, layoutID uint) (*models.NewPropertyLayout, error) {
+	var layout models.NewPropertyLayout
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND new_property_id = ?", layoutID, newPropertyID).
+		First(&layout).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("layout not found")
+		}
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// CreateLayout 创建户型
+func (r *NewDevelopmentRepo) CreateLayout(ctx context.Context, layout *models.NewPropertyLayout) error {
+	return r.db.WithContext(ctx).Create(layout).Error
+}
+
+// UpdateLayout 更新户型
+func (r *NewDevelopmentRepo) UpdateLayout(ctx context.Context, layout *models.NewPropertyLayout) error {
+	return r.db.WithContext(ctx).Save(layout).Error
+}
+
+// DeleteLayout 删除户型
+func (r *NewDevelopmentRepo) DeleteLayout(ctx context.Context, newPropertyID, layoutID uint) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND new_property_id = ?", layoutID, newPropertyID).
+		Delete(&models.NewPropertyLayout{}).Error
+}
+
+// FindImages 查询图片列表（按排序）
+func (r *NewDevelopmentRepo) FindImages(ctx context.Context, newPropertyID uint) ([]models.NewPropertyImage, error) {
+	var images []models.NewPropertyImage
+	err := r.db.WithContext(ctx).
+		Where("new_property_id = ?", newPropertyID).
+		Order("sort_order ASC").
+		Find(&images).Error
+	return images, err
+}
+
+// FindImage 查询单张图片
+func (r *NewDevelopmentRepo) FindImage(ctx context.Context, newPropertyID, imageID uint) (*models.NewPropertyImage, error) {
+	var image models.NewPropertyImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND new_property_id = ?", imageID, newPropertyID).
+		First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("image not found")
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage 创建单张图片
+func (r *NewDevelopmentRepo) CreateImage(ctx context.Context, image *models.NewPropertyImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+// UpdateImage 更新图片信息
+func (r *NewDevelopmentRepo) UpdateImage(ctx context.Context, image *models.NewPropertyImage) error {
+	return r.db.WithContext(ctx).Save(image).Error
+}
+
+// DeleteImage 删除图片（重排剩余图片，必要时由第一张接替封面）
+func (r *NewDevelopmentRepo) DeleteImage(ctx context.Context, newPropertyID, imageID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteImage(tx, &models.NewPropertyImage{}, "new_property_id", newPropertyID, imageID, "is_cover", true, false)
+	})
+}
+
+// ReorderImages 按给定顺序重排图片
+func (r *NewDevelopmentRepo) ReorderImages(ctx context.Context, newPropertyID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorderImages(tx, &models.NewPropertyImage{}, "new_property_id", newPropertyID, imageIDs)
+	})
+}
+
+// SetCoverImage 设置封面图，并按 imageIDs 重排（封面在首位）
+func (r *NewDevelopmentRepo) SetCoverImage(ctx context.Context, newPropertyID, imageID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setCoverImage(tx, &models.NewPropertyImage{}, "new_property_id", newPropertyID, imageID, "is_cover", true, false); err != nil {
+			return err
+		}
+		return reorderImages(tx, &models.NewPropertyImage{}, "new_property_id", newPropertyID, imageIDs)
+	})
+}
@@ -0,0 +1,160 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnitAlreadySold 单位已售出或已有未终止的成交
+var ErrUnitAlreadySold = errors.New("unit already has an active transaction")
+
+// NewPropertyTransactionRepo 新盘成交纪录仓储
+type NewPropertyTransactionRepo struct {
+	db *gorm.DB
+}
+
+// NewNewPropertyTransactionRepo 创建新盘成交纪录仓储
+func NewNewPropertyTransactionRepo(db *gorm.DB) *NewPropertyTransactionRepo {
+	return &NewPropertyTransactionRepo{db: db}
+}
+
+// FindAll 分页查询成交纪录（按成交日期倒序）
+func (r *NewPropertyTransactionRepo) FindAll(ctx context.Context, newPropertyID uint, req *models.ListNewPropertyTransactionsRequest) ([]models.NewPropertyTransaction, int64, error) {
+	var transactions []models.NewPropertyTransaction
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.NewPropertyTransaction{}).Where("new_property_id = ?", newPropertyID)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.LayoutID != nil {
+		query = query.Where("layout_id = ?", *req.LayoutID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	err := query.Order("transaction_date DESC, id DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&transactions).Error
+	return transactions, total, err
+}
+
+// FindByID 查询单宗成交
+func (r *NewPropertyTransactionRepo) FindByID(ctx context.Context, newPropertyID, transactionID uint) (*models.NewPropertyTransaction, error) {
+	var transaction models.NewPropertyTransaction
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND new_property_id = ?", transactionID, newPropertyID).
+		First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// FindActive 查询未终止的成交（按成交日期升序，用于销售进度及户型概况）
+func (r *NewPropertyTransactionRepo) FindActive(ctx context.Context, newPropertyID uint) ([]models.NewPropertyTransaction, error) {
+	var transactions []models.NewPropertyTransaction
+	err := r.db.WithContext(ctx).
+		Where("new_property_id = ? AND status <> ?", newPropertyID, models.NewPropertyTxTerminated).
+		Order("transaction_date ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// FindUnit 查询新盘单位
+func (r *NewPropertyTransactionRepo) FindUnit(ctx context.Context, newPropertyID, unitID uint) (*models.NewPropertyUnit, error) {
+	var unit models.NewPropertyUnit
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND new_property_id = ?", unitID, newPropertyID).
+		First(&unit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("unit not found")
+		}
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// FindUnitReleaseDates 各单位首次出现在价单的公布日期（升序）
+func (r *NewPropertyTransactionRepo) FindUnitReleaseDates(ctx context.Context, newPropertyID uint) ([]time.Time, error) {
+	var dates []time.Time
+	err := r.db.WithContext(ctx).
+		Table("price_list_items AS i").
+		Select("MIN(pl.release_date) AS released_at").
+		Joins("JOIN price_lists pl ON pl.id = i.price_list_id").
+		Where("pl.new_property_id = ?", newPropertyID).
+		Group("i.unit_id").
+		Order("released_at ASC").
+		Pluck("released_at", &dates).Error
+	return dates, err
+}
+
+// Record 登记成交：写入成交纪录，单位标记为已售并重新汇总单位数
+// 先锁定单位行再检查单位是否已售或已有未终止的成交，并发登记同一单位时只有一笔成功
+func (r *NewPropertyTransactionRepo) Record(ctx context.Context, transaction *models.NewPropertyTransaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var unit models.NewPropertyUnit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND new_property_id = ?", transaction.UnitID, transaction.NewPropertyID).
+			First(&unit).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("unit not found")
+			}
+			return err
+		}
+		if unit.Status == models.UnitStatusSold {
+			return ErrUnitAlreadySold
+		}
+		var active int64
+		if err := tx.Model(&models.NewPropertyTransaction{}).
+			Where("unit_id = ? AND status <> ?", unit.ID, models.NewPropertyTxTerminated).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrUnitAlreadySold
+		}
+
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.NewPropertyUnit{}).
+			Where("id = ?", transaction.UnitID).
+			Updates(map[string]interface{}{"status": models.UnitStatusSold, "sold_at": transaction.TransactionDate}).Error; err != nil {
+			return err
+		}
+		_, _, err := rollupUnits(tx, transaction.NewPropertyID)
+		return err
+	})
+}
+
+// Update 更新成交；终止时单位恢复可售并重新汇总单位数
+func (r *NewPropertyTransactionRepo) Update(ctx context.Context, transaction *models.NewPropertyTransaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(transaction).Error; err != nil {
+			return err
+		}
+		if transaction.Status != models.NewPropertyTxTerminated {
+			return nil
+		}
+		if err := tx.Model(&models.NewPropertyUnit{}).
+			Where("id = ?", transaction.UnitID).
+			Updates(map[string]interface{}{"status": models.UnitStatusAvailable, "sold_at": nil}).Error; err != nil {
+			return err
+		}
+		_, _, err := rollupUnits(tx, transaction.NewPropertyID)
+		return err
+	})
+}
@@ -0,0 +1,71 @@
+package databases
+
+import (
+	"context"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// NotificationRepo 站内通知仓储
+type NotificationRepo struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepo 创建站内通知仓储
+func NewNotificationRepo(db *gorm.DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+// Create 创建通知
+func (r *NotificationRepo) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+// FindByUser 查询用户通知（分页）
+func (r *NotificationRepo) FindByUser(ctx context.Context, userID uint, req *models.ListNotificationsRequest) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ?", userID)
+	if req.UnreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread 统计未读通知数量
+func (r *NotificationRepo) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkAsRead 标记通知为已读，返回受影响行数
+func (r *NotificationRepo) MarkAsRead(ctx context.Context, id uint, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"is_read": true,
+			"read_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
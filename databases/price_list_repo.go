@@ -0,0 +1,231 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// PriceListRepo 新盘价单、单位及销售安排仓储
+type PriceListRepo struct {
+	db *gorm.DB
+}
+
+// NewPriceListRepo 创建新盘价单仓储
+func NewPriceListRepo(db *gorm.DB) *PriceListRepo {
+	return &PriceListRepo{db: db}
+}
+
+// FindPriceLists 查询新盘的价单（按公布日期倒序，不加载单位行）
+func (r *PriceListRepo) FindPriceLists(ctx context.Context, newPropertyID uint) ([]models.PriceList, error) {
+	var priceLists []models.PriceList
+	err := r.db.WithContext(ctx).
+		Where("new_property_id = ?", newPropertyID).
+		Order("release_date DESC, id DESC").
+		Find(&priceLists).Error
+	return priceLists, err
+}
+
+// FindPriceList 查询单份价单及其单位行
+func (r *PriceListRepo) FindPriceList(ctx context.Context, newPropertyID, priceListID uint) (*models.PriceList, error) {
+	var priceList models.PriceList
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("block ASC, floor ASC, flat ASC")
+		}).
+		Where("id = ? AND new_property_id = ?", priceListID, newPropertyID).
+		First(&priceList).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("price list not found")
+		}
+		return nil, err
+	}
+	return &priceList, nil
+}
+
+// ExistsPriceListNo 价单编号是否已存在
+func (r *PriceListRepo) ExistsPriceListNo(ctx context.Context, newPropertyID uint, priceListNo string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PriceList{}).
+		Where("new_property_id = ? AND price_list_no = ?", newPropertyID, priceListNo).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindAllUnits 查询新盘的全部单位
+func (r *PriceListRepo) FindAllUnits(ctx context.Context, newPropertyID uint) ([]models.NewPropertyUnit, error) {
+	var units []models.NewPropertyUnit
+	err := r.db.WithContext(ctx).
+		Where("new_property_id = ?", newPropertyID).
+		Find(&units).Error
+	return units, err
+}
+
+// FindUnits 分页查询新盘单位
+func (r *PriceListRepo) FindUnits(ctx context.Context, newPropertyID uint, req *models.ListNewPropertyUnitsRequest) ([]models.NewPropertyUnit, int64, error) {
+	var units []models.NewPropertyUnit
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.NewPropertyUnit{}).Where("new_property_id = ?", newPropertyID)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Block != "" {
+		query = query.Where("block = ?", req.Block)
+	}
+	if req.LayoutID != nil {
+		query = query.Where("layout_id = ?", *req.LayoutID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	err := query.Order("block ASC, floor ASC, flat ASC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&units).Error
+	return units, total, err
+}
+
+// ImportPriceList 保存价单：新建价单，新增或更新单位，写入单位行并重新汇总单位数
+// units 中 ID 为 0 的为新单位；items 与 units 一一对应
+func (r *PriceListRepo) ImportPriceList(ctx context.Context, priceList *models.PriceList, units []*models.NewPropertyUnit, items []models.PriceListItem) (sold, forSale int, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(priceList).Error; err != nil {
+			return err
+		}
+
+		for i, unit := range units {
+			unit.PriceListID = priceList.ID
+			if err := tx.Save(unit).Error; err != nil {
+				return err
+			}
+			items[i].PriceListID = priceList.ID
+			items[i].UnitID = unit.ID
+		}
+		if err := tx.CreateInBatches(items, 200).Error; err != nil {
+			return err
+		}
+
+		sold, forSale, err = rollupUnits(tx, priceList.NewPropertyID)
+		return err
+	})
+	return sold, forSale, err
+}
+
+// UpdateUnitStatus 批量更新单位销售状态并重新汇总，返回状态有变化的单位数
+func (r *PriceListRepo) UpdateUnitStatus(ctx context.Context, newPropertyID uint, unitIDs []uint, status string) (updated, sold, forSale int, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"status": status, "sold_at": nil}
+		if status == models.UnitStatusSold {
+			updates["sold_at"] = time.Now()
+		}
+		result := tx.Model(&models.NewPropertyUnit{}).
+			Where("new_property_id = ? AND id IN ? AND status <> ?", newPropertyID, unitIDs, status).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		updated = int(result.RowsAffected)
+
+		sold, forSale, err = rollupUnits(tx, newPropertyID)
+		return err
+	})
+	return updated, sold, forSale, err
+}
+
+// CountUnitsByIDs 统计属于新盘的单位数（用于校验单位ID）
+func (r *PriceListRepo) CountUnitsByIDs(ctx context.Context, newPropertyID uint, unitIDs []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.NewPropertyUnit{}).
+		Where("new_property_id = ? AND id IN ?", newPropertyID, unitIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// rollupUnits 按单位销售状态汇总新盘已售/在售单位数及户型可售单位数
+// 已订单位计入在售；即将推出的新盘出现已售单位时转为销售中；没有关联单位的户型保留手动维护的可售单位数
+func rollupUnits(tx *gorm.DB, newPropertyID uint) (sold, forSale int, err error) {
+	var counts []struct {
+		Status string
+		Count  int
+	}
+	if err := tx.Model(&models.NewPropertyUnit{}).
+		Select("status, COUNT(*) AS count").
+		Where("new_property_id = ?", newPropertyID).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		return 0, 0, err
+	}
+	for _, c := range counts {
+		if c.Status == models.UnitStatusSold {
+			sold += c.Count
+		} else {
+			forSale += c.Count
+		}
+	}
+
+	updates := map[string]interface{}{"units_sold": sold, "units_for_sale": forSale}
+	if sold > 0 {
+		updates["status"] = gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", "upcoming", "selling")
+	}
+	if err := tx.Model(&models.NewProperty{}).Where("id = ?", newPropertyID).Updates(updates).Error; err != nil {
+		return 0, 0, err
+	}
+
+	err = tx.Exec(`
+		UPDATE new_property_layouts SET available_units = (
+			SELECT COUNT(*) FROM new_property_units u WHERE u.layout_id = new_property_layouts.id AND u.status = ?
+		), updated_at = ?
+		WHERE new_property_id = ? AND EXISTS (
+			SELECT 1 FROM new_property_units u WHERE u.layout_id = new_property_layouts.id
+		)`, models.UnitStatusAvailable, time.Now(), newPropertyID).Error
+	return sold, forSale, err
+}
+
+// FindSalesArrangements 查询新盘的销售安排（按开售时间倒序）
+func (r *PriceListRepo) FindSalesArrangements(ctx context.Context, newPropertyID uint) ([]models.SalesArrangement, error) {
+	var arrangements []models.SalesArrangement
+	err := r.db.WithContext(ctx).
+		Where("new_property_id = ?", newPropertyID).
+		Order("sale_start_at DESC").
+		Find(&arrangements).Error
+	return arrangements, err
+}
+
+// FindSalesArrangement 查询单个销售安排
+func (r *PriceListRepo) FindSalesArrangement(ctx context.Context, newPropertyID, arrangementID uint) (*models.SalesArrangement, error) {
+	var arrangement models.SalesArrangement
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND new_property_id = ?", arrangementID, newPropertyID).
+		First(&arrangement).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sales arrangement not found")
+		}
+		return nil, err
+	}
+	return &arrangement, nil
+}
+
+// CreateSalesArrangement 创建销售安排
+func (r *PriceListRepo) CreateSalesArrangement(ctx context.Context, arrangement *models.SalesArrangement) error {
+	return r.db.WithContext(ctx).Create(arrangement).Error
+}
+
+// UpdateSalesArrangement 更新销售安排
+func (r *PriceListRepo) UpdateSalesArrangement(ctx context.Context, arrangement *models.SalesArrangement) error {
+	return r.db.WithContext(ctx).Save(arrangement).Error
+}
+
+// DeleteSalesArrangement 删除销售安排
+func (r *PriceListRepo) DeleteSalesArrangement(ctx context.Context, newPropertyID, arrangementID uint) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND new_property_id = ?", arrangementID, newPropertyID).
+		Delete(&models.SalesArrangement{}).Error
+}
@@ -0,0 +1,122 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// importProgressColumns 更新进度时写入的列
+var importProgressColumns = []string{
+	"status", "processed_rows", "created_count", "updated_count", "unchanged_count",
+	"failed_count", "row_errors", "error", "finished_at", "updated_at",
+}
+
+// PropertyImportRepo 房产导入任务仓储
+type PropertyImportRepo struct {
+	db *gorm.DB
+}
+
+// NewPropertyImportRepo 创建房产导入任务仓储
+func NewPropertyImportRepo(db *gorm.DB) *PropertyImportRepo {
+	return &PropertyImportRepo{db: db}
+}
+
+// Create 创建导入任务
+func (r *PropertyImportRepo) Create(ctx context.Context, imp *models.PropertyImport) error {
+	return r.db.WithContext(ctx).Create(imp).Error
+}
+
+// FindByID 根据ID查询导入任务（不加载数据行）
+func (r *PropertyImportRepo) FindByID(ctx context.Context, id uint) (*models.PropertyImport, error) {
+	var imp models.PropertyImport
+	if err := r.db.WithContext(ctx).Omit("header", "rows").First(&imp, id).Error; err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// FindWithRows 根据ID查询导入任务及数据行
+func (r *PropertyImportRepo) FindWithRows(ctx context.Context, id uint) (*models.PropertyImport, error) {
+	var imp models.PropertyImport
+	if err := r.db.WithContext(ctx).First(&imp, id).Error; err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// FindByPublisher 查询发布者的导入任务（按创建时间倒序，不加载数据行）
+func (r *PropertyImportRepo) FindByPublisher(ctx context.Context, publisherID uint, page, pageSize int) ([]models.PropertyImport, int64, error) {
+	var imports []models.PropertyImport
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PropertyImport{}).Where("publisher_id = ?", publisherID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Omit("header", "rows").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&imports).Error
+	return imports, total, err
+}
+
+// FindActiveByHash 查询同一发布者尚未完成的相同文件导入（不存在时返回 nil）
+func (r *PropertyImportRepo) FindActiveByHash(ctx context.Context, publisherID uint, fileHash string, dryRun bool) (*models.PropertyImport, error) {
+	var imp models.PropertyImport
+	err := r.db.WithContext(ctx).Omit("header", "rows").
+		Where("publisher_id = ? AND file_hash = ? AND dry_run = ?", publisherID, fileHash, dryRun).
+		Where("status IN ?", []string{models.ImportStatusQueued, models.ImportStatusRunning}).
+		Order("id DESC").
+		First(&imp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &imp, nil
+}
+
+// Claim 将排队中的任务标记为处理中（多个执行者竞争时只有一个成功）
+func (r *PropertyImportRepo) Claim(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.PropertyImport{}).
+		Where("id = ? AND status = ?", id, models.ImportStatusQueued).
+		Updates(map[string]interface{}{
+			"status":     models.ImportStatusRunning,
+			"started_at": now,
+			"updated_at": now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// SaveProgress 保存处理进度与结果（同时刷新 updated_at 作为心跳）
+func (r *PropertyImportRepo) SaveProgress(ctx context.Context, imp *models.PropertyImport) error {
+	imp.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Model(imp).Select(importProgressColumns).Updates(imp).Error
+}
+
+// RequeueStale 将长时间没有心跳的处理中任务重新排队（进程重启等导致中断）
+func (r *PropertyImportRepo) RequeueStale(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.PropertyImport{}).
+		Where("status = ? AND updated_at < ?", models.ImportStatusRunning, before).
+		Update("status", models.ImportStatusQueued)
+	return result.RowsAffected, result.Error
+}
+
+// FindQueuedIDs 查询排队中的任务ID（按创建顺序）
+func (r *PropertyImportRepo) FindQueuedIDs(ctx context.Context, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.PropertyImport{}).
+		Where("status = ?", models.ImportStatusQueued).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
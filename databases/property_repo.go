@@ -3,21 +3,30 @@ package databases
 import (
 	"context"
 	"errors"
-	"fmt"
 	"math"
+	"time"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
 	"gorm.io/gorm"
 )
 
 // PropertyRepo 房产仓储
 type PropertyRepo struct {
-	db *gorm.DB
+	db         *gorm.DB
+	propertyNo *IDGenerator // 房产编号生成器
 }
 
-// NewPropertyRepo 创建房产仓储
-func NewPropertyRepo(db *gorm.DB) *PropertyRepo {
-	return &PropertyRepo{db: db}
+// NewPropertyRepo 创建房产仓储（编号格式无效时返回错误）
+func NewPropertyRepo(db *gorm.DB) (*PropertyRepo, error) {
+	format, err := tools.LoadIDFormat("PROPERTY_NO", "P", "{prefix}{seq:8}")
+	if err != nil {
+		return nil, err
+	}
+	return &PropertyRepo{
+		db:         db,
+		propertyNo: NewIDGenerator(db, "property_no", "properties", "property_no", format),
+	}, nil
 }
 
 // Create 创建房产
@@ -85,11 +94,25 @@ func (r *PropertyRepo) FindAll(ctx context.Context, filter *models.ListPropertie
 	if filter.SecondarySchool != nil {
 		query = query.Where("secondary_school_net = ?", *filter.SecondarySchool)
 	}
+	status := "available" // 默认只显示可用状态
 	if filter.Status != nil {
-		query = query.Where("status = ?", *filter.Status)
-	} else {
-		// 默认只显示可用状态
-		query = query.Where("status = ?", "available")
+		status = *filter.Status
+	}
+	query = query.Where("status = ?", status)
+
+	// 折叠重复刊登：每个重复组只保留主刊登（合并时指定，否则为最早刊登），标记为非重复的组不折叠
+	if filter.CollapseDuplicates {
+		query = query.Where(`(properties.fingerprint = '' OR properties.id = (
+			SELECT COALESCE(
+				(SELECT c.canonical_property_id FROM duplicate_clusters c
+					JOIN properties cp ON cp.id = c.canonical_property_id AND cp.status = ? AND cp.deleted_at IS NULL
+					WHERE c.fingerprint = properties.fingerprint AND c.status = ?),
+				MIN(d.id))
+			FROM properties d
+			WHERE d.fingerprint = properties.fingerprint AND d.status = ? AND d.deleted_at IS NULL
+		) OR EXISTS (
+			SELECT 1 FROM duplicate_clusters c WHERE c.fingerprint = properties.fingerprint AND c.status = ?
+		))`, status, models.DuplicateClusterMerged, status, models.DuplicateClusterDismissed)
 	}
 
 	// 统计总数
@@ -231,16 +254,305 @@ func (r *PropertyRepo) CreateImages(ctx context.Context, images []models.Propert
 	if len(images) == 0 {
 		return nil
 	}
-	return r.db.WithContext(ctx).Create(&images).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+		return touchProperty(tx, images[0].PropertyID)
+	})
 }
 
-// GeneratePropertyNo 生成房产编号
-func (r *PropertyRepo) GeneratePropertyNo(ctx context.Context) (string, error) {
+// FindImagesByPropertyID 查询图片列表（按排序）
+func (r *PropertyRepo) FindImagesByPropertyID(ctx context.Context, propertyID uint) ([]models.PropertyImage, error) {
+	var images []models.PropertyImage
+	err := r.db.WithContext(ctx).
+		Where("property_id = ?", propertyID).
+		Order("sort_order ASC").
+		Find(&images).Error
+	return images, err
+}
+
+// FindImage 查询单张图片
+func (r *PropertyRepo) FindImage(ctx context.Context, propertyID, imageID uint) (*models.PropertyImage, error) {
+	var image models.PropertyImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND property_id = ?", imageID, propertyID).
+		First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("image not found")
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage 创建单张图片
+func (r *PropertyRepo) CreateImage(ctx context.Context, image *models.PropertyImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		return touchProperty(tx, image.PropertyID)
+	})
+}
+
+// UpdateImage 更新图片信息
+func (r *PropertyRepo) UpdateImage(ctx context.Context, image *models.PropertyImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(image).Error; err != nil {
+			return err
+		}
+		return touchProperty(tx, image.PropertyID)
+	})
+}
+
+// DeleteImage 删除图片（重排剩余图片，必要时由第一张接替封面）
+func (r *PropertyRepo) DeleteImage(ctx context.Context, propertyID, imageID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteImage(tx, &models.PropertyImage{}, "property_id", propertyID, imageID, "image_type", "cover", "interior"); err != nil {
+			return err
+		}
+		return touchProperty(tx, propertyID)
+	})
+}
+
+// ReorderImages 按给定顺序重排图片
+func (r *PropertyRepo) ReorderImages(ctx context.Context, propertyID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := reorderImages(tx, &models.PropertyImage{}, "property_id", propertyID, imageIDs); err != nil {
+			return err
+		}
+		return touchProperty(tx, propertyID)
+	})
+}
+
+// SetCoverImage 设置封面图，并按 imageIDs 重排（封面在首位）
+func (r *PropertyRepo) SetCoverImage(ctx context.Context, propertyID, imageID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setCoverImage(tx, &models.PropertyImage{}, "property_id", propertyID, imageID, "image_type", "cover", "interior"); err != nil {
+			return err
+		}
+		if err := reorderImages(tx, &models.PropertyImage{}, "property_id", propertyID, imageIDs); err != nil {
+			return err
+		}
+		return touchProperty(tx, propertyID)
+	})
+}
+
+// touchProperty 图片变更时刷新房产修改时间（Feed 增量更新依赖 updated_at）
+func touchProperty(tx *gorm.DB, propertyID uint) error {
+	return tx.Model(&models.Property{}).
+		Where("id = ?", propertyID).
+		UpdateColumn("updated_at", time.Now()).Error
+}
+
+// FindByIDs 根据ID批量查询房产（不加载关联）
+func (r *PropertyRepo) FindByIDs(ctx context.Context, ids []uint) ([]models.Property, error) {
+	var properties []models.Property
+	if len(ids) == 0 {
+		return properties, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&properties).Error
+	return properties, err
+}
+
+// FindByIDsWithDetails 根据ID批量查询房产（加载地区及图片）
+func (r *PropertyRepo) FindByIDsWithDetails(ctx context.Context, ids []uint) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.db.WithContext(ctx).
+		Preload("District").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Where("id IN ?", ids).
+		Find(&properties).Error
+	return properties, err
+}
+
+// CountDuplicates 统计同一发布者的重复刊登（同标题，或同地址/楼层/面积/类型）
+func (r *PropertyRepo) CountDuplicates(ctx context.Context, property *models.Property) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Property{}).Count(&count).Error; err != nil {
-		return "", err
+	err := r.db.WithContext(ctx).Model(&models.Property{}).
+		Where("publisher_id = ? AND id <> ?", property.PublisherID, property.ID).
+		Where("status IN ?", []string{models.ListingStatusPublished, models.ListingStatusPendingReview}).
+		Where("LOWER(title) = LOWER(?) OR (address = ? AND floor = ? AND area = ? AND listing_type = ?)",
+			property.Title, property.Address, property.Floor, property.Area, property.ListingType).
+		Count(&count).Error
+	return count, err
+}
+
+// GetDistrictUnitPrice 查询地区在架房源的平均呎价及样本数（同刊登类型）
+func (r *PropertyRepo) GetDistrictUnitPrice(ctx context.Context, districtID uint, listingType string, excludeID uint) (float64, int64, error) {
+	var result struct {
+		AvgPrice float64
+		Samples  int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Property{}).
+		Select("COALESCE(AVG(price / area), 0) AS avg_price, COUNT(*) AS samples").
+		Where("district_id = ? AND listing_type = ? AND status = ? AND area > 0 AND id <> ?",
+			districtID, listingType, models.ListingStatusPublished, excludeID).
+		Scan(&result).Error
+	return result.AvgPrice, result.Samples, err
+}
+
+// FindByFingerprint 查询指纹相同的在架房产（按发布时间排序）
+func (r *PropertyRepo) FindByFingerprint(ctx context.Context, fingerprint string) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.db.WithContext(ctx).
+		Preload("District").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Where("fingerprint = ? AND status = ?", fingerprint, "available").
+		Order("id ASC").
+		Find(&properties).Error
+	return properties, err
+}
+
+// CountByFingerprints 统计各指纹的在架房产数（排除已标记为非重复的组）
+func (r *PropertyRepo) CountByFingerprints(ctx context.Context, fingerprints []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(fingerprints) == 0 {
+		return counts, nil
 	}
-	return fmt.Sprintf("P%08d", count+1), nil
+
+	var rows []models.DuplicateGroup
+	err := r.db.WithContext(ctx).Model(&models.Property{}).
+		Select("fingerprint, COUNT(*) AS listing_count").
+		Where("fingerprint IN ? AND status = ?", fingerprints, "available").
+		Where("NOT EXISTS (SELECT 1 FROM duplicate_clusters c WHERE c.fingerprint = properties.fingerprint AND c.status = ?)", models.DuplicateClusterDismissed).
+		Group("fingerprint").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.Fingerprint] = row.ListingCount
+	}
+	return counts, nil
+}
+
+// FindDuplicateGroups 查询重复组（同指纹在架房产不少于2个），按处理状态筛选，刊登数多的在前
+func (r *PropertyRepo) FindDuplicateGroups(ctx context.Context, status string, page, pageSize int) ([]models.DuplicateGroup, int64, error) {
+	query := r.db.WithContext(ctx).Table("properties p").
+		Select("p.fingerprint, COUNT(*) AS listing_count, COUNT(DISTINCT p.publisher_id) AS publisher_count").
+		Joins("LEFT JOIN duplicate_clusters c ON c.fingerprint = p.fingerprint").
+		Where("p.fingerprint <> '' AND p.status = ? AND p.deleted_at IS NULL", "available").
+		Group("p.fingerprint").
+		Having("COUNT(*) > 1")
+	if status == "open" {
+		query = query.Where("c.id IS NULL")
+	} else {
+		query = query.Where("c.status = ?", status)
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS g", query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var groups []models.DuplicateGroup
+	err := query.
+		Order("listing_count DESC, p.fingerprint ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&groups).Error
+	return groups, total, err
+}
+
+// FindWithoutFingerprint 按ID顺序查询尚未计算指纹的房产（定时任务分批调用）
+func (r *PropertyRepo) FindWithoutFingerprint(ctx context.Context, afterID uint, limit int) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.db.WithContext(ctx).
+		Where("fingerprint = '' AND id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&properties).Error
+	return properties, err
+}
+
+// UpdateFingerprint 更新房产指纹（不修改更新时间）
+func (r *PropertyRepo) UpdateFingerprint(ctx context.Context, id uint, fingerprint string) error {
+	return r.db.WithContext(ctx).Model(&models.Property{}).
+		Where("id = ?", id).
+		UpdateColumn("fingerprint", fingerprint).Error
+}
+
+// FindByPublisherEstateNos 按发布者自己的楼盘编号查询房产（批量导入按外部编号更新，不加载关联）
+func (r *PropertyRepo) FindByPublisherEstateNos(ctx context.Context, publisherID uint, estateNos []string) ([]models.Property, error) {
+	var properties []models.Property
+	if len(estateNos) == 0 {
+		return properties, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("publisher_id = ? AND estate_no IN ?", publisherID, estateNos).
+		Order("id ASC").
+		Find(&properties).Error
+	return properties, err
+}
+
+// FindExpired 查找已到期但仍在架的房产（定时任务调用）
+func (r *PropertyRepo) FindExpired(ctx context.Context, now time.Time) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "available").
+		Where("expired_at IS NOT NULL AND expired_at <= ?", now).
+		Find(&properties).Error
+	return properties, err
+}
+
+// MarkExpired 批量将房产标记为已过期
+func (r *PropertyRepo) MarkExpired(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.Property{}).
+		Where("id IN ? AND status = ?", ids, "available").
+		Update("status", "expired").Error
+}
+
+// FindScheduledDue 查找已到计划发布时间的定时发布房产（定时任务调用）
+func (r *PropertyRepo) FindScheduledDue(ctx context.Context, now time.Time) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.db.WithContext(ctx).
+		Where("status = ?", models.ListingStatusScheduled).
+		Where("published_at IS NOT NULL AND published_at <= ?", now).
+		Find(&properties).Error
+	return properties, err
+}
+
+// MarkPublished 批量将定时发布的房产上架
+func (r *PropertyRepo) MarkPublished(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.Property{}).
+		Where("id IN ? AND status = ?", ids, models.ListingStatusScheduled).
+		Update("status", models.ListingStatusPublished).Error
+}
+
+// FindExpiringSoon 查找即将到期且未发送提醒的房产（定时任务调用）
+func (r *PropertyRepo) FindExpiringSoon(ctx context.Context, now, deadline time.Time) ([]models.Property, error) {
+	var properties []models.Property
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "available").
+		Where("expired_at > ? AND expired_at <= ?", now, deadline).
+		Where("reminder_sent_at IS NULL").
+		Find(&properties).Error
+	return properties, err
+}
+
+// MarkReminderSent 记录到期提醒发送时间
+func (r *PropertyRepo) MarkReminderSent(ctx context.Context, id uint, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Property{}).
+		Where("id = ?", id).
+		UpdateColumn("reminder_sent_at", sentAt).Error
+}
+
+// GeneratePropertyNo 生成房产编号（格式可通过 PROPERTY_NO_PREFIX、PROPERTY_NO_FORMAT 配置）
+func (r *PropertyRepo) GeneratePropertyNo(ctx context.Context) (string, error) {
+	return r.propertyNo.Next(ctx)
 }
 
 // CalculateTotalPages 计算总页数
@@ -188,3 +188,13 @@ func (r *SchoolNetRepo) Search(ctx context.Context, keyword string, page, pageSi
 
 	return schoolNets, total, err
 }
+
+// RecountSchools 重新统计所有校网的学校数量（定时任务调用）
+func (r *SchoolNetRepo) RecountSchools(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE school_nets SET school_count = (
+			SELECT COUNT(*) FROM schools
+			WHERE schools.school_net_id = school_nets.id AND schools.deleted_at IS NULL
+		)
+		WHERE deleted_at IS NULL`).Error
+}
@@ -0,0 +1,200 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 预订仓储错误（调用方用 errors.Is 判断）
+var (
+	ErrUnitUnavailable      = errors.New("unit not available for the selected dates") // 所选日期房量不足
+	ErrBookingStatusChanged = errors.New("booking status has changed")                // 预订状态已被同时修改
+)
+
+// ServicedApartmentBookingRepo 服务式住宅预订及房量仓储
+type ServicedApartmentBookingRepo struct {
+	db *gorm.DB
+}
+
+// NewServicedApartmentBookingRepo 创建服务式住宅预订仓储
+func NewServicedApartmentBookingRepo(db *gorm.DB) *ServicedApartmentBookingRepo {
+	return &ServicedApartmentBookingRepo{db: db}
+}
+
+// FindInventory 查询房型在 [from, to) 期间的已占用房量
+func (r *ServicedApartmentBookingRepo) FindInventory(ctx context.Context, unitIDs []uint, from, to time.Time) ([]models.ServicedApartmentInventory, error) {
+	var inventory []models.ServicedApartmentInventory
+	if len(unitIDs) == 0 {
+		return inventory, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("unit_id IN ? AND date >= ? AND date < ?", unitIDs, from, to).
+		Order("date ASC").
+		Find(&inventory).Error
+	return inventory, err
+}
+
+// FindMaxBooked 查询房型自 from 起任一晚的最高已占用房量
+func (r *ServicedApartmentBookingRepo) FindMaxBooked(ctx context.Context, unitID uint, from time.Time) (int, error) {
+	var maxBooked int
+	err := r.db.WithContext(ctx).Model(&models.ServicedApartmentInventory{}).
+		Where("unit_id = ? AND date >= ?", unitID, from).
+		Select("COALESCE(MAX(booked), 0)").
+		Scan(&maxBooked).Error
+	return maxBooked, err
+}
+
+// CountActiveBookings 统计房型未退房的待确认及已确认预订
+func (r *ServicedApartmentBookingRepo) CountActiveBookings(ctx context.Context, unitID uint, today time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ServicedApartmentBooking{}).
+		Where("unit_id = ? AND status IN ? AND check_out_date > ?", unitID,
+			[]string{models.BookingStatusPending, models.BookingStatusConfirmed}, today).
+		Count(&count).Error
+	return count, err
+}
+
+// FindBookingStats 按住宅统计 since 起收到的申请、待确认及已确认未退房的预订
+func (r *ServicedApartmentBookingRepo) FindBookingStats(ctx context.Context, apartmentIDs []uint, since, today time.Time) ([]models.ServicedApartmentBookingStats, error) {
+	var stats []models.ServicedApartmentBookingStats
+	if len(apartmentIDs) == 0 {
+		return stats, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.ServicedApartmentBooking{}).
+		Select(`serviced_apartment_id,
+			COUNT(*) FILTER (WHERE created_at >= ?) AS enquiries,
+			COUNT(*) FILTER (WHERE status = ?) AS pending_bookings,
+			COUNT(*) FILTER (WHERE status = ? AND check_out_date > ?) AS upcoming_bookings`,
+			since, models.BookingStatusPending, models.BookingStatusConfirmed, today).
+		Where("serviced_apartment_id IN ?", apartmentIDs).
+		Group("serviced_apartment_id").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// FindAll 分页查询服务式住宅的预订
+func (r *ServicedApartmentBookingRepo) FindAll(ctx context.Context, apartmentID uint, req *models.ListBookingsRequest) ([]models.ServicedApartmentBooking, int64, error) {
+	return r.paginate(ctx, r.db.WithContext(ctx).Where("serviced_apartment_id = ?", apartmentID), req)
+}
+
+// FindByUser 分页查询用户的预订
+func (r *ServicedApartmentBookingRepo) FindByUser(ctx context.Context, userID uint, req *models.ListBookingsRequest) ([]models.ServicedApartmentBooking, int64, error) {
+	return r.paginate(ctx, r.db.WithContext(ctx).Where("user_id = ?", userID), req)
+}
+
+// paginate 预订列表筛选、分页（按申请时间倒序）
+func (r *ServicedApartmentBookingRepo) paginate(ctx context.Context, query *gorm.DB, req *models.ListBookingsRequest) ([]models.ServicedApartmentBooking, int64, error) {
+	var bookings []models.ServicedApartmentBooking
+	var total int64
+
+	query = query.Model(&models.ServicedApartmentBooking{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.UnitID != nil {
+		query = query.Where("unit_id = ?", *req.UnitID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	err := query.Preload("Unit").
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&bookings).Error
+	return bookings, total, err
+}
+
+// FindByID 查询单个预订
+func (r *ServicedApartmentBookingRepo) FindByID(ctx context.Context, apartmentID, bookingID uint) (*models.ServicedApartmentBooking, error) {
+	var booking models.ServicedApartmentBooking
+	err := r.db.WithContext(ctx).
+		Preload("Unit").
+		Where("id = ? AND serviced_apartment_id = ?", bookingID, apartmentID).
+		First(&booking).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("booking not found")
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// FindExpiredHolds 查询保留已到期但仍待确认的预订
+func (r *ServicedApartmentBookingRepo) FindExpiredHolds(ctx context.Context, now time.Time) ([]models.ServicedApartmentBooking, error) {
+	var bookings []models.ServicedApartmentBooking
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND hold_expires_at <= ?", models.BookingStatusPending, now).
+		Order("hold_expires_at ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// CreateWithHold 创建预订并占用入住期间每晚一个单位；任一晚已满则整笔回滚
+// 占用通过条件更新（booked < capacity）完成，并发申请同一晚时由行锁保证不会超卖
+func (r *ServicedApartmentBookingRepo) CreateWithHold(ctx context.Context, booking *models.ServicedApartmentBooking, capacity int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if capacity <= 0 {
+			return ErrUnitUnavailable
+		}
+
+		rows := make([]models.ServicedApartmentInventory, 0, booking.Nights)
+		for d := booking.CheckInDate; d.Before(booking.CheckOutDate); d = d.AddDate(0, 0, 1) {
+			rows = append(rows, models.ServicedApartmentInventory{UnitID: booking.UnitID, Date: d})
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "unit_id"}, {Name: "date"}},
+			DoNothing: true,
+		}).Create(&rows).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.ServicedApartmentInventory{}).
+			Where("unit_id = ? AND date >= ? AND date < ? AND booked < ?", booking.UnitID, booking.CheckInDate, booking.CheckOutDate, capacity).
+			UpdateColumn("booked", gorm.Expr("booked + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(rows)) {
+			return ErrUnitUnavailable
+		}
+
+		return tx.Omit(clause.Associations).Create(booking).Error
+	})
+}
+
+// Transition 按当前状态条件更新预订状态（防止与其他操作或保留到期任务并发处理）；release 为 true 时释放占用的房量
+func (r *ServicedApartmentBookingRepo) Transition(ctx context.Context, booking *models.ServicedApartmentBooking, fromStatus string, release bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ServicedApartmentBooking{}).
+			Where("id = ? AND status = ?", booking.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":          booking.Status,
+				"hold_expires_at": booking.HoldExpiresAt,
+				"operator_note":   booking.OperatorNote,
+				"responded_at":    booking.RespondedAt,
+				"cancelled_at":    booking.CancelledAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBookingStatusChanged
+		}
+		if !release {
+			return nil
+		}
+
+		return tx.Model(&models.ServicedApartmentInventory{}).
+			Where("unit_id = ? AND date >= ? AND date < ? AND booked > 0", booking.UnitID, booking.CheckInDate, booking.CheckOutDate).
+			UpdateColumn("booked", gorm.Expr("booked - 1")).Error
+	})
+}
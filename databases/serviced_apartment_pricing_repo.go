@@ -0,0 +1,69 @@
+package databases
+
+import (
+	"context"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"gorm.io/gorm"
+)
+
+// ServicedApartmentPricingRepo 服务式住宅季节性调价及长住折扣仓储
+type ServicedApartmentPricingRepo struct {
+	db *gorm.DB
+}
+
+// NewServicedApartmentPricingRepo 创建服务式住宅调价仓储
+func NewServicedApartmentPricingRepo(db *gorm.DB) *ServicedApartmentPricingRepo {
+	return &ServicedApartmentPricingRepo{db: db}
+}
+
+// FindSeasonalRates 查询住宅的季节性调价；from、to 非零时只返回与 [from, to) 有重叠的设置
+func (r *ServicedApartmentPricingRepo) FindSeasonalRates(ctx context.Context, apartmentIDs []uint, from, to time.Time) ([]models.ServicedApartmentSeasonalRate, error) {
+	var rates []models.ServicedApartmentSeasonalRate
+	if len(apartmentIDs) == 0 {
+		return rates, nil
+	}
+	query := r.db.WithContext(ctx).Where("serviced_apartment_id IN ?", apartmentIDs)
+	if !from.IsZero() && !to.IsZero() {
+		query = query.Where("start_date < ? AND end_date >= ?", to, from)
+	}
+	err := query.Order("start_date ASC, id ASC").Find(&rates).Error
+	return rates, err
+}
+
+// FindStayDiscounts 查询住宅的长住折扣（按最少晚数升序）
+func (r *ServicedApartmentPricingRepo) FindStayDiscounts(ctx context.Context, apartmentIDs []uint) ([]models.ServicedApartmentStayDiscount, error) {
+	var discounts []models.ServicedApartmentStayDiscount
+	if len(apartmentIDs) == 0 {
+		return discounts, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("serviced_apartment_id IN ?", apartmentIDs).
+		Order("min_nights ASC").
+		Find(&discounts).Error
+	return discounts, err
+}
+
+// ReplaceRules 整体替换住宅的季节性调价及长住折扣
+func (r *ServicedApartmentPricingRepo) ReplaceRules(ctx context.Context, apartmentID uint, rates []models.ServicedApartmentSeasonalRate, discounts []models.ServicedApartmentStayDiscount) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("serviced_apartment_id = ?", apartmentID).Delete(&models.ServicedApartmentSeasonalRate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("serviced_apartment_id = ?", apartmentID).Delete(&models.ServicedApartmentStayDiscount{}).Error; err != nil {
+			return err
+		}
+		if len(rates) > 0 {
+			if err := tx.Create(&rates).Error; err != nil {
+				return err
+			}
+		}
+		if len(discounts) > 0 {
+			if err := tx.Create(&discounts).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -28,24 +28,7 @@ func (r *ServicedApartmentRepo) FindAll(ctx context.Context, filter *models.List
 	var apartments []models.ServicedApartment
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&models.ServicedApartment{})
-
-	// 应用筛选条件
-	if filter.DistrictID != nil {
-		query = query.Where("district_id = ?", *filter.DistrictID)
-	}
-	if filter.Status != nil {
-		query = query.Where("status = ?", *filter.Status)
-	} else {
-		// 默认只显示营业中
-		query = query.Where("status = ?", "active")
-	}
-	if filter.MinRating != nil {
-		query = query.Where("rating >= ?", *filter.MinRating)
-	}
-	if filter.IsFeatured != nil {
-		query = query.Where("is_featured = ?", *filter.IsFeatured)
-	}
+	query := applyServicedApartmentFilter(r.db.WithContext(ctx).Model(&models.ServicedApartment{}), filter)
 
 	// 统计总数
 	if err := query.Count(&total).Error; err != nil {
@@ -66,6 +49,9 @@ func (r *ServicedApartmentRepo) FindAll(ctx context.Context, filter *models.List
 		}).
 		Preload("Units", func(db *gorm.DB) *gorm.DB {
 			return db.Order("monthly_price ASC").Limit(1) // 只加载最低价房型
+		}).
+		Preload("Facilities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("facilities.sort_order ASC")
 		})
 
 	if err := query.Find(&apartments).Error; err != nil {
@@ -75,6 +61,104 @@ func (r *ServicedApartmentRepo) FindAll(ctx context.Context, filter *models.List
 	return apartments, total, nil
 }
 
+// FindAllForStay 查找符合筛选条件的服务式住宅及全部房型（不分页，用于按入住日期报价后再排序分页）
+func (r *ServicedApartmentRepo) FindAllForStay(ctx context.Context, filter *models.ListServicedApartmentsRequest, limit int) ([]models.ServicedApartment, error) {
+	var apartments []models.ServicedApartment
+	err := applyServicedApartmentFilter(r.db.WithContext(ctx).Model(&models.ServicedApartment{}), filter).
+		Order("is_featured DESC, rating DESC, created_at DESC").
+		Limit(limit).
+		Preload("District").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Where("serviced_apartment_id IS NOT NULL").Order("sort_order ASC")
+		}).
+		Preload("Units", func(db *gorm.DB) *gorm.DB {
+			return db.Order("monthly_price ASC")
+		}).
+		Preload("Facilities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("facilities.sort_order ASC")
+		}).
+		Find(&apartments).Error
+	return apartments, err
+}
+
+// applyServicedApartmentFilter 应用列表筛选条件
+func applyServicedApartmentFilter(query *gorm.DB, filter *models.ListServicedApartmentsRequest) *gorm.DB {
+	if filter.DistrictID != nil {
+		query = query.Where("district_id = ?", *filter.DistrictID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	} else {
+		// 默认只显示营业中
+		query = query.Where("status = ?", "active")
+	}
+	if filter.MinRating != nil {
+		query = query.Where("rating >= ?", *filter.MinRating)
+	}
+	if filter.IsFeatured != nil {
+		query = query.Where("is_featured = ?", *filter.IsFeatured)
+	}
+
+	// 须具备全部指定设施
+	if len(filter.FacilityIDs) > 0 {
+		facilityIDs := uniqueIDs(filter.FacilityIDs)
+		query = query.Where(`id IN (
+			SELECT serviced_apartment_id FROM serviced_apartment_facilities
+			WHERE facility_id IN ? GROUP BY serviced_apartment_id HAVING COUNT(DISTINCT facility_id) = ?
+		)`, facilityIDs, len(facilityIDs))
+	}
+	if filter.MinCleaning != "" {
+		for i, frequency := range models.CleaningFrequencies {
+			if frequency == filter.MinCleaning {
+				query = query.Where("cleaning_frequency IN ?", models.CleaningFrequencies[i:])
+				break
+			}
+		}
+	}
+	if filter.UtilitiesIncluded != nil {
+		query = query.Where("utilities_included = ?", *filter.UtilitiesIncluded)
+	}
+	if filter.InternetIncluded != nil {
+		query = query.Where("internet_included = ?", *filter.InternetIncluded)
+	}
+	if filter.PetsAllowed != nil {
+		query = query.Where("pets_allowed = ?", *filter.PetsAllowed)
+	}
+	if filter.SmokingAllowed != nil {
+		query = query.Where("smoking_allowed = ?", *filter.SmokingAllowed)
+	}
+	return query
+}
+
+// CountFacilities 统计设施字典中存在的设施数
+func (r *ServicedApartmentRepo) CountFacilities(ctx context.Context, facilityIDs []uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Facility{}).Where("id IN ?", uniqueIDs(facilityIDs)).Count(&count).Error
+	return count, err
+}
+
+// ReplaceFacilities 覆盖更新服务式住宅设施
+func (r *ServicedApartmentRepo) ReplaceFacilities(ctx context.Context, apartmentID uint, facilityIDs []uint) error {
+	facilityIDs = uniqueIDs(facilityIDs)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("serviced_apartment_id = ?", apartmentID).Delete(&models.ServicedApartmentFacility{}).Error; err != nil {
+			return err
+		}
+		if len(facilityIDs) == 0 {
+			return nil
+		}
+
+		facilities := make([]models.ServicedApartmentFacility, len(facilityIDs))
+		for i, facilityID := range facilityIDs {
+			facilities[i] = models.ServicedApartmentFacility{
+				ServicedApartmentID: apartmentID,
+				FacilityID:          facilityID,
+			}
+		}
+		return tx.Create(&facilities).Error
+	})
+}
+
 // FindByID 根据ID查找服务式住宅
 func (r *ServicedApartmentRepo) FindByID(ctx context.Context, id uint) (*models.ServicedApartment, error) {
 	var apartment models.ServicedApartment
@@ -84,7 +168,10 @@ func (r *ServicedApartmentRepo) FindByID(ctx context.Context, id uint) (*models.
 			return db.Order("sort_order ASC")
 		}).
 		Preload("Units", func(db *gorm.DB) *gorm.DB {
-			return db.Order("monthly_price ASC")
+			return db.Order("sort_order ASC, monthly_price ASC")
+		}).
+		Preload("Facilities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("facilities.sort_order ASC")
 		}).
 		First(&apartment, id).Error
 
@@ -114,16 +201,93 @@ func (r *ServicedApartmentRepo) IncrementViewCount(ctx context.Context, id uint)
 		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
 }
 
-// FindUnits 查找服务式住宅的所有房型
+// FindUnits 查找服务式住宅的所有房型（按营运商设置的排序，其次月租）
 func (r *ServicedApartmentRepo) FindUnits(ctx context.Context, apartmentID uint) ([]models.ServicedApartmentUnit, error) {
 	var units []models.ServicedApartmentUnit
 	err := r.db.WithContext(ctx).
 		Where("serviced_apartment_id = ?", apartmentID).
-		Order("monthly_price ASC").
+		Order("sort_order ASC, monthly_price ASC").
 		Find(&units).Error
 	return units, err
 }
 
+// FindUnit 查询服务式住宅的单个房型
+func (r *ServicedApartmentRepo) FindUnit(ctx context.Context, apartmentID, unitID uint) (*models.ServicedApartmentUnit, error) {
+	var unit models.ServicedApartmentUnit
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND serviced_apartment_id = ?", unitID, apartmentID).
+		First(&unit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("unit not found")
+		}
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// CreateUnit 创建房型
+func (r *ServicedApartmentRepo) CreateUnit(ctx context.Context, unit *models.ServicedApartmentUnit) error {
+	return r.db.WithContext(ctx).Create(unit).Error
+}
+
+// UpdateUnit 更新房型
+func (r *ServicedApartmentRepo) UpdateUnit(ctx context.Context, unit *models.ServicedApartmentUnit) error {
+	return r.db.WithContext(ctx).Save(unit).Error
+}
+
+// DeleteUnit 删除房型及其图片、专属季节性调价
+func (r *ServicedApartmentRepo) DeleteUnit(ctx context.Context, apartmentID, unitID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("unit_id = ?", unitID).Delete(&models.ServicedApartmentImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("unit_id = ?", unitID).Delete(&models.ServicedApartmentSeasonalRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND serviced_apartment_id = ?", unitID, apartmentID).Delete(&models.ServicedApartmentUnit{}).Error
+	})
+}
+
+// FindUnitImages 查找房型的所有图片
+func (r *ServicedApartmentRepo) FindUnitImages(ctx context.Context, unitID uint) ([]models.ServicedApartmentImage, error) {
+	var images []models.ServicedApartmentImage
+	err := r.db.WithContext(ctx).
+		Where("unit_id = ?", unitID).
+		Order("sort_order ASC").
+		Find(&images).Error
+	return images, err
+}
+
+// FindUnitImage 查找房型的单张图片
+func (r *ServicedApartmentRepo) FindUnitImage(ctx context.Context, unitID, imageID uint) (*models.ServicedApartmentImage, error) {
+	var image models.ServicedApartmentImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND unit_id = ?", imageID, unitID).
+		First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("image not found")
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// DeleteUnitImage 删除房型图片（重排剩余图片，必要时由第一张接替封面）
+func (r *ServicedApartmentRepo) DeleteUnitImage(ctx context.Context, unitID, imageID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteImage(tx, &models.ServicedApartmentImage{}, "unit_id", unitID, imageID, "is_cover", true, false)
+	})
+}
+
+// ReorderUnitImages 按给定顺序重排房型图片
+func (r *ServicedApartmentRepo) ReorderUnitImages(ctx context.Context, unitID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorderImages(tx, &models.ServicedApartmentImage{}, "unit_id", unitID, imageIDs)
+	})
+}
+
 // FindImages 查找服务式住宅的所有图片
 func (r *ServicedApartmentRepo) FindImages(ctx context.Context, apartmentID uint) ([]models.ServicedApartmentImage, error) {
 	var images []models.ServicedApartmentImage
@@ -134,6 +298,55 @@ func (r *ServicedApartmentRepo) FindImages(ctx context.Context, apartmentID uint
 	return images, err
 }
 
+// FindImage 查询单张图片
+func (r *ServicedApartmentRepo) FindImage(ctx context.Context, apartmentID, imageID uint) (*models.ServicedApartmentImage, error) {
+	var image models.ServicedApartmentImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND serviced_apartment_id = ?", imageID, apartmentID).
+		First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("image not found")
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage 创建单张图片
+func (r *ServicedApartmentRepo) CreateImage(ctx context.Context, image *models.ServicedApartmentImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+// UpdateImage 更新图片信息
+func (r *ServicedApartmentRepo) UpdateImage(ctx context.Context, image *models.ServicedApartmentImage) error {
+	return r.db.WithContext(ctx).Save(image).Error
+}
+
+// DeleteImage 删除图片（重排剩余图片，必要时由第一张接替封面）
+func (r *ServicedApartmentRepo) DeleteImage(ctx context.Context, apartmentID, imageID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteImage(tx, &models.ServicedApartmentImage{}, "serviced_apartment_id", apartmentID, imageID, "is_cover", true, false)
+	})
+}
+
+// ReorderImages 按给定顺序重排图片
+func (r *ServicedApartmentRepo) ReorderImages(ctx context.Context, apartmentID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorderImages(tx, &models.ServicedApartmentImage{}, "serviced_apartment_id", apartmentID, imageIDs)
+	})
+}
+
+// SetCoverImage 设置封面图，并按 imageIDs 重排（封面在首位）
+func (r *ServicedApartmentRepo) SetCoverImage(ctx context.Context, apartmentID, imageID uint, imageIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setCoverImage(tx, &models.ServicedApartmentImage{}, "serviced_apartment_id", apartmentID, imageID, "is_cover", true, false); err != nil {
+			return err
+		}
+		return reorderImages(tx, &models.ServicedApartmentImage{}, "serviced_apartment_id", apartmentID, imageIDs)
+	})
+}
+
 // FindByCompanyID 根据公司ID查找服务式住宅
 func (r *ServicedApartmentRepo) FindByCompanyID(ctx context.Context, companyID uint) ([]models.ServicedApartment, error) {
 	var apartments []models.ServicedApartment
@@ -143,7 +356,21 @@ func (r *ServicedApartmentRepo) FindByCompanyID(ctx context.Context, companyID u
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Where("serviced_apartment_id IS NOT NULL").Order("sort_order ASC").Limit(1)
 		}).
+		Preload("Units").
 		Order("created_at DESC").
 		Find(&apartments).Error
 	return apartments, err
 }
+
+// uniqueIDs 去除重复ID（保留顺序）
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
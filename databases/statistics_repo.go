@@ -144,9 +144,9 @@ func (r *StatisticsRepo) GetPropertyStatistics(ctx context.Context, startDate, e
 		Count        int64
 	}
 	r.db.WithContext(ctx).Model(&models.Property{}).
-		Select("properties.district_id, districts.name_zh as district_name, COUNT(*) as count").
+		Select("properties.district_id, districts.name_zh_hant as district_name, COUNT(*) as count").
 		Joins("LEFT JOIN districts ON properties.district_id = districts.id").
-		Group("properties.district_id, districts.name_zh").
+		Group("properties.district_id, districts.name_zh_hant").
 		Order("count DESC").
 		Limit(10).
 		Scan(&districtDist)
@@ -259,10 +259,10 @@ func (r *StatisticsRepo) GetTransactionStatistics(ctx context.Context, startDate
 		AvgPrice     float64
 	}
 	r.db.WithContext(ctx).Model(&models.Property{}).
-		Select("properties.district_id, districts.name_zh as district_name, COUNT(*) as count, SUM(properties.price) as total_value, AVG(properties.price) as avg_price").
+		Select("properties.district_id, districts.name_zh_hant as district_name, COUNT(*) as count, SUM(properties.price) as total_value, AVG(properties.price) as avg_price").
 		Joins("LEFT JOIN districts ON properties.district_id = districts.id").
 		Where("properties.status IN ?", []string{"sold", "rented"}).
-		Group("properties.district_id, districts.name_zh").
+		Group("properties.district_id, districts.name_zh_hant").
 		Order("count DESC").
 		Limit(10).
 		Scan(&districtTrans)
@@ -365,3 +365,115 @@ func (r *StatisticsRepo) GetUserStatistics(ctx context.Context, startDate, endDa
 
 	return &stats, nil
 }
+
+// GetFirstHandStatistics 获取一手成交统计（按新盘成交纪录册，不含已终止成交）
+func (r *StatisticsRepo) GetFirstHandStatistics(ctx context.Context, startDate, endDate *time.Time, districtID *uint) (*models.FirstHandStatisticsResponse, error) {
+	var stats models.FirstHandStatisticsResponse
+
+	baseQuery := func() *gorm.DB {
+		return r.firstHandQuery(ctx, startDate, endDate, districtID)
+	}
+
+	// 成交总览
+	var totals struct {
+		TotalTransactions int64
+		TotalValue        float64
+		AvgPrice          float64
+		AvgPricePerSqft   float64
+		TotalListValue    float64
+		ActiveProjects    int64
+	}
+	if err := baseQuery().
+		Select("COUNT(*) AS total_transactions, COALESCE(SUM(t.transaction_price), 0) AS total_value, " +
+			"COALESCE(AVG(t.transaction_price), 0) AS avg_price, COALESCE(AVG(t.price_per_sqft), 0) AS avg_price_per_sqft, " +
+			"COALESCE(SUM(t.list_price), 0) AS total_list_value, COUNT(DISTINCT t.new_property_id) AS active_projects").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	stats.TotalTransactions = totals.TotalTransactions
+	stats.TotalValue = totals.TotalValue
+	stats.AvgPrice = totals.AvgPrice
+	stats.AvgPricePerSqft = totals.AvgPricePerSqft
+	stats.ActiveProjects = totals.ActiveProjects
+	if totals.TotalListValue > 0 {
+		stats.PriceRealised = totals.TotalValue / totals.TotalListValue * 100
+	}
+
+	// 地区成交统计
+	var districtTrans []struct {
+		DistrictID   uint
+		DistrictName string
+		Count        int64
+		TotalValue   float64
+		AvgPrice     float64
+	}
+	if err := baseQuery().
+		Select("np.district_id, districts.name_zh_hant AS district_name, COUNT(*) AS count, SUM(t.transaction_price) AS total_value, AVG(t.transaction_price) AS avg_price").
+		Joins("LEFT JOIN districts ON np.district_id = districts.id").
+		Group("np.district_id, districts.name_zh_hant").
+		Order("count DESC").
+		Limit(10).
+		Scan(&districtTrans).Error; err != nil {
+		return nil, err
+	}
+	stats.DistrictTransactions = make([]models.DistrictTransactionStat, 0, len(districtTrans))
+	for _, d := range districtTrans {
+		stats.DistrictTransactions = append(stats.DistrictTransactions, models.DistrictTransactionStat{
+			DistrictID:       d.DistrictID,
+			DistrictName:     d.DistrictName,
+			TransactionCount: d.Count,
+			TotalValue:       d.TotalValue,
+			AvgPrice:         d.AvgPrice,
+		})
+	}
+
+	// 新盘成交排行（去化率按新盘累计已售单位计算）
+	var developments []models.FirstHandDevelopmentStat
+	if err := baseQuery().
+		Select("t.new_property_id, np.name, np.status, np.units_sold, np.total_units, COUNT(*) AS transaction_count, AVG(t.price_per_sqft) AS avg_price_per_sqft").
+		Group("t.new_property_id, np.name, np.status, np.units_sold, np.total_units").
+		Order("transaction_count DESC").
+		Limit(10).
+		Scan(&developments).Error; err != nil {
+		return nil, err
+	}
+	for i := range developments {
+		if developments[i].TotalUnits > 0 {
+			developments[i].SellThroughRate = float64(developments[i].UnitsSold) / float64(developments[i].TotalUnits) * 100
+		}
+	}
+	if developments == nil {
+		developments = []models.FirstHandDevelopmentStat{}
+	}
+	stats.Developments = developments
+
+	return &stats, nil
+}
+
+// FindFirstHandTransactions 查询期内一手成交的日期及成交价（按成交日期升序，用于月度趋势）
+func (r *StatisticsRepo) FindFirstHandTransactions(ctx context.Context, startDate, endDate *time.Time, districtID *uint) ([]models.NewPropertyTransaction, error) {
+	var transactions []models.NewPropertyTransaction
+	err := r.firstHandQuery(ctx, startDate, endDate, districtID).
+		Select("t.transaction_date, t.transaction_price").
+		Order("t.transaction_date ASC").
+		Scan(&transactions).Error
+	return transactions, err
+}
+
+// firstHandQuery 一手成交基础查询（不含已终止成交及已删除新盘，结束日期当天包含在内）
+func (r *StatisticsRepo) firstHandQuery(ctx context.Context, startDate, endDate *time.Time, districtID *uint) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Table("new_property_transactions AS t").
+		Joins("JOIN new_properties np ON np.id = t.new_property_id AND np.deleted_at IS NULL").
+		Where("t.status <> ?", models.NewPropertyTxTerminated)
+	if startDate != nil {
+		query = query.Where("t.transaction_date >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("t.transaction_date < ?", endDate.AddDate(0, 0, 1))
+	}
+	if districtID != nil {
+		query = query.Where("np.district_id = ?", *districtID)
+	}
+	return query
+}
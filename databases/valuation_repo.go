@@ -2,6 +2,7 @@ package databases
 
 import (
 	"context"
+	"time"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
 	"gorm.io/gorm"
@@ -45,6 +46,10 @@ func (r *ValuationRepo) FindAllValuations(ctx context.Context, filter *models.Li
 		query = query.Where("avg_transaction_price <= ?", *filter.MaxAvgPrice)
 	}
 
+	if filter.MinRentalYield != nil {
+		query = query.Where("rental_yield >= ?", *filter.MinRentalYield)
+	}
+
 	// 关键词搜索
 	if filter.Keyword != "" {
 		keyword := "%" + filter.Keyword + "%"
@@ -65,7 +70,7 @@ func (r *ValuationRepo) FindAllValuations(ctx context.Context, filter *models.Li
 		case "price":
 			sortBy = "avg_transaction_price"
 		case "yield":
-			sortBy = "avg_transaction_price" // TODO: 增加租金回报率字段后修改
+			sortBy = "rental_yield"
 		case "transactions":
 			sortBy = "recent_transactions_count"
 		}
@@ -76,6 +81,10 @@ func (r *ValuationRepo) FindAllValuations(ctx context.Context, filter *models.Li
 		sortOrder = "asc"
 	}
 
+	// 未有租金回报率的屋苑排在最后
+	if sortBy == "rental_yield" {
+		query = query.Order("rental_yield > 0 DESC")
+	}
 	query = query.Order(sortBy + " " + sortOrder)
 
 	// 分页
@@ -138,9 +147,11 @@ func (r *ValuationRepo) GetEstateValuation(ctx context.Context, estateID uint) (
 	valuation.AvgSalePrice = avgPrices["sale"]
 	valuation.AvgRentPrice = avgPrices["rent"]
 
-	// 计算租金回报率
-	if valuation.AvgSalePrice > 0 && valuation.AvgRentPrice > 0 {
-		valuation.RentalYield = (valuation.AvgRentPrice * 12 / valuation.AvgSalePrice) * 100
+	// 租金回报率（定时任务按户型配对计算）
+	valuation.RentalYield = estate.RentalYield
+	unitTypeYields, err := r.FindEstateYields(ctx, estate.ID)
+	if err == nil {
+		valuation.UnitTypeYields = unitTypeYields
 	}
 
 	// 获取价格范围
@@ -216,8 +227,8 @@ func (r *ValuationRepo) GetDistrictValuations(ctx context.Context, districtID ui
 	}
 
 	if len(estates) > 0 {
-		var sumPrice, minPrice, maxPrice float64
-		var totalTransactions int
+		var sumPrice, minPrice, maxPrice, sumYield float64
+		var totalTransactions, yieldCount int
 
 		minPrice = estates[0].AvgTransactionPrice
 		maxPrice = estates[0].AvgTransactionPrice
@@ -225,6 +236,10 @@ func (r *ValuationRepo) GetDistrictValuations(ctx context.Context, districtID ui
 		for _, estate := range estates {
 			sumPrice += estate.AvgTransactionPrice
 			totalTransactions += estate.RecentTransactionsCount
+			if estate.RentalYield > 0 {
+				sumYield += estate.RentalYield
+				yieldCount++
+			}
 
 			if estate.AvgTransactionPrice < minPrice && estate.AvgTransactionPrice > 0 {
 				minPrice = estate.AvgTransactionPrice
@@ -238,6 +253,9 @@ func (r *ValuationRepo) GetDistrictValuations(ctx context.Context, districtID ui
 		summary.MinPricePerSqft = minPrice
 		summary.MaxPricePerSqft = maxPrice
 		summary.TotalTransactions = totalTransactions
+		if yieldCount > 0 {
+			summary.AvgRentalYield = sumYield / float64(yieldCount)
+		}
 	}
 
 	// 转换屋苑列表为估价响应
@@ -272,10 +290,8 @@ func (r *ValuationRepo) estateToValuation(ctx context.Context, estate *models.Es
 	valuation.AvgSalePrice = avgPrices["sale"]
 	valuation.AvgRentPrice = avgPrices["rent"]
 
-	// 计算租金回报率
-	if valuation.AvgSalePrice > 0 && valuation.AvgRentPrice > 0 {
-		valuation.RentalYield = (valuation.AvgRentPrice * 12 / valuation.AvgSalePrice) * 100
-	}
+	// 租金回报率（定时任务按户型配对计算）
+	valuation.RentalYield = estate.RentalYield
 
 	// 获取价格范围
 	priceRange := r.getPriceRange(ctx, estate.Name)
@@ -357,3 +373,113 @@ func (r *ValuationRepo) getPriceRange(ctx context.Context, estateName string) ma
 
 	return result
 }
+
+// ============ 租金回报率 ============
+
+// FindEstatesForYield 查询需计算租金回报率的屋苑（仅 ID 及名称）
+func (r *ValuationRepo) FindEstatesForYield(ctx context.Context) ([]models.Estate, error) {
+	var estates []models.Estate
+	err := r.db.WithContext(ctx).
+		Select("id", "name").
+		Order("id ASC").
+		Find(&estates).Error
+	return estates, err
+}
+
+// FindYieldSamples 按户型及放盘类型汇总屋苑的在架放盘及 since 之后的成交（sold/rented）
+func (r *ValuationRepo) FindYieldSamples(ctx context.Context, estateName string, since time.Time) ([]models.YieldSample, error) {
+	var samples []models.YieldSample
+	err := r.db.WithContext(ctx).
+		Model(&models.Property{}).
+		Select("bedrooms, listing_type, AVG(price) AS avg_price, AVG(price / area) AS avg_price_per_sqft, COUNT(*) AS count").
+		Where("building_name = ? AND area > 0 AND price > 0", estateName).
+		Where("status = ? OR (listing_type = ? AND status = ? AND updated_at >= ?) OR (listing_type = ? AND status = ? AND updated_at >= ?)",
+			"available", "sale", "sold", since, "rent", "rented", since).
+		Group("bedrooms, listing_type").
+		Scan(&samples).Error
+	return samples, err
+}
+
+// SaveEstateYields 替换屋苑户型租金回报率并更新屋苑整体回报率
+func (r *ValuationRepo) SaveEstateYields(ctx context.Context, estateID uint, yields []models.EstateYield, overall float64, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("estate_id = ?", estateID).Delete(&models.EstateYield{}).Error; err != nil {
+			return err
+		}
+		if len(yields) > 0 {
+			if err := tx.Create(&yields).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Estate{}).
+			Where("id = ?", estateID).
+			UpdateColumns(map[string]interface{}{
+				"rental_yield":            overall,
+				"rental_yield_updated_at": now,
+			}).Error
+	})
+}
+
+// FindEstateYields 查询屋苑户型租金回报率
+func (r *ValuationRepo) FindEstateYields(ctx context.Context, estateID uint) ([]models.EstateYield, error) {
+	var yields []models.EstateYield
+	err := r.db.WithContext(ctx).
+		Where("estate_id = ?", estateID).
+		Order("bedrooms ASC").
+		Find(&yields).Error
+	return yields, err
+}
+
+// FindYieldRanking 查询屋苑户型租金回报率排行
+func (r *ValuationRepo) FindYieldRanking(ctx context.Context, filter *models.YieldRankingRequest) ([]models.YieldRankingItem, int64, error) {
+	var items []models.YieldRankingItem
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Table("estate_yields AS y").
+		Joins("JOIN estates e ON e.id = y.estate_id AND e.deleted_at IS NULL").
+		Joins("LEFT JOIN districts d ON d.id = e.district_id").
+		Where("y.sale_samples >= ? AND y.rent_samples >= ?", filter.MinSamples, filter.MinSamples)
+
+	if filter.DistrictID != nil {
+		query = query.Where("e.district_id = ?", *filter.DistrictID)
+	}
+	if filter.PrimarySchoolNet != nil && *filter.PrimarySchoolNet != "" {
+		query = query.Where("e.primary_school_net = ?", *filter.PrimarySchoolNet)
+	}
+	if filter.SecondarySchoolNet != nil && *filter.SecondarySchoolNet != "" {
+		query = query.Where("e.secondary_school_net = ?", *filter.SecondarySchoolNet)
+	}
+	if filter.MinBudget != nil {
+		query = query.Where("y.avg_sale_price >= ?", *filter.MinBudget)
+	}
+	if filter.MaxBudget != nil {
+		query = query.Where("y.avg_sale_price <= ?", *filter.MaxBudget)
+	}
+	if filter.Bedrooms != nil {
+		query = query.Where("y.bedrooms = ?", *filter.Bedrooms)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.
+		Select(`y.estate_id, e.name AS estate_name, e.name_en AS estate_name_en, e.district_id,
+			d.name_zh_hant AS district_name, e.primary_school_net, e.secondary_school_net,
+			y.bedrooms, y.avg_sale_price, y.avg_monthly_rent, y.avg_sale_price_per_sqft, y.avg_rent_per_sqft,
+			y.gross_yield, y.sale_samples, y.rent_samples, y.updated_at`).
+		Order("y.gross_yield DESC, y.estate_id ASC, y.bedrooms ASC").
+		Offset(offset).
+		Limit(filter.PageSize).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range items {
+		items[i].Rank = offset + i + 1
+	}
+	return items, total, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/clutchtechnology/hk_ajoliving_app_go/middlewares"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/routes"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/services"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
@@ -27,12 +28,18 @@ func main() {
 
 	// 初始化仓储层
 	userRepo := databases.NewUserRepo(databases.DB)
-	propertyRepo := databases.NewPropertyRepo(databases.DB)
+	propertyRepo, err := databases.NewPropertyRepo(databases.DB)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize property repository: %v", err)
+	}
 	newDevelopmentRepo := databases.NewNewDevelopmentRepo(databases.DB)
 	servicedApartmentRepo := databases.NewServicedApartmentRepo(databases.DB)
 	estateRepo := databases.NewEstateRepo(databases.DB)
 	valuationRepo := databases.NewValuationRepo(databases.DB)
-	furnitureRepo := databases.NewFurnitureRepo(databases.DB)
+	furnitureRepo, err := databases.NewFurnitureRepo(databases.DB)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize furniture repository: %v", err)
+	}
 	cartRepo := databases.NewCartRepo(databases.DB)
 	schoolNetRepo := databases.NewSchoolNetRepo(databases.DB)
 	schoolRepo := databases.NewSchoolRepo(databases.DB)
@@ -42,25 +49,89 @@ func main() {
 	facilityRepo := databases.NewFacilityRepo(databases.DB)
 	searchRepo := databases.NewSearchRepo(databases.DB)
 	statisticsRepo := databases.NewStatisticsRepo(databases.DB)
+	notificationRepo := databases.NewNotificationRepo(databases.DB)
+	jobRepo := databases.NewJobRepo(databases.DB)
+	mediaRepo := databases.NewMediaRepo(databases.DB)
+	listingReviewRepo := databases.NewListingReviewRepo(databases.DB)
+	duplicateClusterRepo := databases.NewDuplicateClusterRepo(databases.DB)
+	auditRepo := databases.NewAuditRepo(databases.DB)
+	propertyImportRepo := databases.NewPropertyImportRepo(databases.DB)
+	agencyFeedRepo := databases.NewAgencyFeedRepo(databases.DB)
+	priceListRepo := databases.NewPriceListRepo(databases.DB)
+	newPropertyTransactionRepo := databases.NewNewPropertyTransactionRepo(databases.DB)
+	servicedApartmentBookingRepo := databases.NewServicedApartmentBookingRepo(databases.DB)
+	servicedApartmentPricingRepo := databases.NewServicedApartmentPricingRepo(databases.DB)
+	estateBlockRepo := databases.NewEstateBlockRepo(databases.DB)
+
+	// 初始化对象存储
+	storage, err := tools.NewStorageFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize storage: %v", err)
+	}
+
+	// 刊登续期规则
+	renewalPolicy := services.LoadRenewalPolicy()
+
+	// 按揭计算规则
+	mortgagePolicy := services.LoadMortgagePolicy()
+
+	// 印花税及交易费用规则
+	stampDutyPolicy := services.LoadStampDutyPolicy()
+
+	// 刊登审核规则
+	moderationPolicy := services.LoadModerationPolicy()
 
 	// 初始化服务层
+	auditService := services.NewAuditService(auditRepo, tools.GetEnvInt("AUDIT_RETENTION_DAYS", 365))
+	mediaService := services.NewMediaService(storage, mediaRepo)
+	moderationService := services.NewModerationService(listingReviewRepo, propertyRepo, furnitureRepo, notificationRepo, renewalPolicy, moderationPolicy, auditService)
 	authService := services.NewAuthService(userRepo)
-	userService := services.NewUserService(userRepo, propertyRepo)
-	propertyService := services.NewPropertyService(propertyRepo)
-	newDevelopmentService := services.NewNewDevelopmentService(newDevelopmentRepo)
-	servicedApartmentService := services.NewServicedApartmentService(servicedApartmentRepo)
-	estateService := services.NewEstateService(estateRepo)
+	mortgageService := services.NewMortgageService(mortgagePolicy)
+	stampDutyService := services.NewStampDutyService(stampDutyPolicy)
+	comparisonService := services.NewComparisonService(propertyRepo, estateRepo)
+	userService := services.NewUserService(userRepo, propertyRepo, notificationRepo)
+	estateBlockService := services.NewEstateBlockService(estateBlockRepo, estateRepo, auditService)
+	estateImportService := services.NewEstateImportService(estateRepo, facilityRepo, districtRepo, auditService)
+	propertyService := services.NewPropertyService(propertyRepo, renewalPolicy, mediaService, moderationService, auditService, mortgageService, stampDutyService, estateBlockService)
+	newDevelopmentService := services.NewNewDevelopmentService(newDevelopmentRepo, newPropertyTransactionRepo, mediaService, auditService)
+	priceListService := services.NewPriceListService(priceListRepo, newDevelopmentRepo, auditService)
+	servicedApartmentOperatorService := services.NewServicedApartmentOperatorService(servicedApartmentRepo, servicedApartmentBookingRepo, mediaService, auditService)
+	servicedApartmentPricingService := services.NewServicedApartmentPricingService(servicedApartmentPricingRepo, servicedApartmentRepo, servicedApartmentBookingRepo, auditService)
+	servicedApartmentService := services.NewServicedApartmentService(servicedApartmentRepo, mediaService, servicedApartmentPricingService, auditService)
+	servicedApartmentBookingService := services.NewServicedApartmentBookingService(servicedApartmentBookingRepo, servicedApartmentRepo, notificationRepo, auditService, tools.GetEnvInt("SERVICED_APARTMENT_HOLD_HOURS", 48))
+	estateService := services.NewEstateService(estateRepo, mediaService, auditService)
 	valuationService := services.NewValuationService(valuationRepo)
-	furnitureService := services.NewFurnitureService(furnitureRepo)
+	furnitureService := services.NewFurnitureService(furnitureRepo, renewalPolicy, mediaService, moderationService, auditService)
 	cartService := services.NewCartService(cartRepo, furnitureRepo)
 	schoolNetService := services.NewSchoolNetService(schoolNetRepo)
 	schoolService := services.NewSchoolService(schoolRepo)
 	agentService := services.NewAgentService(agentRepo)
 	agencyService := services.NewAgencyService(agencyRepo)
+	agencyFeedService := services.NewAgencyFeedService(agencyRepo, agencyFeedRepo, auditService)
 	districtService := services.NewDistrictService(districtRepo)
-	facilityService := services.NewFacilityService(facilityRepo)
+	facilityService := services.NewFacilityService(facilityRepo, auditService)
 	searchService := services.NewSearchService(searchRepo)
 	statisticsService := services.NewStatisticsService(statisticsRepo)
+	propertyImportService := services.NewPropertyImportService(propertyImportRepo, propertyRepo, propertyService, moderationService, auditService)
+	duplicateService := services.NewDuplicateService(propertyRepo, duplicateClusterRepo, auditService)
+	listingExpiryService := services.NewListingExpiryService(propertyRepo, furnitureRepo, notificationRepo, renewalPolicy, auditService)
+
+	maintenanceService := services.NewMaintenanceService(estateRepo, schoolNetRepo, agencyRepo)
+
+	// 注册定时任务（cron 表达式可通过环境变量覆盖）
+	scheduler := tools.NewScheduler(jobRepo, jobRepo)
+	scheduler.MustRegister("listing_expiry", tools.GetEnv("JOB_LISTING_EXPIRY_SCHEDULE", "*/15 * * * *"), listingExpiryService.ExpireListings)
+	scheduler.MustRegister("listing_renewal_reminder", tools.GetEnv("JOB_LISTING_REMINDER_SCHEDULE", "0 10 * * *"), listingExpiryService.SendRenewalReminders)
+	scheduler.MustRegister("scheduled_publishing", tools.GetEnv("JOB_SCHEDULED_PUBLISHING_SCHEDULE", "* * * * *"), listingExpiryService.PublishScheduledListings)
+	scheduler.MustRegister("estate_recount", tools.GetEnv("JOB_ESTATE_RECOUNT_SCHEDULE", "0 3 * * *"), maintenanceService.RecountEstates)
+	scheduler.MustRegister("estate_yield", tools.GetEnv("JOB_ESTATE_YIELD_SCHEDULE", "15 3 * * *"), valuationService.RecomputeEstateYields)
+	scheduler.MustRegister("school_net_recount", tools.GetEnv("JOB_SCHOOL_NET_RECOUNT_SCHEDULE", "30 3 * * *"), maintenanceService.RecountSchoolNets)
+	scheduler.MustRegister("agency_agent_recount", tools.GetEnv("JOB_AGENCY_RECOUNT_SCHEDULE", "45 3 * * *"), maintenanceService.RecountAgencyAgents)
+	scheduler.MustRegister("property_fingerprint_backfill", tools.GetEnv("JOB_FINGERPRINT_BACKFILL_SCHEDULE", "0 4 * * *"), duplicateService.BackfillFingerprints)
+	scheduler.MustRegister("audit_log_cleanup", tools.GetEnv("JOB_AUDIT_CLEANUP_SCHEDULE", "30 4 * * *"), auditService.PurgeExpired)
+	scheduler.MustRegister("property_import_recovery", tools.GetEnv("JOB_PROPERTY_IMPORT_SCHEDULE", "*/5 * * * *"), propertyImportService.ProcessPending)
+	scheduler.MustRegister("booking_hold_expiry", tools.GetEnv("JOB_BOOKING_HOLD_EXPIRY_SCHEDULE", "*/10 * * * *"), servicedApartmentBookingService.ExpireHolds)
+	jobService := services.NewJobService(scheduler, jobRepo)
 
 	// 初始化控制器层
 	healthCtrl := controllers.NewHealthController()
@@ -69,7 +140,12 @@ func main() {
 	propertyCtrl := controllers.NewPropertyController(propertyService)
 	newDevelopmentCtrl := controllers.NewNewDevelopmentController(newDevelopmentService)
 	servicedApartmentCtrl := controllers.NewServicedApartmentController(servicedApartmentService)
+	servicedApartmentBookingCtrl := controllers.NewServicedApartmentBookingController(servicedApartmentBookingService)
+	servicedApartmentPricingCtrl := controllers.NewServicedApartmentPricingController(servicedApartmentPricingService)
+	servicedApartmentOperatorCtrl := controllers.NewServicedApartmentOperatorController(servicedApartmentOperatorService)
 	estateCtrl := controllers.NewEstateController(estateService)
+	estateBlockCtrl := controllers.NewEstateBlockController(estateBlockService)
+	estateImportCtrl := controllers.NewEstateImportController(estateImportService)
 	valuationCtrl := controllers.NewValuationController(valuationService)
 	furnitureCtrl := controllers.NewFurnitureController(furnitureService)
 	cartCtrl := controllers.NewCartController(cartService)
@@ -77,10 +153,21 @@ func main() {
 	schoolCtrl := controllers.NewSchoolController(schoolService)
 	agentCtrl := controllers.NewAgentController(agentService)
 	agencyCtrl := controllers.NewAgencyController(agencyService)
+	agencyFeedCtrl := controllers.NewAgencyFeedController(agencyFeedService)
+	mortgageCtrl := controllers.NewMortgageController(mortgageService)
+	stampDutyCtrl := controllers.NewStampDutyController(stampDutyService)
+	comparisonCtrl := controllers.NewComparisonController(comparisonService)
+	priceListCtrl := controllers.NewPriceListController(priceListService)
 	districtCtrl := controllers.NewDistrictController(districtService)
 	facilityCtrl := controllers.NewFacilityController(facilityService)
 	searchCtrl := controllers.NewSearchController(searchService)
 	statisticsCtrl := controllers.NewStatisticsController(statisticsService)
+	jobCtrl := controllers.NewJobController(jobService)
+	uploadCtrl := controllers.NewUploadController(mediaService)
+	moderationCtrl := controllers.NewModerationController(moderationService)
+	duplicateCtrl := controllers.NewDuplicateController(duplicateService)
+	auditCtrl := controllers.NewAuditController(auditService)
+	propertyImportCtrl := controllers.NewPropertyImportController(propertyImportService)
 
 	// 设置 Gin 模式
 	mode := os.Getenv("GIN_MODE")
@@ -95,8 +182,20 @@ func main() {
 	// 应用 CORS 中间件
 	r.Use(middlewares.CORS())
 
+	// 请求ID（写入响应头，审计日志按请求关联）
+	r.Use(middlewares.RequestID())
+
+	// 本地存储时由 Gin 提供上传文件的静态访问
+	if local, ok := storage.(*tools.LocalStorage); ok {
+		r.Static(local.PublicURL(), local.BaseDir())
+	}
+
 	// 设置路由
-	routes.SetupRoutes(r, healthCtrl, authCtrl, userCtrl, propertyCtrl, newDevelopmentCtrl, servicedApartmentCtrl, estateCtrl, valuationCtrl, furnitureCtrl, cartCtrl, schoolNetCtrl, schoolCtrl, agentCtrl, agencyCtrl, districtCtrl, facilityCtrl, searchCtrl, statisticsCtrl)
+	routes.SetupRoutes(r, healthCtrl, authCtrl, userCtrl, propertyCtrl, newDevelopmentCtrl, servicedApartmentCtrl, estateCtrl, valuationCtrl, furnitureCtrl, cartCtrl, schoolNetCtrl, schoolCtrl, agentCtrl, agencyCtrl, districtCtrl, facilityCtrl, searchCtrl, statisticsCtrl, jobCtrl, uploadCtrl, moderationCtrl, duplicateCtrl, auditCtrl, propertyImportCtrl, agencyFeedCtrl, mortgageCtrl, stampDutyCtrl, comparisonCtrl, priceListCtrl, servicedApartmentBookingCtrl, servicedApartmentPricingCtrl, servicedApartmentOperatorCtrl, estateBlockCtrl, estateImportCtrl)
+
+	// 启动定时任务
+	scheduler.Start()
+	defer scheduler.Stop()
 
 	// 启动服务器
 	port := os.Getenv("SERVER_PORT")
@@ -40,7 +40,41 @@ func JWTAuth() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("user_type", claims.UserType)
+		c.Request = c.Request.WithContext(tools.WithActor(c.Request.Context(), tools.Actor{UserID: claims.UserID, UserType: claims.UserType}))
 
 		c.Next()
 	}
 }
+
+// OptionalJWTAuth 可选 JWT 认证中间件：携带有效 token 时写入用户信息，否则按游客放行
+func OptionalJWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			if claims, err := tools.ParseToken(strings.TrimPrefix(authHeader, "Bearer ")); err == nil {
+				c.Set("user_id", claims.UserID)
+				c.Set("email", claims.Email)
+				c.Set("user_type", claims.UserType)
+				c.Request = c.Request.WithContext(tools.WithActor(c.Request.Context(), tools.Actor{UserID: claims.UserID, UserType: claims.UserType}))
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireUserType 用户类型校验中间件（需在 JWTAuth 之后使用）
+func RequireUserType(userTypes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userType, _ := c.Get("user_type")
+		for _, t := range userTypes {
+			if userType == t {
+				c.Next()
+				return
+			}
+		}
+
+		tools.Forbidden(c, "insufficient permissions")
+		c.Abort()
+	}
+}
@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求ID响应头（客户端或网关传入时沿用）
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID 请求ID中间件：为每个请求分配ID，写入响应头和请求上下文
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			buf := make([]byte, 16)
+			_, _ = rand.Read(buf)
+			requestID = hex.EncodeToString(buf)
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(tools.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
@@ -28,6 +28,7 @@ type AgencyDetail struct {
 	ReviewCount            int            `gorm:"default:0" json:"review_count"`
 	IsVerified             bool           `gorm:"default:false" json:"is_verified"`
 	VerifiedAt             *time.Time     `json:"verified_at"`
+	FeedPrivate            bool           `gorm:"default:false" json:"feed_private"` // 刊登 Feed 是否需要 API Key 访问
 	CreatedAt              time.Time      `json:"created_at"`
 	UpdatedAt              time.Time      `json:"updated_at"`
 
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// AgencyFeedVersion 刊登 Feed 结构版本（字段有不兼容调整时递增）
+const AgencyFeedVersion = "1.0"
+
+// ============ GORM Model ============
+
+// AgencyFeedKey 代理公司 Feed API Key（私有 Feed 需携带，明文只在创建时返回一次）
+type AgencyFeedKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	AgencyID   uint       `gorm:"not null;index" json:"agency_id"`       // 代理公司ID（agency_details.id）
+	Name       string     `gorm:"size:100;not null" json:"name"`         // 备注名称（如对接的门户）
+	KeyPrefix  string     `gorm:"size:16;not null" json:"key_prefix"`    // Key 前缀（用于识别）
+	KeyHash    string     `gorm:"size:64;uniqueIndex;not null" json:"-"` // Key 的 SHA-256
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`                // 最近使用时间
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`     // 吊销时间
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (AgencyFeedKey) TableName() string {
+	return "agency_feed_keys"
+}
+
+// AgencyFeedStats Feed 数据版本统计（用于 ETag / Last-Modified，含已删除房产）
+type AgencyFeedStats struct {
+	Count        int64
+	LastModified *time.Time
+}
+
+// ============ Request DTO ============
+
+// AgencyFeedRequest Feed 请求（私有 Feed 的 Key 也可通过 X-API-Key 请求头传递）
+type AgencyFeedRequest struct {
+	Since  *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"` // 增量更新：只返回该时间之后有变化的房源（RFC 3339）
+	APIKey string     `form:"api_key"`                                       // API Key
+}
+
+// CreateAgencyFeedKeyRequest 创建 Feed API Key 请求
+type CreateAgencyFeedKeyRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// UpdateAgencyFeedSettingsRequest 更新 Feed 设置请求
+type UpdateAgencyFeedSettingsRequest struct {
+	Private *bool `json:"private" binding:"required"` // 是否为私有 Feed（需 API Key 访问）
+}
+
+// ============ Response DTO ============
+
+// AgencyFeed 代理公司刊登 Feed（XML 与 JSON 使用同一结构）
+type AgencyFeed struct {
+	XMLName     xml.Name            `xml:"feed" json:"-"`
+	Version     string              `xml:"version,attr" json:"version"`              // 结构版本
+	GeneratedAt time.Time           `xml:"generated_at" json:"generated_at"`         // 生成时间
+	Since       *time.Time          `xml:"since,omitempty" json:"since,omitempty"`   // 增量起点（全量时为空）
+	Agency      AgencyFeedAgency    `xml:"agency" json:"agency"`                     // 代理公司
+	Listings    []AgencyFeedListing `xml:"listings>listing" json:"listings"`         // 在架房源
+	Removed     []AgencyFeedRemoved `xml:"removed>listing,omitempty" json:"removed"` // 增量期间下架或删除的房源
+}
+
+// AgencyFeedAgency Feed 代理公司信息
+type AgencyFeedAgency struct {
+	ID         uint   `xml:"id,attr" json:"id"`
+	Name       string `xml:"name" json:"name"`
+	NameEn     string `xml:"name_en,omitempty" json:"name_en,omitempty"`
+	LicenseNo  string `xml:"license_no" json:"license_no"`
+	Phone      string `xml:"phone" json:"phone"`
+	Email      string `xml:"email" json:"email"`
+	WebsiteURL string `xml:"website_url,omitempty" json:"website_url,omitempty"`
+}
+
+// AgencyFeedListing Feed 房源
+type AgencyFeedListing struct {
+	ID           uint               `xml:"id,attr" json:"id"`                                      // 房产ID
+	PropertyNo   string             `xml:"property_no" json:"property_no"`                         // 物业编号
+	Reference    string             `xml:"reference,omitempty" json:"reference,omitempty"`         // 代理公司自有编号（estate_no）
+	ListingType  string             `xml:"listing_type" json:"listing_type"`                       // sale, rent
+	PropertyType string             `xml:"property_type" json:"property_type"`                     // 物业类型
+	Title        string             `xml:"title" json:"title"`                                     // 标题
+	Description  string             `xml:"description,omitempty" json:"description,omitempty"`     // 描述
+	Price        float64            `xml:"price" json:"price"`                                     // 价格
+	Currency     string             `xml:"currency" json:"currency"`                               // 币种（HKD）
+	Area         float64            `xml:"area" json:"area"`                                       // 面积
+	AreaUnit     string             `xml:"area_unit" json:"area_unit"`                             // 面积单位（sqft）
+	Bedrooms     int                `xml:"bedrooms" json:"bedrooms"`                               // 房间数
+	Bathrooms    int                `xml:"bathrooms" json:"bathrooms"`                             // 浴室数
+	Address      string             `xml:"address" json:"address"`                                 // 地址
+	BuildingName string             `xml:"building_name,omitempty" json:"building_name,omitempty"` // 大厦名称
+	Floor        string             `xml:"floor,omitempty" json:"floor,omitempty"`                 // 楼层
+	District     AgencyFeedDistrict `xml:"district" json:"district"`                               // 地区
+	Images       []AgencyFeedImage  `xml:"images>image" json:"images"`                             // 图片（按排序，封面在首位）
+	URL          string             `xml:"url,omitempty" json:"url,omitempty"`                     // 房源详情页地址
+	PublishedAt  *time.Time         `xml:"published_at,omitempty" json:"published_at,omitempty"`   // 发布时间
+	ExpiresAt    *time.Time         `xml:"expires_at,omitempty" json:"expires_at,omitempty"`       // 到期时间
+	UpdatedAt    time.Time          `xml:"updated_at" json:"updated_at"`                           // 最后修改时间
+}
+
+// AgencyFeedDistrict Feed 地区
+type AgencyFeedDistrict struct {
+	ID     uint   `xml:"id,attr" json:"id"`
+	Name   string `xml:"name" json:"name"`                           // 繁体中文名称
+	NameEn string `xml:"name_en,omitempty" json:"name_en,omitempty"` // 英文名称
+	Region string `xml:"region,omitempty" json:"region,omitempty"`   // HK_ISLAND, KOWLOON, NEW_TERRITORIES
+}
+
+// AgencyFeedImage Feed 图片
+type AgencyFeedImage struct {
+	URL          string `xml:"url" json:"url"`
+	ThumbnailURL string `xml:"thumbnail_url,omitempty" json:"thumbnail_url,omitempty"`
+	Type         string `xml:"type,attr" json:"type"` // cover, interior, exterior, floorplan
+}
+
+// AgencyFeedRemoved Feed 已下架房源（增量更新时返回，接收方应删除）
+type AgencyFeedRemoved struct {
+	ID         uint      `xml:"id,attr" json:"id"`
+	PropertyNo string    `xml:"property_no" json:"property_no"`
+	Reference  string    `xml:"reference,omitempty" json:"reference,omitempty"`
+	Status     string    `xml:"status" json:"status"` // 当前状态（sold, expired 等，已删除为 deleted）
+	RemovedAt  time.Time `xml:"removed_at" json:"removed_at"`
+}
+
+// AgencyFeedSettingsResponse Feed 设置响应
+type AgencyFeedSettingsResponse struct {
+	AgencyID uint            `json:"agency_id"`
+	Private  bool            `json:"private"`
+	Keys     []AgencyFeedKey `json:"keys"`
+}
+
+// CreateAgencyFeedKeyResponse 创建 Feed API Key 响应（key 只返回一次）
+type CreateAgencyFeedKeyResponse struct {
+	AgencyFeedKey
+	Key string `json:"key"`
+}
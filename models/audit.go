@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// 审计动作
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRenew   = "renew"   // 续期
+	AuditActionSubmit  = "submit"  // 提交审核
+	AuditActionReview  = "review"  // 审核通过或拒绝
+	AuditActionExpire  = "expire"  // 到期下架
+	AuditActionPublish = "publish" // 定时发布或立即发布
+)
+
+// AuditChange 字段变更
+type AuditChange struct {
+	Field string      `json:"field"` // 字段名（JSON 字段名）
+	Old   interface{} `json:"old"`   // 修改前的值
+	New   interface{} `json:"new"`   // 修改后的值
+}
+
+// ============ GORM Model ============
+
+// AuditLog 审计日志（记录每次数据变更的操作者和字段差异）
+type AuditLog struct {
+	ID         uint          `gorm:"primaryKey" json:"id"`
+	EntityType string        `gorm:"size:50;not null;index:idx_audit_entity" json:"entity_type"` // property, furniture, estate, facility
+	EntityID   uint          `gorm:"not null;index:idx_audit_entity" json:"entity_id"`           // 实体ID
+	Action     string        `gorm:"size:30;not null;index" json:"action"`                       // create, update, delete, renew, submit, review, expire, publish
+	ActorID    *uint         `gorm:"index" json:"actor_id,omitempty"`                            // 操作者ID（系统任务为空）
+	ActorType  string        `gorm:"size:20;not null" json:"actor_type"`                         // 操作者类型（individual, agency, admin, system）
+	RequestID  string        `gorm:"size:64;index" json:"request_id,omitempty"`                  // 请求ID
+	Changes    []AuditChange `gorm:"type:text;serializer:json" json:"changes"`                   // 字段差异
+	CreatedAt  time.Time     `gorm:"index" json:"created_at"`                                    // 操作时间
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// ============ Request DTO ============
+
+// ListAuditLogsRequest 审计日志查询请求
+type ListAuditLogsRequest struct {
+	Entity    string `form:"entity" binding:"omitempty,max=50"`     // 实体类型
+	ID        *uint  `form:"id"`                                    // 实体ID（需同时指定 entity）
+	ActorID   *uint  `form:"actor_id"`                              // 操作者ID
+	Action    string `form:"action" binding:"omitempty,max=30"`     // 动作
+	RequestID string `form:"request_id" binding:"omitempty,max=64"` // 请求ID
+	Page      int    `form:"page,default=1" binding:"min=1"`
+	PageSize  int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// ============ Response DTO ============
+
+// PaginatedAuditLogsResponse 分页审计日志响应
+type PaginatedAuditLogsResponse struct {
+	Data       []AuditLog `json:"data"`
+	Total      int64      `json:"total"`
+	Page       int        `json:"page"`
+	PageSize   int        `json:"page_size"`
+	TotalPages int        `json:"total_pages"`
+}
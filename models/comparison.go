@@ -0,0 +1,104 @@
+package models
+
+// 对比优选方向
+const (
+	ComparePreferLower  = "lower"  // 数值越低越好（如呎价、楼龄）
+	ComparePreferHigher = "higher" // 数值越高越好（如面积、回报率）
+)
+
+// ============ Request DTO ============
+
+// CompareRequest 对比请求（2-5 个 ID，按传入顺序返回）
+type CompareRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=2,max=5,dive,gt=0"`
+}
+
+// ============ Response DTO ============
+
+// ComparisonDifference 对比差异项（仅列出各项取值不同的字段）
+type ComparisonDifference struct {
+	Field   string            `json:"field"`              // 字段名（与对比项 JSON 字段一致）
+	Values  []ComparisonValue `json:"values"`             // 各对比项取值（按对比项顺序）
+	Prefer  string            `json:"prefer,omitempty"`   // 优选方向：lower, higher
+	BestIDs []uint            `json:"best_ids,omitempty"` // 该字段表现最佳的对比项ID
+}
+
+// ComparisonValue 对比项取值
+type ComparisonValue struct {
+	ID    uint        `json:"id"`
+	Value interface{} `json:"value"`
+}
+
+// ComparisonEstate 房产所属屋苑摘要
+type ComparisonEstate struct {
+	ID                  uint    `json:"id"`
+	Name                string  `json:"name"`
+	CompletionYear      int     `json:"completion_year,omitempty"`
+	AvgTransactionPrice float64 `json:"avg_transaction_price,omitempty"` // 平均成交呎价
+	RentalYield         float64 `json:"rental_yield,omitempty"`          // 毛租金回报率 (%)
+}
+
+// PropertyComparisonItem 房产对比项
+type PropertyComparisonItem struct {
+	ID                 uint              `json:"id"`
+	PropertyNo         string            `json:"property_no"`
+	Title              string            `json:"title"`
+	ListingType        string            `json:"listing_type"`
+	PropertyType       string            `json:"property_type"`
+	Status             string            `json:"status"`
+	Price              float64           `json:"price"`
+	Area               float64           `json:"area"`
+	PricePerSqft       float64           `json:"price_per_sqft"` // 每平方尺价格
+	Bedrooms           int               `json:"bedrooms"`
+	Bathrooms          int               `json:"bathrooms"`
+	Floor              string            `json:"floor,omitempty"`
+	Orientation        string            `json:"orientation,omitempty"`
+	Address            string            `json:"address"`
+	BuildingName       string            `json:"building_name,omitempty"`
+	District           *District         `json:"district,omitempty"`
+	CoverImage         string            `json:"cover_image,omitempty"`
+	PrimarySchoolNet   string            `json:"primary_school_net,omitempty"`
+	SecondarySchoolNet string            `json:"secondary_school_net,omitempty"`
+	Estate             *ComparisonEstate `json:"estate,omitempty"`              // 所属屋苑（按大厦名称匹配）
+	BuildingAge        *int              `json:"building_age,omitempty"`        // 楼龄（按屋苑落成年份计算）
+	PriceVsEstateAvg   *float64          `json:"price_vs_estate_avg,omitempty"` // 呎价较屋苑平均成交呎价差异 (%)
+	Facilities         []string          `json:"facilities"`                    // 屋苑设施
+}
+
+// PropertyComparisonResponse 房产对比响应
+type PropertyComparisonResponse struct {
+	Items            []PropertyComparisonItem `json:"items"`
+	Differences      []ComparisonDifference   `json:"differences"`       // 差异项
+	CommonFacilities []string                 `json:"common_facilities"` // 共同设施
+}
+
+// EstateComparisonItem 屋苑对比项
+type EstateComparisonItem struct {
+	ID                      uint      `json:"id"`
+	Name                    string    `json:"name"`
+	NameEn                  string    `json:"name_en,omitempty"`
+	Address                 string    `json:"address"`
+	District                *District `json:"district,omitempty"`
+	CoverImage              string    `json:"cover_image,omitempty"`
+	CompletionYear          int       `json:"completion_year,omitempty"`
+	BuildingAge             *int      `json:"building_age,omitempty"` // 楼龄
+	Developer               string    `json:"developer,omitempty"`
+	ManagementCompany       string    `json:"management_company,omitempty"`
+	TotalBlocks             int       `json:"total_blocks"`
+	TotalUnits              int       `json:"total_units"`
+	PrimarySchoolNet        string    `json:"primary_school_net,omitempty"`
+	SecondarySchoolNet      string    `json:"secondary_school_net,omitempty"`
+	AvgTransactionPrice     float64   `json:"avg_transaction_price"` // 平均成交呎价
+	RentalYield             float64   `json:"rental_yield"`          // 毛租金回报率 (%)
+	RecentTransactionsCount int       `json:"recent_transactions_count"`
+	ForSaleCount            int       `json:"for_sale_count"`
+	ForRentCount            int       `json:"for_rent_count"`
+	Facilities              []string  `json:"facilities"`
+}
+
+// EstateComparisonResponse 屋苑对比响应
+type EstateComparisonResponse struct {
+	Items            []EstateComparisonItem `json:"items"`
+	Differences      []ComparisonDifference `json:"differences"`       // 差异项
+	CommonFacilities []string               `json:"common_facilities"` // 共同设施
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+)
+
+// 重复刊登：同一单位由多个代理刊登时，按指纹（地址、大厦、楼层、面积、刊登类型）归为一组
+
+// 重复组处理状态（未处理的重复组没有记录）
+const (
+	DuplicateClusterMerged    = "merged"    // 已合并（指定主刊登）
+	DuplicateClusterDismissed = "dismissed" // 非重复（不再折叠）
+)
+
+// ============ GORM Model ============
+
+// DuplicateCluster 重复组处理记录
+type DuplicateCluster struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	Fingerprint         string    `gorm:"size:40;not null;uniqueIndex" json:"fingerprint"` // 房产指纹
+	Status              string    `gorm:"size:20;not null;index" json:"status"`            // merged=已合并, dismissed=非重复
+	CanonicalPropertyID *uint     `json:"canonical_property_id,omitempty"`                 // 主刊登ID（合并时指定）
+	Note                string    `gorm:"type:text" json:"note,omitempty"`                 // 管理员备注
+	ResolvedBy          uint      `gorm:"not null" json:"resolved_by"`                     // 处理管理员ID
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+func (DuplicateCluster) TableName() string {
+	return "duplicate_clusters"
+}
+
+// DuplicateGroup 重复组统计（查询结果，非数据表）
+type DuplicateGroup struct {
+	Fingerprint    string
+	ListingCount   int64
+	PublisherCount int64
+}
+
+// ============ Request DTO ============
+
+// ListDuplicateClustersRequest 重复组列表请求
+type ListDuplicateClustersRequest struct {
+	Status   string `form:"status,default=open" binding:"omitempty,oneof=open merged dismissed"` // open=未处理
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// MergeDuplicatesRequest 合并重复组请求
+type MergeDuplicatesRequest struct {
+	CanonicalPropertyID uint   `json:"canonical_property_id" binding:"required"` // 主刊登ID（须属于该组）
+	Note                string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// DismissDuplicatesRequest 标记非重复请求
+type DismissDuplicatesRequest struct {
+	Note string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// ============ Response DTO ============
+
+// DuplicateClusterResponse 重复组响应
+type DuplicateClusterResponse struct {
+	Fingerprint         string             `json:"fingerprint"`
+	ListingCount        int64              `json:"listing_count"`   // 在架刊登数
+	PublisherCount      int64              `json:"publisher_count"` // 发布者数
+	Status              string             `json:"status"`          // open=未处理, merged=已合并, dismissed=非重复
+	CanonicalPropertyID *uint              `json:"canonical_property_id,omitempty"`
+	Note                string             `json:"note,omitempty"`
+	Listings            []PropertyResponse `json:"listings"`
+}
+
+// PaginatedDuplicateClustersResponse 分页重复组响应
+type PaginatedDuplicateClustersResponse struct {
+	Data       []DuplicateClusterResponse `json:"data"`
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	PageSize   int                        `json:"page_size"`
+	TotalPages int                        `json:"total_pages"`
+}
@@ -27,6 +27,8 @@ type Estate struct {
 	ForRentCount                 int            `gorm:"default:0" json:"for_rent_count"`                                  // 当前租盘数量
 	AvgTransactionPrice          float64        `gorm:"index" json:"avg_transaction_price,omitempty"`                     // 平均成交价（港币/平方尺）
 	AvgTransactionPriceUpdatedAt *time.Time     `json:"avg_transaction_price_updated_at,omitempty"`                       // 平均成交价更新时间
+	RentalYield                  float64        `gorm:"index" json:"rental_yield,omitempty"`                              // 毛租金回报率 (%)，按户型配对放盘及成交计算
+	RentalYieldUpdatedAt         *time.Time     `json:"rental_yield_updated_at,omitempty"`                                // 租金回报率更新时间
 	Description                  string         `gorm:"type:text" json:"description,omitempty"`                           // 屋苑描述
 	ViewCount                    int            `gorm:"default:0" json:"view_count"`                                      // 浏览次数
 	FavoriteCount                int            `gorm:"default:0" json:"favorite_count"`                                  // 收藏次数
@@ -48,13 +50,16 @@ func (Estate) TableName() string {
 
 // EstateImage 屋苑图片
 type EstateImage struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	EstateID  uint      `gorm:"not null;index" json:"estate_id"`
-	ImageURL  string    `gorm:"size:500;not null" json:"image_url"`
-	ImageType string    `gorm:"size:20;not null" json:"image_type"` // exterior=外观, facilities=设施, environment=环境, aerial=航拍
-	Title     string    `gorm:"size:200" json:"title,omitempty"`
-	SortOrder int       `gorm:"not null" json:"sort_order"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	EstateID     uint      `gorm:"not null;index" json:"estate_id"`
+	ImageURL     string    `gorm:"size:500;not null" json:"image_url"`
+	ObjectKey    string    `gorm:"size:500;index" json:"object_key,omitempty"` // 存储对象键（上传图片，外部URL 为空）
+	ThumbnailURL string    `gorm:"size:500" json:"thumbnail_url,omitempty"`    // 缩略图URL
+	ImageType    string    `gorm:"size:20;not null" json:"image_type"`         // exterior=外观, facilities=设施, environment=环境, aerial=航拍
+	Title        string    `gorm:"size:200" json:"title,omitempty"`
+	IsCover      bool      `gorm:"default:false" json:"is_cover"` // 是否为封面图
+	SortOrder    int       `gorm:"not null" json:"sort_order"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 func (EstateImage) TableName() string {
@@ -156,6 +161,11 @@ type UpdateEstateRequest struct {
 	FacilityIDs        []uint  `json:"facility_ids"` // 设施ID列表（覆盖更新）
 }
 
+// UpdateEstateFacilitiesRequest 覆盖更新屋苑设施请求（空数组为清空）
+type UpdateEstateFacilitiesRequest struct {
+	FacilityIDs []uint `json:"facility_ids" binding:"required,max=100,dive,gt=0"`
+}
+
 // ============ Response DTO ============
 
 // EstateResponse 屋苑响应
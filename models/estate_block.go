@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// ============ GORM Model ============
+
+// EstateBlock 屋苑座数（楼层数、每层单位数及单位总数按单位目录汇总）
+type EstateBlock struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	EstateID      uint      `gorm:"not null;uniqueIndex:idx_estate_block" json:"estate_id"`
+	Name          string    `gorm:"size:50;not null;uniqueIndex:idx_estate_block" json:"name"` // 座数名称（如 第1座）
+	NameEn        string    `gorm:"size:50" json:"name_en,omitempty"`                          // 英文名称（如 Tower 1）
+	Floors        int       `gorm:"not null;default:0" json:"floors"`                          // 楼层数
+	FlatsPerFloor int       `gorm:"not null;default:0" json:"flats_per_floor"`                 // 每层单位数（取单位最多的一层）
+	TotalUnits    int       `gorm:"not null;default:0" json:"total_units"`                     // 单位总数
+	SortOrder     int       `gorm:"not null;default:0" json:"sort_order"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// 关联
+	Units []EstateUnit `gorm:"foreignKey:BlockID" json:"units,omitempty"`
+}
+
+func (EstateBlock) TableName() string {
+	return "estate_blocks"
+}
+
+// EstateUnit 屋苑单位（按座数/楼层/单位唯一）
+type EstateUnit struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	EstateID     uint      `gorm:"not null;index" json:"estate_id"`
+	BlockID      uint      `gorm:"not null;uniqueIndex:idx_estate_unit" json:"block_id"`
+	Floor        string    `gorm:"size:20;not null;uniqueIndex:idx_estate_unit" json:"floor"` // 楼层（如 23、G）
+	Flat         string    `gorm:"size:20;not null;uniqueIndex:idx_estate_unit" json:"flat"`  // 单位（如 A）
+	FloorLevel   int       `gorm:"not null;default:0" json:"-"`                               // 楼层数值（用于排序，非数字楼层为 0）
+	SaleableArea float64   `json:"saleable_area,omitempty"`                                   // 实用面积（平方尺）
+	GrossArea    float64   `json:"gross_area,omitempty"`                                      // 建筑面积（平方尺）
+	Orientation  string    `gorm:"size:50" json:"orientation,omitempty"`                      // 座向
+	Bedrooms     *int      `json:"bedrooms,omitempty"`                                        // 房间数
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// 关联
+	Block *EstateBlock `gorm:"foreignKey:BlockID" json:"block,omitempty"`
+}
+
+func (EstateUnit) TableName() string {
+	return "estate_units"
+}
+
+// ============ Request DTO ============
+
+// ListEstateBlocksRequest 屋苑座数列表请求
+type ListEstateBlocksRequest struct {
+	IncludeUnits bool `form:"include_units"` // 是否同时返回各座单位
+}
+
+// ListEstateUnitsRequest 座数单位列表请求
+type ListEstateUnitsRequest struct {
+	Floor string `form:"floor" binding:"omitempty,max=20"` // 楼层
+}
+
+// ============ Response DTO ============
+
+// EstateUnitImportResponse 单位目录导入结果
+type EstateUnitImportResponse struct {
+	CreatedBlocks int `json:"created_blocks"` // 新增座数
+	CreatedUnits  int `json:"created_units"`  // 新增单位数
+	UpdatedUnits  int `json:"updated_units"`  // 更新单位数
+	TotalBlocks   int `json:"total_blocks"`   // 导入后目录座数
+	TotalUnits    int `json:"total_units"`    // 导入后目录单位数
+}
@@ -0,0 +1,24 @@
+package models
+
+// 屋苑导入结果动作
+const (
+	EstateImportCreated = "created" // 新建屋苑
+	EstateImportUpdated = "updated" // 更新已有屋苑
+)
+
+// ============ Response DTO ============
+
+// EstateImportItem 导入文件中单行的处理结果
+type EstateImportItem struct {
+	Row    int    `json:"row"`    // 行号（第 1 行为表头）
+	ID     uint   `json:"id"`     // 屋苑ID
+	Name   string `json:"name"`   // 屋苑名称
+	Action string `json:"action"` // created, updated
+}
+
+// EstateImportResponse 屋苑批量导入结果
+type EstateImportResponse struct {
+	CreatedCount int                `json:"created_count"` // 新建屋苑数
+	UpdatedCount int                `json:"updated_count"` // 更新屋苑数
+	Items        []EstateImportItem `json:"items"`
+}
@@ -22,7 +22,7 @@ type Furniture struct {
 	DeliveryDistrictID uint           `gorm:"not null;index" json:"delivery_district_id"`              // 交收地区ID
 	DeliveryTime       string         `gorm:"size:100" json:"delivery_time,omitempty"`                 // 交收时间
 	DeliveryMethod     string         `gorm:"size:50;not null;index" json:"delivery_method"`           // self_pickup=自取, delivery=送货, negotiable=面议
-	Status             string         `gorm:"size:20;not null;default:'available';index" json:"status"`// available=可用, reserved=已预订, sold=已售出, expired=已过期, cancelled=已取消
+	Status             string         `gorm:"size:20;not null;default:'available';index" json:"status"`// draft=草稿, pending_review=待审核, rejected=审核未通过, available=可用, reserved=已预订, sold=已售出, expired=已过期, cancelled=已取消
 	PublisherID        uint           `gorm:"not null;index" json:"publisher_id"`                      // 发布者ID
 	PublisherType      string         `gorm:"size:20;not null" json:"publisher_type"`                  // individual=个人, agency=代理公司
 	ViewCount          int            `gorm:"default:0" json:"view_count"`                             // 浏览次数
@@ -30,6 +30,8 @@ type Furniture struct {
 	PublishedAt        time.Time      `gorm:"not null;index" json:"published_at"`                      // 刊登日期
 	UpdatedAt          time.Time      `gorm:"index" json:"updated_at"`                                 // 更新日期
 	ExpiresAt          time.Time      `gorm:"not null;index" json:"expires_at"`                        // 到期日期
+	RenewalCount       int            `gorm:"default:0" json:"renewal_count"`                          // 续期次数
+	ReminderSentAt     *time.Time     `json:"-"`                                                       // 到期提醒发送时间
 	CreatedAt          time.Time      `json:"created_at"`                                              // 创建时间
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`                                          // 软删除时间
 
@@ -68,12 +70,14 @@ func (FurnitureCategory) TableName() string {
 
 // FurnitureImage 家具图片
 type FurnitureImage struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	FurnitureID uint      `gorm:"not null;index" json:"furniture_id"`
-	ImageURL    string    `gorm:"size:500;not null" json:"image_url"`
-	IsCover     bool      `gorm:"default:false" json:"is_cover"` // 是否为封面图
-	SortOrder   int       `gorm:"not null" json:"sort_order"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	FurnitureID  uint      `gorm:"not null;index" json:"furniture_id"`
+	ImageURL     string    `gorm:"size:500;not null" json:"image_url"`
+	ObjectKey    string    `gorm:"size:500;index" json:"object_key,omitempty"` // 存储对象键（上传图片，外部URL 为空）
+	ThumbnailURL string    `gorm:"size:500" json:"thumbnail_url,omitempty"`    // 缩略图URL
+	IsCover      bool      `gorm:"default:false" json:"is_cover"`              // 是否为封面图
+	SortOrder    int       `gorm:"not null" json:"sort_order"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 func (FurnitureImage) TableName() string {
@@ -111,7 +115,9 @@ type CreateFurnitureRequest struct {
 	DeliveryDistrictID uint       `json:"delivery_district_id" binding:"required"`
 	DeliveryTime       string     `json:"delivery_time" binding:"omitempty,max=100"`
 	DeliveryMethod     string     `json:"delivery_method" binding:"required,oneof=self_pickup delivery negotiable"`
-	ImageURLs          []string   `json:"image_urls" binding:"required,min=1"` // 至少1张图片
+	ImageURLs          []string   `json:"image_urls" binding:"required_without=ImageKeys,omitempty,min=1,max=20"` // 外部图片URL（与 image_keys 至少提供一项）
+	ImageKeys          []string   `json:"image_keys" binding:"required_without=ImageURLs,omitempty,min=1,max=20"` // 已上传图片对象键（排在外部URL之前）
+	SaveAsDraft        bool       `json:"save_as_draft"`                                                   // 保存为草稿（不提交审核）
 }
 
 // UpdateFurnitureRequest 更新家具请求
@@ -126,7 +132,8 @@ type UpdateFurnitureRequest struct {
 	DeliveryDistrictID *uint      `json:"delivery_district_id"`
 	DeliveryTime       *string    `json:"delivery_time" binding:"omitempty,max=100"`
 	DeliveryMethod     *string    `json:"delivery_method" binding:"omitempty,oneof=self_pickup delivery negotiable"`
-	ImageURLs          []string   `json:"image_urls"` // 覆盖更新图片
+	ImageURLs          []string   `json:"image_urls" binding:"omitempty,max=20"` // 覆盖更新图片（外部URL）
+	ImageKeys          []string   `json:"image_keys" binding:"omitempty,max=20"` // 覆盖更新图片（已上传对象键）
 }
 
 // UpdateFurnitureStatusRequest 更新家具状态请求
@@ -160,6 +167,7 @@ type FurnitureResponse struct {
 	PublishedAt        time.Time          `json:"published_at"`
 	UpdatedAt          time.Time          `json:"updated_at"`
 	ExpiresAt          time.Time          `json:"expires_at"`
+	RenewalCount       int                `json:"renewal_count"`
 }
 
 // PaginatedFurnitureResponse 分页家具列表响应
@@ -0,0 +1,24 @@
+package models
+
+// 房产、家具、屋苑、新盘、服务式住宅共用的图片管理请求
+
+// ============ Request DTO ============
+
+// AddImageRequest 添加图片请求（image_key 与 image_url 二选一）
+type AddImageRequest struct {
+	ImageKey  string `json:"image_key" binding:"required_without=ImageURL,omitempty,max=500"`     // 已上传图片对象键
+	ImageURL  string `json:"image_url" binding:"required_without=ImageKey,omitempty,url,max=500"` // 外部图片URL
+	ImageType string `json:"image_type" binding:"omitempty,max=20"`                               // 图片类型（取值因实体而异，家具无此字段）
+	Title     string `json:"title" binding:"omitempty,max=200"`                                   // 图片标题（房产、家具无此字段）
+}
+
+// UpdateImageRequest 更新图片信息请求
+type UpdateImageRequest struct {
+	ImageType *string `json:"image_type" binding:"omitempty,max=20"`
+	Title     *string `json:"title" binding:"omitempty,max=200"`
+}
+
+// ReorderImagesRequest 批量排序请求（需包含该实体的全部图片ID）
+type ReorderImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1,dive,gt=0"`
+}
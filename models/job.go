@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// ============ GORM Model ============
+
+// JobRun 定时任务执行记录
+type JobRun struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	JobName     string     `gorm:"size:100;not null;index" json:"job_name"` // 任务名称
+	Trigger     string     `gorm:"size:20;not null" json:"trigger"`         // schedule=定时触发, manual=手动触发
+	TriggeredBy *uint      `json:"triggered_by,omitempty"`                  // 手动触发的用户ID
+	Status      string     `gorm:"size:20;not null;index" json:"status"`    // running=执行中, success=成功, failed=失败
+	Error       string     `gorm:"type:text" json:"error,omitempty"`        // 失败原因
+	StartedAt   time.Time  `gorm:"not null;index" json:"started_at"`        // 开始时间
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`                   // 结束时间
+	DurationMs  int64      `gorm:"default:0" json:"duration_ms"`            // 执行耗时（毫秒）
+	Hostname    string     `gorm:"size:255" json:"hostname,omitempty"`      // 执行实例主机名
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// ============ Request DTO ============
+
+// ListJobRunsRequest 获取任务执行记录请求
+type ListJobRunsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=running success failed"` // 状态
+	Page     int    `form:"page,default=1" binding:"min=1"`                          // 页码
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`            // 每页数量
+}
+
+// ============ Response DTO ============
+
+// JobResponse 定时任务状态响应
+type JobResponse struct {
+	Name      string     `json:"name"`
+	Schedule  string     `json:"schedule"`
+	Running   bool       `json:"running"`               // 本实例是否正在执行
+	NextRunAt *time.Time `json:"next_run_at,omitempty"` // 下次执行时间
+	LastRun   *JobRun    `json:"last_run,omitempty"`    // 最近一次执行记录（所有实例）
+}
+
+// PaginatedJobRunsResponse 分页任务执行记录响应
+type PaginatedJobRunsResponse struct {
+	Data       []JobRun `json:"data"`
+	Total      int64    `json:"total"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"page_size"`
+	TotalPages int      `json:"total_pages"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// ============ GORM Model ============
+
+// MediaObject 已上传的媒体文件（按内容哈希去重）
+type MediaObject struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Hash         string    `gorm:"size:64;uniqueIndex;not null" json:"hash"`        // 原始文件内容 SHA-256
+	ObjectKey    string    `gorm:"size:500;uniqueIndex;not null" json:"object_key"` // 存储对象键
+	ThumbnailKey string    `gorm:"size:500" json:"thumbnail_key,omitempty"`         // 缩略图对象键
+	ContentType  string    `gorm:"size:50;not null" json:"content_type"`            // MIME 类型
+	Size         int64     `gorm:"not null" json:"size"`                            // 处理后文件大小（字节）
+	Width        int       `json:"width"`                                           // 宽度（像素）
+	Height       int       `json:"height"`                                          // 高度（像素）
+	UploaderID   uint      `gorm:"not null;index" json:"uploader_id"`               // 首次上传用户ID
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (MediaObject) TableName() string {
+	return "media_objects"
+}
+
+// ============ Response DTO ============
+
+// UploadImageResponse 图片上传响应
+type UploadImageResponse struct {
+	ObjectKey    string `json:"object_key"`
+	URL          string `json:"url"`
+	ThumbnailKey string `json:"thumbnail_key,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Hash         string `json:"hash"`
+	Deduplicated bool   `json:"deduplicated"` // 是否命中已有文件
+}
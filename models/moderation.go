@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+)
+
+// 刊登审核状态（存于 Property.Status / Furniture.Status）
+// draft → pending_review → available（已发布）/ rejected，被拒绝或草稿可重新提交
+// 房产指定了未来发布时间时，审核通过后先进入 scheduled，到时由定时任务发布
+const (
+	ListingStatusDraft         = "draft"          // 草稿
+	ListingStatusPendingReview = "pending_review" // 待审核
+	ListingStatusRejected      = "rejected"       // 审核未通过
+	ListingStatusScheduled     = "scheduled"      // 已通过审核，等待定时发布
+	ListingStatusPublished     = "available"      // 已发布（审核通过）
+)
+
+// UnpublishedListingStatuses 尚未公开的刊登状态（不出现在任何公开列表和搜索中）
+var UnpublishedListingStatuses = []string{
+	ListingStatusDraft,
+	ListingStatusPendingReview,
+	ListingStatusRejected,
+	ListingStatusScheduled,
+}
+
+// 审核记录状态
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// 自动审核命中级别
+const (
+	FlagSeverityBlock  = "block"  // 直接拒绝
+	FlagSeverityReview = "review" // 需人工审核
+)
+
+// ModerationFlag 自动审核命中项
+type ModerationFlag struct {
+	Code     string `json:"code"`     // banned_word=违禁词, phone_number=描述含电话, duplicate=重复刊登, price_outlier=价格异常
+	Severity string `json:"severity"` // block=直接拒绝, review=需人工审核
+	Detail   string `json:"detail"`   // 命中详情
+}
+
+// ============ GORM Model ============
+
+// ListingReview 刊登审核记录（每次提交审核一条）
+type ListingReview struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	EntityType   string           `gorm:"size:20;not null;index:idx_listing_review_entity" json:"entity_type"` // property, furniture
+	EntityID     uint             `gorm:"not null;index:idx_listing_review_entity" json:"entity_id"`           // 刊登ID
+	PublisherID  uint             `gorm:"not null;index" json:"publisher_id"`                                  // 发布者ID
+	Status       string           `gorm:"size:20;not null;index" json:"status"`                                // pending=待审核, approved=已通过, rejected=已拒绝
+	Flags        []ModerationFlag `gorm:"type:text;serializer:json" json:"flags"`                              // 自动审核命中项
+	Reason       string           `gorm:"type:text" json:"reason,omitempty"`                                   // 拒绝原因
+	WasPublished bool             `gorm:"default:false" json:"was_published"`                                  // 提交时是否已发布过（重新审核通过沿用原到期时间）
+	ReviewerID   *uint            `gorm:"index" json:"reviewer_id,omitempty"`                                  // 审核管理员ID（自动审核为空）
+	ReviewedAt   *time.Time       `json:"reviewed_at,omitempty"`                                               // 审核时间
+	CreatedAt    time.Time        `gorm:"index" json:"created_at"`                                             // 提交时间
+}
+
+func (ListingReview) TableName() string {
+	return "listing_reviews"
+}
+
+// ============ Request DTO ============
+
+// ListReviewsRequest 审核队列请求
+type ListReviewsRequest struct {
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=property furniture"`
+	Status     string `form:"status,default=pending" binding:"omitempty,oneof=pending approved rejected"`
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	PageSize   int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// RejectReviewRequest 拒绝刊登请求
+type RejectReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// ============ Response DTO ============
+
+// ListingReviewResponse 审核记录响应（含刊登摘要）
+type ListingReviewResponse struct {
+	ListingReview
+	ListingTitle  string  `json:"listing_title"`
+	ListingPrice  float64 `json:"listing_price"`
+	ListingStatus string  `json:"listing_status"`
+}
+
+// PaginatedListingReviewsResponse 分页审核记录响应
+type PaginatedListingReviewsResponse struct {
+	Data       []ListingReviewResponse `json:"data"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	PageSize   int                     `json:"page_size"`
+	TotalPages int                     `json:"total_pages"`
+}
@@ -0,0 +1,88 @@
+package models
+
+// 按揭方案
+const (
+	MortgageSchemeStandard  = "standard"  // 一般按揭（金管局按揭成数上限）
+	MortgageSchemeInsurance = "insurance" // 按揭保险计划（HKMC MIP）
+)
+
+// 供款表粒度
+const (
+	MortgageScheduleNone    = "none"
+	MortgageScheduleYearly  = "yearly"
+	MortgageScheduleMonthly = "monthly"
+)
+
+// ============ Request DTO ============
+
+// MortgageRequest 按揭计算请求（贷款额按 loan_amount > down_payment > ltv 的优先级确定，均不填时按最高成数计算）
+type MortgageRequest struct {
+	Price               float64  `json:"price" binding:"required,gt=0"`                          // 楼价
+	LoanAmount          float64  `json:"loan_amount" binding:"omitempty,gt=0"`                   // 贷款额
+	DownPayment         float64  `json:"down_payment" binding:"omitempty,gt=0"`                  // 首期
+	LTV                 float64  `json:"ltv" binding:"omitempty,gt=0,lte=100"`                   // 按揭成数 (%)
+	AnnualRate          *float64 `json:"annual_rate" binding:"omitempty,gte=0,lte=20"`           // 年利率 (%)，不填使用默认利率
+	TenureYears         int      `json:"tenure_years" binding:"omitempty,min=1,max=30"`          // 还款年期，不填使用默认年期
+	MortgageInsurance   bool     `json:"mortgage_insurance"`                                     // 是否使用按揭保险计划
+	FirstTimeBuyer      bool     `json:"first_time_buyer"`                                       // 是否首次置业
+	HasExistingMortgage bool     `json:"has_existing_mortgage"`                                  // 是否已有其他按揭
+	IncomeOutsideHK     bool     `json:"income_outside_hk"`                                      // 主要收入是否来自香港以外
+	MonthlyIncome       float64  `json:"monthly_income" binding:"omitempty,gt=0"`                // 家庭月入（用于供款与入息比率测试）
+	OtherMonthlyDebt    float64  `json:"other_monthly_debt" binding:"omitempty,gte=0"`           // 其他每月债务供款
+	Schedule            string   `json:"schedule" binding:"omitempty,oneof=none yearly monthly"` // 供款表粒度（默认 yearly）
+}
+
+// ============ Response DTO ============
+
+// MortgageResponse 按揭计算结果
+type MortgageResponse struct {
+	Price          float64                  `json:"price"`              // 楼价
+	Scheme         string                   `json:"scheme"`             // 按揭方案
+	MaxLTV         float64                  `json:"max_ltv"`            // 适用最高按揭成数 (%)
+	MaxLoanAmount  float64                  `json:"max_loan_amount"`    // 最高贷款额
+	LoanAmount     float64                  `json:"loan_amount"`        // 贷款额
+	DownPayment    float64                  `json:"down_payment"`       // 首期
+	LTV            float64                  `json:"ltv"`                // 按揭成数 (%)
+	AnnualRate     float64                  `json:"annual_rate"`        // 年利率 (%)
+	TenureYears    int                      `json:"tenure_years"`       // 还款年期
+	MonthlyPayment float64                  `json:"monthly_payment"`    // 每月供款
+	TotalPayment   float64                  `json:"total_payment"`      // 总还款额
+	TotalInterest  float64                  `json:"total_interest"`     // 总利息
+	Affordability  MortgageAffordability    `json:"affordability"`      // 供款能力测试
+	Schedule       []MortgageSchedulePeriod `json:"schedule,omitempty"` // 供款表
+	Notes          []string                 `json:"notes,omitempty"`    // 适用规则说明
+}
+
+// MortgageAffordability 供款与入息比率（DSR）及压力测试
+type MortgageAffordability struct {
+	DSRLimit              float64  `json:"dsr_limit"`                // 供款与入息比率上限 (%)
+	StressedRate          float64  `json:"stressed_rate"`            // 压力测试利率 (%)
+	StressedPayment       float64  `json:"stressed_payment"`         // 压力测试下每月供款
+	StressedDSRLimit      float64  `json:"stressed_dsr_limit"`       // 压力测试下供款与入息比率上限 (%)
+	RequiredMonthlyIncome float64  `json:"required_monthly_income"`  // 通过测试所需最低月入
+	MonthlyIncome         float64  `json:"monthly_income,omitempty"` // 家庭月入
+	DSR                   *float64 `json:"dsr,omitempty"`            // 供款与入息比率 (%)
+	StressedDSR           *float64 `json:"stressed_dsr,omitempty"`   // 压力测试下供款与入息比率 (%)
+	Passed                *bool    `json:"passed,omitempty"`         // 是否通过（提供月入时返回）
+}
+
+// MortgageSchedulePeriod 供款表（按月或按年汇总）
+type MortgageSchedulePeriod struct {
+	Period    int     `json:"period"`    // 期数（月或年）
+	Payment   float64 `json:"payment"`   // 供款
+	Principal float64 `json:"principal"` // 本金
+	Interest  float64 `json:"interest"`  // 利息
+	Balance   float64 `json:"balance"`   // 期末结欠
+}
+
+// MortgageEstimate 房产详情中的按揭估算（默认利率、年期及最高成数）
+type MortgageEstimate struct {
+	LoanAmount            float64 `json:"loan_amount"`
+	DownPayment           float64 `json:"down_payment"`
+	LTV                   float64 `json:"ltv"`
+	AnnualRate            float64 `json:"annual_rate"`
+	TenureYears           int     `json:"tenure_years"`
+	MonthlyPayment        float64 `json:"monthly_payment"`
+	TotalInterest         float64 `json:"total_interest"`
+	RequiredMonthlyIncome float64 `json:"required_monthly_income"`
+}
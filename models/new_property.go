@@ -54,8 +54,11 @@ type NewPropertyImage struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
 	NewPropertyID uint      `gorm:"not null;index" json:"new_property_id"`
 	ImageURL      string    `gorm:"size:500;not null" json:"image_url"`
-	ImageType     string    `gorm:"size:20;not null" json:"image_type"` // exterior=外观, interior=室内示范单位, facilities=设施, floorplan=户型图, location=位置图
+	ObjectKey     string    `gorm:"size:500;index" json:"object_key,omitempty"` // 存储对象键（上传图片，外部URL 为空）
+	ThumbnailURL  string    `gorm:"size:500" json:"thumbnail_url,omitempty"`    // 缩略图URL
+	ImageType     string    `gorm:"size:20;not null" json:"image_type"`         // exterior=外观, interior=室内示范单位, facilities=设施, floorplan=户型图, location=位置图
 	Title         string    `gorm:"size:200" json:"title,omitempty"`
+	IsCover       bool      `gorm:"default:false" json:"is_cover"` // 是否为封面图
 	SortOrder     int       `gorm:"not null;default:0" json:"sort_order"`
 	CreatedAt     time.Time `json:"created_at"`
 }
@@ -80,6 +83,8 @@ type NewPropertyLayout struct {
 	FloorplanURL  string    `gorm:"size:500" json:"floorplan_url,omitempty"`    // 户型图URL
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+
+	SalesSummary *LayoutSalesSummary `gorm:"-" json:"sales_summary,omitempty"` // 成交概况（按成交纪录计算）
 }
 
 func (NewPropertyLayout) TableName() string {
@@ -93,13 +98,108 @@ type ListNewPropertiesRequest struct {
 	DistrictID         *uint    `form:"district_id"`                                                            // 地区ID
 	Status             *string  `form:"status" binding:"omitempty,oneof=upcoming presale selling completed"`   // 状态
 	Developer          *string  `form:"developer"`                                                              // 开发商
-	MinPrice           *float64 `form:"min_price" binding:"omitempty,gt=0"`                                     // 最低价格
-	MaxPrice           *float64 `form:"max_price" binding:"omitempty,gt=0"`                                     // 最高价格
+	MinPrice           *float64 `form:"min_price" binding:"omitempty,gt=0"`                                     // 最低价格（按户型售价）
+	MaxPrice           *float64 `form:"max_price" binding:"omitempty,gt=0"`                                     // 最高价格（按户型售价）
 	PrimarySchoolNet   *string  `form:"primary_school_net"`                                                     // 小学校网
 	SecondarySchoolNet *string  `form:"secondary_school_net"`                                                   // 中学校网
 	IsFeatured         *bool    `form:"is_featured"`                                                            // 是否精选
 	Page               int      `form:"page,default=1" binding:"min=1"`                                         // 页码
 	PageSize           int      `form:"page_size,default=20" binding:"min=1,max=100"`                           // 每页数量
+
+	// 户型筛选：新盘须至少有一个户型同时满足价格及以下条件，匹配的户型随列表返回
+	Bedrooms        *int     `form:"bedrooms" binding:"omitempty,min=0,max=10"`   // 房间数（0 为开放式）
+	MinSaleableArea *float64 `form:"min_saleable_area" binding:"omitempty,gt=0"`  // 最小实用面积（平方尺）
+	MaxPricePerSqft *float64 `form:"max_price_per_sqft" binding:"omitempty,gt=0"` // 最高实用呎价
+
+	// 排序：price/price_per_sqft 按匹配户型的最低售价/呎价，saleable_area 按匹配户型的最大实用面积；未指定时精选优先
+	ExpectedCompletionBefore *string `form:"expected_completion_before" binding:"omitempty,datetime=2006-01-02"`                                  // 预计落成日期不迟于
+	SortBy                   string  `form:"sort_by" binding:"omitempty,oneof=price price_per_sqft saleable_area expected_completion created_at"` // 排序字段
+	SortOrder                string  `form:"sort_order" binding:"omitempty,oneof=asc desc"`                                                       // 排序方向（默认 asc，created_at 默认 desc）
+}
+
+// HasLayoutFilter 是否包含户型筛选条件
+func (f *ListNewPropertiesRequest) HasLayoutFilter() bool {
+	return f.MinPrice != nil || f.MaxPrice != nil || f.Bedrooms != nil || f.MinSaleableArea != nil || f.MaxPricePerSqft != nil
+}
+
+// CreateNewPropertyRequest 创建新盘请求（管理员）
+type CreateNewPropertyRequest struct {
+	Name               string `json:"name" binding:"required,max=200"`
+	NameEn             string `json:"name_en" binding:"omitempty,max=200"`
+	Address            string `json:"address" binding:"required,max=500"`
+	DistrictID         uint   `json:"district_id" binding:"required"`
+	Status             string `json:"status" binding:"required,oneof=upcoming presale selling completed"`
+	UnitsForSale       int    `json:"units_for_sale" binding:"omitempty,min=0"`
+	UnitsSold          int    `json:"units_sold" binding:"omitempty,min=0"`
+	Developer          string `json:"developer" binding:"required,max=200"`
+	ManagementCompany  string `json:"management_company" binding:"omitempty,max=200"`
+	TotalUnits         int    `json:"total_units" binding:"required,min=1"`
+	TotalBlocks        int    `json:"total_blocks" binding:"required,min=1"`
+	MaxFloors          int    `json:"max_floors" binding:"required,min=1"`
+	PrimarySchoolNet   string `json:"primary_school_net" binding:"omitempty,max=50"`
+	SecondarySchoolNet string `json:"secondary_school_net" binding:"omitempty,max=50"`
+	WebsiteURL         string `json:"website_url" binding:"omitempty,url,max=500"`
+	SalesOfficeAddress string `json:"sales_office_address" binding:"omitempty,max=500"`
+	SalesPhone         string `json:"sales_phone" binding:"omitempty,max=50"`
+	ExpectedCompletion string `json:"expected_completion" binding:"omitempty,datetime=2006-01-02"` // 预计落成日期
+	OccupationDate     string `json:"occupation_date" binding:"omitempty,datetime=2006-01-02"`     // 入伙日期
+	Description        string `json:"description"`
+	SortOrder          int    `json:"sort_order"`
+	IsFeatured         bool   `json:"is_featured"`
+}
+
+// UpdateNewPropertyRequest 更新新盘请求（管理员，日期传空字符串表示清除）
+type UpdateNewPropertyRequest struct {
+	Name               *string `json:"name" binding:"omitempty,max=200"`
+	NameEn             *string `json:"name_en" binding:"omitempty,max=200"`
+	Address            *string `json:"address" binding:"omitempty,max=500"`
+	DistrictID         *uint   `json:"district_id"`
+	Status             *string `json:"status" binding:"omitempty,oneof=upcoming presale selling completed"`
+	UnitsForSale       *int    `json:"units_for_sale" binding:"omitempty,min=0"` // 已公布价单后由单位销售状态汇总，不可修改
+	UnitsSold          *int    `json:"units_sold" binding:"omitempty,min=0"`     // 已公布价单后由单位销售状态汇总，不可修改
+	Developer          *string `json:"developer" binding:"omitempty,max=200"`
+	ManagementCompany  *string `json:"management_company" binding:"omitempty,max=200"`
+	TotalUnits         *int    `json:"total_units" binding:"omitempty,min=1"`
+	TotalBlocks        *int    `json:"total_blocks" binding:"omitempty,min=1"`
+	MaxFloors          *int    `json:"max_floors" binding:"omitempty,min=1"`
+	PrimarySchoolNet   *string `json:"primary_school_net" binding:"omitempty,max=50"`
+	SecondarySchoolNet *string `json:"secondary_school_net" binding:"omitempty,max=50"`
+	WebsiteURL         *string `json:"website_url" binding:"omitempty,max=500"`
+	SalesOfficeAddress *string `json:"sales_office_address" binding:"omitempty,max=500"`
+	SalesPhone         *string `json:"sales_phone" binding:"omitempty,max=50"`
+	ExpectedCompletion *string `json:"expected_completion"` // 预计落成日期 YYYY-MM-DD
+	OccupationDate     *string `json:"occupation_date"`     // 入伙日期 YYYY-MM-DD
+	Description        *string `json:"description"`
+	SortOrder          *int    `json:"sort_order"`
+	IsFeatured         *bool   `json:"is_featured"`
+}
+
+// CreateNewPropertyLayoutRequest 创建新盘户型请求（管理员）
+type CreateNewPropertyLayoutRequest struct {
+	UnitType       string  `json:"unit_type" binding:"required,max=50"`
+	Bedrooms       int     `json:"bedrooms" binding:"min=0"`
+	Bathrooms      int     `json:"bathrooms" binding:"omitempty,min=0"`
+	SaleableArea   float64 `json:"saleable_area" binding:"required,gt=0"`
+	GrossArea      float64 `json:"gross_area" binding:"omitempty,gt=0"`
+	MinPrice       float64 `json:"min_price" binding:"required,gt=0"`
+	MaxPrice       float64 `json:"max_price" binding:"omitempty,gt=0"`
+	PricePerSqft   float64 `json:"price_per_sqft" binding:"omitempty,gt=0"` // 不填按最低售价 / 实用面积计算
+	AvailableUnits int     `json:"available_units" binding:"min=0"`
+	FloorplanURL   string  `json:"floorplan_url" binding:"omitempty,url,max=500"`
+}
+
+// UpdateNewPropertyLayoutRequest 更新新盘户型请求（管理员）
+type UpdateNewPropertyLayoutRequest struct {
+	UnitType       *string  `json:"unit_type" binding:"omitempty,max=50"`
+	Bedrooms       *int     `json:"bedrooms" binding:"omitempty,min=0"`
+	Bathrooms      *int     `json:"bathrooms" binding:"omitempty,min=0"`
+	SaleableArea   *float64 `json:"saleable_area" binding:"omitempty,gt=0"`
+	GrossArea      *float64 `json:"gross_area" binding:"omitempty,min=0"`
+	MinPrice       *float64 `json:"min_price" binding:"omitempty,gt=0"`
+	MaxPrice       *float64 `json:"max_price" binding:"omitempty,min=0"`
+	PricePerSqft   *float64 `json:"price_per_sqft" binding:"omitempty,min=0"` // 传 0 表示按最低售价 / 实用面积重新计算
+	AvailableUnits *int     `json:"available_units" binding:"omitempty,min=0"`
+	FloorplanURL   *string  `json:"floorplan_url" binding:"omitempty,max=500"`
 }
 
 // ============ Response DTO ============
@@ -122,6 +222,8 @@ type NewPropertyResponse struct {
 	CoverImage         string     `json:"cover_image,omitempty"`
 	District           *District  `json:"district,omitempty"`
 	CreatedAt          time.Time  `json:"created_at"`
+
+	MatchingLayouts []NewPropertyLayout `json:"matching_layouts,omitempty"` // 符合户型筛选条件的户型（仅在有户型筛选时返回）
 }
 
 // NewPropertyDetailResponse 新盘详情响应
@@ -154,6 +256,8 @@ type NewPropertyDetailResponse struct {
 	Layouts             []NewPropertyLayout   `json:"layouts,omitempty"`
 	CreatedAt           time.Time             `json:"created_at"`
 	UpdatedAt           time.Time             `json:"updated_at"`
+
+	SalesProgress *NewPropertySalesProgress `json:"sales_progress,omitempty"` // 销售进度（按成交纪录计算）
 }
 
 // PaginatedNewPropertiesResponse 分页新盘响应
@@ -185,14 +289,22 @@ func (np *NewProperty) ToNewPropertyResponse() *NewPropertyResponse {
 		CreatedAt:          np.CreatedAt,
 	}
 
-	// 获取封面图
+	// 获取封面图（优先使用指定封面，其次外观图）
 	if len(np.Images) > 0 {
 		for _, img := range np.Images {
-			if img.ImageType == "exterior" {
+			if img.IsCover {
 				resp.CoverImage = img.ImageURL
 				break
 			}
 		}
+		if resp.CoverImage == "" {
+			for _, img := range np.Images {
+				if img.ImageType == "exterior" {
+					resp.CoverImage = img.ImageURL
+					break
+				}
+			}
+		}
 		if resp.CoverImage == "" {
 			resp.CoverImage = np.Images[0].ImageURL
 		}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+)
+
+// 一手成交状态（成交纪录册）
+const (
+	NewPropertyTxPASP       = "pasp"       // 已签临时买卖合约
+	NewPropertyTxASP        = "asp"        // 已签正式买卖合约
+	NewPropertyTxTerminated = "terminated" // 已终止（单位恢复可售）
+)
+
+// ============ GORM Model ============
+
+// NewPropertyTransaction 新盘成交纪录（每宗一手成交一条，单位信息按成交时记录）
+type NewPropertyTransaction struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	NewPropertyID    uint       `gorm:"not null;index" json:"new_property_id"`
+	UnitID           uint       `gorm:"not null;index;uniqueIndex:idx_new_property_tx_active_unit,where:status <> 'terminated'" json:"unit_id"` // 新盘单位ID（未终止的成交每个单位只能有一笔）
+	LayoutID         *uint      `gorm:"index" json:"layout_id,omitempty"`                                                                       // 所属户型
+	Block            string     `gorm:"size:50" json:"block"`                                                                                   // 座数
+	Floor            string     `gorm:"size:20;not null" json:"floor"`                                                                          // 楼层
+	Flat             string     `gorm:"size:20;not null" json:"flat"`                                                                           // 单位
+	SaleableArea     float64    `gorm:"not null" json:"saleable_area"`                                                                          // 实用面积（平方尺）
+	ListPrice        float64    `gorm:"not null" json:"list_price"`                                                                             // 成交时价单售价
+	TransactionPrice float64    `gorm:"not null" json:"transaction_price"`                                                                      // 成交价
+	PricePerSqft     float64    `gorm:"not null" json:"price_per_sqft"`                                                                         // 成交实用呎价
+	PaymentPlan      string     `gorm:"size:200" json:"payment_plan,omitempty"`                                                                 // 付款办法
+	TransactionDate  time.Time  `gorm:"not null;index" json:"transaction_date"`                                                                 // 临时买卖合约日期
+	Status           string     `gorm:"size:20;not null;index" json:"status"`                                                                   // pasp, asp, terminated
+	TerminatedAt     *time.Time `json:"terminated_at,omitempty"`                                                                                // 终止时间
+	Remarks          string     `gorm:"type:text" json:"remarks,omitempty"`                                                                     // 备注
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func (NewPropertyTransaction) TableName() string {
+	return "new_property_transactions"
+}
+
+// ============ Request DTO ============
+
+// RecordNewPropertyTransactionRequest 登记一手成交请求（单位随即标记为已售）
+type RecordNewPropertyTransactionRequest struct {
+	UnitID           uint    `json:"unit_id" binding:"required"`
+	TransactionPrice float64 `json:"transaction_price" binding:"required,gt=0"`
+	TransactionDate  string  `json:"transaction_date" binding:"required,datetime=2006-01-02"`
+	PaymentPlan      string  `json:"payment_plan" binding:"omitempty,max=200"`
+	Status           string  `json:"status" binding:"omitempty,oneof=pasp asp"` // 默认 pasp
+	Remarks          string  `json:"remarks"`
+}
+
+// UpdateNewPropertyTransactionRequest 更新一手成交请求（终止后不可再修改）
+type UpdateNewPropertyTransactionRequest struct {
+	Status      *string `json:"status" binding:"omitempty,oneof=pasp asp terminated"`
+	PaymentPlan *string `json:"payment_plan" binding:"omitempty,max=200"`
+	Remarks     *string `json:"remarks"`
+}
+
+// ListNewPropertyTransactionsRequest 成交纪录列表请求
+type ListNewPropertyTransactionsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pasp asp terminated"`
+	LayoutID *uint  `form:"layout_id"`
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// ============ Response DTO ============
+
+// PaginatedNewPropertyTransactionsResponse 分页成交纪录响应
+type PaginatedNewPropertyTransactionsResponse struct {
+	Data       []NewPropertyTransaction `json:"data"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+	TotalPages int                      `json:"total_pages"`
+}
+
+// LayoutSalesSummary 户型成交概况（不含已终止成交）
+type LayoutSalesSummary struct {
+	TransactionCount    int     `json:"transaction_count"`     // 成交宗数
+	AvgTransactionPrice float64 `json:"avg_transaction_price"` // 平均成交价
+	AvgListPrice        float64 `json:"avg_list_price"`        // 成交单位平均价单售价
+	AvgPricePerSqft     float64 `json:"avg_price_per_sqft"`    // 平均成交呎价
+	PriceRealised       float64 `json:"price_realised"`        // 成交价相对价单售价 (%)
+}
+
+// SalesProgressPoint 销售进度（按月）
+type SalesProgressPoint struct {
+	Month                   string  `json:"month"`                      // 月份 (YYYY-MM)
+	ReleasedUnits           int     `json:"released_units"`             // 累计已公布价单单位数
+	UnitsSold               int     `json:"units_sold"`                 // 当月成交宗数
+	CumulativeSold          int     `json:"cumulative_sold"`            // 累计成交宗数
+	SellThroughRate         float64 `json:"sell_through_rate"`          // 累计成交占总伙数 (%)
+	ReleasedSellThroughRate float64 `json:"released_sell_through_rate"` // 累计成交占已公布单位 (%)
+}
+
+// NewPropertySalesProgress 新盘销售进度（自首份价单或首宗成交起按月统计）
+type NewPropertySalesProgress struct {
+	LaunchDate      *time.Time           `json:"launch_date,omitempty"` // 开售日期
+	TotalUnits      int                  `json:"total_units"`
+	ReleasedUnits   int                  `json:"released_units"`    // 已公布价单单位数
+	UnitsSold       int                  `json:"units_sold"`        // 成交纪录册成交宗数
+	SellThroughRate float64              `json:"sell_through_rate"` // 累计成交占总伙数 (%)
+	Timeline        []SalesProgressPoint `json:"timeline"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// ============ GORM Model ============
+
+// Notification 站内通知模型
+type Notification struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`              // 接收用户ID
+	Type       string     `gorm:"size:50;not null;index" json:"type"`         // listing_expiring=即将到期, listing_expired=已过期
+	Title      string     `gorm:"size:255;not null" json:"title"`             // 通知标题
+	Content    string     `gorm:"type:text" json:"content,omitempty"`         // 通知内容
+	EntityType string     `gorm:"size:50;index" json:"entity_type,omitempty"` // 关联实体类型：property, furniture
+	EntityID   uint       `gorm:"index" json:"entity_id,omitempty"`           // 关联实体ID
+	IsRead     bool       `gorm:"default:false;index" json:"is_read"`         // 是否已读
+	ReadAt     *time.Time `json:"read_at,omitempty"`                          // 阅读时间
+	CreatedAt  time.Time  `gorm:"index" json:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// ============ Request DTO ============
+
+// ListNotificationsRequest 获取通知列表请求
+type ListNotificationsRequest struct {
+	UnreadOnly bool `form:"unread_only"`                                  // 只看未读
+	Page       int  `form:"page,default=1" binding:"min=1"`               // 页码
+	PageSize   int  `form:"page_size,default=20" binding:"min=1,max=100"` // 每页数量
+}
+
+// ============ Response DTO ============
+
+// PaginatedNotificationsResponse 分页通知响应
+type PaginatedNotificationsResponse struct {
+	Data        []Notification `json:"data"`
+	UnreadCount int64          `json:"unread_count"`
+	Total       int64          `json:"total"`
+	Page        int            `json:"page"`
+	PageSize    int            `json:"page_size"`
+	TotalPages  int            `json:"total_pages"`
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"time"
+)
+
+// 新盘单位销售状态
+const (
+	UnitStatusAvailable = "available" // 可售
+	UnitStatusReserved  = "reserved"  // 已订（临时买卖合约未转正式）
+	UnitStatusSold      = "sold"      // 已售
+)
+
+// ============ GORM Model ============
+
+// PriceList 新盘价单（一手住宅按批次公布，同一新盘的价单编号唯一）
+type PriceList struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	NewPropertyID uint      `gorm:"not null;uniqueIndex:idx_price_list_no" json:"new_property_id"`
+	PriceListNo   string    `gorm:"size:20;not null;uniqueIndex:idx_price_list_no" json:"price_list_no"` // 价单编号（如 1、1A、2）
+	ReleaseDate   time.Time `gorm:"not null;index" json:"release_date"`                                  // 公布日期
+	Remarks       string    `gorm:"type:text" json:"remarks,omitempty"`                                  // 备注（付款办法、折扣等）
+	FileName      string    `gorm:"size:255" json:"file_name,omitempty"`                                 // 导入文件名
+	UnitCount     int       `gorm:"not null;default:0" json:"unit_count"`                                // 单位数
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// 关联
+	Items []PriceListItem `gorm:"foreignKey:PriceListID" json:"items,omitempty"`
+}
+
+func (PriceList) TableName() string {
+	return "price_lists"
+}
+
+// PriceListItem 价单单位行（价单公布时的面积及售价，不随后续价单变化）
+type PriceListItem struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	PriceListID  uint    `gorm:"not null;index" json:"price_list_id"`
+	UnitID       uint    `gorm:"not null;index" json:"unit_id"`  // 新盘单位ID
+	Block        string  `gorm:"size:50" json:"block"`           // 座数
+	Floor        string  `gorm:"size:20;not null" json:"floor"`  // 楼层
+	Flat         string  `gorm:"size:20;not null" json:"flat"`   // 单位
+	SaleableArea float64 `gorm:"not null" json:"saleable_area"`  // 实用面积（平方尺）
+	ListPrice    float64 `gorm:"not null" json:"list_price"`     // 售价（港币）
+	PricePerSqft float64 `gorm:"not null" json:"price_per_sqft"` // 实用呎价
+}
+
+func (PriceListItem) TableName() string {
+	return "price_list_items"
+}
+
+// NewPropertyUnit 新盘单位（按座数/楼层/单位唯一，记录最新价单售价及销售状态）
+type NewPropertyUnit struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	NewPropertyID uint       `gorm:"not null;uniqueIndex:idx_new_property_unit" json:"new_property_id"`
+	Block         string     `gorm:"size:50;uniqueIndex:idx_new_property_unit" json:"block"`          // 座数
+	Floor         string     `gorm:"size:20;not null;uniqueIndex:idx_new_property_unit" json:"floor"` // 楼层
+	Flat          string     `gorm:"size:20;not null;uniqueIndex:idx_new_property_unit" json:"flat"`  // 单位
+	LayoutID      *uint      `gorm:"index" json:"layout_id,omitempty"`                                // 所属户型（按户型名称或房间数匹配）
+	UnitType      string     `gorm:"size:50" json:"unit_type,omitempty"`                              // 户型
+	Bedrooms      int        `json:"bedrooms"`                                                        // 房间数
+	SaleableArea  float64    `gorm:"not null" json:"saleable_area"`                                   // 实用面积（平方尺）
+	ListPrice     float64    `gorm:"not null" json:"list_price"`                                      // 最新售价（港币）
+	PricePerSqft  float64    `gorm:"not null" json:"price_per_sqft"`                                  // 最新实用呎价
+	PriceListID   uint       `gorm:"not null;index" json:"price_list_id"`                             // 最新所属价单
+	Status        string     `gorm:"size:20;not null;index" json:"status"`                            // available, reserved, sold
+	SoldAt        *time.Time `json:"sold_at,omitempty"`                                               // 售出时间
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (NewPropertyUnit) TableName() string {
+	return "new_property_units"
+}
+
+// SalesArrangement 新盘销售安排（每次推售的日期、地点及推售单位）
+type SalesArrangement struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	NewPropertyID uint       `gorm:"not null;index" json:"new_property_id"`
+	PriceListID   *uint      `gorm:"index" json:"price_list_id,omitempty"`      // 关联价单
+	SaleStartAt   time.Time  `gorm:"not null;index" json:"sale_start_at"`       // 开售时间
+	SaleEndAt     *time.Time `json:"sale_end_at,omitempty"`                     // 结束时间
+	Location      string     `gorm:"size:500" json:"location,omitempty"`        // 销售地点
+	UnitsOffered  int        `gorm:"not null;default:0" json:"units_offered"`   // 推售单位数
+	UnitsDetail   string     `gorm:"type:text" json:"units_detail,omitempty"`   // 推售单位说明
+	SellingMethod string     `gorm:"type:text" json:"selling_method,omitempty"` // 销售方式（抽签、先到先得等）
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (SalesArrangement) TableName() string {
+	return "sales_arrangements"
+}
+
+// ============ Request DTO ============
+
+// ImportPriceListRequest 导入价单请求（multipart/form-data，文件字段名 file）
+// 文件列：block, floor, flat, unit_type, bedrooms, saleable_area, list_price, price_per_sqft, status
+type ImportPriceListRequest struct {
+	PriceListNo string `form:"price_list_no" binding:"required,max=20"`             // 价单编号
+	ReleaseDate string `form:"release_date" binding:"required,datetime=2006-01-02"` // 公布日期
+	Remarks     string `form:"remarks"`                                             // 备注
+}
+
+// ListNewPropertyUnitsRequest 新盘单位列表请求
+type ListNewPropertyUnitsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=available reserved sold"`
+	Block    string `form:"block"`
+	LayoutID *uint  `form:"layout_id"`
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=50" binding:"min=1,max=200"`
+}
+
+// UpdateUnitStatusRequest 批量更新单位销售状态请求
+type UpdateUnitStatusRequest struct {
+	UnitIDs []uint `json:"unit_ids" binding:"required,min=1,max=500,dive,gt=0"`
+	Status  string `json:"status" binding:"required,oneof=available reserved sold"`
+}
+
+// SalesArrangementRequest 创建/更新销售安排请求
+type SalesArrangementRequest struct {
+	PriceListID   *uint      `json:"price_list_id"`
+	SaleStartAt   time.Time  `json:"sale_start_at" binding:"required"`
+	SaleEndAt     *time.Time `json:"sale_end_at"`
+	Location      string     `json:"location" binding:"omitempty,max=500"`
+	UnitsOffered  int        `json:"units_offered" binding:"min=0"`
+	UnitsDetail   string     `json:"units_detail"`
+	SellingMethod string     `json:"selling_method"`
+}
+
+// ============ Response DTO ============
+
+// PriceListImportResponse 价单导入结果
+type PriceListImportResponse struct {
+	PriceList    PriceList `json:"price_list"`
+	CreatedUnits int       `json:"created_units"`  // 新增单位数
+	UpdatedUnits int       `json:"updated_units"`  // 更新售价的单位数
+	UnitsForSale int       `json:"units_for_sale"` // 汇总后在售单位数
+	UnitsSold    int       `json:"units_sold"`     // 汇总后已售单位数
+}
+
+// UnitStatusResponse 单位状态更新结果
+type UnitStatusResponse struct {
+	Updated      int `json:"updated"`        // 状态有变化的单位数
+	UnitsForSale int `json:"units_for_sale"` // 汇总后在售单位数
+	UnitsSold    int `json:"units_sold"`     // 汇总后已售单位数
+}
+
+// PaginatedNewPropertyUnitsResponse 分页新盘单位响应
+type PaginatedNewPropertyUnitsResponse struct {
+	Data       []NewPropertyUnit `json:"data"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
@@ -28,18 +28,23 @@ type Property struct {
 	PrimarySchool  string         `gorm:"size:50;index" json:"primary_school_net,omitempty"`            // 小学校网
 	SecondarySchool string        `gorm:"size:50;index" json:"secondary_school_net,omitempty"`          // 中学校网
 	PropertyType   string         `gorm:"size:50;not null;index" json:"property_type"`                  // 物业类型
-	Status         string         `gorm:"size:20;not null;default:'available';index" json:"status"`     // 状态
+	Status         string         `gorm:"size:20;not null;default:'available';index" json:"status"`     // 状态（draft=草稿, pending_review=待审核, rejected=审核未通过, scheduled=定时发布, available=在架）
 	PublisherID    uint           `gorm:"not null;index" json:"publisher_id"`                           // 发布者ID
 	PublisherType  string         `gorm:"size:20;not null" json:"publisher_type"`                       // individual=个人, agency=代理公司
 	AgentID        *uint          `gorm:"index" json:"agent_id,omitempty"`                              // 负责地产代理ID
 	ViewCount      int            `gorm:"default:0" json:"view_count"`                                  // 浏览次数
 	FavoriteCount  int            `gorm:"default:0" json:"favorite_count"`                              // 收藏次数
-	PublishedAt    *time.Time     `gorm:"index" json:"published_at,omitempty"`                          // 发布时间
+	PublishedAt    *time.Time     `gorm:"index" json:"published_at,omitempty"`                          // 发布时间（定时发布时为计划发布时间）
 	ExpiredAt      *time.Time     `gorm:"index" json:"expired_at,omitempty"`                            // 过期时间
+	RenewalCount   int            `gorm:"default:0" json:"renewal_count"`                               // 续期次数
+	ReminderSentAt *time.Time     `json:"-"`                                                            // 到期提醒发送时间
+	Fingerprint    string         `gorm:"size:40;not null;default:'';index" json:"-"`                   // 重复检测指纹（地址、大厦、楼层、面积、刊登类型）
 	CreatedAt      time.Time      `gorm:"index" json:"created_at"`                                      // 创建时间
 	UpdatedAt      time.Time      `json:"updated_at"`                                                   // 更新时间
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`                                               // 软删除时间
 
+	UnitID *uint `gorm:"index" json:"unit_id,omitempty"` // 屋苑单位目录ID（面积、座向及楼层取自单位目录）
+
 	// 关联
 	District *District `gorm:"foreignKey:DistrictID" json:"district,omitempty"`
 	Images   []PropertyImage `gorm:"foreignKey:PropertyID" json:"images,omitempty"`
@@ -51,12 +56,14 @@ func (Property) TableName() string {
 
 // PropertyImage 房产图片
 type PropertyImage struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	PropertyID uint      `gorm:"not null;index" json:"property_id"`
-	ImageURL   string    `gorm:"size:500;not null" json:"image_url"`
-	ImageType  string    `gorm:"size:20;not null" json:"image_type"` // cover=封面, interior=室内, exterior=外观, floorplan=户型图
-	SortOrder  int       `gorm:"not null;default:0" json:"sort_order"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	PropertyID   uint      `gorm:"not null;index" json:"property_id"`
+	ImageURL     string    `gorm:"size:500;not null" json:"image_url"`
+	ObjectKey    string    `gorm:"size:500;index" json:"object_key,omitempty"` // 存储对象键（上传图片，外部URL 为空）
+	ThumbnailURL string    `gorm:"size:500" json:"thumbnail_url,omitempty"`    // 缩略图URL
+	ImageType    string    `gorm:"size:20;not null" json:"image_type"`         // cover=封面, interior=室内, exterior=外观, floorplan=户型图
+	SortOrder    int       `gorm:"not null;default:0" json:"sort_order"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 func (PropertyImage) TableName() string {
@@ -78,32 +85,38 @@ type ListPropertiesRequest struct {
 	BuildingName     *string  `form:"building_name"`                                         // 大厦名称
 	PrimarySchool    *string  `form:"primary_school_net"`                                    // 小学校网
 	SecondarySchool  *string  `form:"secondary_school_net"`                                  // 中学校网
-	Status           *string  `form:"status" binding:"omitempty,oneof=available pending sold cancelled"` // 状态
+	Status           *string  `form:"status" binding:"omitempty,oneof=available pending sold cancelled expired"` // 状态
 	SortBy           string   `form:"sort_by" binding:"omitempty,oneof=price_asc price_desc area_asc area_desc created_at_desc"` // 排序方式
+	CollapseDuplicates bool     `form:"collapse_duplicates"`                                 // 折叠重复刊登（同一单位只显示主刊登）
 	Page             int      `form:"page,default=1" binding:"min=1"`                        // 页码
 	PageSize         int      `form:"page_size,default=20" binding:"min=1,max=100"`          // 每页数量
 }
 
 // CreatePropertyRequest 创建房产请求
 type CreatePropertyRequest struct {
-	EstateNo        string   `json:"estate_no" binding:"omitempty,max=50"`                           // 楼盘编号
-	ListingType     string   `json:"listing_type" binding:"required,oneof=sale rent"`                // sale=出售, rent=出租
-	Title           string   `json:"title" binding:"required,max=255"`                               // 房产标题
-	Description     string   `json:"description" binding:"omitempty"`                                // 房产描述
-	Area            float64  `json:"area" binding:"required,gt=0"`                                   // 面积（平方尺）
-	Price           float64  `json:"price" binding:"required,gt=0"`                                  // 价格（港币）
-	Address         string   `json:"address" binding:"required,max=500"`                             // 详细地址
-	DistrictID      uint     `json:"district_id" binding:"required"`                                 // 所属地区ID
-	BuildingName    string   `json:"building_name" binding:"omitempty,max=200"`                      // 大厦/楼宇名称
-	Floor           string   `json:"floor" binding:"omitempty,max=20"`                               // 楼层
-	Orientation     string   `json:"orientation" binding:"omitempty,max=50"`                         // 座向
-	Bedrooms        int      `json:"bedrooms" binding:"required,min=0"`                              // 房间数
-	Bathrooms       int      `json:"bathrooms" binding:"omitempty,min=0"`                            // 浴室数
-	PrimarySchool   string   `json:"primary_school_net" binding:"omitempty,max=50"`                  // 小学校网
-	SecondarySchool string   `json:"secondary_school_net" binding:"omitempty,max=50"`                // 中学校网
-	PropertyType    string   `json:"property_type" binding:"required,max=50"`                        // 物业类型
-	AgentID         *uint    `json:"agent_id" binding:"omitempty"`                                   // 负责地产代理ID
-	ImageURLs       []string `json:"image_urls" binding:"omitempty,max=20,dive,url"`                 // 图片URL列表
+	EstateNo        string     `json:"estate_no" binding:"omitempty,max=50"`                  // 楼盘编号
+	ListingType     string     `json:"listing_type" binding:"required,oneof=sale rent"`       // sale=出售, rent=出租
+	Title           string     `json:"title" binding:"required,max=255"`                      // 房产标题
+	Description     string     `json:"description" binding:"omitempty"`                       // 房产描述
+	Area            float64    `json:"area" binding:"required_without=UnitID,omitempty,gt=0"` // 面积（平方尺），引用单位时可留空
+	Price           float64    `json:"price" binding:"required,gt=0"`                         // 价格（港币）
+	Address         string     `json:"address" binding:"required,max=500"`                    // 详细地址
+	DistrictID      uint       `json:"district_id" binding:"required"`                        // 所属地区ID
+	BuildingName    string     `json:"building_name" binding:"omitempty,max=200"`             // 大厦/楼宇名称
+	Floor           string     `json:"floor" binding:"omitempty,max=20"`                      // 楼层
+	Orientation     string     `json:"orientation" binding:"omitempty,max=50"`                // 座向
+	Bedrooms        int        `json:"bedrooms" binding:"required,min=0"`                     // 房间数
+	Bathrooms       int        `json:"bathrooms" binding:"omitempty,min=0"`                   // 浴室数
+	PrimarySchool   string     `json:"primary_school_net" binding:"omitempty,max=50"`         // 小学校网
+	SecondarySchool string     `json:"secondary_school_net" binding:"omitempty,max=50"`       // 中学校网
+	PropertyType    string     `json:"property_type" binding:"required,max=50"`               // 物业类型
+	AgentID         *uint      `json:"agent_id" binding:"omitempty"`                          // 负责地产代理ID
+	ImageURLs       []string   `json:"image_urls" binding:"omitempty,max=20,dive,url"`        // 外部图片URL列表
+	ImageKeys       []string   `json:"image_keys" binding:"omitempty,max=20"`                 // 已上传图片对象键列表（排在外部URL之前）
+	SaveAsDraft     bool       `json:"save_as_draft"`                                         // 保存为草稿（不提交审核）
+	PublishAt       *time.Time `json:"publish_at"`                                            // 定时发布时间（审核通过后到时自动上架，为空则审核通过即上架）
+
+	UnitID *uint `json:"unit_id"` // 屋苑单位目录ID（留空的面积、座向及楼层按单位填写，已填写的须与单位一致）
 }
 
 // UpdatePropertyRequest 更新房产请求
@@ -119,6 +132,11 @@ type UpdatePropertyRequest struct {
 	AgentID         *uint    `json:"agent_id"`
 }
 
+// PublishPropertyRequest 发布房产请求（publish_at 为空表示立即发布）
+type PublishPropertyRequest struct {
+	PublishAt *time.Time `json:"publish_at"` // 定时发布时间（须晚于当前时间）
+}
+
 // ============ Response DTO ============
 
 // PropertyResponse 房产响应（列表用）
@@ -141,6 +159,8 @@ type PropertyResponse struct {
 	District      *District  `json:"district,omitempty"`
 	PublishedAt   *time.Time `json:"published_at,omitempty"`
 	CreatedAt     time.Time  `json:"created_at"`
+
+	DuplicateCount int `json:"duplicate_count,omitempty"` // 同一单位的其他刊登数（折叠重复时返回）
 }
 
 // PropertyDetailResponse 房产详情响应
@@ -172,8 +192,13 @@ type PropertyDetailResponse struct {
 	Images          []PropertyImage `json:"images,omitempty"`
 	PublishedAt     *time.Time      `json:"published_at,omitempty"`
 	ExpiredAt       *time.Time      `json:"expired_at,omitempty"`
+	RenewalCount    int             `json:"renewal_count"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
+	UnitID          *uint           `json:"unit_id,omitempty"` // 屋苑单位目录ID
+
+	MortgageEstimate  *MortgageEstimate  `json:"mortgage_estimate,omitempty"`   // 按揭估算（仅出售房源）
+	StampDutyEstimate *StampDutyEstimate `json:"stamp_duty_estimate,omitempty"` // 印花税及交易费用估算（仅出售房源）
 }
 
 // PaginatedPropertiesResponse 分页房产响应
@@ -254,7 +279,9 @@ func (p *Property) ToPropertyDetailResponse() *PropertyDetailResponse {
 		Images:          p.Images,
 		PublishedAt:     p.PublishedAt,
 		ExpiredAt:       p.ExpiredAt,
+		RenewalCount:    p.RenewalCount,
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
+		UnitID:          p.UnitID,
 	}
 }
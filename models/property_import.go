@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+)
+
+// 导入任务状态
+const (
+	ImportStatusQueued    = "queued"    // 排队中
+	ImportStatusRunning   = "running"   // 处理中
+	ImportStatusCompleted = "completed" // 已完成（部分行失败时见 row_errors）
+	ImportStatusFailed    = "failed"    // 整体失败
+)
+
+// ImportRowError 导入行错误（行号从 1 开始，第 1 行为表头）
+type ImportRowError struct {
+	Row      int    `json:"row"`                 // 行号
+	EstateNo string `json:"estate_no,omitempty"` // 楼盘编号（外部编号）
+	Field    string `json:"field,omitempty"`     // 列名
+	Message  string `json:"message"`             // 错误信息
+}
+
+// ============ GORM Model ============
+
+// PropertyImport 房产批量导入任务
+// 文件首行为表头，列名与 CreatePropertyRequest 的 JSON 字段一致（见 GET /properties/import/template），
+// 以发布者自己的 estate_no 为外部编号：已存在则更新，不存在则创建，重复上传同一文件结果不变
+type PropertyImport struct {
+	ID             uint             `gorm:"primaryKey" json:"id"`
+	PublisherID    uint             `gorm:"not null;index" json:"publisher_id"`          // 发布者ID
+	PublisherType  string           `gorm:"size:20;not null" json:"publisher_type"`      // 发布者类型
+	FileName       string           `gorm:"size:255;not null" json:"file_name"`          // 原始文件名
+	FileHash       string           `gorm:"size:64;not null;index" json:"file_hash"`     // 文件内容 SHA-256
+	Format         string           `gorm:"size:10;not null" json:"format"`              // csv, xlsx
+	DryRun         bool             `gorm:"not null;default:false" json:"dry_run"`       // 仅校验，不写入
+	Status         string           `gorm:"size:20;not null;index" json:"status"`        // queued, running, completed, failed
+	TotalRows      int              `gorm:"not null;default:0" json:"total_rows"`        // 数据行数（不含表头和空行）
+	ProcessedRows  int              `gorm:"not null;default:0" json:"processed_rows"`    // 已处理行数
+	CreatedCount   int              `gorm:"not null;default:0" json:"created_count"`     // 新建数（试运行时为将新建数）
+	UpdatedCount   int              `gorm:"not null;default:0" json:"updated_count"`     // 更新数
+	UnchangedCount int              `gorm:"not null;default:0" json:"unchanged_count"`   // 无变化数
+	FailedCount    int              `gorm:"not null;default:0" json:"failed_count"`      // 失败行数
+	RowErrors      []ImportRowError `gorm:"type:text;serializer:json" json:"row_errors"` // 行错误（最多保留 500 条）
+	Error          string           `gorm:"type:text" json:"error,omitempty"`            // 整体失败原因
+	RequestID      string           `gorm:"size:64" json:"request_id,omitempty"`         // 上传请求ID（用于审计日志关联）
+	Header         []string         `gorm:"type:text;serializer:json" json:"-"`          // 表头
+	Rows           [][]string       `gorm:"type:text;serializer:json" json:"-"`          // 数据行（处理中断后可重新执行）
+	StartedAt      *time.Time       `json:"started_at,omitempty"`                        // 开始处理时间
+	FinishedAt     *time.Time       `json:"finished_at,omitempty"`                       // 完成时间
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+func (PropertyImport) TableName() string {
+	return "property_imports"
+}
+
+// ============ Request DTO ============
+
+// ImportPropertiesRequest 批量导入请求（multipart/form-data，文件字段名 file）
+type ImportPropertiesRequest struct {
+	DryRun bool `form:"dry_run"` // 仅校验并返回每行结果，不写入
+}
+
+// ListPropertyImportsRequest 导入任务列表请求
+type ListPropertyImportsRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// ============ Response DTO ============
+
+// PropertyImportResponse 导入任务响应
+type PropertyImportResponse struct {
+	PropertyImport
+	Progress int `json:"progress"` // 进度百分比
+}
+
+// PaginatedPropertyImportsResponse 分页导入任务响应
+type PaginatedPropertyImportsResponse struct {
+	Data       []PropertyImportResponse `json:"data"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+	TotalPages int                      `json:"total_pages"`
+}
+
+// ToPropertyImportResponse 转换为导入任务响应
+func (i *PropertyImport) ToPropertyImportResponse() *PropertyImportResponse {
+	progress := 100
+	if i.TotalRows > 0 && i.Status != ImportStatusCompleted {
+		progress = i.ProcessedRows * 100 / i.TotalRows
+	}
+	if i.Status == ImportStatusQueued {
+		progress = 0
+	}
+	return &PropertyImportResponse{PropertyImport: *i, Progress: progress}
+}
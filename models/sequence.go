@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// ============ GORM Model ============
+
+// IDSequence 业务编号序列（房产编号、家具编号等的并发安全分配）
+type IDSequence struct {
+	Name      string    `gorm:"primaryKey;size:100" json:"name"` // 序列名（含日期分段，如 furniture_no:20261018）
+	Value     int64     `gorm:"not null" json:"value"`           // 已分配的最大序号
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (IDSequence) TableName() string {
+	return "id_sequences"
+}
@@ -33,11 +33,16 @@ type ServicedApartment struct {
 	UpdatedAt    time.Time      `json:"updated_at"`                                             // 更新时间
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`                                         // 软删除时间
 
+	Inclusions ServicedApartmentInclusions `gorm:"embedded" json:"inclusions"`  // 租金包含项目
+	HouseRules ServicedApartmentHouseRules `gorm:"embedded" json:"house_rules"` // 住客守则
+
 	// 关联
 	District *District                    `gorm:"foreignKey:DistrictID" json:"district,omitempty"`
 	Company  *User                        `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
 	Images   []ServicedApartmentImage     `gorm:"foreignKey:ServicedApartmentID" json:"images,omitempty"`
 	Units    []ServicedApartmentUnit      `gorm:"foreignKey:ServicedApartmentID" json:"units,omitempty"`
+
+	Facilities []Facility `gorm:"many2many:serviced_apartment_facilities;" json:"facilities,omitempty"` // 设施（取自设施字典）
 }
 
 func (ServicedApartment) TableName() string {
@@ -73,8 +78,11 @@ type ServicedApartmentImage struct {
 	ServicedApartmentID *uint     `gorm:"index" json:"serviced_apartment_id,omitempty"` // 关联的服务式住宅ID（整体照片）
 	UnitID              *uint     `gorm:"index" json:"unit_id,omitempty"`               // 关联的房型ID（房型照片）
 	ImageURL            string    `gorm:"size:500;not null" json:"image_url"`
-	ImageType           string    `gorm:"size:20;not null" json:"image_type"` // exterior=外观, lobby=大堂, room=房间, bathroom=浴室, facilities=设施
+	ObjectKey           string    `gorm:"size:500;index" json:"object_key,omitempty"` // 存储对象键（上传图片，外部URL 为空）
+	ThumbnailURL        string    `gorm:"size:500" json:"thumbnail_url,omitempty"`    // 缩略图URL
+	ImageType           string    `gorm:"size:20;not null" json:"image_type"`         // exterior=外观, lobby=大堂, room=房间, bathroom=浴室, facilities=设施
 	Title               string    `gorm:"size:200" json:"title,omitempty"`
+	IsCover             bool      `gorm:"default:false" json:"is_cover"` // 是否为封面图
 	SortOrder           int       `gorm:"default:0" json:"sort_order"`
 	CreatedAt           time.Time `json:"created_at"`
 }
@@ -95,6 +103,19 @@ type ListServicedApartmentsRequest struct {
 	IsFeatured    *bool    `form:"is_featured"`                                                     // 是否精选
 	Page          int      `form:"page,default=1" binding:"min=1"`                                  // 页码
 	PageSize      int      `form:"page_size,default=20" binding:"min=1,max=100"`                    // 每页数量
+
+	// 入住日期筛选：只显示整段期间有房的住宅及房型，并按报价总额升序排列
+	CheckIn  string `form:"check_in" binding:"omitempty,datetime=2006-01-02"`  // 入住日期（须与退房日期同时提供）
+	CheckOut string `form:"check_out" binding:"omitempty,datetime=2006-01-02"` // 退房日期
+	Guests   *int   `form:"guests" binding:"omitempty,min=1"`                  // 入住人数
+
+	// 设施、租金包含项目及住客守则筛选
+	FacilityIDs       []uint `form:"facility_ids" binding:"omitempty,max=20"`                                           // 须具备全部设施（可重复传入）
+	MinCleaning       string `form:"min_cleaning" binding:"omitempty,oneof=monthly biweekly weekly twice_weekly daily"` // 清洁频率不低于
+	UtilitiesIncluded *bool  `form:"utilities_included"`                                                                // 包水电煤
+	InternetIncluded  *bool  `form:"internet_included"`                                                                 // 包上网
+	PetsAllowed       *bool  `form:"pets_allowed"`                                                                      // 可养宠物
+	SmokingAllowed    *bool  `form:"smoking_allowed"`                                                                   // 可吸烟
 }
 
 // CreateServicedApartmentRequest 创建服务式住宅请求
@@ -110,6 +131,10 @@ type CreateServicedApartmentRequest struct {
 	CheckInTime  string `json:"check_in_time" binding:"omitempty"`      // 入住时间
 	CheckOutTime string `json:"check_out_time" binding:"omitempty"`     // 退房时间
 	MinStayDays  int    `json:"min_stay_days" binding:"omitempty,min=1"`// 最少入住天数
+
+	FacilityIDs []uint                            `json:"facility_ids" binding:"omitempty,max=100"` // 设施ID列表
+	Inclusions  *ServicedApartmentInclusionsInput `json:"inclusions"`                               // 租金包含项目
+	HouseRules  *ServicedApartmentHouseRulesInput `json:"house_rules"`                              // 住客守则
 }
 
 // UpdateServicedApartmentRequest 更新服务式住宅请求
@@ -123,6 +148,10 @@ type UpdateServicedApartmentRequest struct {
 	CheckOutTime *string `json:"check_out_time"`
 	MinStayDays  *int    `json:"min_stay_days" binding:"omitempty,min=1"`
 	Status       *string `json:"status" binding:"omitempty,oneof=active inactive closed"`
+
+	FacilityIDs []uint                            `json:"facility_ids" binding:"omitempty,max=100"` // 设施ID列表（提供时覆盖更新）
+	Inclusions  *ServicedApartmentInclusionsInput `json:"inclusions"`                               // 租金包含项目（未提供的字段不变）
+	HouseRules  *ServicedApartmentHouseRulesInput `json:"house_rules"`                              // 住客守则（未提供的字段不变）
 }
 
 // ============ Response DTO ============
@@ -144,6 +173,12 @@ type ServicedApartmentResponse struct {
 	CoverImage    string    `json:"cover_image,omitempty"`
 	District      *District `json:"district,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
+
+	StayQuotes []StayQuoteSummary `json:"stay_quotes,omitempty"` // 按入住日期筛选时可订房型报价（总额升序）
+
+	Inclusions ServicedApartmentInclusions `json:"inclusions"`
+	HouseRules ServicedApartmentHouseRules `json:"house_rules"`
+	Facilities []Facility                  `json:"facilities,omitempty"`
 }
 
 // ServicedApartmentDetailResponse 服务式住宅详情响应
@@ -171,6 +206,10 @@ type ServicedApartmentDetailResponse struct {
 	Units         []ServicedApartmentUnit     `json:"units,omitempty"`
 	CreatedAt     time.Time                   `json:"created_at"`
 	UpdatedAt     time.Time                   `json:"updated_at"`
+
+	Inclusions ServicedApartmentInclusions `json:"inclusions"`
+	HouseRules ServicedApartmentHouseRules `json:"house_rules"`
+	Facilities []Facility                  `json:"facilities,omitempty"`
 }
 
 // PaginatedServicedApartmentsResponse 分页服务式住宅响应
@@ -180,6 +219,7 @@ type PaginatedServicedApartmentsResponse struct {
 	Page       int                         `json:"page"`
 	PageSize   int                         `json:"page_size"`
 	TotalPages int                         `json:"total_pages"`
+	Truncated  bool                        `json:"truncated,omitempty"` // 按入住日期筛选时符合条件的住宅超过报价上限，结果只含前一部分（请缩小筛选范围）
 }
 
 // ToServicedApartmentResponse 转换为服务式住宅响应
@@ -198,6 +238,9 @@ func (sa *ServicedApartment) ToServicedApartmentResponse() *ServicedApartmentRes
 		IsFeatured:    sa.IsFeatured,
 		District:      sa.District,
 		CreatedAt:     sa.CreatedAt,
+		Inclusions:    sa.Inclusions,
+		HouseRules:    sa.HouseRules,
+		Facilities:    sa.Facilities,
 	}
 
 	// 获取最低月租
@@ -211,14 +254,22 @@ func (sa *ServicedApartment) ToServicedApartmentResponse() *ServicedApartmentRes
 		resp.MinMonthlyPrice = minPrice
 	}
 
-	// 获取封面图
+	// 获取封面图（优先使用指定封面，其次外观图）
 	if len(sa.Images) > 0 {
 		for _, img := range sa.Images {
-			if img.ImageType == "exterior" {
+			if img.IsCover {
 				resp.CoverImage = img.ImageURL
 				break
 			}
 		}
+		if resp.CoverImage == "" {
+			for _, img := range sa.Images {
+				if img.ImageType == "exterior" {
+					resp.CoverImage = img.ImageURL
+					break
+				}
+			}
+		}
 		if resp.CoverImage == "" {
 			resp.CoverImage = sa.Images[0].ImageURL
 		}
@@ -253,5 +304,8 @@ func (sa *ServicedApartment) ToServicedApartmentDetailResponse() *ServicedApartm
 		Units:         sa.Units,
 		CreatedAt:     sa.CreatedAt,
 		UpdatedAt:     sa.UpdatedAt,
+		Inclusions:    sa.Inclusions,
+		HouseRules:    sa.HouseRules,
+		Facilities:    sa.Facilities,
 	}
 }
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+)
+
+// CleaningFrequencies 清洁频率（由低到高）
+var CleaningFrequencies = []string{"none", "monthly", "biweekly", "weekly", "twice_weekly", "daily"}
+
+// ============ GORM Model ============
+
+// ServicedApartmentFacility 服务式住宅设施关联表（设施取自 Facility 字典）
+type ServicedApartmentFacility struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	ServicedApartmentID uint      `gorm:"not null;index;uniqueIndex:idx_serviced_apartment_facility" json:"serviced_apartment_id"`
+	FacilityID          uint      `gorm:"not null;index;uniqueIndex:idx_serviced_apartment_facility" json:"facility_id"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+func (ServicedApartmentFacility) TableName() string {
+	return "serviced_apartment_facilities"
+}
+
+// ServicedApartmentInclusions 租金包含项目（嵌入 serviced_apartments 表）
+type ServicedApartmentInclusions struct {
+	CleaningFrequency string `gorm:"size:20;not null;default:'none';index" json:"cleaning_frequency"` // none, monthly, biweekly, weekly, twice_weekly, daily
+	UtilitiesIncluded bool   `gorm:"default:false;index" json:"utilities_included"`                   // 包水电煤
+	InternetIncluded  bool   `gorm:"default:false;index" json:"internet_included"`                    // 包上网
+	RatesIncluded     bool   `gorm:"default:false" json:"rates_included"`                             // 包差饷及管理费
+	LinenIncluded     bool   `gorm:"default:false" json:"linen_included"`                             // 提供床单毛巾
+}
+
+// ServicedApartmentHouseRules 住客守则（嵌入 serviced_apartments 表）
+type ServicedApartmentHouseRules struct {
+	PetsAllowed            bool   `gorm:"default:false;index" json:"pets_allowed"`       // 可养宠物
+	SmokingAllowed         bool   `gorm:"default:false;index" json:"smoking_allowed"`    // 可吸烟
+	OvernightGuestsAllowed bool   `gorm:"default:false" json:"overnight_guests_allowed"` // 访客可留宿（列默认不允许；新建住宅由服务层设为允许，加列时已有住宅补填为允许）
+	QuietHours             string `gorm:"size:20" json:"quiet_hours,omitempty"`          // 静音时段（如 23:00-07:00）
+}
+
+// ============ Request DTO ============
+
+// ServicedApartmentInclusionsInput 租金包含项目设置（未提供的字段保持不变）
+type ServicedApartmentInclusionsInput struct {
+	CleaningFrequency *string `json:"cleaning_frequency" binding:"omitempty,oneof=none monthly biweekly weekly twice_weekly daily"`
+	UtilitiesIncluded *bool   `json:"utilities_included"`
+	InternetIncluded  *bool   `json:"internet_included"`
+	RatesIncluded     *bool   `json:"rates_included"`
+	LinenIncluded     *bool   `json:"linen_included"`
+}
+
+// ApplyTo 将设置写入租金包含项目
+func (in *ServicedApartmentInclusionsInput) ApplyTo(inclusions *ServicedApartmentInclusions) {
+	if in == nil {
+		return
+	}
+	if in.CleaningFrequency != nil {
+		inclusions.CleaningFrequency = *in.CleaningFrequency
+	}
+	if in.UtilitiesIncluded != nil {
+		inclusions.UtilitiesIncluded = *in.UtilitiesIncluded
+	}
+	if in.InternetIncluded != nil {
+		inclusions.InternetIncluded = *in.InternetIncluded
+	}
+	if in.RatesIncluded != nil {
+		inclusions.RatesIncluded = *in.RatesIncluded
+	}
+	if in.LinenIncluded != nil {
+		inclusions.LinenIncluded = *in.LinenIncluded
+	}
+}
+
+// ServicedApartmentHouseRulesInput 住客守则设置（未提供的字段保持不变）
+type ServicedApartmentHouseRulesInput struct {
+	PetsAllowed            *bool   `json:"pets_allowed"`
+	SmokingAllowed         *bool   `json:"smoking_allowed"`
+	OvernightGuestsAllowed *bool   `json:"overnight_guests_allowed"`
+	QuietHours             *string `json:"quiet_hours" binding:"omitempty,max=20"`
+}
+
+// ApplyTo 将设置写入住客守则
+func (in *ServicedApartmentHouseRulesInput) ApplyTo(rules *ServicedApartmentHouseRules) {
+	if in == nil {
+		return
+	}
+	if in.PetsAllowed != nil {
+		rules.PetsAllowed = *in.PetsAllowed
+	}
+	if in.SmokingAllowed != nil {
+		rules.SmokingAllowed = *in.SmokingAllowed
+	}
+	if in.OvernightGuestsAllowed != nil {
+		rules.OvernightGuestsAllowed = *in.OvernightGuestsAllowed
+	}
+	if in.QuietHours != nil {
+		rules.QuietHours = *in.QuietHours
+	}
+}
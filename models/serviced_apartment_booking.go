@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+)
+
+// 服务式住宅预订状态
+const (
+	BookingStatusPending   = "pending"   // 待营运商确认（占用房量直至确认、拒绝或保留到期）
+	BookingStatusConfirmed = "confirmed" // 已确认
+	BookingStatusDeclined  = "declined"  // 营运商已拒绝
+	BookingStatusCancelled = "cancelled" // 已取消
+	BookingStatusExpired   = "expired"   // 保留到期未确认
+)
+
+// ============ GORM Model ============
+
+// ServicedApartmentBooking 服务式住宅预订申请（按房型预订，入住至退房前一晚占用房量）
+type ServicedApartmentBooking struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	ServicedApartmentID uint       `gorm:"not null;index" json:"serviced_apartment_id"`
+	UnitID              uint       `gorm:"not null;index" json:"unit_id"`                  // 房型ID
+	UserID              uint       `gorm:"not null;index" json:"user_id"`                  // 申请人
+	CheckInDate         time.Time  `gorm:"type:date;not null;index" json:"check_in_date"`  // 入住日期
+	CheckOutDate        time.Time  `gorm:"type:date;not null;index" json:"check_out_date"` // 退房日期
+	Nights              int        `gorm:"not null" json:"nights"`                         // 入住晚数
+	Guests              int        `gorm:"not null" json:"guests"`                         // 入住人数
+	ContactName         string     `gorm:"size:100;not null" json:"contact_name"`          // 联系人
+	ContactPhone        string     `gorm:"size:50;not null" json:"contact_phone"`          // 联系电话
+	ContactEmail        string     `gorm:"size:255" json:"contact_email,omitempty"`        // 联系邮箱
+	Message             string     `gorm:"type:text" json:"message,omitempty"`             // 留言
+	Status              string     `gorm:"size:20;not null;index" json:"status"`           // pending, confirmed, declined, cancelled, expired
+	HoldExpiresAt       *time.Time `gorm:"index" json:"hold_expires_at,omitempty"`         // 待确认保留到期时间
+	OperatorNote        string     `gorm:"type:text" json:"operator_note,omitempty"`       // 营运商备注
+	RespondedAt         *time.Time `json:"responded_at,omitempty"`                         // 营运商处理时间
+	CancelledAt         *time.Time `json:"cancelled_at,omitempty"`                         // 取消时间
+	CreatedAt           time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+
+	// 关联
+	Unit *ServicedApartmentUnit `gorm:"foreignKey:UnitID" json:"unit,omitempty"`
+}
+
+func (ServicedApartmentBooking) TableName() string {
+	return "serviced_apartment_bookings"
+}
+
+// ServicedApartmentInventory 房型每晚已占用房量（待确认及已确认预订），可用 = 房型单位数 - 已占用
+type ServicedApartmentInventory struct {
+	ID     uint      `gorm:"primaryKey" json:"id"`
+	UnitID uint      `gorm:"not null;uniqueIndex:idx_unit_inventory_date" json:"unit_id"`
+	Date   time.Time `gorm:"type:date;not null;uniqueIndex:idx_unit_inventory_date" json:"date"`
+	Booked int       `gorm:"not null;default:0" json:"booked"` // 已占用单位数
+}
+
+func (ServicedApartmentInventory) TableName() string {
+	return "serviced_apartment_inventories"
+}
+
+// ============ Request DTO ============
+
+// GetAvailabilityRequest 房态日历请求（默认今天起 30 天，最多 92 天）
+type GetAvailabilityRequest struct {
+	StartDate string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `form:"end_date" binding:"omitempty,datetime=2006-01-02"` // 不含当天
+	UnitID    *uint  `form:"unit_id"`
+}
+
+// CreateBookingRequest 预订申请请求
+type CreateBookingRequest struct {
+	UnitID       uint   `json:"unit_id" binding:"required"`
+	CheckInDate  string `json:"check_in_date" binding:"required,datetime=2006-01-02"`
+	CheckOutDate string `json:"check_out_date" binding:"required,datetime=2006-01-02"`
+	Guests       int    `json:"guests" binding:"required,min=1"`
+	ContactName  string `json:"contact_name" binding:"required,max=100"`
+	ContactPhone string `json:"contact_phone" binding:"required,max=50"`
+	ContactEmail string `json:"contact_email" binding:"omitempty,email,max=255"`
+	Message      string `json:"message" binding:"omitempty,max=2000"`
+}
+
+// RespondBookingRequest 营运商确认/拒绝及取消预订请求
+type RespondBookingRequest struct {
+	Note string `json:"note" binding:"omitempty,max=2000"` // 备注或原因
+}
+
+// ListBookingsRequest 预订列表请求
+type ListBookingsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending confirmed declined cancelled expired"`
+	UnitID   *uint  `form:"unit_id"`
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// ============ Response DTO ============
+
+// AvailabilityDay 房型单日房态
+type AvailabilityDay struct {
+	Date      string `json:"date"`      // 日期 (YYYY-MM-DD)
+	Booked    int    `json:"booked"`    // 已占用
+	Available int    `json:"available"` // 可预订
+}
+
+// UnitAvailability 房型房态日历
+type UnitAvailability struct {
+	UnitID       uint              `json:"unit_id"`
+	UnitType     string            `json:"unit_type"`
+	TotalUnits   int               `json:"total_units"`   // 房型单位数
+	MinAvailable int               `json:"min_available"` // 期内最少可预订数（大于 0 即整段可订）
+	Days         []AvailabilityDay `json:"days"`
+}
+
+// AvailabilityCalendarResponse 房态日历响应
+type AvailabilityCalendarResponse struct {
+	ServicedApartmentID uint               `json:"serviced_apartment_id"`
+	StartDate           string             `json:"start_date"`
+	EndDate             string             `json:"end_date"`
+	MinStayDays         int                `json:"min_stay_days,omitempty"`
+	Units               []UnitAvailability `json:"units"`
+}
+
+// PaginatedBookingsResponse 分页预订响应
+type PaginatedBookingsResponse struct {
+	Data       []ServicedApartmentBooking `json:"data"`
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	PageSize   int                        `json:"page_size"`
+	TotalPages int                        `json:"total_pages"`
+}
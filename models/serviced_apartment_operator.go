@@ -0,0 +1,75 @@
+package models
+
+// ============ Request DTO ============
+
+// CreateServicedApartmentUnitRequest 新增房型请求
+type CreateServicedApartmentUnitRequest struct {
+	UnitType       string  `json:"unit_type" binding:"required,max=50"`           // 房型名称
+	Bedrooms       int     `json:"bedrooms" binding:"min=0,max=10"`               // 房间数（开放式为 0）
+	Bathrooms      int     `json:"bathrooms" binding:"min=0,max=10"`              // 浴室数
+	Area           float64 `json:"area" binding:"required,gt=0"`                  // 面积（平方尺）
+	MaxOccupancy   int     `json:"max_occupancy" binding:"required,min=1,max=20"` // 最多入住人数
+	DailyPrice     float64 `json:"daily_price" binding:"gte=0"`                   // 日租价格，0 为不设日租
+	WeeklyPrice    float64 `json:"weekly_price" binding:"gte=0"`                  // 周租价格，0 为不设周租
+	MonthlyPrice   float64 `json:"monthly_price" binding:"required,gt=0"`         // 月租价格
+	AvailableUnits int     `json:"available_units" binding:"min=0,max=1000"`      // 可用单位数
+	Description    string  `json:"description" binding:"omitempty,max=5000"`      // 房型描述
+	SortOrder      *int    `json:"sort_order" binding:"omitempty,min=0"`          // 排序顺序，默认排在最后
+}
+
+// UpdateServicedApartmentUnitRequest 更新房型请求
+type UpdateServicedApartmentUnitRequest struct {
+	UnitType       *string  `json:"unit_type" binding:"omitempty,max=50"`
+	Bedrooms       *int     `json:"bedrooms" binding:"omitempty,min=0,max=10"`
+	Bathrooms      *int     `json:"bathrooms" binding:"omitempty,min=0,max=10"`
+	Area           *float64 `json:"area" binding:"omitempty,gt=0"`
+	MaxOccupancy   *int     `json:"max_occupancy" binding:"omitempty,min=1,max=20"`
+	DailyPrice     *float64 `json:"daily_price" binding:"omitempty,gte=0"`
+	WeeklyPrice    *float64 `json:"weekly_price" binding:"omitempty,gte=0"`
+	MonthlyPrice   *float64 `json:"monthly_price" binding:"omitempty,gt=0"`
+	AvailableUnits *int     `json:"available_units" binding:"omitempty,min=0,max=1000"` // 不可低于未来已占用的单位数
+	Description    *string  `json:"description" binding:"omitempty,max=5000"`
+	SortOrder      *int     `json:"sort_order" binding:"omitempty,min=0"`
+}
+
+// GetOperatorDashboardRequest 营运商概览请求（管理员可指定公司）
+type GetOperatorDashboardRequest struct {
+	CompanyID *uint `form:"company_id"`
+}
+
+// ============ Response DTO ============
+
+// ServicedApartmentBookingStats 住宅预订统计
+type ServicedApartmentBookingStats struct {
+	ServicedApartmentID uint  `json:"-"`
+	Enquiries           int64 `json:"enquiries"`         // 统计期内收到的预订申请
+	PendingBookings     int64 `json:"pending_bookings"`  // 待确认预订
+	UpcomingBookings    int64 `json:"upcoming_bookings"` // 已确认且未退房的预订
+}
+
+// OperatorDashboardItem 营运商概览中的单个住宅
+type OperatorDashboardItem struct {
+	ID              uint      `json:"id"`
+	Name            string    `json:"name"`
+	Status          string    `json:"status"`
+	District        *District `json:"district,omitempty"`
+	ViewCount       int       `json:"view_count"`
+	FavoriteCount   int       `json:"favorite_count"`
+	UnitTypes       int       `json:"unit_types"`       // 房型数
+	TotalUnits      int       `json:"total_units"`      // 单位总数
+	OccupiedTonight int       `json:"occupied_tonight"` // 今晚已占用单位数
+	OccupancyRate   float64   `json:"occupancy_rate"`   // 未来统计期内入住率（%，含待确认预订）
+
+	ServicedApartmentBookingStats
+}
+
+// OperatorDashboardResponse 营运商概览响应
+type OperatorDashboardResponse struct {
+	CompanyID       uint                    `json:"company_id"`
+	WindowDays      int                     `json:"window_days"` // 统计期天数（申请按过去、入住率按未来计）
+	TotalViews      int                     `json:"total_views"`
+	TotalEnquiries  int64                   `json:"total_enquiries"`
+	PendingBookings int64                   `json:"pending_bookings"`
+	OccupancyRate   float64                 `json:"occupancy_rate"` // 全部住宅未来统计期内入住率（%）
+	Properties      []OperatorDashboardItem `json:"properties"`
+}
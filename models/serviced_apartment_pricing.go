@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+)
+
+// 报价明细类型
+const (
+	QuoteItemDaily    = "daily"    // 日租
+	QuoteItemWeekly   = "weekly"   // 周租（7 晚）
+	QuoteItemMonthly  = "monthly"  // 月租（30 晚）
+	QuoteItemDiscount = "discount" // 长住折扣
+)
+
+// ============ GORM Model ============
+
+// ServicedApartmentSeasonalRate 季节性调价（按入住晚调整租金，房型专属设置优先于全住宅设置）
+type ServicedApartmentSeasonalRate struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	ServicedApartmentID uint      `gorm:"not null;index" json:"serviced_apartment_id"`
+	UnitID              *uint     `gorm:"index" json:"unit_id,omitempty"`       // 适用房型，空为全部房型
+	Name                string    `gorm:"size:100;not null" json:"name"`        // 名称（如：圣诞旺季）
+	StartDate           time.Time `gorm:"type:date;not null" json:"start_date"` // 开始日期
+	EndDate             time.Time `gorm:"type:date;not null" json:"end_date"`   // 结束日期（含当晚）
+	AdjustmentPercent   float64   `gorm:"not null" json:"adjustment_percent"`   // 调整百分比（20 为加价 20%，-10 为减价 10%）
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+func (ServicedApartmentSeasonalRate) TableName() string {
+	return "serviced_apartment_seasonal_rates"
+}
+
+// ServicedApartmentStayDiscount 长住折扣（按入住晚数取最高适用档）
+type ServicedApartmentStayDiscount struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	ServicedApartmentID uint      `gorm:"not null;index" json:"serviced_apartment_id"`
+	MinNights           int       `gorm:"not null" json:"min_nights"`       // 最少入住晚数
+	DiscountPercent     float64   `gorm:"not null" json:"discount_percent"` // 折扣百分比
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+func (ServicedApartmentStayDiscount) TableName() string {
+	return "serviced_apartment_stay_discounts"
+}
+
+// ============ Request DTO ============
+
+// SeasonalRateInput 季节性调价设置
+type SeasonalRateInput struct {
+	UnitID            *uint   `json:"unit_id"`
+	Name              string  `json:"name" binding:"required,max=100"`
+	StartDate         string  `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate           string  `json:"end_date" binding:"required,datetime=2006-01-02"`
+	AdjustmentPercent float64 `json:"adjustment_percent" binding:"gte=-90,lte=500"`
+}
+
+// StayDiscountInput 长住折扣设置
+type StayDiscountInput struct {
+	MinNights       int     `json:"min_nights" binding:"required,min=2"`
+	DiscountPercent float64 `json:"discount_percent" binding:"gt=0,lte=90"`
+}
+
+// UpdatePricingRulesRequest 整体替换住宅的调价及折扣设置
+type UpdatePricingRulesRequest struct {
+	SeasonalRates []SeasonalRateInput `json:"seasonal_rates" binding:"max=100,dive"`
+	StayDiscounts []StayDiscountInput `json:"stay_discounts" binding:"max=20,dive"`
+}
+
+// GetStayQuoteRequest 入住报价请求
+type GetStayQuoteRequest struct {
+	CheckIn  string `form:"check_in" binding:"required,datetime=2006-01-02"`
+	CheckOut string `form:"check_out" binding:"required,datetime=2006-01-02"`
+	UnitID   *uint  `form:"unit_id"`
+	Guests   *int   `form:"guests" binding:"omitempty,min=1"`
+}
+
+// ============ Response DTO ============
+
+// PricingRulesResponse 住宅调价及折扣设置
+type PricingRulesResponse struct {
+	SeasonalRates []ServicedApartmentSeasonalRate `json:"seasonal_rates"`
+	StayDiscounts []ServicedApartmentStayDiscount `json:"stay_discounts"`
+}
+
+// QuoteLineItem 报价明细
+type QuoteLineItem struct {
+	Type              string  `json:"type"`                         // daily, weekly, monthly, discount
+	Description       string  `json:"description"`                  // 说明
+	StartDate         string  `json:"start_date,omitempty"`         // 首晚日期
+	EndDate           string  `json:"end_date,omitempty"`           // 末晚日期
+	Nights            int     `json:"nights,omitempty"`             // 覆盖晚数
+	Quantity          int     `json:"quantity"`                     // 计价单位数（日/周/月数）
+	UnitPrice         float64 `json:"unit_price"`                   // 调价后单价
+	AdjustmentPercent float64 `json:"adjustment_percent,omitempty"` // 季节性调整百分比
+	Amount            float64 `json:"amount"`                       // 金额（折扣为负数）
+}
+
+// UnitQuote 房型报价
+type UnitQuote struct {
+	UnitID         uint            `json:"unit_id"`
+	UnitType       string          `json:"unit_type"`
+	MaxOccupancy   int             `json:"max_occupancy"`
+	Available      bool            `json:"available"`            // 整段入住期间有房且满足人数
+	AvailableUnits int             `json:"available_units"`      // 期内最少可预订数
+	Subtotal       float64         `json:"subtotal"`             // 租金小计
+	Discount       float64         `json:"discount"`             // 长住折扣
+	Total          float64         `json:"total"`                // 应付总额
+	NightlyAverage float64         `json:"nightly_average"`      // 平均每晚
+	LineItems      []QuoteLineItem `json:"line_items"`           // 明细
+	Unquotable     bool            `json:"unquotable,omitempty"` // 房型未设置租金
+}
+
+// StayQuoteResponse 入住报价响应（按总额升序，无房房型排最后）
+type StayQuoteResponse struct {
+	ServicedApartmentID uint        `json:"serviced_apartment_id"`
+	CheckIn             string      `json:"check_in"`
+	CheckOut            string      `json:"check_out"`
+	Nights              int         `json:"nights"`
+	MinStayDays         int         `json:"min_stay_days,omitempty"`
+	Units               []UnitQuote `json:"units"`
+}
+
+// StayQuoteSummary 列表中可订房型的报价摘要
+type StayQuoteSummary struct {
+	UnitID   uint    `json:"unit_id"`
+	UnitType string  `json:"unit_type"`
+	Total    float64 `json:"total"`
+}
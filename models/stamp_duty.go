@@ -0,0 +1,67 @@
+package models
+
+// 买家类型
+const (
+	BuyerTypeFirstTime     = "hk_first_time"     // 香港永久性居民，成交时无其他住宅物业
+	BuyerTypeExistingOwner = "hk_existing_owner" // 香港永久性居民，成交时已持有其他住宅物业
+	BuyerTypeNonPermanent  = "non_permanent"     // 非香港永久性居民
+	BuyerTypeCompany       = "company"           // 公司
+)
+
+// 印花税类型
+const (
+	StampDutyAVD = "avd" // 从价印花税
+	StampDutyBSD = "bsd" // 买家印花税
+)
+
+// ============ Request DTO ============
+
+// StampDutyRequest 印花税及交易费用估算请求
+type StampDutyRequest struct {
+	Price                float64  `json:"price" binding:"required,gt=0"`                                                              // 成交价
+	BuyerType            string   `json:"buyer_type" binding:"omitempty,oneof=hk_first_time hk_existing_owner non_permanent company"` // 买家类型（默认 hk_first_time）
+	TransactionDate      string   `json:"transaction_date" binding:"omitempty,datetime=2006-01-02"`                                   // 成交日期（默认今天，决定适用税率表）
+	AgencyCommissionRate *float64 `json:"agency_commission_rate" binding:"omitempty,gte=0,lte=5"`                                     // 代理佣金 (%)，不填使用默认值
+	LegalFee             *float64 `json:"legal_fee" binding:"omitempty,gte=0"`                                                        // 律师费，不填使用默认值
+}
+
+// ============ Response DTO ============
+
+// StampDutyResponse 印花税及交易费用估算结果
+type StampDutyResponse struct {
+	Price            float64         `json:"price"`             // 成交价
+	BuyerType        string          `json:"buyer_type"`        // 买家类型
+	TransactionDate  string          `json:"transaction_date"`  // 成交日期
+	RateTable        StampDutyTable  `json:"rate_table"`        // 适用税率表
+	Duties           []StampDutyItem `json:"duties"`            // 各项印花税
+	TotalStampDuty   float64         `json:"total_stamp_duty"`  // 印花税合计
+	AgencyCommission float64         `json:"agency_commission"` // 代理佣金
+	LegalFee         float64         `json:"legal_fee"`         // 律师费
+	TotalCost        float64         `json:"total_cost"`        // 交易费用合计（不含楼价）
+	TotalOutlay      float64         `json:"total_outlay"`      // 楼价连交易费用
+}
+
+// StampDutyTable 税率表版本
+type StampDutyTable struct {
+	Version       string `json:"version"`        // 版本
+	EffectiveFrom string `json:"effective_from"` // 生效日期
+	Description   string `json:"description"`    // 说明
+}
+
+// StampDutyItem 单项印花税
+type StampDutyItem struct {
+	Type   string  `json:"type"`            // avd, bsd
+	Scale  string  `json:"scale,omitempty"` // 从价印花税税率（scale_1, scale_2）
+	Amount float64 `json:"amount"`          // 税额
+	Rate   float64 `json:"rate"`            // 实际税率 (%)
+}
+
+// StampDutyEstimate 房产详情中的印花税及交易费用估算（默认买家类型及费用）
+type StampDutyEstimate struct {
+	BuyerType        string  `json:"buyer_type"`
+	RateVersion      string  `json:"rate_version"`
+	StampDuty        float64 `json:"stamp_duty"`
+	AgencyCommission float64 `json:"agency_commission"`
+	LegalFee         float64 `json:"legal_fee"`
+	TotalCost        float64 `json:"total_cost"`
+}
@@ -24,6 +24,13 @@ type GetTransactionStatisticsRequest struct {
 	DistrictID *uint   `form:"district_id"` // 地区ID
 }
 
+// GetFirstHandStatisticsRequest 获取一手成交统计请求
+type GetFirstHandStatisticsRequest struct {
+	StartDate  *string `form:"start_date" binding:"omitempty,datetime=2006-01-02"` // 开始日期 (YYYY-MM-DD)
+	EndDate    *string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`   // 结束日期 (YYYY-MM-DD)
+	DistrictID *uint   `form:"district_id"`                                        // 地区ID
+}
+
 // GetUserStatisticsRequest 获取用户统计请求
 type GetUserStatisticsRequest struct {
 	StartDate *string `form:"start_date"` // 开始日期 (YYYY-MM-DD)
@@ -188,6 +195,36 @@ type MonthlyTransactionStat struct {
 	AvgPrice         float64 `json:"avg_price"`         // 平均价格
 }
 
+// FirstHandStatisticsResponse 一手成交统计响应（按新盘成交纪录册，不含已终止成交）
+type FirstHandStatisticsResponse struct {
+	TotalTransactions int64   `json:"total_transactions"` // 成交宗数
+	TotalValue        float64 `json:"total_value"`        // 成交总额
+	AvgPrice          float64 `json:"avg_price"`          // 平均成交价
+	AvgPricePerSqft   float64 `json:"avg_price_per_sqft"` // 平均实用呎价
+	PriceRealised     float64 `json:"price_realised"`     // 成交价相对价单售价 (%)
+	ActiveProjects    int64   `json:"active_projects"`    // 期内有成交的新盘数
+
+	DistrictTransactions []DistrictTransactionStat  `json:"district_transactions"` // 地区成交统计
+	Developments         []FirstHandDevelopmentStat `json:"developments"`          // 成交最多的新盘（最多 10 个）
+	MonthlyTrend         []MonthlyTransactionStat   `json:"monthly_trend"`         // 月度趋势（未指定开始日期时为最近12个月）
+
+	StartDate  *string `json:"start_date,omitempty"`
+	EndDate    *string `json:"end_date,omitempty"`
+	DistrictID *uint   `json:"district_id,omitempty"`
+}
+
+// FirstHandDevelopmentStat 新盘成交统计
+type FirstHandDevelopmentStat struct {
+	NewPropertyID    uint    `json:"new_property_id"`
+	Name             string  `json:"name"`
+	Status           string  `json:"status"`
+	TransactionCount int64   `json:"transaction_count"`  // 期内成交宗数
+	AvgPricePerSqft  float64 `json:"avg_price_per_sqft"` // 期内平均实用呎价
+	UnitsSold        int     `json:"units_sold"`         // 累计已售单位
+	TotalUnits       int     `json:"total_units"`
+	SellThroughRate  float64 `json:"sell_through_rate"` // 累计去化率 (%)
+}
+
 // UserStatisticsResponse 用户统计响应
 type UserStatisticsResponse struct {
 	// 用户总数统计
@@ -11,7 +11,7 @@ import (
 // User 用户模型
 type User struct {
 	ID              uint           `gorm:"primaryKey" json:"id"`
-	UserType        string         `gorm:"size:20;not null;index" json:"user_type"`                        // individual=普通用户, agency=地产代理公司
+	UserType        string         `gorm:"size:20;not null;index" json:"user_type"`                        // individual=普通用户, agency=地产代理公司, admin=平台管理员（不可注册）
 	Email           string         `gorm:"size:255;uniqueIndex;not null" json:"email"`                     // 邮箱地址
 	PasswordHash    string         `gorm:"size:255;not null" json:"-"`                                     // 密码哈希值
 	Name            string         `gorm:"size:100;not null" json:"name"`                                  // 用户名称/公司名称
@@ -4,6 +4,36 @@ import (
 	"time"
 )
 
+// ============ GORM Model ============
+
+// EstateYield 屋苑户型租金回报率（定时任务按户型配对放盘及成交计算）
+type EstateYield struct {
+	ID                  uint      `gorm:"primaryKey" json:"-"`
+	EstateID            uint      `gorm:"not null;uniqueIndex:idx_estate_yield_unit" json:"estate_id"` // 屋苑ID
+	Bedrooms            int       `gorm:"not null;uniqueIndex:idx_estate_yield_unit" json:"bedrooms"`  // 户型（房间数）
+	AvgSalePrice        float64   `gorm:"index" json:"avg_sale_price"`                                 // 平均售价
+	AvgMonthlyRent      float64   `json:"avg_monthly_rent"`                                            // 平均月租
+	AvgSalePricePerSqft float64   `json:"avg_sale_price_per_sqft"`                                     // 平均每平方尺售价
+	AvgRentPerSqft      float64   `json:"avg_rent_per_sqft"`                                           // 平均每平方尺月租
+	GrossYield          float64   `gorm:"index" json:"gross_yield"`                                    // 毛租金回报率 (%)
+	SaleSamples         int       `json:"sale_samples"`                                                // 出售样本数（在架放盘及成交）
+	RentSamples         int       `json:"rent_samples"`                                                // 出租样本数（在架放盘及成交）
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+func (EstateYield) TableName() string {
+	return "estate_yields"
+}
+
+// YieldSample 户型价格样本（按房间数及放盘类型汇总）
+type YieldSample struct {
+	Bedrooms        int
+	ListingType     string
+	AvgPrice        float64
+	AvgPricePerSqft float64
+	Count           int
+}
+
 // ============ Response DTO ============
 
 // ValuationResponse 屋苑估价响应
@@ -56,6 +86,7 @@ type EstateValuationDetail struct {
 	RentalYield            float64                   `json:"rental_yield"`
 	PriceHistory           []PriceHistoryPoint       `json:"price_history"`           // 价格历史
 	UnitTypePrices         []UnitTypePriceBreakdown  `json:"unit_type_prices"`        // 户型价格分布
+	UnitTypeYields         []EstateYield             `json:"unit_type_yields"`        // 户型租金回报率
 	RecentTransactions     []TransactionSummary      `json:"recent_transactions"`     // 近期成交
 	LastUpdated            time.Time                 `json:"last_updated"`
 }
@@ -131,3 +162,46 @@ type DistrictValuationSummary struct {
 	TotalTransactions int                 `json:"total_transactions"`  // 总成交数量
 	Estates           []ValuationResponse `json:"estates"`             // 屋苑列表
 }
+
+// YieldRankingRequest 租金回报率排行请求
+type YieldRankingRequest struct {
+	DistrictID         *uint    `form:"district_id"`                                 // 地区ID
+	PrimarySchoolNet   *string  `form:"primary_school_net"`                          // 小学校网
+	SecondarySchoolNet *string  `form:"secondary_school_net"`                        // 中学校网
+	MinBudget          *float64 `form:"min_budget" binding:"omitempty,gte=0"`        // 最低预算（户型平均售价）
+	MaxBudget          *float64 `form:"max_budget" binding:"omitempty,gt=0"`         // 最高预算（户型平均售价）
+	Bedrooms           *int     `form:"bedrooms" binding:"omitempty,min=0"`          // 房间数
+	MinSamples         int      `form:"min_samples" binding:"omitempty,min=1"`       // 出售及出租最少样本数（默认 1）
+	Page               int      `form:"page" binding:"omitempty,min=1"`              // 页码
+	PageSize           int      `form:"page_size" binding:"omitempty,min=1,max=100"` // 每页数量
+}
+
+// YieldRankingItem 租金回报率排行项（屋苑户型）
+type YieldRankingItem struct {
+	Rank                int       `json:"rank" gorm:"-"`
+	EstateID            uint      `json:"estate_id"`
+	EstateName          string    `json:"estate_name"`
+	EstateNameEn        string    `json:"estate_name_en,omitempty"`
+	DistrictID          uint      `json:"district_id"`
+	DistrictName        string    `json:"district_name,omitempty"`
+	PrimarySchoolNet    string    `json:"primary_school_net,omitempty"`
+	SecondarySchoolNet  string    `json:"secondary_school_net,omitempty"`
+	Bedrooms            int       `json:"bedrooms"`
+	AvgSalePrice        float64   `json:"avg_sale_price"`
+	AvgMonthlyRent      float64   `json:"avg_monthly_rent"`
+	AvgSalePricePerSqft float64   `json:"avg_sale_price_per_sqft"`
+	AvgRentPerSqft      float64   `json:"avg_rent_per_sqft"`
+	GrossYield          float64   `json:"gross_yield"`
+	SaleSamples         int       `json:"sale_samples"`
+	RentSamples         int       `json:"rent_samples"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// PaginatedYieldRankingResponse 分页租金回报率排行响应
+type PaginatedYieldRankingResponse struct {
+	Data       []YieldRankingItem `json:"data"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalPages int                `json:"total_pages"`
+}
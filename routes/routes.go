@@ -27,6 +27,22 @@ func SetupRoutes(
 	facilityCtrl *controllers.FacilityController,
 	searchCtrl *controllers.SearchController,
 	statisticsCtrl *controllers.StatisticsController,
+	jobCtrl *controllers.JobController,
+	uploadCtrl *controllers.UploadController,
+	moderationCtrl *controllers.ModerationController,
+	duplicateCtrl *controllers.DuplicateController,
+	auditCtrl *controllers.AuditController,
+	propertyImportCtrl *controllers.PropertyImportController,
+	agencyFeedCtrl *controllers.AgencyFeedController,
+	mortgageCtrl *controllers.MortgageController,
+	stampDutyCtrl *controllers.StampDutyController,
+	comparisonCtrl *controllers.ComparisonController,
+	priceListCtrl *controllers.PriceListController,
+	servicedApartmentBookingCtrl *controllers.ServicedApartmentBookingController,
+	servicedApartmentPricingCtrl *controllers.ServicedApartmentPricingController,
+	servicedApartmentOperatorCtrl *controllers.ServicedApartmentOperatorController,
+	estateBlockCtrl *controllers.EstateBlockController,
+	estateImportCtrl *controllers.EstateImportController,
 ) {
 	// API v1 路由组
 	v1 := r.Group("/api/v1")
@@ -50,6 +66,9 @@ func SetupRoutes(
 		userGroup.GET("/me", userCtrl.GetCurrentUser)          // 获取当前用户信息
 		userGroup.PUT("/me", userCtrl.UpdateCurrentUser)       // 更新当前用户信息
 		userGroup.GET("/me/listings", userCtrl.GetMyListings)  // 获取我的发布
+		userGroup.GET("/me/notifications", userCtrl.GetMyNotifications)              // 获取我的通知
+		userGroup.PUT("/me/notifications/:id/read", userCtrl.MarkNotificationRead)   // 标记通知已读
+		userGroup.GET("/me/bookings", servicedApartmentBookingCtrl.ListMyBookings) // 我的服务式住宅预订
 	}
 
 	// ========== 房产路由 ==========
@@ -59,8 +78,11 @@ func SetupRoutes(
 		propertyGroup.GET("", propertyCtrl.ListProperties)                    // 房产列表
 		propertyGroup.GET("/featured", propertyCtrl.GetFeaturedProperties)    // 精选房源
 		propertyGroup.GET("/hot", propertyCtrl.GetHotProperties)              // 热门房源
-		propertyGroup.GET("/:id", propertyCtrl.GetProperty)                   // 房产详情
+		propertyGroup.POST("/compare", comparisonCtrl.CompareProperties)      // 房产对比（2-5 个）
+		propertyGroup.GET("/:id", middlewares.OptionalJWTAuth(), propertyCtrl.GetProperty) // 房产详情（可选认证，发布者可查看未发布房产）
 		propertyGroup.GET("/:id/similar", propertyCtrl.GetSimilarProperties)  // 相似房源
+		propertyGroup.GET("/:id/images", propertyCtrl.GetPropertyImages)      // 房产图片
+		propertyGroup.GET("/:id/duplicates", duplicateCtrl.GetPropertyDuplicates) // 同一单位的其他刊登
 
 		// 买房分类
 		buyGroup := propertyGroup.Group("/buy")
@@ -82,9 +104,31 @@ func SetupRoutes(
 		authenticated := propertyGroup.Group("")
 		authenticated.Use(middlewares.JWTAuth())
 		{
-			authenticated.POST("", propertyCtrl.CreateProperty)            // 创建房产
-			authenticated.PUT("/:id", propertyCtrl.UpdateProperty)         // 更新房产
-			authenticated.DELETE("/:id", propertyCtrl.DeleteProperty)      // 删除房产
+			authenticated.POST("", propertyCtrl.CreateProperty)                        // 创建房产
+			authenticated.PUT("/:id", propertyCtrl.UpdateProperty)                     // 更新房产
+			authenticated.DELETE("/:id", propertyCtrl.DeleteProperty)                  // 删除房产
+			authenticated.POST("/:id/renew", propertyCtrl.RenewProperty)               // 续期房产
+			authenticated.POST("/:id/submit", propertyCtrl.SubmitProperty)             // 提交审核
+			authenticated.POST("/:id/publish", propertyCtrl.PublishProperty)           // 立即发布或定时发布
+			authenticated.DELETE("/:id/schedule", propertyCtrl.CancelScheduledPublish) // 取消定时发布
+			authenticated.GET("/:id/reviews", moderationCtrl.GetPropertyReviews)       // 审核历史
+
+			// 图片管理（发布者或管理员）
+			authenticated.POST("/:id/images", propertyCtrl.AddPropertyImage)                         // 添加图片
+			authenticated.PUT("/:id/images", propertyCtrl.ReorderPropertyImages)                     // 批量排序
+			authenticated.PUT("/:id/images/:imageId", propertyCtrl.UpdatePropertyImage)              // 更新图片类型
+			authenticated.DELETE("/:id/images/:imageId", propertyCtrl.DeletePropertyImage)           // 删除图片
+			authenticated.PUT("/:id/images/:imageId/cover", propertyCtrl.SetPropertyCoverImage)      // 设为封面
+		}
+
+		// 批量导入（代理公司）
+		importGroup := propertyGroup.Group("/import")
+		importGroup.Use(middlewares.JWTAuth(), middlewares.RequireUserType("agency"))
+		{
+			importGroup.POST("", propertyImportCtrl.ImportProperties)         // 上传 CSV/XLSX 创建导入任务
+			importGroup.GET("", propertyImportCtrl.ListImports)               // 导入任务列表
+			importGroup.GET("/template", propertyImportCtrl.DownloadTemplate) // 下载导入模板
+			importGroup.GET("/:importId", propertyImportCtrl.GetImport)       // 导入进度与结果
 		}
 	}
 
@@ -94,6 +138,43 @@ func SetupRoutes(
 		newPropertyGroup.GET("", newDevelopmentCtrl.ListNewDevelopments)         // 新盘列表
 		newPropertyGroup.GET("/:id", newDevelopmentCtrl.GetNewDevelopment)       // 新盘详情
 		newPropertyGroup.GET("/:id/layouts", newDevelopmentCtrl.GetDevelopmentLayouts) // 户型列表
+		newPropertyGroup.GET("/:id/images", newDevelopmentCtrl.GetNewPropertyImages)   // 图片列表
+
+		// 价单、单位及销售安排
+		newPropertyGroup.GET("/price-lists/template", priceListCtrl.DownloadTemplate)        // 下载价单导入模板
+		newPropertyGroup.GET("/:id/price-lists", priceListCtrl.ListPriceLists)               // 价单列表
+		newPropertyGroup.GET("/:id/price-lists/:priceListId", priceListCtrl.GetPriceList)    // 价单详情
+		newPropertyGroup.GET("/:id/units", priceListCtrl.ListUnits)                          // 单位及销售状态
+		newPropertyGroup.GET("/:id/sales-arrangements", priceListCtrl.ListSalesArrangements) // 销售安排
+
+		// 成交纪录册
+		newPropertyGroup.GET("/:id/transactions", newDevelopmentCtrl.ListDevelopmentTransactions) // 成交纪录
+
+		// 新盘、户型及图片管理（管理员）
+		admin := newPropertyGroup.Group("")
+		admin.Use(middlewares.JWTAuth(), middlewares.RequireUserType("admin"))
+		{
+			admin.POST("", newDevelopmentCtrl.CreateNewDevelopment)                              // 创建新盘
+			admin.PUT("/:id", newDevelopmentCtrl.UpdateNewDevelopment)                           // 更新新盘
+			admin.DELETE("/:id", newDevelopmentCtrl.DeleteNewDevelopment)                        // 删除新盘
+			admin.POST("/:id/layouts", newDevelopmentCtrl.CreateDevelopmentLayout)               // 添加户型
+			admin.PUT("/:id/layouts/:layoutId", newDevelopmentCtrl.UpdateDevelopmentLayout)      // 更新户型
+			admin.DELETE("/:id/layouts/:layoutId", newDevelopmentCtrl.DeleteDevelopmentLayout)   // 删除户型
+			admin.POST("/:id/images", newDevelopmentCtrl.AddNewPropertyImage)                    // 添加图片
+			admin.PUT("/:id/images", newDevelopmentCtrl.ReorderNewPropertyImages)                // 批量排序
+			admin.PUT("/:id/images/:imageId", newDevelopmentCtrl.UpdateNewPropertyImage)         // 更新图片信息
+			admin.DELETE("/:id/images/:imageId", newDevelopmentCtrl.DeleteNewPropertyImage)      // 删除图片
+			admin.PUT("/:id/images/:imageId/cover", newDevelopmentCtrl.SetNewPropertyCoverImage) // 设为封面
+
+			admin.POST("/:id/price-lists", priceListCtrl.ImportPriceList)                                // 导入价单
+			admin.PUT("/:id/units/status", priceListCtrl.UpdateUnitStatus)                               // 批量更新单位销售状态
+			admin.POST("/:id/sales-arrangements", priceListCtrl.CreateSalesArrangement)                  // 创建销售安排
+			admin.PUT("/:id/sales-arrangements/:arrangementId", priceListCtrl.UpdateSalesArrangement)    // 更新销售安排
+			admin.DELETE("/:id/sales-arrangements/:arrangementId", priceListCtrl.DeleteSalesArrangement) // 删除销售安排
+
+			admin.POST("/:id/transactions", newDevelopmentCtrl.RecordDevelopmentTransaction)               // 登记成交
+			admin.PUT("/:id/transactions/:transactionId", newDevelopmentCtrl.UpdateDevelopmentTransaction) // 更新成交（转正式合约或终止）
+		}
 	}
 
 	// ========== 服务式住宅路由 ==========
@@ -104,14 +185,45 @@ func SetupRoutes(
 		servicedApartmentGroup.GET("/:id", servicedApartmentCtrl.GetServicedApartment)        // 服务式住宅详情
 		servicedApartmentGroup.GET("/:id/units", servicedApartmentCtrl.GetServicedApartmentUnits)   // 房型列表
 		servicedApartmentGroup.GET("/:id/images", servicedApartmentCtrl.GetServicedApartmentImages) // 图片列表
+		servicedApartmentGroup.GET("/:id/availability", servicedApartmentBookingCtrl.GetAvailability) // 房态日历
+		servicedApartmentGroup.GET("/:id/quote", servicedApartmentPricingCtrl.GetQuote)                // 入住报价
+		servicedApartmentGroup.GET("/:id/pricing-rules", servicedApartmentPricingCtrl.GetPricingRules) // 季节性调价及长住折扣
+		servicedApartmentGroup.GET("/:id/units/:unitId/images", servicedApartmentOperatorCtrl.GetUnitImages) // 房型图片列表
 
 		// 需要认证的接口
 		authenticated := servicedApartmentGroup.Group("")
 		authenticated.Use(middlewares.JWTAuth())
 		{
+			authenticated.GET("/dashboard", servicedApartmentOperatorCtrl.GetDashboard) // 营运商概览
 			authenticated.POST("", servicedApartmentCtrl.CreateServicedApartment)             // 创建服务式住宅
 			authenticated.PUT("/:id", servicedApartmentCtrl.UpdateServicedApartment)          // 更新服务式住宅
 			authenticated.DELETE("/:id", servicedApartmentCtrl.DeleteServicedApartment)       // 删除服务式住宅
+
+			// 图片管理（所属公司或管理员）
+			authenticated.POST("/:id/images", servicedApartmentCtrl.AddServicedApartmentImage)                    // 添加图片
+			authenticated.PUT("/:id/images", servicedApartmentCtrl.ReorderServicedApartmentImages)                // 批量排序
+			authenticated.PUT("/:id/images/:imageId", servicedApartmentCtrl.UpdateServicedApartmentImage)         // 更新图片信息
+			authenticated.DELETE("/:id/images/:imageId", servicedApartmentCtrl.DeleteServicedApartmentImage)      // 删除图片
+			authenticated.PUT("/:id/images/:imageId/cover", servicedApartmentCtrl.SetServicedApartmentCoverImage) // 设为封面
+
+			// 房型及房型图片管理（所属公司或管理员）
+			authenticated.POST("/:id/units", servicedApartmentOperatorCtrl.CreateUnit)                                // 新增房型
+			authenticated.PUT("/:id/units/:unitId", servicedApartmentOperatorCtrl.UpdateUnit)                         // 更新房型
+			authenticated.DELETE("/:id/units/:unitId", servicedApartmentOperatorCtrl.DeleteUnit)                      // 删除房型
+			authenticated.POST("/:id/units/:unitId/images", servicedApartmentOperatorCtrl.AddUnitImage)               // 添加房型图片
+			authenticated.PUT("/:id/units/:unitId/images", servicedApartmentOperatorCtrl.ReorderUnitImages)           // 批量排序
+			authenticated.PUT("/:id/units/:unitId/images/:imageId", servicedApartmentOperatorCtrl.UpdateUnitImage)    // 更新房型图片信息
+			authenticated.DELETE("/:id/units/:unitId/images/:imageId", servicedApartmentOperatorCtrl.DeleteUnitImage) // 删除房型图片
+
+			// 预订（申请人提交及取消；确认、拒绝及列表限所属公司或管理员）
+			authenticated.POST("/:id/bookings", servicedApartmentBookingCtrl.CreateBooking)                    // 提交预订申请
+			authenticated.GET("/:id/bookings", servicedApartmentBookingCtrl.ListBookings)                      // 预订列表
+			authenticated.PUT("/:id/bookings/:bookingId/confirm", servicedApartmentBookingCtrl.ConfirmBooking) // 确认预订
+			authenticated.PUT("/:id/bookings/:bookingId/decline", servicedApartmentBookingCtrl.DeclineBooking) // 拒绝预订
+			authenticated.PUT("/:id/bookings/:bookingId/cancel", servicedApartmentBookingCtrl.CancelBooking)   // 取消预订
+
+			// 调价设置（所属公司或管理员）
+			authenticated.PUT("/:id/pricing-rules", servicedApartmentPricingCtrl.UpdatePricingRules) // 替换季节性调价及长住折扣
 		}
 	}
 
@@ -121,12 +233,17 @@ func SetupRoutes(
 		// 公开接口（无需认证）
 		estateGroup.GET("", estateCtrl.ListEstates)                           // 屋苑列表
 		estateGroup.GET("/featured", estateCtrl.GetFeaturedEstates)           // 精选屋苑
+		estateGroup.POST("/compare", comparisonCtrl.CompareEstates)           // 屋苑对比（2-5 个）
 		estateGroup.GET("/:id", estateCtrl.GetEstate)                         // 屋苑详情
 		estateGroup.GET("/:id/properties", estateCtrl.GetEstateProperties)    // 屋苑内房源列表
 		estateGroup.GET("/:id/images", estateCtrl.GetEstateImages)            // 屋苑图片
 		estateGroup.GET("/:id/facilities", estateCtrl.GetEstateFacilities)    // 屋苑设施
 		estateGroup.GET("/:id/transactions", estateCtrl.GetEstateTransactions) // 屋苑成交记录
 		estateGroup.GET("/:id/statistics", estateCtrl.GetEstateStatistics)    // 屋苑统计数据
+		estateGroup.GET("/blocks/template", estateBlockCtrl.DownloadTemplate)    // 下载单位目录导入模板
+		estateGroup.GET("/:id/blocks", estateBlockCtrl.ListBlocks)               // 座数列表（include_units=true 时含单位）
+		estateGroup.GET("/:id/blocks/:blockId/units", estateBlockCtrl.ListUnits) // 座数单位列表
+		estateGroup.GET("/import/template", estateImportCtrl.DownloadTemplate)   // 下载屋苑导入模板
 
 		// 需要认证的接口
 		authenticated := estateGroup.Group("")
@@ -136,6 +253,21 @@ func SetupRoutes(
 			authenticated.PUT("/:id", estateCtrl.UpdateEstate)           // 更新屋苑
 			authenticated.DELETE("/:id", estateCtrl.DeleteEstate)        // 删除屋苑
 		}
+
+		// 图片、设施及批量导入（管理员）
+		admin := estateGroup.Group("")
+		admin.Use(middlewares.JWTAuth(), middlewares.RequireUserType("admin"))
+		{
+			admin.POST("/:id/images", estateCtrl.AddEstateImage)                    // 添加图片
+			admin.PUT("/:id/images", estateCtrl.ReorderEstateImages)                // 批量排序
+			admin.PUT("/:id/images/:imageId", estateCtrl.UpdateEstateImage)         // 更新图片信息
+			admin.DELETE("/:id/images/:imageId", estateCtrl.DeleteEstateImage)      // 删除图片
+			admin.PUT("/:id/images/:imageId/cover", estateCtrl.SetEstateCoverImage) // 设为封面
+
+			admin.PUT("/:id/facilities", estateCtrl.UpdateEstateFacilities) // 覆盖更新设施
+			admin.POST("/import", estateImportCtrl.ImportEstates)           // 导入屋苑及设施
+			admin.POST("/:id/blocks/import", estateBlockCtrl.ImportUnits)   // 导入单位目录
+		}
 	}
 
 	// ========== 物业估价路由 ==========
@@ -144,10 +276,18 @@ func SetupRoutes(
 		// 公开接口（无需认证）
 		valuationGroup.GET("", valuationCtrl.ListValuations)                           // 获取屋苑估价列表
 		valuationGroup.GET("/search", valuationCtrl.SearchValuations)                  // 搜索屋苑估价
+		valuationGroup.GET("/yield-ranking", valuationCtrl.GetYieldRanking)            // 屋苑户型租金回报率排行（需在 :estateId 前）
 		valuationGroup.GET("/:estateId", valuationCtrl.GetEstateValuation)             // 获取指定屋苑估价参考
 		valuationGroup.GET("/districts/:districtId", valuationCtrl.GetDistrictValuations) // 获取地区屋苑估价列表
 	}
 
+	// ========== 置业工具路由（公开） ==========
+	toolGroup := v1.Group("/tools")
+	{
+		toolGroup.POST("/mortgage", mortgageCtrl.CalculateMortgage)     // 按揭及供款能力计算
+		toolGroup.POST("/stamp-duty", stampDutyCtrl.CalculateStampDuty) // 印花税及交易费用估算
+	}
+
 	// ========== 家具商城路由 ==========
 	furnitureGroup := v1.Group("/furniture")
 	{
@@ -155,7 +295,7 @@ func SetupRoutes(
 		furnitureGroup.GET("", furnitureCtrl.ListFurniture)                    // 家具列表
 		furnitureGroup.GET("/categories", furnitureCtrl.GetFurnitureCategories) // 家具分类
 		furnitureGroup.GET("/featured", furnitureCtrl.GetFeaturedFurniture)    // 精选家具
-		furnitureGroup.GET("/:id", furnitureCtrl.GetFurniture)                 // 家具详情
+		furnitureGroup.GET("/:id", middlewares.OptionalJWTAuth(), furnitureCtrl.GetFurniture) // 家具详情（可选认证，发布者可查看未发布家具）
 		furnitureGroup.GET("/:id/images", furnitureCtrl.GetFurnitureImages)    // 家具图片
 
 		// 需要认证的接口
@@ -166,6 +306,15 @@ func SetupRoutes(
 			authenticated.PUT("/:id", furnitureCtrl.UpdateFurniture)               // 更新家具
 			authenticated.DELETE("/:id", furnitureCtrl.DeleteFurniture)            // 删除家具
 			authenticated.PUT("/:id/status", furnitureCtrl.UpdateFurnitureStatus)  // 更新家具状态
+			authenticated.POST("/:id/renew", furnitureCtrl.RenewFurniture)         // 续期家具
+			authenticated.POST("/:id/submit", furnitureCtrl.SubmitFurniture)       // 提交审核
+			authenticated.GET("/:id/reviews", moderationCtrl.GetFurnitureReviews)  // 审核历史
+
+			// 图片管理（发布者或管理员）
+			authenticated.POST("/:id/images", furnitureCtrl.AddFurnitureImage)                    // 添加图片
+			authenticated.PUT("/:id/images", furnitureCtrl.ReorderFurnitureImages)                // 批量排序
+			authenticated.DELETE("/:id/images/:imageId", furnitureCtrl.DeleteFurnitureImage)      // 删除图片
+			authenticated.PUT("/:id/images/:imageId/cover", furnitureCtrl.SetFurnitureCoverImage) // 设为封面
 		}
 	}
 
@@ -217,6 +366,18 @@ func SetupRoutes(
 		agencyGroup.GET("/:id", agencyCtrl.GetAgency)                   // 代理公司详情
 		agencyGroup.GET("/:id/properties", agencyCtrl.GetAgencyProperties) // 代理公司房源列表
 		agencyGroup.POST("/:id/contact", agencyCtrl.ContactAgency)      // 联系代理公司
+		agencyGroup.GET("/:id/feed.xml", agencyFeedCtrl.GetFeedXML)     // 刊登 Feed（XML，私有 Feed 需 API Key）
+		agencyGroup.GET("/:id/feed.json", agencyFeedCtrl.GetFeedJSON)   // 刊登 Feed（JSON，私有 Feed 需 API Key）
+
+		// 代理公司 Feed 管理
+		agencyFeedGroup := agencyGroup.Group("/me/feed")
+		agencyFeedGroup.Use(middlewares.JWTAuth(), middlewares.RequireUserType("agency"))
+		{
+			agencyFeedGroup.GET("", agencyFeedCtrl.GetFeedSettings)              // Feed 设置及 API Key 列表
+			agencyFeedGroup.PUT("", agencyFeedCtrl.UpdateFeedSettings)           // 更新 Feed 设置
+			agencyFeedGroup.POST("/keys", agencyFeedCtrl.CreateFeedKey)          // 创建 API Key
+			agencyFeedGroup.DELETE("/keys/:keyId", agencyFeedCtrl.RevokeFeedKey) // 吊销 API Key
+		}
 	}
 
 	// ========== 地区路由（公开） ==========
@@ -256,6 +417,34 @@ func SetupRoutes(
 		statisticsGroup.GET("/overview", statisticsCtrl.GetOverviewStatistics)       // 总览统计
 		statisticsGroup.GET("/properties", statisticsCtrl.GetPropertyStatistics)     // 房产统计
 		statisticsGroup.GET("/transactions", statisticsCtrl.GetTransactionStatistics) // 成交统计
+		statisticsGroup.GET("/first-hand", statisticsCtrl.GetFirstHandStatistics)     // 一手成交统计
 		statisticsGroup.GET("/users", statisticsCtrl.GetUserStatistics)              // 用户统计
 	}
+
+	// ========== 文件上传路由（需要认证） ==========
+	uploadGroup := v1.Group("/uploads")
+	uploadGroup.Use(middlewares.JWTAuth())
+	{
+		uploadGroup.POST("/images", uploadCtrl.UploadImage) // 上传图片
+	}
+
+	// ========== 管理后台路由（需要管理员权限） ==========
+	adminGroup := v1.Group("/admin")
+	adminGroup.Use(middlewares.JWTAuth(), middlewares.RequireUserType("admin"))
+	{
+		adminGroup.GET("/jobs", jobCtrl.ListJobs)                // 定时任务列表
+		adminGroup.GET("/jobs/:name/runs", jobCtrl.GetJobRuns)   // 任务执行记录
+		adminGroup.POST("/jobs/:name/run", jobCtrl.TriggerJob)   // 手动触发任务
+
+		adminGroup.GET("/moderation/reviews", moderationCtrl.ListReviews)                // 审核队列
+		adminGroup.POST("/moderation/reviews/:id/approve", moderationCtrl.ApproveReview) // 审核通过
+		adminGroup.POST("/moderation/reviews/:id/reject", moderationCtrl.RejectReview)   // 审核拒绝
+
+		adminGroup.GET("/duplicates", duplicateCtrl.ListClusters)                         // 重复组列表
+		adminGroup.GET("/duplicates/:fingerprint", duplicateCtrl.GetCluster)              // 重复组详情
+		adminGroup.POST("/duplicates/:fingerprint/merge", duplicateCtrl.MergeCluster)     // 合并重复组
+		adminGroup.POST("/duplicates/:fingerprint/dismiss", duplicateCtrl.DismissCluster) // 标记为非重复
+
+		adminGroup.GET("/audit", auditCtrl.ListAuditLogs) // 审计日志
+	}
 }
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"gorm.io/gorm"
+)
+
+// AgencyFeedService Methods:
+// 0. NewAgencyFeedService(agencyRepo *databases.AgencyRepo, feedRepo *databases.AgencyFeedRepo, auditService *AuditService) -> 注入依赖
+// 1. PrepareFeed(ctx context.Context, agencyID uint, apiKey, format string, since *time.Time) -> 校验访问权限并计算 ETag / Last-Modified
+// 2. BuildFeed(ctx context.Context, version *FeedVersion, since *time.Time) -> 生成 Feed
+// 3. GetSettings(ctx context.Context, userID uint) -> Feed 设置及 API Key 列表（代理公司）
+// 4. UpdateSettings(ctx context.Context, userID uint, req *models.UpdateAgencyFeedSettingsRequest) -> 更新 Feed 设置（代理公司）
+// 5. CreateKey(ctx context.Context, userID uint, req *models.CreateAgencyFeedKeyRequest) -> 创建 API Key（代理公司）
+// 6. RevokeKey(ctx context.Context, userID uint, keyID uint) -> 吊销 API Key（代理公司）
+
+// feedKeyPrefix API Key 前缀
+const feedKeyPrefix = "ajo_"
+
+// FeedVersion Feed 版本信息（用于条件请求）
+type FeedVersion struct {
+	Agency       *models.AgencyDetail
+	ETag         string
+	LastModified time.Time
+}
+
+// AgencyFeedService 代理公司刊登 Feed 服务
+type AgencyFeedService struct {
+	agencyRepo   *databases.AgencyRepo
+	feedRepo     *databases.AgencyFeedRepo
+	auditService *AuditService
+	siteBaseURL  string // 站点地址，用于生成房源链接和补全相对图片地址（为空时不生成链接）
+}
+
+// 0. NewAgencyFeedService 构造函数
+func NewAgencyFeedService(agencyRepo *databases.AgencyRepo, feedRepo *databases.AgencyFeedRepo, auditService *AuditService) *AgencyFeedService {
+	return &AgencyFeedService{
+		agencyRepo:   agencyRepo,
+		feedRepo:     feedRepo,
+		auditService: auditService,
+		siteBaseURL:  strings.TrimRight(tools.GetEnv("SITE_BASE_URL", ""), "/"),
+	}
+}
+
+// 1. PrepareFeed 校验访问权限（私有 Feed 需有效 API Key）并计算 Feed 版本
+func (s *AgencyFeedService) PrepareFeed(ctx context.Context, agencyID uint, apiKey, format string, since *time.Time) (*FeedVersion, error) {
+	agency, err := s.agencyRepo.FindByID(ctx, agencyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if apiKey != "" || agency.FeedPrivate {
+		if err := s.authorize(ctx, agency.ID, apiKey); err != nil {
+			return nil, err
+		}
+	}
+
+	stats, err := s.feedRepo.FindStats(ctx, agency.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastModified := agency.UpdatedAt
+	if stats.LastModified != nil && stats.LastModified.After(lastModified) {
+		lastModified = *stats.LastModified
+	}
+	sinceKey := ""
+	if since != nil {
+		sinceKey = since.UTC().Format(time.RFC3339Nano)
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%d|%d|%d|%s",
+		models.AgencyFeedVersion, format, agency.ID, stats.Count, lastModified.UnixNano(), sinceKey)))
+
+	return &FeedVersion{
+		Agency:       agency,
+		ETag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		LastModified: lastModified.UTC(),
+	}, nil
+}
+
+// 2. BuildFeed 生成 Feed：全量返回全部在架房源；增量时返回之后有修改的在架房源及下架/删除的房源
+func (s *AgencyFeedService) BuildFeed(ctx context.Context, version *FeedVersion, since *time.Time) (*models.AgencyFeed, error) {
+	agency := version.Agency
+
+	properties, err := s.feedRepo.FindListings(ctx, agency.UserID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.AgencyFeed{
+		Version:     models.AgencyFeedVersion,
+		GeneratedAt: time.Now().UTC(),
+		Since:       since,
+		Agency: models.AgencyFeedAgency{
+			ID:         agency.ID,
+			Name:       agency.CompanyName,
+			NameEn:     agency.CompanyNameEn,
+			LicenseNo:  agency.LicenseNo,
+			Phone:      agency.Phone,
+			Email:      agency.Email,
+			WebsiteURL: agency.WebsiteURL,
+		},
+		Listings: make([]models.AgencyFeedListing, len(properties)),
+		Removed:  []models.AgencyFeedRemoved{},
+	}
+	for i := range properties {
+		feed.Listings[i] = s.toFeedListing(&properties[i])
+	}
+
+	if since != nil {
+		removed, err := s.feedRepo.FindRemovedSince(ctx, agency.UserID, *since)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range removed {
+			item := models.AgencyFeedRemoved{
+				ID:         p.ID,
+				PropertyNo: p.PropertyNo,
+				Reference:  p.EstateNo,
+				Status:     p.Status,
+				RemovedAt:  p.UpdatedAt.UTC(),
+			}
+			if p.DeletedAt.Valid {
+				item.Status = "deleted"
+				item.RemovedAt = p.DeletedAt.Time.UTC()
+			}
+			feed.Removed = append(feed.Removed, item)
+		}
+	}
+
+	return feed, nil
+}
+
+// 3. GetSettings Feed 设置及 API Key 列表
+func (s *AgencyFeedService) GetSettings(ctx context.Context, userID uint) (*models.AgencyFeedSettingsResponse, error) {
+	agency, err := s.findOwnAgency(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.feedRepo.FindKeysByAgency(ctx, agency.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AgencyFeedSettingsResponse{
+		AgencyID: agency.ID,
+		Private:  agency.FeedPrivate,
+		Keys:     keys,
+	}, nil
+}
+
+// 4. UpdateSettings 更新 Feed 设置
+func (s *AgencyFeedService) UpdateSettings(ctx context.Context, userID uint, req *models.UpdateAgencyFeedSettingsRequest) (*models.AgencyFeedSettingsResponse, error) {
+	agency, err := s.findOwnAgency(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *agency
+	if err := s.agencyRepo.UpdateFeedPrivate(ctx, agency.ID, *req.Private); err != nil {
+		return nil, err
+	}
+	agency.FeedPrivate = *req.Private
+	s.auditService.Record(ctx, "agency", agency.ID, models.AuditActionUpdate, &before, agency)
+
+	return s.GetSettings(ctx, userID)
+}
+
+// 5. CreateKey 创建 API Key（明文只在此时返回）
+func (s *AgencyFeedService) CreateKey(ctx context.Context, userID uint, req *models.CreateAgencyFeedKeyRequest) (*models.CreateAgencyFeedKeyResponse, error) {
+	agency, err := s.findOwnAgency(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	plain := feedKeyPrefix + hex.EncodeToString(buf)
+
+	key := &models.AgencyFeedKey{
+		AgencyID:  agency.ID,
+		Name:      req.Name,
+		KeyPrefix: plain[:len(feedKeyPrefix)+8],
+		KeyHash:   hashFeedKey(plain),
+	}
+	if err := s.feedRepo.CreateKey(ctx, key); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "agency_feed_key", key.ID, models.AuditActionCreate, nil, key)
+
+	return &models.CreateAgencyFeedKeyResponse{AgencyFeedKey: *key, Key: plain}, nil
+}
+
+// 6. RevokeKey 吊销 API Key
+func (s *AgencyFeedService) RevokeKey(ctx context.Context, userID uint, keyID uint) error {
+	agency, err := s.findOwnAgency(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	key, err := s.feedRepo.FindKey(ctx, agency.ID, keyID)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return tools.ErrNotFound
+	}
+
+	now := time.Now()
+	revoked, err := s.feedRepo.RevokeKey(ctx, agency.ID, keyID, now)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return tools.ErrNotFound
+	}
+	before := *key
+	key.RevokedAt = &now
+	s.auditService.Record(ctx, "agency_feed_key", key.ID, models.AuditActionUpdate, &before, key)
+	return nil
+}
+
+// authorize 校验 API Key 属于该代理公司且未吊销
+func (s *AgencyFeedService) authorize(ctx context.Context, agencyID uint, apiKey string) error {
+	if apiKey == "" {
+		return tools.ErrUnauthorized
+	}
+	key, err := s.feedRepo.FindActiveKeyByHash(ctx, hashFeedKey(apiKey))
+	if err != nil {
+		return err
+	}
+	if key == nil || key.AgencyID != agencyID {
+		return tools.ErrUnauthorized
+	}
+	_ = s.feedRepo.TouchKey(ctx, key.ID, time.Now())
+	return nil
+}
+
+// findOwnAgency 查询当前用户的代理公司
+func (s *AgencyFeedService) findOwnAgency(ctx context.Context, userID uint) (*models.AgencyDetail, error) {
+	agency, err := s.agencyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+	return agency, nil
+}
+
+// toFeedListing 转换为 Feed 房源
+func (s *AgencyFeedService) toFeedListing(p *models.Property) models.AgencyFeedListing {
+	listing := models.AgencyFeedListing{
+		ID:           p.ID,
+		PropertyNo:   p.PropertyNo,
+		Reference:    p.EstateNo,
+		ListingType:  p.ListingType,
+		PropertyType: p.PropertyType,
+		Title:        p.Title,
+		Description:  p.Description,
+		Price:        p.Price,
+		Currency:     "HKD",
+		Area:         p.Area,
+		AreaUnit:     "sqft",
+		Bedrooms:     p.Bedrooms,
+		Bathrooms:    p.Bathrooms,
+		Address:      p.Address,
+		BuildingName: p.BuildingName,
+		Floor:        p.Floor,
+		District:     models.AgencyFeedDistrict{ID: p.DistrictID},
+		Images:       make([]models.AgencyFeedImage, len(p.Images)),
+		PublishedAt:  utcTime(p.PublishedAt),
+		ExpiresAt:    utcTime(p.ExpiredAt),
+		UpdatedAt:    p.UpdatedAt.UTC(),
+	}
+	if p.District != nil {
+		listing.District.Name = p.District.NameZhHant
+		listing.District.NameEn = p.District.NameEn
+		listing.District.Region = p.District.Region
+	}
+	for i, img := range p.Images {
+		listing.Images[i] = models.AgencyFeedImage{
+			URL:          s.absoluteURL(img.ImageURL),
+			ThumbnailURL: s.absoluteURL(img.ThumbnailURL),
+			Type:         img.ImageType,
+		}
+	}
+	if s.siteBaseURL != "" {
+		listing.URL = fmt.Sprintf("%s/properties/%d", s.siteBaseURL, p.ID)
+	}
+	return listing
+}
+
+// absoluteURL 相对地址（本地存储）补全为站点绝对地址
+func (s *AgencyFeedService) absoluteURL(url string) string {
+	if s.siteBaseURL != "" && strings.HasPrefix(url, "/") {
+		return s.siteBaseURL + url
+	}
+	return url
+}
+
+// hashFeedKey API Key 哈希
+func hashFeedKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// utcTime 转换为 UTC（nil 保持 nil）
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
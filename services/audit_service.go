@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// AuditService Methods:
+// 0. NewAuditService(repo *databases.AuditRepo, retentionDays int) -> 注入依赖
+// 1. Record(ctx context.Context, entityType string, entityID uint, action string, before, after interface{}) -> 记录变更
+// 2. ListAuditLogs(ctx context.Context, req *models.ListAuditLogsRequest) -> 查询审计日志（管理员）
+// 3. PurgeExpired(ctx context.Context) -> 清理超过保留期的审计日志（定时任务）
+// 4. RecordEach(ctx context.Context, entityType string, action string, before, after interface{}) -> 逐条记录批量变更
+
+// AuditService 审计日志服务
+type AuditService struct {
+	repo          *databases.AuditRepo
+	retentionDays int // 保留天数，0 表示永久保留
+}
+
+// 0. NewAuditService 构造函数
+func NewAuditService(repo *databases.AuditRepo, retentionDays int) *AuditService {
+	return &AuditService{repo: repo, retentionDays: retentionDays}
+}
+
+// 1. Record 记录一次变更：before/after 为同类型的模型（指针或值），创建时 before 为 nil，删除时 after 为 nil
+// 操作者和请求ID从上下文读取（定时任务记为 system）；写入失败只记录日志，不影响业务
+func (s *AuditService) Record(ctx context.Context, entityType string, entityID uint, action string, before, after interface{}) {
+	changes := diffFields(before, after)
+	if len(changes) == 0 && action == models.AuditActionUpdate {
+		return // 无实际变更
+	}
+
+	entry := &models.AuditLog{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ActorType:  "system",
+		RequestID:  tools.RequestIDFromContext(ctx),
+		Changes:    changes,
+	}
+	if actor, ok := tools.ActorFromContext(ctx); ok {
+		entry.ActorID = &actor.UserID
+		entry.ActorType = actor.UserType
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		log.Printf("⚠️  Failed to record audit log for %s %d (%s): %v", entityType, entityID, action, err)
+	}
+}
+
+// 2. ListAuditLogs 查询审计日志
+func (s *AuditService) ListAuditLogs(ctx context.Context, req *models.ListAuditLogsRequest) (*models.PaginatedAuditLogsResponse, error) {
+	if req.ID != nil && req.Entity == "" {
+		return nil, tools.WrapError(400, "entity is required when filtering by id", tools.ErrInvalidInput)
+	}
+
+	logs, total, err := s.repo.FindAll(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedAuditLogsResponse{
+		Data:       logs,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// 3. PurgeExpired 清理超过保留期的审计日志
+func (s *AuditService) PurgeExpired(ctx context.Context) error {
+	if s.retentionDays <= 0 {
+		return nil
+	}
+
+	deleted, err := s.repo.DeleteBefore(ctx, time.Now().AddDate(0, 0, -s.retentionDays))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("🧹 Audit log cleanup: %d entries older than %d days removed", deleted, s.retentionDays)
+	}
+	return nil
+}
+
+// 4. RecordEach 逐条记录批量操作（如图片排序、设置封面）：before/after 为同类型模型的切片，按 ID 字段匹配，未变化的实体不记录
+func (s *AuditService) RecordEach(ctx context.Context, entityType string, action string, before, after interface{}) {
+	befores, _ := entitiesByID(before)
+	afters, ids := entitiesByID(after)
+	for _, id := range ids {
+		s.Record(ctx, entityType, id, action, befores[id], afters[id])
+	}
+}
+
+// entitiesByID 按 ID 字段索引模型切片，同时返回切片中的 ID 顺序
+func entitiesByID(list interface{}) (map[uint]interface{}, []uint) {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice {
+		return nil, nil
+	}
+	entities := make(map[uint]interface{}, rv.Len())
+	ids := make([]uint, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		field := item.FieldByName("ID")
+		if !field.IsValid() || field.Kind() != reflect.Uint {
+			continue
+		}
+		id := uint(field.Uint())
+		entities[id] = item.Addr().Interface()
+		ids = append(ids, id)
+	}
+	return entities, ids
+}
+
+// ============ 字段差异 ============
+
+// auditSkipFields 不记录差异的字段（时间戳由数据库维护）
+var auditSkipFields = map[string]bool{
+	"CreatedAt": true,
+	"UpdatedAt": true,
+	"DeletedAt": true,
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// diffFields 比较两个同类型模型的字段，返回有变化的字段
+// 只比较基础类型、时间及其指针字段；关联（结构体、切片）和 json:"-" 字段不记录
+func diffFields(before, after interface{}) []models.AuditChange {
+	b, a := structValue(before), structValue(after)
+	if !b.IsValid() && !a.IsValid() {
+		return nil
+	}
+	if !b.IsValid() {
+		b = reflect.Zero(a.Type())
+	}
+	if !a.IsValid() {
+		a = reflect.Zero(b.Type())
+	}
+
+	var changes []models.AuditChange
+	t := b.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := auditFieldName(field)
+		if !ok {
+			continue
+		}
+
+		oldValue, newValue := auditValue(b.Field(i)), auditValue(a.Field(i))
+		if auditEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, models.AuditChange{Field: name, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+// structValue 解引用得到结构体值，nil 返回无效值
+func structValue(v interface{}) reflect.Value {
+	if v == nil {
+		return reflect.Value{}
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return rv
+}
+
+// auditFieldName 返回字段的 JSON 名称，不需要记录的字段返回 false
+func auditFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() || auditSkipFields[field.Name] {
+		return "", false
+	}
+
+	ft := field.Type
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+	switch ft.Kind() {
+	case reflect.Struct:
+		if ft != timeType {
+			return "", false
+		}
+	case reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return "", false
+	}
+
+	name := field.Name
+	if tag := field.Tag.Get("json"); tag != "" {
+		if tag == "-" {
+			return "", false
+		}
+		if jsonName := strings.Split(tag, ",")[0]; jsonName != "" {
+			name = jsonName
+		}
+	}
+	return name, true
+}
+
+// auditValue 取字段值（指针解引用，nil 指针为 nil）
+func auditValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// auditEqual 比较字段值（时间按时刻比较，忽略时区和单调时钟）
+func auditEqual(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Equal(tb)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
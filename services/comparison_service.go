@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// ComparisonService Methods:
+// 0. NewComparisonService(propertyRepo *databases.PropertyRepo, estateRepo *databases.EstateRepo) -> 注入依赖
+// 1. CompareProperties(ctx context.Context, ids []uint) -> 房产对比
+// 2. CompareEstates(ctx context.Context, ids []uint) -> 屋苑对比
+
+// ComparisonService 房产及屋苑对比服务
+type ComparisonService struct {
+	propertyRepo *databases.PropertyRepo
+	estateRepo   *databases.EstateRepo
+}
+
+// 0. NewComparisonService 构造函数
+func NewComparisonService(propertyRepo *databases.PropertyRepo, estateRepo *databases.EstateRepo) *ComparisonService {
+	return &ComparisonService{
+		propertyRepo: propertyRepo,
+		estateRepo:   estateRepo,
+	}
+}
+
+// comparisonField 参与差异计算的字段（values 按对比项顺序，缺失值为 nil）
+type comparisonField struct {
+	name   string
+	prefer string
+	values []interface{}
+}
+
+// 1. CompareProperties 房产对比（未发布的房产视为不存在）
+func (s *ComparisonService) CompareProperties(ctx context.Context, ids []uint) (*models.PropertyComparisonResponse, error) {
+	ids, err := uniqueCompareIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := s.propertyRepo.FindByIDsWithDetails(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Property, len(properties))
+	names := make([]string, 0, len(properties))
+	for i := range properties {
+		p := &properties[i]
+		if isUnderModeration(p.Status) {
+			continue
+		}
+		byID[p.ID] = p
+		if p.BuildingName != "" {
+			names = append(names, p.BuildingName)
+		}
+	}
+
+	estates, err := s.estateRepo.FindByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	estateByName := make(map[string]*models.Estate, len(estates))
+	for i := range estates {
+		if _, ok := estateByName[estates[i].Name]; !ok {
+			estateByName[estates[i].Name] = &estates[i]
+		}
+	}
+
+	now := time.Now()
+	items := make([]models.PropertyComparisonItem, len(ids))
+	facilities := make([][]string, len(ids))
+	for i, id := range ids {
+		p, ok := byID[id]
+		if !ok {
+			return nil, tools.ErrNotFound
+		}
+		items[i] = toPropertyComparisonItem(p, estateByName[p.BuildingName], now)
+		facilities[i] = items[i].Facilities
+	}
+
+	common, unique := splitFacilities(facilities)
+	fields := []comparisonField{
+		{name: "price", prefer: models.ComparePreferLower},
+		{name: "area", prefer: models.ComparePreferHigher},
+		{name: "price_per_sqft", prefer: models.ComparePreferLower},
+		{name: "bedrooms", prefer: models.ComparePreferHigher},
+		{name: "bathrooms", prefer: models.ComparePreferHigher},
+		{name: "building_age", prefer: models.ComparePreferLower},
+		{name: "price_vs_estate_avg", prefer: models.ComparePreferLower},
+		{name: "property_type"},
+		{name: "floor"},
+		{name: "orientation"},
+		{name: "district"},
+		{name: "estate"},
+		{name: "primary_school_net"},
+		{name: "secondary_school_net"},
+		{name: "facilities"},
+	}
+	for i := range fields {
+		fields[i].values = make([]interface{}, len(items))
+	}
+	for i, item := range items {
+		var estateName interface{}
+		if item.Estate != nil {
+			estateName = item.Estate.Name
+		}
+		values := []interface{}{
+			item.Price, item.Area, item.PricePerSqft, item.Bedrooms, item.Bathrooms,
+			intValue(item.BuildingAge), floatValue(item.PriceVsEstateAvg),
+			item.PropertyType, item.Floor, item.Orientation, districtName(item.District), estateName,
+			item.PrimarySchoolNet, item.SecondarySchoolNet, unique[i],
+		}
+		for j := range fields {
+			fields[j].values[i] = values[j]
+		}
+	}
+
+	return &models.PropertyComparisonResponse{
+		Items:            items,
+		Differences:      comparisonDifferences(ids, fields),
+		CommonFacilities: common,
+	}, nil
+}
+
+// 2. CompareEstates 屋苑对比
+func (s *ComparisonService) CompareEstates(ctx context.Context, ids []uint) (*models.EstateComparisonResponse, error) {
+	ids, err := uniqueCompareIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	estates, err := s.estateRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Estate, len(estates))
+	for i := range estates {
+		byID[estates[i].ID] = &estates[i]
+	}
+
+	now := time.Now()
+	items := make([]models.EstateComparisonItem, len(ids))
+	facilities := make([][]string, len(ids))
+	for i, id := range ids {
+		e, ok := byID[id]
+		if !ok {
+			return nil, tools.ErrNotFound
+		}
+		items[i] = toEstateComparisonItem(e, now)
+		facilities[i] = items[i].Facilities
+	}
+
+	common, unique := splitFacilities(facilities)
+	fields := []comparisonField{
+		{name: "avg_transaction_price", prefer: models.ComparePreferLower},
+		{name: "rental_yield", prefer: models.ComparePreferHigher},
+		{name: "building_age", prefer: models.ComparePreferLower},
+		{name: "total_units"},
+		{name: "total_blocks"},
+		{name: "recent_transactions_count", prefer: models.ComparePreferHigher},
+		{name: "for_sale_count"},
+		{name: "for_rent_count"},
+		{name: "district"},
+		{name: "developer"},
+		{name: "management_company"},
+		{name: "primary_school_net"},
+		{name: "secondary_school_net"},
+		{name: "facilities"},
+	}
+	for i := range fields {
+		fields[i].values = make([]interface{}, len(items))
+	}
+	for i, item := range items {
+		values := []interface{}{
+			item.AvgTransactionPrice, item.RentalYield, intValue(item.BuildingAge),
+			item.TotalUnits, item.TotalBlocks, item.RecentTransactionsCount, item.ForSaleCount, item.ForRentCount,
+			districtName(item.District), item.Developer, item.ManagementCompany,
+			item.PrimarySchoolNet, item.SecondarySchoolNet, unique[i],
+		}
+		for j := range fields {
+			fields[j].values[i] = values[j]
+		}
+	}
+
+	return &models.EstateComparisonResponse{
+		Items:            items,
+		Differences:      comparisonDifferences(ids, fields),
+		CommonFacilities: common,
+	}, nil
+}
+
+// toPropertyComparisonItem 转换为房产对比项（estate 可为 nil）
+func toPropertyComparisonItem(p *models.Property, estate *models.Estate, now time.Time) models.PropertyComparisonItem {
+	item := models.PropertyComparisonItem{
+		ID:                 p.ID,
+		PropertyNo:         p.PropertyNo,
+		Title:              p.Title,
+		ListingType:        p.ListingType,
+		PropertyType:       p.PropertyType,
+		Status:             p.Status,
+		Price:              p.Price,
+		Area:               p.Area,
+		Bedrooms:           p.Bedrooms,
+		Bathrooms:          p.Bathrooms,
+		Floor:              p.Floor,
+		Orientation:        p.Orientation,
+		Address:            p.Address,
+		BuildingName:       p.BuildingName,
+		District:           p.District,
+		PrimarySchoolNet:   p.PrimarySchool,
+		SecondarySchoolNet: p.SecondarySchool,
+		Facilities:         []string{},
+	}
+	if p.Area > 0 {
+		item.PricePerSqft = round2(p.Price / p.Area)
+	}
+	if len(p.Images) > 0 {
+		item.CoverImage = p.Images[0].ImageURL
+	}
+
+	if estate != nil {
+		item.Estate = &models.ComparisonEstate{
+			ID:                  estate.ID,
+			Name:                estate.Name,
+			CompletionYear:      estate.CompletionYear,
+			AvgTransactionPrice: estate.AvgTransactionPrice,
+			RentalYield:         estate.RentalYield,
+		}
+		item.BuildingAge = buildingAge(estate.CompletionYear, now)
+		// 校网以房源填写为准，未填写时沿用屋苑
+		if item.PrimarySchoolNet == "" {
+			item.PrimarySchoolNet = estate.PrimarySchoolNet
+		}
+		if item.SecondarySchoolNet == "" {
+			item.SecondarySchoolNet = estate.SecondarySchoolNet
+		}
+		// 呎价仅与出售房源比较（屋苑平均成交呎价为出售成交）
+		if p.ListingType == "sale" && estate.AvgTransactionPrice > 0 && item.PricePerSqft > 0 {
+			diff := round2((item.PricePerSqft - estate.AvgTransactionPrice) / estate.AvgTransactionPrice * 100)
+			item.PriceVsEstateAvg = &diff
+		}
+		item.Facilities = facilityNames(estate.Facilities)
+	}
+	return item
+}
+
+// toEstateComparisonItem 转换为屋苑对比项
+func toEstateComparisonItem(e *models.Estate, now time.Time) models.EstateComparisonItem {
+	item := models.EstateComparisonItem{
+		ID:                      e.ID,
+		Name:                    e.Name,
+		NameEn:                  e.NameEn,
+		Address:                 e.Address,
+		District:                e.District,
+		CompletionYear:          e.CompletionYear,
+		BuildingAge:             buildingAge(e.CompletionYear, now),
+		Developer:               e.Developer,
+		ManagementCompany:       e.ManagementCompany,
+		TotalBlocks:             e.TotalBlocks,
+		TotalUnits:              e.TotalUnits,
+		PrimarySchoolNet:        e.PrimarySchoolNet,
+		SecondarySchoolNet:      e.SecondarySchoolNet,
+		AvgTransactionPrice:     e.AvgTransactionPrice,
+		RentalYield:             e.RentalYield,
+		RecentTransactionsCount: e.RecentTransactionsCount,
+		ForSaleCount:            e.ForSaleCount,
+		ForRentCount:            e.ForRentCount,
+		Facilities:              facilityNames(e.Facilities),
+	}
+	for _, img := range e.Images {
+		if img.IsCover {
+			item.CoverImage = img.ImageURL
+			break
+		}
+	}
+	if item.CoverImage == "" && len(e.Images) > 0 {
+		item.CoverImage = e.Images[0].ImageURL
+	}
+	return item
+}
+
+// uniqueCompareIDs 去除重复ID（保留顺序），去重后少于 2 个时返回错误
+func uniqueCompareIDs(ids []uint) ([]uint, error) {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	if len(result) < 2 {
+		return nil, tools.WrapError(400, "at least 2 different ids are required", tools.ErrInvalidInput)
+	}
+	return result, nil
+}
+
+// comparisonDifferences 找出取值不同的字段，并按优选方向标出表现最佳的对比项
+func comparisonDifferences(ids []uint, fields []comparisonField) []models.ComparisonDifference {
+	differences := []models.ComparisonDifference{}
+	for _, field := range fields {
+		distinct := make(map[string]bool)
+		for _, v := range field.values {
+			distinct[fmt.Sprint(v)] = true
+		}
+		if len(distinct) <= 1 {
+			continue
+		}
+
+		diff := models.ComparisonDifference{
+			Field:  field.name,
+			Prefer: field.prefer,
+			Values: make([]models.ComparisonValue, len(ids)),
+		}
+		var best float64
+		found := false
+		for i, v := range field.values {
+			diff.Values[i] = models.ComparisonValue{ID: ids[i], Value: v}
+
+			n, ok := numericValue(v)
+			if field.prefer == "" || !ok {
+				continue
+			}
+			better := !found ||
+				(field.prefer == models.ComparePreferLower && n < best) ||
+				(field.prefer == models.ComparePreferHigher && n > best)
+			if better {
+				best, found = n, true
+				diff.BestIDs = []uint{ids[i]}
+			} else if n == best {
+				diff.BestIDs = append(diff.BestIDs, ids[i])
+			}
+		}
+		differences = append(differences, diff)
+	}
+	return differences
+}
+
+// splitFacilities 拆分共同设施及各对比项独有设施
+func splitFacilities(lists [][]string) ([]string, [][]string) {
+	counts := make(map[string]int)
+	for _, list := range lists {
+		for _, name := range list {
+			counts[name]++
+		}
+	}
+
+	common := []string{}
+	if len(lists) > 0 {
+		for _, name := range lists[0] {
+			if counts[name] == len(lists) {
+				common = append(common, name)
+			}
+		}
+	}
+
+	unique := make([][]string, len(lists))
+	for i, list := range lists {
+		unique[i] = []string{}
+		for _, name := range list {
+			if counts[name] < len(lists) {
+				unique[i] = append(unique[i], name)
+			}
+		}
+	}
+	return common, unique
+}
+
+// facilityNames 设施名称（繁体中文）
+func facilityNames(facilities []models.Facility) []string {
+	names := make([]string, len(facilities))
+	for i, f := range facilities {
+		names[i] = f.NameZhHant
+	}
+	return names
+}
+
+// buildingAge 按落成年份计算楼龄（未知时返回 nil）
+func buildingAge(completionYear int, now time.Time) *int {
+	if completionYear <= 0 {
+		return nil
+	}
+	age := now.Year() - completionYear
+	if age < 0 {
+		age = 0
+	}
+	return &age
+}
+
+// districtName 地区名称（繁体中文）
+func districtName(d *models.District) interface{} {
+	if d == nil {
+		return nil
+	}
+	return d.NameZhHant
+}
+
+// intValue 指针解引用（nil 保持 nil，便于比较）
+func intValue(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// floatValue 指针解引用（nil 保持 nil，便于比较）
+func floatValue(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// numericValue 转换为数值（非数值返回 false）
+func numericValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
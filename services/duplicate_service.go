@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// DuplicateService Methods:
+// 0. NewDuplicateService(propertyRepo *databases.PropertyRepo, clusterRepo *databases.DuplicateClusterRepo, auditService *AuditService) -> 注入依赖
+// 1. GetPropertyDuplicates(ctx context.Context, id uint) -> 同一单位的其他在架刊登
+// 2. ListClusters(ctx context.Context, req *models.ListDuplicateClustersRequest) -> 重复组列表（管理员）
+// 3. GetCluster(ctx context.Context, fingerprint string) -> 重复组详情（管理员）
+// 4. MergeCluster(ctx context.Context, fingerprint string, adminID uint, req *models.MergeDuplicatesRequest) -> 合并重复组并指定主刊登（管理员）
+// 5. DismissCluster(ctx context.Context, fingerprint string, adminID uint, req *models.DismissDuplicatesRequest) -> 标记为非重复（管理员）
+// 6. BackfillFingerprints(ctx context.Context) -> 补算房产指纹（定时任务）
+
+// DuplicateService 重复刊登检测服务
+type DuplicateService struct {
+	propertyRepo *databases.PropertyRepo
+	clusterRepo  *databases.DuplicateClusterRepo
+	auditService *AuditService
+}
+
+// 0. NewDuplicateService 构造函数
+func NewDuplicateService(propertyRepo *databases.PropertyRepo, clusterRepo *databases.DuplicateClusterRepo, auditService *AuditService) *DuplicateService {
+	return &DuplicateService{
+		propertyRepo: propertyRepo,
+		clusterRepo:  clusterRepo,
+		auditService: auditService,
+	}
+}
+
+// 1. GetPropertyDuplicates 同一单位的其他在架刊登（已标记为非重复时返回空）
+func (s *DuplicateService) GetPropertyDuplicates(ctx context.Context, id uint) ([]models.PropertyResponse, error) {
+	property, err := s.propertyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []models.PropertyResponse{}
+	if property.Fingerprint == "" {
+		return data, nil
+	}
+
+	cluster, err := s.clusterRepo.FindByFingerprint(ctx, property.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if cluster != nil && cluster.Status == models.DuplicateClusterDismissed {
+		return data, nil
+	}
+
+	listings, err := s.propertyRepo.FindByFingerprint(ctx, property.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range listings {
+		if p.ID != id {
+			data = append(data, *p.ToPropertyResponse())
+		}
+	}
+	return data, nil
+}
+
+// 2. ListClusters 重复组列表
+func (s *DuplicateService) ListClusters(ctx context.Context, req *models.ListDuplicateClustersRequest) (*models.PaginatedDuplicateClustersResponse, error) {
+	groups, total, err := s.propertyRepo.FindDuplicateGroups(ctx, req.Status, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints := make([]string, len(groups))
+	for i, g := range groups {
+		fingerprints[i] = g.Fingerprint
+	}
+	clusters, err := s.clusterRepo.FindByFingerprints(ctx, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	clusterByFingerprint := make(map[string]*models.DuplicateCluster, len(clusters))
+	for i := range clusters {
+		clusterByFingerprint[clusters[i].Fingerprint] = &clusters[i]
+	}
+
+	data := make([]models.DuplicateClusterResponse, len(groups))
+	for i, g := range groups {
+		listings, err := s.propertyRepo.FindByFingerprint(ctx, g.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = toDuplicateClusterResponse(g, clusterByFingerprint[g.Fingerprint], listings)
+	}
+
+	return &models.PaginatedDuplicateClustersResponse{
+		Data:       data,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// 3. GetCluster 重复组详情
+func (s *DuplicateService) GetCluster(ctx context.Context, fingerprint string) (*models.DuplicateClusterResponse, error) {
+	listings, cluster, err := s.findCluster(ctx, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	response := toDuplicateClusterResponse(groupOf(fingerprint, listings), cluster, listings)
+	return &response, nil
+}
+
+// 4. MergeCluster 合并重复组：指定主刊登，列表折叠时只显示主刊登
+func (s *DuplicateService) MergeCluster(ctx context.Context, fingerprint string, adminID uint, req *models.MergeDuplicatesRequest) (*models.DuplicateClusterResponse, error) {
+	listings, cluster, err := s.findCluster(ctx, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, p := range listings {
+		if p.ID == req.CanonicalPropertyID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, tools.WrapError(400, "canonical property must be an available listing in this cluster", tools.ErrInvalidInput)
+	}
+
+	cluster, before := prepareCluster(cluster, fingerprint)
+	canonicalID := req.CanonicalPropertyID
+	cluster.Status = models.DuplicateClusterMerged
+	cluster.CanonicalPropertyID = &canonicalID
+	cluster.Note = req.Note
+	cluster.ResolvedBy = adminID
+	if err := s.clusterRepo.Save(ctx, cluster); err != nil {
+		return nil, err
+	}
+	s.recordCluster(ctx, before, cluster)
+
+	response := toDuplicateClusterResponse(groupOf(fingerprint, listings), cluster, listings)
+	return &response, nil
+}
+
+// 5. DismissCluster 标记为非重复：不再折叠，也不再出现在未处理列表中
+func (s *DuplicateService) DismissCluster(ctx context.Context, fingerprint string, adminID uint, req *models.DismissDuplicatesRequest) (*models.DuplicateClusterResponse, error) {
+	listings, cluster, err := s.findCluster(ctx, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, before := prepareCluster(cluster, fingerprint)
+	cluster.Status = models.DuplicateClusterDismissed
+	cluster.CanonicalPropertyID = nil
+	cluster.Note = req.Note
+	cluster.ResolvedBy = adminID
+	if err := s.clusterRepo.Save(ctx, cluster); err != nil {
+		return nil, err
+	}
+	s.recordCluster(ctx, before, cluster)
+
+	response := toDuplicateClusterResponse(groupOf(fingerprint, listings), cluster, listings)
+	return &response, nil
+}
+
+// prepareCluster 返回待处理的重复组（尚无记录时新建）及其处理前的副本（新建时为 nil）
+func prepareCluster(cluster *models.DuplicateCluster, fingerprint string) (*models.DuplicateCluster, *models.DuplicateCluster) {
+	if cluster == nil {
+		return &models.DuplicateCluster{Fingerprint: fingerprint}, nil
+	}
+	before := *cluster
+	return cluster, &before
+}
+
+// recordCluster 记录重复组的合并/标记操作
+func (s *DuplicateService) recordCluster(ctx context.Context, before, after *models.DuplicateCluster) {
+	if before == nil {
+		s.auditService.Record(ctx, "duplicate_cluster", after.ID, models.AuditActionCreate, nil, after)
+		return
+	}
+	s.auditService.Record(ctx, "duplicate_cluster", after.ID, models.AuditActionUpdate, before, after)
+}
+
+// 6. BackfillFingerprints 补算尚未计算指纹的房产（历史数据及指纹规则调整后）
+func (s *DuplicateService) BackfillFingerprints(ctx context.Context) error {
+	const batchSize = 500
+
+	var afterID uint
+	updated := 0
+	for {
+		properties, err := s.propertyRepo.FindWithoutFingerprint(ctx, afterID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(properties) == 0 {
+			break
+		}
+
+		for i := range properties {
+			afterID = properties[i].ID
+			fingerprint := propertyFingerprint(&properties[i])
+			if fingerprint == "" {
+				continue
+			}
+			if err := s.propertyRepo.UpdateFingerprint(ctx, properties[i].ID, fingerprint); err != nil {
+				return err
+			}
+			updated++
+		}
+	}
+
+	if updated > 0 {
+		log.Printf("🔁 Property fingerprint backfill: %d updated", updated)
+	}
+	return nil
+}
+
+// findCluster 查询重复组的在架刊登及处理记录（少于2个刊登视为不存在）
+func (s *DuplicateService) findCluster(ctx context.Context, fingerprint string) ([]models.Property, *models.DuplicateCluster, error) {
+	listings, err := s.propertyRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(listings) < 2 {
+		return nil, nil, tools.ErrNotFound
+	}
+
+	cluster, err := s.clusterRepo.FindByFingerprint(ctx, fingerprint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return listings, cluster, nil
+}
+
+// groupOf 根据刊登列表统计重复组
+func groupOf(fingerprint string, listings []models.Property) models.DuplicateGroup {
+	publishers := make(map[uint]struct{})
+	for _, p := range listings {
+		publishers[p.PublisherID] = struct{}{}
+	}
+	return models.DuplicateGroup{
+		Fingerprint:    fingerprint,
+		ListingCount:   int64(len(listings)),
+		PublisherCount: int64(len(publishers)),
+	}
+}
+
+// toDuplicateClusterResponse 转换为重复组响应
+func toDuplicateClusterResponse(group models.DuplicateGroup, cluster *models.DuplicateCluster, listings []models.Property) models.DuplicateClusterResponse {
+	response := models.DuplicateClusterResponse{
+		Fingerprint:    group.Fingerprint,
+		ListingCount:   group.ListingCount,
+		PublisherCount: group.PublisherCount,
+		Status:         "open",
+		Listings:       make([]models.PropertyResponse, len(listings)),
+	}
+	if cluster != nil {
+		response.Status = cluster.Status
+		response.CanonicalPropertyID = cluster.CanonicalPropertyID
+		response.Note = cluster.Note
+	}
+	for i, p := range listings {
+		response.Listings[i] = *p.ToPropertyResponse()
+	}
+	return response
+}
+
+// ============ 指纹 ============
+
+// propertyFingerprint 计算房产指纹：规范化地址、大厦、楼层、面积（取整）和刊登类型后取 SHA-1
+// 已关联屋苑单位的房源改用单位ID和刊登类型（同一单位不同写法的地址也视为重复）；
+// 地址为空时返回空字符串（不参与重复检测）
+func propertyFingerprint(p *models.Property) string {
+	if p.UnitID != nil {
+		key := fmt.Sprintf("unit:%d|%s", *p.UnitID, p.ListingType)
+		sum := sha1.Sum([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+
+	address := normalizeAddress(p.Address)
+	if address == "" {
+		return ""
+	}
+
+	key := fmt.Sprintf("%s|%s|%s|%d|%s",
+		address,
+		normalizeAddress(p.BuildingName),
+		normalizeFloor(p.Floor),
+		int64(math.Round(p.Area)),
+		p.ListingType,
+	)
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// addressNoise 地址中不影响定位的常见词（规范化后匹配）
+var addressNoise = []string{"flat", "unit", "room", "rm", "block", "blk", "tower", "室", "單位", "单位", "座"}
+
+// normalizeAddress 规范化地址：全角转半角、转小写、去除标点空格和常见修饰词
+func normalizeAddress(s string) string {
+	s = strings.ToLower(toHalfWidth(s))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+	for _, w := range addressNoise {
+		s = strings.ReplaceAll(s, w, "")
+	}
+	return s
+}
+
+// floorSuffixes 楼层写法中的后缀（如 12/F、12樓、12 floor）
+var floorSuffixes = []string{"floor", "f", "樓", "楼", "層", "层"}
+
+// normalizeFloor 规范化楼层：12/F、12樓、12 Floor 均视为 12；G/F、地下视为 g
+func normalizeFloor(s string) string {
+	s = normalizeAddress(s)
+	if s == "地下" || s == "gf" || s == "ground" || s == "groundfloor" {
+		return "g"
+	}
+	for _, suffix := range floorSuffixes {
+		if trimmed := strings.TrimSuffix(s, suffix); trimmed != s && trimmed != "" {
+			return trimmed
+		}
+	}
+	return s
+}
+
+// toHalfWidth 全角字符转半角
+func toHalfWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			return r - 0xFEE0
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"gorm.io/gorm"
+)
+
+// EstateBlockService Methods:
+// 0. NewEstateBlockService(repo *databases.EstateBlockRepo, estateRepo *databases.EstateRepo, auditService *AuditService) -> 注入依赖
+// 1. ListBlocks(ctx context.Context, estateID uint, req *models.ListEstateBlocksRequest) -> 屋苑座数列表（可含单位）
+// 2. ListUnits(ctx context.Context, estateID uint, blockID uint, req *models.ListEstateUnitsRequest) -> 座数单位列表
+// 3. ImportUnits(ctx context.Context, estateID uint, fileName string, data []byte) -> 导入 CSV/XLSX 单位目录（管理员）
+// 4. Template() -> 单位目录导入模板（CSV 表头及示例行）
+// 5. GetUnit(ctx context.Context, unitID uint) -> 单位详情（含所属座数）及所属屋苑
+
+// estateUnitColumn 单位目录导入列定义
+type estateUnitColumn struct {
+	Name     string
+	Required bool
+	Example  string
+}
+
+// estateUnitColumns 单位目录文件列（面积、座向及房间数留空时已有单位保持原值）
+var estateUnitColumns = []estateUnitColumn{
+	{Name: "block", Required: true, Example: "第1座"},
+	{Name: "block_en", Example: "Tower 1"},
+	{Name: "floor", Required: true, Example: "23"},
+	{Name: "flat", Required: true, Example: "A"},
+	{Name: "saleable_area", Example: "452"},
+	{Name: "gross_area", Example: "598"},
+	{Name: "orientation", Example: "東南"},
+	{Name: "bedrooms", Example: "2"},
+}
+
+// estateUnitMaxRowErrors 导入失败时最多返回的行错误数
+const estateUnitMaxRowErrors = 20
+
+// EstateBlockService 屋苑座数及单位目录服务
+type EstateBlockService struct {
+	repo         *databases.EstateBlockRepo
+	estateRepo   *databases.EstateRepo
+	auditService *AuditService
+	maxSize      int64 // 单个文件最大字节数
+}
+
+// 0. NewEstateBlockService 构造函数
+func NewEstateBlockService(repo *databases.EstateBlockRepo, estateRepo *databases.EstateRepo, auditService *AuditService) *EstateBlockService {
+	return &EstateBlockService{
+		repo:         repo,
+		estateRepo:   estateRepo,
+		auditService: auditService,
+		maxSize:      int64(tools.GetEnvInt("IMPORT_MAX_SIZE_MB", 10)) << 20,
+	}
+}
+
+// MaxSize 单个单位目录文件最大字节数
+func (s *EstateBlockService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// estateUnitRow 单位目录文件中的一行
+type estateUnitRow struct {
+	block        string
+	blockEn      string
+	floor        string
+	flat         string
+	saleableArea float64
+	grossArea    float64
+	orientation  string
+	bedrooms     *int
+}
+
+// 1. ListBlocks 屋苑座数列表
+func (s *EstateBlockService) ListBlocks(ctx context.Context, estateID uint, req *models.ListEstateBlocksRequest) ([]models.EstateBlock, error) {
+	if err := s.checkEstate(ctx, estateID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindBlocks(ctx, estateID, req.IncludeUnits)
+}
+
+// 2. ListUnits 座数单位列表（楼层按 12/F、12樓 等写法规范化后筛选）
+func (s *EstateBlockService) ListUnits(ctx context.Context, estateID uint, blockID uint, req *models.ListEstateUnitsRequest) ([]models.EstateUnit, error) {
+	if err := s.checkEstate(ctx, estateID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindBlock(ctx, estateID, blockID); err != nil {
+		return nil, err
+	}
+
+	floor := ""
+	if req.Floor != "" {
+		floor = estateUnitFloor(req.Floor)
+	}
+	return s.repo.FindUnits(ctx, blockID, floor)
+}
+
+// 3. ImportUnits 导入单位目录：整份文件校验通过才写入；按座数/楼层/单位新增或更新单位，随后汇总各座楼层数及单位数
+func (s *EstateBlockService) ImportUnits(ctx context.Context, estateID uint, fileName string, data []byte) (*models.EstateUnitImportResponse, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, tools.ErrFileTooLarge
+	}
+	format := tools.SpreadsheetFormat(fileName)
+	if format == "" {
+		return nil, tools.ErrUnsupportedSpreadsheet
+	}
+	if err := s.checkEstate(ctx, estateID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tools.ReadSpreadsheet(format, data)
+	if err != nil {
+		return nil, tools.WrapError(400, err.Error(), tools.ErrInvalidInput)
+	}
+	parsed, err := parseEstateUnitRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.repo.FindBlocks(ctx, estateID, false)
+	if err != nil {
+		return nil, err
+	}
+	blockByName := make(map[string]*models.EstateBlock, len(blocks))
+	blockNames := make(map[uint]string, len(blocks))
+	for i := range blocks {
+		blockByName[strings.ToUpper(blocks[i].Name)] = &blocks[i]
+		blockNames[blocks[i].ID] = blocks[i].Name
+	}
+
+	existing, err := s.repo.FindAllUnits(ctx, estateID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*models.EstateUnit, len(existing))
+	for i := range existing {
+		byKey[unitKey(blockNames[existing[i].BlockID], existing[i].Floor, existing[i].Flat)] = &existing[i]
+	}
+
+	var touchedBlocks []*models.EstateBlock
+	touched := make(map[*models.EstateBlock]bool)
+	units := make([]*models.EstateUnit, 0, len(parsed))
+	result := &models.EstateUnitImportResponse{}
+	for _, row := range parsed {
+		block, ok := blockByName[strings.ToUpper(row.block)]
+		if !ok {
+			block = &models.EstateBlock{
+				EstateID:  estateID,
+				Name:      row.block,
+				SortOrder: len(blockByName),
+			}
+			blockByName[strings.ToUpper(row.block)] = block
+			result.CreatedBlocks++
+		}
+		if !touched[block] && (block.ID == 0 || (row.blockEn != "" && row.blockEn != block.NameEn)) {
+			touched[block] = true
+			touchedBlocks = append(touchedBlocks, block)
+		}
+		if row.blockEn != "" {
+			block.NameEn = row.blockEn
+		}
+
+		unit, ok := byKey[unitKey(row.block, row.floor, row.flat)]
+		if ok {
+			result.UpdatedUnits++
+		} else {
+			result.CreatedUnits++
+			unit = &models.EstateUnit{
+				EstateID: estateID,
+				Floor:    row.floor,
+				Flat:     row.flat,
+			}
+		}
+
+		unit.Block = block
+		unit.FloorLevel = estateUnitFloorLevel(row.floor)
+		if row.saleableArea > 0 {
+			unit.SaleableArea = row.saleableArea
+		}
+		if row.grossArea > 0 {
+			unit.GrossArea = row.grossArea
+		}
+		if row.orientation != "" {
+			unit.Orientation = row.orientation
+		}
+		if row.bedrooms != nil {
+			unit.Bedrooms = row.bedrooms
+		}
+		units = append(units, unit)
+	}
+
+	result.TotalBlocks, result.TotalUnits, err = s.repo.ImportUnits(ctx, estateID, touchedBlocks, units)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "estate", estateID, models.AuditActionUpdate, nil, result)
+
+	return result, nil
+}
+
+// 4. Template 单位目录导入模板：全部列名及一行示例（UTF-8 BOM，Excel 可直接打开）
+func (s *EstateBlockService) Template() ([]byte, error) {
+	header := make([]string, len(estateUnitColumns))
+	example := make([]string, len(estateUnitColumns))
+	for i, col := range estateUnitColumns {
+		header[i] = col.Name
+		example[i] = col.Example
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf")
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll([][]string{header, example}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 5. GetUnit 单位详情（含所属座数）及所属屋苑
+func (s *EstateBlockService) GetUnit(ctx context.Context, unitID uint) (*models.EstateUnit, *models.Estate, error) {
+	unit, err := s.repo.FindUnit(ctx, unitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	estate, err := s.estateRepo.FindByID(ctx, unit.EstateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return unit, estate, nil
+}
+
+// checkEstate 检查屋苑是否存在
+func (s *EstateBlockService) checkEstate(ctx context.Context, estateID uint) error {
+	if _, err := s.estateRepo.FindByID(ctx, estateID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tools.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ============ 单位目录解析 ============
+
+// parseEstateUnitRows 校验表头并解析全部数据行，有任何行错误时整体失败
+func parseEstateUnitRows(rows [][]string) ([]estateUnitRow, error) {
+	if len(rows) == 0 {
+		return nil, tools.WrapError(400, "file is empty", tools.ErrInvalidInput)
+	}
+
+	known := make(map[string]bool, len(estateUnitColumns))
+	for _, col := range estateUnitColumns {
+		known[col.Name] = true
+	}
+	index := make(map[string]int, len(rows[0]))
+	var unknown []string
+	for i, name := range rows[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, dup := index[name]; dup {
+			return nil, tools.WrapError(400, "duplicate column: "+name, tools.ErrInvalidInput)
+		}
+		index[name] = i
+	}
+	if len(unknown) > 0 {
+		return nil, tools.WrapError(400, "unknown columns: "+strings.Join(unknown, ", "), tools.ErrInvalidInput)
+	}
+	var missing []string
+	for _, col := range estateUnitColumns {
+		if _, ok := index[col.Name]; col.Required && !ok {
+			missing = append(missing, col.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, tools.WrapError(400, "missing required columns: "+strings.Join(missing, ", "), tools.ErrInvalidInput)
+	}
+
+	var parsed []estateUnitRow
+	var rowErrors []string
+	seen := make(map[string]int)
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		rowNo := i + 2
+		cell := func(name string) string {
+			if j, ok := index[name]; ok && j < len(row) {
+				return strings.TrimSpace(row[j])
+			}
+			return ""
+		}
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, fmt.Sprintf("row %d %s: %s", rowNo, field, message))
+		}
+
+		r := estateUnitRow{
+			block:       cell("block"),
+			blockEn:     cell("block_en"),
+			floor:       estateUnitFloor(cell("floor")),
+			flat:        strings.ToUpper(cell("flat")),
+			orientation: cell("orientation"),
+		}
+		if r.block == "" {
+			fail("block", "is required")
+		} else if len([]rune(r.block)) > 50 {
+			fail("block", "must be at most 50 characters")
+		}
+		if len([]rune(r.blockEn)) > 50 {
+			fail("block_en", "must be at most 50 characters")
+		}
+		if r.floor == "" {
+			fail("floor", "is required")
+		} else if len([]rune(r.floor)) > 20 {
+			fail("floor", "must be at most 20 characters")
+		}
+		if r.flat == "" {
+			fail("flat", "is required")
+		} else if len([]rune(r.flat)) > 20 {
+			fail("flat", "must be at most 20 characters")
+		}
+		if len([]rune(r.orientation)) > 50 {
+			fail("orientation", "must be at most 50 characters")
+		}
+		if v := cell("saleable_area"); v != "" {
+			if err := parseImportFloat(v, &r.saleableArea); err != nil || r.saleableArea <= 0 {
+				fail("saleable_area", "must be a positive number")
+			}
+		}
+		if v := cell("gross_area"); v != "" {
+			if err := parseImportFloat(v, &r.grossArea); err != nil || r.grossArea <= 0 {
+				fail("gross_area", "must be a positive number")
+			}
+		}
+		if r.saleableArea > 0 && r.grossArea > 0 && r.grossArea < r.saleableArea {
+			fail("gross_area", "must not be less than saleable_area")
+		}
+		if v := cell("bedrooms"); v != "" {
+			var bedrooms int
+			if err := parseImportInt(v, &bedrooms); err != nil || bedrooms < 0 {
+				fail("bedrooms", "must be a non-negative integer")
+			} else {
+				r.bedrooms = &bedrooms
+			}
+		}
+
+		key := unitKey(r.block, r.floor, r.flat)
+		if first, dup := seen[key]; dup {
+			fail("flat", fmt.Sprintf("duplicates row %d", first))
+		} else {
+			seen[key] = rowNo
+		}
+		parsed = append(parsed, r)
+	}
+
+	if len(rowErrors) > 0 {
+		if len(rowErrors) > estateUnitMaxRowErrors {
+			rowErrors = append(rowErrors[:estateUnitMaxRowErrors], fmt.Sprintf("and %d more", len(rowErrors)-estateUnitMaxRowErrors))
+		}
+		return nil, tools.WrapError(400, "invalid unit directory: "+strings.Join(rowErrors, "; "), tools.ErrInvalidInput)
+	}
+	if len(parsed) == 0 {
+		return nil, tools.WrapError(400, "file has no data rows", tools.ErrInvalidInput)
+	}
+	return parsed, nil
+}
+
+// estateUnitFloor 单位目录楼层写法（12/F、12樓 记为 12，G/F、地下记为 G）
+func estateUnitFloor(floor string) string {
+	return strings.ToUpper(normalizeFloor(floor))
+}
+
+// estateUnitFloorLevel 楼层数值（用于排序，非数字楼层为 0）
+func estateUnitFloorLevel(floor string) int {
+	level, err := strconv.Atoi(floor)
+	if err != nil {
+		return 0
+	}
+	return level
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// EstateImportService Methods:
+// 0. NewEstateImportService(estateRepo *databases.EstateRepo, facilityRepo *databases.FacilityRepo, districtRepo *databases.DistrictRepo, auditService *AuditService) -> 注入依赖
+// 1. ImportEstates(ctx context.Context, fileName string, data []byte) -> 导入 CSV/XLSX 屋苑及设施（管理员）
+// 2. Template() -> 屋苑导入模板（CSV 表头及示例行）
+
+// estateImportColumn 屋苑导入列定义
+type estateImportColumn struct {
+	Name     string
+	Required bool
+	Example  string
+}
+
+// estateImportColumns 屋苑文件列（facilities 为设施名称或ID，多个用 | 分隔；已有屋苑的留空列保持原值）
+var estateImportColumns = []estateImportColumn{
+	{Name: "name", Required: true, Example: "太古城"},
+	{Name: "name_en", Example: "Taikoo Shing"},
+	{Name: "address", Required: true, Example: "太古城道"},
+	{Name: "district_id", Required: true, Example: "1"},
+	{Name: "total_blocks", Example: "61"},
+	{Name: "total_units", Example: "12698"},
+	{Name: "completion_year", Example: "1987"},
+	{Name: "developer", Example: "太古地產"},
+	{Name: "management_company", Example: "太古地產物業管理"},
+	{Name: "primary_school_net", Example: "14"},
+	{Name: "secondary_school_net", Example: "東區"},
+	{Name: "description", Example: ""},
+	{Name: "is_featured", Example: "false"},
+	{Name: "facilities", Example: "會所|泳池"},
+}
+
+// estateImportMaxRowErrors 导入失败时最多返回的行错误数
+const estateImportMaxRowErrors = 20
+
+// EstateImportService 屋苑批量导入服务
+type EstateImportService struct {
+	estateRepo   *databases.EstateRepo
+	facilityRepo *databases.FacilityRepo
+	districtRepo *databases.DistrictRepo
+	auditService *AuditService
+	maxSize      int64 // 单个文件最大字节数
+}
+
+// 0. NewEstateImportService 构造函数
+func NewEstateImportService(estateRepo *databases.EstateRepo, facilityRepo *databases.FacilityRepo, districtRepo *databases.DistrictRepo, auditService *AuditService) *EstateImportService {
+	return &EstateImportService{
+		estateRepo:   estateRepo,
+		facilityRepo: facilityRepo,
+		districtRepo: districtRepo,
+		auditService: auditService,
+		maxSize:      int64(tools.GetEnvInt("IMPORT_MAX_SIZE_MB", 10)) << 20,
+	}
+}
+
+// MaxSize 单个屋苑文件最大字节数
+func (s *EstateImportService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// estateImportRow 屋苑文件中的一行（指针字段为空表示该列留空）
+type estateImportRow struct {
+	rowNo              int
+	name               string
+	nameEn             string
+	address            string
+	districtID         uint
+	totalBlocks        *int
+	totalUnits         *int
+	completionYear     *int
+	developer          string
+	managementCompany  string
+	primarySchoolNet   string
+	secondarySchoolNet string
+	description        string
+	isFeatured         *bool
+	facilityIDs        []uint // nil 表示留空
+}
+
+// 1. ImportEstates 导入屋苑：整份文件校验通过才写入；按名称及地区匹配已有屋苑并更新，否则新建，设施列非空时覆盖设施
+func (s *EstateImportService) ImportEstates(ctx context.Context, fileName string, data []byte) (*models.EstateImportResponse, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, tools.ErrFileTooLarge
+	}
+	format := tools.SpreadsheetFormat(fileName)
+	if format == "" {
+		return nil, tools.ErrUnsupportedSpreadsheet
+	}
+
+	rows, err := tools.ReadSpreadsheet(format, data)
+	if err != nil {
+		return nil, tools.WrapError(400, err.Error(), tools.ErrInvalidInput)
+	}
+
+	districts, err := s.districtRepo.FindAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	facilities, err := s.facilityRepo.FindAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseEstateImportRows(rows, districts, facilities)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(parsed))
+	for i, row := range parsed {
+		names[i] = row.name
+	}
+	existing, err := s.estateRepo.FindByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*models.Estate, len(existing))
+	for i := range existing {
+		key := estateImportKey(existing[i].Name, existing[i].DistrictID)
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = &existing[i]
+		}
+	}
+
+	estates := make([]*models.Estate, len(parsed))
+	befores := make([]*models.Estate, len(parsed))
+	facilityIDs := make([][]uint, len(parsed))
+	for i, row := range parsed {
+		estate, ok := byKey[estateImportKey(row.name, row.districtID)]
+		if ok {
+			before := *estate
+			befores[i] = &before
+		} else {
+			estate = &models.Estate{Name: row.name, DistrictID: row.districtID}
+		}
+		applyEstateImportRow(estate, &row)
+		estates[i] = estate
+		facilityIDs[i] = row.facilityIDs
+	}
+
+	if err := s.estateRepo.ImportEstates(ctx, estates, facilityIDs); err != nil {
+		return nil, err
+	}
+
+	result := &models.EstateImportResponse{Items: make([]models.EstateImportItem, len(parsed))}
+	for i, estate := range estates {
+		item := models.EstateImportItem{Row: parsed[i].rowNo, ID: estate.ID, Name: estate.Name}
+		var beforeFacilities []models.Facility
+		if befores[i] == nil {
+			item.Action = models.EstateImportCreated
+			result.CreatedCount++
+			s.auditService.Record(ctx, "estate", estate.ID, models.AuditActionCreate, nil, estate)
+		} else {
+			item.Action = models.EstateImportUpdated
+			result.UpdatedCount++
+			s.auditService.Record(ctx, "estate", estate.ID, models.AuditActionUpdate, befores[i], estate)
+			beforeFacilities = befores[i].Facilities
+		}
+		if facilityIDs[i] != nil {
+			recordEstateFacilities(ctx, s.auditService, estate.ID, beforeFacilities, facilityIDs[i])
+		}
+		result.Items[i] = item
+	}
+	return result, nil
+}
+
+// 2. Template 屋苑导入模板：全部列名及一行示例（UTF-8 BOM，Excel 可直接打开）
+func (s *EstateImportService) Template() ([]byte, error) {
+	header := make([]string, len(estateImportColumns))
+	example := make([]string, len(estateImportColumns))
+	for i, col := range estateImportColumns {
+		header[i] = col.Name
+		example[i] = col.Example
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf")
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll([][]string{header, example}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// applyEstateImportRow 将导入行写入屋苑（留空的列保持原值）
+func applyEstateImportRow(estate *models.Estate, row *estateImportRow) {
+	estate.Address = row.address
+	if row.nameEn != "" {
+		estate.NameEn = row.nameEn
+	}
+	if row.totalBlocks != nil {
+		estate.TotalBlocks = *row.totalBlocks
+	}
+	if row.totalUnits != nil {
+		estate.TotalUnits = *row.totalUnits
+	}
+	if row.completionYear != nil {
+		estate.CompletionYear = *row.completionYear
+	}
+	if row.developer != "" {
+		estate.Developer = row.developer
+	}
+	if row.managementCompany != "" {
+		estate.ManagementCompany = row.managementCompany
+	}
+	if row.primarySchoolNet != "" {
+		estate.PrimarySchoolNet = row.primarySchoolNet
+	}
+	if row.secondarySchoolNet != "" {
+		estate.SecondarySchoolNet = row.secondarySchoolNet
+	}
+	if row.description != "" {
+		estate.Description = row.description
+	}
+	if row.isFeatured != nil {
+		estate.IsFeatured = *row.isFeatured
+	}
+}
+
+// estateImportKey 屋苑匹配键（名称 + 地区）
+func estateImportKey(name string, districtID uint) string {
+	return fmt.Sprintf("%s|%d", name, districtID)
+}
+
+// ============ 屋苑文件解析 ============
+
+// parseEstateImportRows 校验表头并解析全部数据行（地区及设施须已存在），有任何行错误时整体失败
+func parseEstateImportRows(rows [][]string, districts []models.District, facilities []models.Facility) ([]estateImportRow, error) {
+	if len(rows) == 0 {
+		return nil, tools.WrapError(400, "file is empty", tools.ErrInvalidInput)
+	}
+
+	known := make(map[string]bool, len(estateImportColumns))
+	for _, col := range estateImportColumns {
+		known[col.Name] = true
+	}
+	index := make(map[string]int, len(rows[0]))
+	var unknown []string
+	for i, name := range rows[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, dup := index[name]; dup {
+			return nil, tools.WrapError(400, "duplicate column: "+name, tools.ErrInvalidInput)
+		}
+		index[name] = i
+	}
+	if len(unknown) > 0 {
+		return nil, tools.WrapError(400, "unknown columns: "+strings.Join(unknown, ", "), tools.ErrInvalidInput)
+	}
+	var missing []string
+	for _, col := range estateImportColumns {
+		if _, ok := index[col.Name]; col.Required && !ok {
+			missing = append(missing, col.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, tools.WrapError(400, "missing required columns: "+strings.Join(missing, ", "), tools.ErrInvalidInput)
+	}
+
+	districtIDs := make(map[uint]bool, len(districts))
+	for _, d := range districts {
+		districtIDs[d.ID] = true
+	}
+
+	var parsed []estateImportRow
+	var rowErrors []string
+	seen := make(map[string]int)
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		rowNo := i + 2
+		cell := func(name string) string {
+			if j, ok := index[name]; ok && j < len(row) {
+				return strings.TrimSpace(row[j])
+			}
+			return ""
+		}
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, fmt.Sprintf("row %d %s: %s", rowNo, field, message))
+		}
+		text := func(name string, maxLen int, required bool) string {
+			v := cell(name)
+			switch {
+			case v == "" && required:
+				fail(name, "is required")
+			case len([]rune(v)) > maxLen:
+				fail(name, fmt.Sprintf("must be at most %d characters", maxLen))
+			}
+			return v
+		}
+		number := func(name string, min, max int) *int {
+			v := cell(name)
+			if v == "" {
+				return nil
+			}
+			var n int
+			if err := parseImportInt(v, &n); err != nil || n < min || n > max {
+				fail(name, fmt.Sprintf("must be an integer between %d and %d", min, max))
+				return nil
+			}
+			return &n
+		}
+
+		r := estateImportRow{
+			rowNo:              rowNo,
+			name:               text("name", 200, true),
+			nameEn:             text("name_en", 200, false),
+			address:            text("address", 500, true),
+			totalBlocks:        number("total_blocks", 1, 1000),
+			totalUnits:         number("total_units", 1, 100000),
+			completionYear:     number("completion_year", 1900, 2100),
+			developer:          text("developer", 200, false),
+			managementCompany:  text("management_company", 200, false),
+			primarySchoolNet:   text("primary_school_net", 50, false),
+			secondarySchoolNet: text("secondary_school_net", 50, false),
+			description:        cell("description"),
+		}
+		if v := cell("district_id"); v == "" {
+			fail("district_id", "is required")
+		} else if err := parseImportUint(v, &r.districtID); err != nil || !districtIDs[r.districtID] {
+			fail("district_id", "district not found")
+		}
+		if v := cell("is_featured"); v != "" {
+			featured, err := strconv.ParseBool(v)
+			if err != nil {
+				fail("is_featured", "must be true or false")
+			} else {
+				r.isFeatured = &featured
+			}
+		}
+		if v := cell("facilities"); v != "" {
+			ids, unknownFacilities := matchImportFacilities(v, facilities)
+			if len(unknownFacilities) > 0 {
+				fail("facilities", "unknown facilities: "+strings.Join(unknownFacilities, ", "))
+			}
+			r.facilityIDs = ids
+		}
+
+		key := estateImportKey(r.name, r.districtID)
+		if first, dup := seen[key]; dup {
+			fail("name", fmt.Sprintf("duplicates row %d", first))
+		} else {
+			seen[key] = rowNo
+		}
+		parsed = append(parsed, r)
+	}
+
+	if len(rowErrors) > 0 {
+		if len(rowErrors) > estateImportMaxRowErrors {
+			rowErrors = append(rowErrors[:estateImportMaxRowErrors], fmt.Sprintf("and %d more", len(rowErrors)-estateImportMaxRowErrors))
+		}
+		return nil, tools.WrapError(400, "invalid estate file: "+strings.Join(rowErrors, "; "), tools.ErrInvalidInput)
+	}
+	if len(parsed) == 0 {
+		return nil, tools.WrapError(400, "file has no data rows", tools.ErrInvalidInput)
+	}
+	return parsed, nil
+}
+
+// matchImportFacilities 按设施ID或名称（繁体、简体、英文，不区分大小写）匹配设施，返回去重后的设施ID及未能匹配的项
+func matchImportFacilities(value string, facilities []models.Facility) ([]uint, []string) {
+	ids := []uint{}
+	seen := make(map[uint]bool)
+	var unknown []string
+	for _, token := range strings.Split(value, "|") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		var match *models.Facility
+		id, idErr := strconv.ParseUint(token, 10, 32)
+		for i := range facilities {
+			f := &facilities[i]
+			if (idErr == nil && f.ID == uint(id)) ||
+				strings.EqualFold(f.NameZhHant, token) ||
+				(f.NameZhHans != "" && strings.EqualFold(f.NameZhHans, token)) ||
+				(f.NameEn != "" && strings.EqualFold(f.NameEn, token)) {
+				match = f
+				break
+			}
+		}
+		if match == nil {
+			unknown = append(unknown, token)
+			continue
+		}
+		if !seen[match.ID] {
+			seen[match.ID] = true
+			ids = append(ids, match.ID)
+		}
+	}
+	return ids, unknown
+}
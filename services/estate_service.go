@@ -3,6 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
@@ -12,12 +15,14 @@ import (
 
 // EstateService 屋苑服务
 type EstateService struct {
-	repo *databases.EstateRepo
+	repo         *databases.EstateRepo
+	mediaService *MediaService
+	auditService *AuditService
 }
 
 // NewEstateService 创建屋苑服务
-func NewEstateService(repo *databases.EstateRepo) *EstateService {
-	return &EstateService{repo: repo}
+func NewEstateService(repo *databases.EstateRepo, mediaService *MediaService, auditService *AuditService) *EstateService {
+	return &EstateService{repo: repo, mediaService: mediaService, auditService: auditService}
 }
 
 // ListEstates 获取屋苑列表
@@ -220,12 +225,14 @@ func (s *EstateService) CreateEstate(ctx context.Context, req *models.CreateEsta
 	if err := s.repo.Create(ctx, estate); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "estate", estate.ID, models.AuditActionCreate, nil, estate)
 
 	// 如果有设施，更新设施关联
 	if len(req.FacilityIDs) > 0 {
 		if err := s.repo.UpdateFacilities(ctx, estate.ID, req.FacilityIDs); err != nil {
 			return nil, err
 		}
+		recordEstateFacilities(ctx, s.auditService, estate.ID, nil, req.FacilityIDs)
 	}
 
 	// 重新查询以获取完整数据
@@ -248,6 +255,7 @@ func (s *EstateService) UpdateEstate(ctx context.Context, id uint, req *models.U
 		}
 		return nil, err
 	}
+	before := *estate
 
 	// 更新字段
 	if req.Name != nil {
@@ -293,12 +301,14 @@ func (s *EstateService) UpdateEstate(ctx context.Context, id uint, req *models.U
 	if err := s.repo.Update(ctx, estate); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "estate", id, models.AuditActionUpdate, &before, estate)
 
 	// 更新设施关联
 	if req.FacilityIDs != nil {
 		if err := s.repo.UpdateFacilities(ctx, id, req.FacilityIDs); err != nil {
 			return nil, err
 		}
+		recordEstateFacilities(ctx, s.auditService, id, before.Facilities, req.FacilityIDs)
 	}
 
 	// 重新查询以获取完整数据
@@ -314,7 +324,7 @@ func (s *EstateService) UpdateEstate(ctx context.Context, id uint, req *models.U
 // DeleteEstate 删除屋苑
 func (s *EstateService) DeleteEstate(ctx context.Context, id uint) error {
 	// 查询屋苑是否存在
-	_, err := s.repo.FindByID(ctx, id)
+	estate, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return tools.ErrNotFound
@@ -322,7 +332,11 @@ func (s *EstateService) DeleteEstate(ctx context.Context, id uint) error {
 		return err
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "estate", id, models.AuditActionDelete, estate, nil)
+	return nil
 }
 
 // toEstateResponse 转换为响应格式
@@ -355,3 +369,230 @@ func (s *EstateService) toEstateResponse(estate *models.Estate) models.EstateRes
 		UpdatedAt:               estate.UpdatedAt,
 	}
 }
+
+// ============ 图片管理（管理员） ============
+
+// estateImageTypes 屋苑图片类型
+var estateImageTypes = []string{"exterior", "facilities", "environment", "aerial"}
+
+// findEstateImages 校验屋苑存在并返回其图片
+func (s *EstateService) findEstateImages(ctx context.Context, id uint) ([]models.EstateImage, error) {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.repo.FindImagesByEstateID(ctx, id)
+}
+
+// findEstateImage 查询屋苑图片
+func (s *EstateService) findEstateImage(ctx context.Context, id uint, imageID uint) (*models.EstateImage, error) {
+	image, err := s.repo.FindImage(ctx, id, imageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+	return image, nil
+}
+
+// AddEstateImage 添加屋苑图片（追加到末尾，第一张自动作为封面）
+func (s *EstateService) AddEstateImage(ctx context.Context, id uint, req *models.AddImageRequest) (*models.EstateImage, error) {
+	images, err := s.findEstateImages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImageCount(len(images)); err != nil {
+		return nil, err
+	}
+
+	imageType := req.ImageType
+	if imageType == "" {
+		imageType = "exterior"
+	}
+	if err := checkImageType(imageType, estateImageTypes...); err != nil {
+		return nil, err
+	}
+
+	source, err := s.mediaService.ResolveImage(ctx, req.ImageKey, req.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.EstateImage{
+		EstateID:     id,
+		ImageURL:     source.ImageURL,
+		ObjectKey:    source.ObjectKey,
+		ThumbnailURL: source.ThumbnailURL,
+		ImageType:    imageType,
+		Title:        req.Title,
+		IsCover:      len(images) == 0,
+		SortOrder:    len(images),
+	}
+	if err := s.repo.CreateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "estate_image", image.ID, models.AuditActionCreate, nil, image)
+	return image, nil
+}
+
+// UpdateEstateImage 更新屋苑图片类型/标题
+func (s *EstateService) UpdateEstateImage(ctx context.Context, id uint, imageID uint, req *models.UpdateImageRequest) (*models.EstateImage, error) {
+	image, err := s.findEstateImage(ctx, id, imageID)
+	if err != nil {
+		return nil, err
+	}
+	before := *image
+
+	if req.ImageType != nil {
+		if err := checkImageType(*req.ImageType, estateImageTypes...); err != nil {
+			return nil, err
+		}
+		image.ImageType = *req.ImageType
+	}
+	if req.Title != nil {
+		image.Title = *req.Title
+	}
+
+	if err := s.repo.UpdateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "estate_image", imageID, models.AuditActionUpdate, &before, image)
+	return image, nil
+}
+
+// DeleteEstateImage 删除屋苑图片
+func (s *EstateService) DeleteEstateImage(ctx context.Context, id uint, imageID uint) error {
+	image, err := s.findEstateImage(ctx, id, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteImage(ctx, id, imageID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "estate_image", imageID, models.AuditActionDelete, image, nil)
+	return nil
+}
+
+// ReorderEstateImages 批量调整屋苑图片顺序
+func (s *EstateService) ReorderEstateImages(ctx context.Context, id uint, req *models.ReorderImagesRequest) ([]models.EstateImage, error) {
+	images, err := s.findEstateImages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(images))
+	for i, img := range images {
+		currentIDs[i] = img.ID
+	}
+	if err := checkImageOrder(currentIDs, req.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderImages(ctx, id, req.ImageIDs); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, id, images)
+}
+
+// SetEstateCoverImage 设置屋苑封面图（新封面移到首位）
+func (s *EstateService) SetEstateCoverImage(ctx context.Context, id uint, imageID uint) ([]models.EstateImage, error) {
+	images, err := s.findEstateImages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findEstateImage(ctx, id, imageID); err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(images))
+	for i, img := range images {
+		currentIDs[i] = img.ID
+	}
+	if err := s.repo.SetCoverImage(ctx, id, imageID, coverFirstOrder(currentIDs, imageID)); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, id, images)
+}
+
+// imagesAfterChange 重新查询屋苑图片，并逐张记录排序及封面变更
+func (s *EstateService) imagesAfterChange(ctx context.Context, id uint, before []models.EstateImage) ([]models.EstateImage, error) {
+	images, err := s.repo.FindImagesByEstateID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.RecordEach(ctx, "estate_image", models.AuditActionUpdate, before, images)
+	return images, nil
+}
+
+// ============ 设施管理（管理员） ============
+
+// UpdateEstateFacilities 覆盖更新屋苑设施（设施须存在于设施字典，重复ID自动去除）
+func (s *EstateService) UpdateEstateFacilities(ctx context.Context, id uint, req *models.UpdateEstateFacilitiesRequest) ([]models.Facility, error) {
+	estate, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+
+	facilityIDs := uniqueFacilityIDs(req.FacilityIDs)
+	if len(facilityIDs) > 0 {
+		count, err := s.repo.CountFacilities(ctx, facilityIDs)
+		if err != nil {
+			return nil, err
+		}
+		if int(count) != len(facilityIDs) {
+			return nil, tools.WrapError(400, "facility not found", tools.ErrInvalidInput)
+		}
+	}
+
+	if err := s.repo.UpdateFacilities(ctx, id, facilityIDs); err != nil {
+		return nil, err
+	}
+	recordEstateFacilities(ctx, s.auditService, id, estate.Facilities, facilityIDs)
+	return s.repo.FindFacilitiesByEstateID(ctx, id)
+}
+
+// estateFacilitiesAudit 屋苑设施审计快照（设施为关联表，屋苑审计差异不含设施，单独记录排序后的设施ID）
+type estateFacilitiesAudit struct {
+	FacilityIDs string `json:"facility_ids"`
+}
+
+// recordEstateFacilities 记录屋苑设施变更（设施未变化时不记录）
+func recordEstateFacilities(ctx context.Context, auditService *AuditService, estateID uint, before []models.Facility, afterIDs []uint) {
+	beforeIDs := make([]uint, len(before))
+	for i, f := range before {
+		beforeIDs[i] = f.ID
+	}
+	auditService.Record(ctx, "estate_facilities", estateID, models.AuditActionUpdate,
+		&estateFacilitiesAudit{FacilityIDs: joinFacilityIDs(beforeIDs)},
+		&estateFacilitiesAudit{FacilityIDs: joinFacilityIDs(afterIDs)})
+}
+
+// joinFacilityIDs 去重排序后以逗号连接设施ID
+func joinFacilityIDs(ids []uint) string {
+	ids = uniqueFacilityIDs(ids)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// uniqueFacilityIDs 去除重复设施ID（保留顺序）
+func uniqueFacilityIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
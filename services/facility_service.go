@@ -10,7 +10,7 @@ import (
 )
 
 // FacilityService Methods:
-// 0. NewFacilityService(repo *databases.FacilityRepo) -> 注入依赖
+// 0. NewFacilityService(repo *databases.FacilityRepo, auditService *AuditService) -> 注入依赖
 // 1. ListFacilities(ctx context.Context, category string) -> 获取设施列表
 // 2. GetFacility(ctx context.Context, id uint) -> 获取设施详情
 // 3. CreateFacility(ctx context.Context, req *models.CreateFacilityRequest) -> 创建设施
@@ -18,12 +18,13 @@ import (
 // 5. DeleteFacility(ctx context.Context, id uint) -> 删除设施
 
 type FacilityService struct {
-	repo *databases.FacilityRepo
+	repo         *databases.FacilityRepo
+	auditService *AuditService
 }
 
 // 0. NewFacilityService 构造函数
-func NewFacilityService(repo *databases.FacilityRepo) *FacilityService {
-	return &FacilityService{repo: repo}
+func NewFacilityService(repo *databases.FacilityRepo, auditService *AuditService) *FacilityService {
+	return &FacilityService{repo: repo, auditService: auditService}
 }
 
 // 1. ListFacilities 获取设施列表
@@ -97,6 +98,7 @@ func (s *FacilityService) CreateFacility(ctx context.Context, req *models.Create
 	if err := s.repo.Create(ctx, facility); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "facility", facility.ID, models.AuditActionCreate, nil, facility)
 
 	return &models.FacilityResponse{
 		ID:         facility.ID,
@@ -165,6 +167,7 @@ func (s *FacilityService) UpdateFacility(ctx context.Context, id uint, req *mode
 	if err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "facility", id, models.AuditActionUpdate, facility, updatedFacility)
 
 	return &models.FacilityResponse{
 		ID:         updatedFacility.ID,
@@ -182,7 +185,7 @@ func (s *FacilityService) UpdateFacility(ctx context.Context, id uint, req *mode
 // 5. DeleteFacility 删除设施
 func (s *FacilityService) DeleteFacility(ctx context.Context, id uint) error {
 	// 验证设施存在
-	_, err := s.repo.FindByID(ctx, id)
+	facility, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, tools.ErrNotFound) {
 			return tools.ErrNotFound
@@ -190,5 +193,9 @@ func (s *FacilityService) DeleteFacility(ctx context.Context, id uint) error {
 		return err
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "facility", id, models.AuditActionDelete, facility, nil)
+	return nil
 }
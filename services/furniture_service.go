@@ -13,12 +13,16 @@ import (
 
 // FurnitureService 家具服务
 type FurnitureService struct {
-	repo *databases.FurnitureRepo
+	repo              *databases.FurnitureRepo
+	renewalPolicy     *RenewalPolicy
+	mediaService      *MediaService
+	moderationService *ModerationService
+	auditService      *AuditService
 }
 
 // NewFurnitureService 创建家具服务
-func NewFurnitureService(repo *databases.FurnitureRepo) *FurnitureService {
-	return &FurnitureService{repo: repo}
+func NewFurnitureService(repo *databases.FurnitureRepo, renewalPolicy *RenewalPolicy, mediaService *MediaService, moderationService *ModerationService, auditService *AuditService) *FurnitureService {
+	return &FurnitureService{repo: repo, renewalPolicy: renewalPolicy, mediaService: mediaService, moderationService: moderationService, auditService: auditService}
 }
 
 // ListFurniture 获取家具列表
@@ -48,8 +52,8 @@ func (s *FurnitureService) ListFurniture(ctx context.Context, filter *models.Lis
 	}, nil
 }
 
-// GetFurniture 获取家具详情
-func (s *FurnitureService) GetFurniture(ctx context.Context, id uint) (*models.FurnitureResponse, error) {
+// GetFurniture 获取家具详情（未发布的家具仅发布者和管理员可见）
+func (s *FurnitureService) GetFurniture(ctx context.Context, id uint, viewerID uint, viewerType string) (*models.FurnitureResponse, error) {
 	furniture, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -57,6 +61,9 @@ func (s *FurnitureService) GetFurniture(ctx context.Context, id uint) (*models.F
 		}
 		return nil, err
 	}
+	if isUnderModeration(furniture.Status) && furniture.PublisherID != viewerID && viewerType != "admin" {
+		return nil, tools.ErrNotFound
+	}
 
 	// 增加浏览次数
 	_ = s.repo.IncrementViewCount(ctx, id)
@@ -105,15 +112,24 @@ func (s *FurnitureService) GetFurnitureImages(ctx context.Context, id uint) ([]m
 
 // CreateFurniture 创建家具
 func (s *FurnitureService) CreateFurniture(ctx context.Context, userID uint, userType string, req *models.CreateFurnitureRequest) (*models.FurnitureResponse, error) {
+	// 解析图片（已上传对象键在前，外部URL 在后）
+	if err := checkImageTotal(len(req.ImageKeys) + len(req.ImageURLs)); err != nil {
+		return nil, err
+	}
+	sources, err := s.mediaService.ResolveImages(ctx, req.ImageKeys, req.ImageURLs)
+	if err != nil {
+		return nil, err
+	}
+
 	// 生成家具编号
 	furnitureNo, err := s.repo.GenerateFurnitureNo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// 设置过期时间（90天后）
+	// 设置过期时间（默认90天后）
 	now := time.Now()
-	expiresAt := now.AddDate(0, 0, 90)
+	expiresAt := now.AddDate(0, 0, s.renewalPolicy.FurnitureDays)
 
 	furniture := &models.Furniture{
 		FurnitureNo:        furnitureNo,
@@ -127,7 +143,7 @@ func (s *FurnitureService) CreateFurniture(ctx context.Context, userID uint, use
 		DeliveryDistrictID: req.DeliveryDistrictID,
 		DeliveryTime:       req.DeliveryTime,
 		DeliveryMethod:     req.DeliveryMethod,
-		Status:             "available",
+		Status:             models.ListingStatusDraft, // 先保存为草稿，提交审核后再决定状态
 		PublisherID:        userID,
 		PublisherType:      userType,
 		PublishedAt:        now,
@@ -137,24 +153,23 @@ func (s *FurnitureService) CreateFurniture(ctx context.Context, userID uint, use
 	if err := s.repo.Create(ctx, furniture); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "furniture", furniture.ID, models.AuditActionCreate, nil, furniture)
 
 	// 创建图片
-	if len(req.ImageURLs) > 0 {
-		images := make([]models.FurnitureImage, len(req.ImageURLs))
-		for i, url := range req.ImageURLs {
-			images[i] = models.FurnitureImage{
-				FurnitureID: furniture.ID,
-				ImageURL:    url,
-				IsCover:     i == 0, // 第一张为封面
-				SortOrder:   i,
-				CreatedAt:   now,
-			}
-		}
+	if len(sources) > 0 {
+		images := buildFurnitureImages(furniture.ID, sources, now)
 		if err := s.repo.CreateImages(ctx, images); err != nil {
 			return nil, err
 		}
 	}
 
+	// 提交审核（保存为草稿时跳过）
+	if !req.SaveAsDraft {
+		if err := s.moderationService.SubmitFurniture(ctx, furniture); err != nil {
+			return nil, err
+		}
+	}
+
 	// 重新查询以获取完整数据
 	created, err := s.repo.FindByID(ctx, furniture.ID)
 	if err != nil {
@@ -180,6 +195,20 @@ func (s *FurnitureService) UpdateFurniture(ctx context.Context, id uint, userID
 	if furniture.PublisherID != userID {
 		return nil, tools.ErrForbidden
 	}
+	before := *furniture
+
+	// 解析图片（在写入前校验对象键）
+	var images []models.FurnitureImage
+	if req.ImageURLs != nil || req.ImageKeys != nil {
+		if err := checkImageTotal(len(req.ImageKeys) + len(req.ImageURLs)); err != nil {
+			return nil, err
+		}
+		sources, err := s.mediaService.ResolveImages(ctx, req.ImageKeys, req.ImageURLs)
+		if err != nil {
+			return nil, err
+		}
+		images = buildFurnitureImages(id, sources, time.Now())
+	}
 
 	// 更新字段
 	if req.Title != nil {
@@ -216,10 +245,18 @@ func (s *FurnitureService) UpdateFurniture(ctx context.Context, id uint, userID
 	if err := s.repo.Update(ctx, furniture); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "furniture", id, models.AuditActionUpdate, &before, furniture)
+
+	// 内容修改后重新检查（命中检查的已发布家具将重新进入审核）
+	if req.Title != nil || req.Description != nil || req.Price != nil || req.CategoryID != nil || req.DeliveryDistrictID != nil {
+		if err := s.moderationService.RecheckFurniture(ctx, furniture); err != nil {
+			return nil, err
+		}
+	}
 
 	// 更新图片
-	if req.ImageURLs != nil {
-		if err := s.repo.UpdateImages(ctx, id, req.ImageURLs); err != nil {
+	if images != nil {
+		if err := s.repo.UpdateImages(ctx, id, images); err != nil {
 			return nil, err
 		}
 	}
@@ -250,7 +287,48 @@ func (s *FurnitureService) UpdateFurnitureStatus(ctx context.Context, id uint, u
 		return tools.ErrForbidden
 	}
 
-	return s.repo.UpdateStatus(ctx, id, status)
+	// 草稿、待审核、被拒绝的家具须通过审核发布，不能直接修改状态
+	if isUnderModeration(furniture.Status) {
+		return tools.WrapError(400, "status cannot be changed before the furniture is published", tools.ErrInvalidInput)
+	}
+	if isLeavingExpired(furniture.Status, status) {
+		return tools.WrapError(400, "expired furniture must be renewed via POST /api/v1/furniture/:id/renew", tools.ErrInvalidInput)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	after := *furniture
+	after.Status = status
+	s.auditService.Record(ctx, "furniture", id, models.AuditActionUpdate, furniture, &after)
+	return nil
+}
+
+// SubmitFurniture 提交家具审核（草稿或被拒绝的家具）
+func (s *FurnitureService) SubmitFurniture(ctx context.Context, id uint, userID uint) (*models.FurnitureResponse, error) {
+	furniture, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+
+	// 验证权限：只有发布者可以提交
+	if furniture.PublisherID != userID {
+		return nil, tools.ErrForbidden
+	}
+
+	if furniture.Status != models.ListingStatusDraft && furniture.Status != models.ListingStatusRejected {
+		return nil, tools.ErrNotSubmittable
+	}
+
+	if err := s.moderationService.SubmitFurniture(ctx, furniture); err != nil {
+		return nil, err
+	}
+
+	response := s.toFurnitureResponse(furniture)
+	return &response, nil
 }
 
 // DeleteFurniture 删除家具
@@ -269,7 +347,52 @@ func (s *FurnitureService) DeleteFurniture(ctx context.Context, id uint, userID
 		return tools.ErrForbidden
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "furniture", id, models.AuditActionDelete, furniture, nil)
+	return nil
+}
+
+// RenewFurniture 续期家具
+func (s *FurnitureService) RenewFurniture(ctx context.Context, id uint, userID uint) (*models.FurnitureResponse, error) {
+	// 查询家具是否存在
+	furniture, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+
+	// 验证权限：只有发布者可以续期
+	if furniture.PublisherID != userID {
+		return nil, tools.ErrForbidden
+	}
+
+	// 只有在架或已过期的家具可以续期
+	if furniture.Status != "available" && furniture.Status != "expired" {
+		return nil, tools.ErrNotRenewable
+	}
+
+	// 检查续期次数上限（按发布者类型）
+	if !s.renewalPolicy.CanRenew(furniture.PublisherType, furniture.RenewalCount) {
+		return nil, tools.ErrRenewalLimitReached
+	}
+	before := *furniture
+
+	furniture.ExpiresAt = renewFrom(&furniture.ExpiresAt, time.Now()).AddDate(0, 0, s.renewalPolicy.FurnitureDays)
+	furniture.Status = "available"
+	furniture.RenewalCount++
+	furniture.ReminderSentAt = nil
+
+	if err := s.repo.Update(ctx, furniture); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "furniture", id, models.AuditActionRenew, &before, furniture)
+
+	response := s.toFurnitureResponse(furniture)
+	return &response, nil
 }
 
 // GetFurnitureCategories 获取家具分类列表
@@ -312,6 +435,7 @@ func (s *FurnitureService) toFurnitureResponse(furniture *models.Furniture) mode
 		PublishedAt:        furniture.PublishedAt,
 		UpdatedAt:          furniture.UpdatedAt,
 		ExpiresAt:          furniture.ExpiresAt,
+		RenewalCount:       furniture.RenewalCount,
 	}
 }
 
@@ -342,3 +466,146 @@ func (s *FurnitureService) toCategoryResponse(ctx context.Context, category *mod
 
 	return response
 }
+
+// buildFurnitureImages 根据图片来源构建家具图片记录（第一张为封面）
+func buildFurnitureImages(furnitureID uint, sources []ImageSource, now time.Time) []models.FurnitureImage {
+	images := make([]models.FurnitureImage, len(sources))
+	for i, src := range sources {
+		images[i] = models.FurnitureImage{
+			FurnitureID:  furnitureID,
+			ImageURL:     src.ImageURL,
+			ObjectKey:    src.ObjectKey,
+			ThumbnailURL: src.ThumbnailURL,
+			IsCover:      i == 0,
+			SortOrder:    i,
+			CreatedAt:    now,
+		}
+	}
+	return images
+}
+
+// ============ 图片管理 ============
+
+// findManagedFurniture 查询家具并校验管理权限（发布者或管理员）
+func (s *FurnitureService) findManagedFurniture(ctx context.Context, id uint, userID uint, userType string) (*models.Furniture, error) {
+	furniture, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+	if furniture.PublisherID != userID && userType != "admin" {
+		return nil, tools.ErrForbidden
+	}
+	return furniture, nil
+}
+
+// findFurnitureImage 查询家具图片
+func (s *FurnitureService) findFurnitureImage(ctx context.Context, id uint, imageID uint) (*models.FurnitureImage, error) {
+	image, err := s.repo.FindImage(ctx, id, imageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+	return image, nil
+}
+
+// AddFurnitureImage 添加家具图片（追加到末尾，第一张自动作为封面）
+func (s *FurnitureService) AddFurnitureImage(ctx context.Context, id uint, userID uint, userType string, req *models.AddImageRequest) (*models.FurnitureImage, error) {
+	furniture, err := s.findManagedFurniture(ctx, id, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImageCount(len(furniture.Images)); err != nil {
+		return nil, err
+	}
+
+	source, err := s.mediaService.ResolveImage(ctx, req.ImageKey, req.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.FurnitureImage{
+		FurnitureID:  id,
+		ImageURL:     source.ImageURL,
+		ObjectKey:    source.ObjectKey,
+		ThumbnailURL: source.ThumbnailURL,
+		IsCover:      len(furniture.Images) == 0,
+		SortOrder:    len(furniture.Images),
+	}
+	if err := s.repo.CreateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "furniture_image", image.ID, models.AuditActionCreate, nil, image)
+	return image, nil
+}
+
+// DeleteFurnitureImage 删除家具图片
+func (s *FurnitureService) DeleteFurnitureImage(ctx context.Context, id uint, imageID uint, userID uint, userType string) error {
+	if _, err := s.findManagedFurniture(ctx, id, userID, userType); err != nil {
+		return err
+	}
+	image, err := s.findFurnitureImage(ctx, id, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteImage(ctx, id, imageID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "furniture_image", imageID, models.AuditActionDelete, image, nil)
+	return nil
+}
+
+// ReorderFurnitureImages 批量调整家具图片顺序
+func (s *FurnitureService) ReorderFurnitureImages(ctx context.Context, id uint, userID uint, userType string, req *models.ReorderImagesRequest) ([]models.FurnitureImage, error) {
+	furniture, err := s.findManagedFurniture(ctx, id, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(furniture.Images))
+	for i, img := range furniture.Images {
+		currentIDs[i] = img.ID
+	}
+	if err := checkImageOrder(currentIDs, req.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderImages(ctx, id, req.ImageIDs); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, id, furniture.Images)
+}
+
+// SetFurnitureCoverImage 设置家具封面图（新封面移到首位）
+func (s *FurnitureService) SetFurnitureCoverImage(ctx context.Context, id uint, imageID uint, userID uint, userType string) ([]models.FurnitureImage, error) {
+	furniture, err := s.findManagedFurniture(ctx, id, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findFurnitureImage(ctx, id, imageID); err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(furniture.Images))
+	for i, img := range furniture.Images {
+		currentIDs[i] = img.ID
+	}
+	if err := s.repo.SetCoverImage(ctx, id, imageID, coverFirstOrder(currentIDs, imageID)); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, id, furniture.Images)
+}
+
+// imagesAfterChange 重新查询家具图片，并逐张记录排序及封面变更
+func (s *FurnitureService) imagesAfterChange(ctx context.Context, id uint, before []models.FurnitureImage) ([]models.FurnitureImage, error) {
+	images, err := s.repo.FindImagesByFurnitureID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.RecordEach(ctx, "furniture_image", models.AuditActionUpdate, before, images)
+	return images, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// JobService Methods:
+// 0. NewJobService(scheduler *tools.Scheduler, jobRepo *databases.JobRepo) -> 注入依赖
+// 1. ListJobs(ctx context.Context) -> 获取定时任务列表及状态
+// 2. GetJobRuns(ctx context.Context, name string, req *models.ListJobRunsRequest) -> 获取任务执行记录
+// 3. TriggerJob(ctx context.Context, name string, userID uint) -> 手动触发任务
+
+// JobService 定时任务管理服务
+type JobService struct {
+	scheduler *tools.Scheduler
+	jobRepo   *databases.JobRepo
+}
+
+// 0. NewJobService 构造函数
+func NewJobService(scheduler *tools.Scheduler, jobRepo *databases.JobRepo) *JobService {
+	return &JobService{scheduler: scheduler, jobRepo: jobRepo}
+}
+
+// 1. ListJobs 获取定时任务列表及状态
+func (s *JobService) ListJobs(ctx context.Context) ([]models.JobResponse, error) {
+	jobs := s.scheduler.Jobs()
+
+	responses := make([]models.JobResponse, len(jobs))
+	for i, job := range jobs {
+		lastRun, err := s.jobRepo.FindLatestRun(ctx, job.Name)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = models.JobResponse{
+			Name:      job.Name,
+			Schedule:  job.Schedule,
+			Running:   job.Running,
+			NextRunAt: job.NextRunAt,
+			LastRun:   lastRun,
+		}
+	}
+
+	return responses, nil
+}
+
+// 2. GetJobRuns 获取任务执行记录
+func (s *JobService) GetJobRuns(ctx context.Context, name string, req *models.ListJobRunsRequest) (*models.PaginatedJobRunsResponse, error) {
+	if _, err := s.scheduler.Job(name); err != nil {
+		return nil, err
+	}
+
+	runs, total, err := s.jobRepo.FindRuns(ctx, name, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedJobRunsResponse{
+		Data:       runs,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// 3. TriggerJob 手动触发任务（异步执行，结果见执行记录）
+func (s *JobService) TriggerJob(ctx context.Context, name string, userID uint) error {
+	return s.scheduler.Trigger(name, &userID)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// ListingExpiryService Methods:
+// 0. NewListingExpiryService(propertyRepo, furnitureRepo, notificationRepo, policy, auditService) -> 注入依赖
+// 1. ExpireListings(ctx context.Context) -> 将已到期的房产/家具标记为 expired 并通知发布者（定时任务）
+// 2. SendRenewalReminders(ctx context.Context) -> 到期前发送续期提醒（定时任务）
+// 3. PublishScheduledListings(ctx context.Context) -> 将到达发布时间的定时发布房产上架（定时任务）
+
+// RenewalPolicy 刊登续期规则
+type RenewalPolicy struct {
+	MaxRenewals    map[string]int // 各用户类型最大续期次数，负数表示不限
+	PropertyMonths int            // 房产每次续期月数
+	FurnitureDays  int            // 家具每次续期天数
+	ReminderDays   int            // 到期前多少天发送提醒
+}
+
+// LoadRenewalPolicy 从环境变量加载续期规则
+func LoadRenewalPolicy() *RenewalPolicy {
+	return &RenewalPolicy{
+		MaxRenewals: map[string]int{
+			"individual": tools.GetEnvInt("LISTING_MAX_RENEWALS_INDIVIDUAL", 3),
+			"agency":     tools.GetEnvInt("LISTING_MAX_RENEWALS_AGENCY", 12),
+		},
+		PropertyMonths: tools.GetEnvInt("PROPERTY_RENEWAL_MONTHS", 3),
+		FurnitureDays:  tools.GetEnvInt("FURNITURE_RENEWAL_DAYS", 90),
+		ReminderDays:   tools.GetEnvInt("LISTING_REMINDER_DAYS", 3),
+	}
+}
+
+// CanRenew 判断该用户类型在已续期 renewalCount 次后能否再续期
+func (p *RenewalPolicy) CanRenew(userType string, renewalCount int) bool {
+	limit, ok := p.MaxRenewals[userType]
+	if !ok {
+		limit = p.MaxRenewals["individual"]
+	}
+	return limit < 0 || renewalCount < limit
+}
+
+// renewFrom 计算续期起点：未过期则从原到期时间顺延，已过期则从当前时间起算
+func renewFrom(expiresAt *time.Time, now time.Time) time.Time {
+	if expiresAt != nil && expiresAt.After(now) {
+		return *expiresAt
+	}
+	return now
+}
+
+// ListingExpiryService 刊登到期服务
+type ListingExpiryService struct {
+	propertyRepo     *databases.PropertyRepo
+	furnitureRepo    *databases.FurnitureRepo
+	notificationRepo *databases.NotificationRepo
+	policy           *RenewalPolicy
+	auditService     *AuditService
+}
+
+// 0. NewListingExpiryService 构造函数
+func NewListingExpiryService(
+	propertyRepo *databases.PropertyRepo,
+	furnitureRepo *databases.FurnitureRepo,
+	notificationRepo *databases.NotificationRepo,
+	policy *RenewalPolicy,
+	auditService *AuditService,
+) *ListingExpiryService {
+	return &ListingExpiryService{
+		propertyRepo:     propertyRepo,
+		furnitureRepo:    furnitureRepo,
+		notificationRepo: notificationRepo,
+		policy:           policy,
+		auditService:     auditService,
+	}
+}
+
+// 1. ExpireListings 将已到期的房产/家具标记为 expired 并通知发布者
+func (s *ListingExpiryService) ExpireListings(ctx context.Context) error {
+	now := time.Now()
+
+	// 房产
+	properties, err := s.propertyRepo.FindExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	propertyIDs := make([]uint, len(properties))
+	for i, p := range properties {
+		propertyIDs[i] = p.ID
+	}
+	if err := s.propertyRepo.MarkExpired(ctx, propertyIDs); err != nil {
+		return err
+	}
+	for _, p := range properties {
+		after := p
+		after.Status = "expired"
+		s.auditService.Record(ctx, "property", p.ID, models.AuditActionExpire, &p, &after)
+		s.notify(ctx, p.PublisherID, "listing_expired", "property", p.ID,
+			"房源已过期",
+			fmt.Sprintf("您的房源「%s」已于 %s 到期下架，续期后可重新上架。", p.Title, p.ExpiredAt.Format("2006-01-02")))
+	}
+
+	// 家具
+	furniture, err := s.furnitureRepo.FindExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	furnitureIDs := make([]uint, len(furniture))
+	for i, f := range furniture {
+		furnitureIDs[i] = f.ID
+	}
+	if err := s.furnitureRepo.MarkExpired(ctx, furnitureIDs); err != nil {
+		return err
+	}
+	for _, f := range furniture {
+		after := f
+		after.Status = "expired"
+		s.auditService.Record(ctx, "furniture", f.ID, models.AuditActionExpire, &f, &after)
+		s.notify(ctx, f.PublisherID, "listing_expired", "furniture", f.ID,
+			"家具刊登已过期",
+			fmt.Sprintf("您的家具「%s」已于 %s 到期下架，续期后可重新上架。", f.Title, f.ExpiresAt.Format("2006-01-02")))
+	}
+
+	return nil
+}
+
+// 2. SendRenewalReminders 到期前发送续期提醒（每个刊登只提醒一次，续期后重置）
+func (s *ListingExpiryService) SendRenewalReminders(ctx context.Context) error {
+	now := time.Now()
+	deadline := now.AddDate(0, 0, s.policy.ReminderDays)
+
+	// 房产
+	properties, err := s.propertyRepo.FindExpiringSoon(ctx, now, deadline)
+	if err != nil {
+		return err
+	}
+	for _, p := range properties {
+		s.notify(ctx, p.PublisherID, "listing_expiring", "property", p.ID,
+			"房源即将到期",
+			fmt.Sprintf("您的房源「%s」将于 %s 到期，请及时续期。", p.Title, p.ExpiredAt.Format("2006-01-02")))
+		if err := s.propertyRepo.MarkReminderSent(ctx, p.ID, now); err != nil {
+			return err
+		}
+	}
+
+	// 家具
+	furniture, err := s.furnitureRepo.FindExpiringSoon(ctx, now, deadline)
+	if err != nil {
+		return err
+	}
+	for _, f := range furniture {
+		s.notify(ctx, f.PublisherID, "listing_expiring", "furniture", f.ID,
+			"家具刊登即将到期",
+			fmt.Sprintf("您的家具「%s」将于 %s 到期，请及时续期。", f.Title, f.ExpiresAt.Format("2006-01-02")))
+		if err := s.furnitureRepo.MarkReminderSent(ctx, f.ID, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 3. PublishScheduledListings 将到达发布时间的定时发布房产上架并通知发布者
+func (s *ListingExpiryService) PublishScheduledListings(ctx context.Context) error {
+	properties, err := s.propertyRepo.FindScheduledDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	ids := make([]uint, len(properties))
+	for i, p := range properties {
+		ids[i] = p.ID
+	}
+	if err := s.propertyRepo.MarkPublished(ctx, ids); err != nil {
+		return err
+	}
+	for _, p := range properties {
+		after := p
+		after.Status = models.ListingStatusPublished
+		s.auditService.Record(ctx, "property", p.ID, models.AuditActionPublish, &p, &after)
+		s.notify(ctx, p.PublisherID, "listing_published", "property", p.ID,
+			"房源已发布",
+			fmt.Sprintf("您的房源「%s」已按计划发布。", p.Title))
+	}
+
+	return nil
+}
+
+// notify 创建站内通知（失败不影响任务主流程）
+func (s *ListingExpiryService) notify(ctx context.Context, userID uint, notificationType, entityType string, entityID uint, title, content string) {
+	_ = s.notificationRepo.Create(ctx, &models.Notification{
+		UserID:     userID,
+		Type:       notificationType,
+		Title:      title,
+		Content:    content,
+		EntityType: entityType,
+		EntityID:   entityID,
+	})
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+)
+
+// MaintenanceService Methods:
+// 0. NewMaintenanceService(estateRepo, schoolNetRepo, agencyRepo) -> 注入依赖
+// 1. RecountEstates(ctx context.Context) -> 重算屋苑放盘数量、近期成交及平均成交价（定时任务）
+// 2. RecountSchoolNets(ctx context.Context) -> 重算校网学校数量（定时任务）
+// 3. RecountAgencyAgents(ctx context.Context) -> 重算代理公司代理人数量（定时任务）
+
+// MaintenanceService 数据维护服务（冗余统计字段重算）
+type MaintenanceService struct {
+	estateRepo    *databases.EstateRepo
+	schoolNetRepo *databases.SchoolNetRepo
+	agencyRepo    *databases.AgencyRepo
+}
+
+// 0. NewMaintenanceService 构造函数
+func NewMaintenanceService(
+	estateRepo *databases.EstateRepo,
+	schoolNetRepo *databases.SchoolNetRepo,
+	agencyRepo *databases.AgencyRepo,
+) *MaintenanceService {
+	return &MaintenanceService{
+		estateRepo:    estateRepo,
+		schoolNetRepo: schoolNetRepo,
+		agencyRepo:    agencyRepo,
+	}
+}
+
+// 1. RecountEstates 重算屋苑放盘数量、近期成交及平均成交价
+func (s *MaintenanceService) RecountEstates(ctx context.Context) error {
+	ids, err := s.estateRepo.FindAllIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	var lastErr error
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// 单个屋苑失败不影响其他屋苑
+		if err := s.estateRepo.UpdateCounts(ctx, id); err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d estates failed to update, last error: %w", failed, len(ids), lastErr)
+	}
+	return nil
+}
+
+// 2. RecountSchoolNets 重算校网学校数量
+func (s *MaintenanceService) RecountSchoolNets(ctx context.Context) error {
+	return s.schoolNetRepo.RecountSchools(ctx)
+}
+
+// 3. RecountAgencyAgents 重算代理公司代理人数量
+func (s *MaintenanceService) RecountAgencyAgents(ctx context.Context) error {
+	return s.agencyRepo.RecountAgents(ctx)
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// MediaService Methods:
+// 0. NewMediaService(storage tools.Storage, repo *databases.MediaRepo) -> 注入依赖
+// 1. UploadImage(ctx context.Context, userID uint, data []byte) -> 上传图片（校验、去 EXIF、缩略图、按哈希去重）
+// 2. ResolveImages(ctx context.Context, keys []string, urls []string) -> 将对象键/外部URL 转换为图片来源列表
+// 3. ResolveImage(ctx context.Context, key string, url string) -> 解析单张图片来源（对象键优先）
+
+// ImageSource 图片来源（上传对象或外部URL）
+type ImageSource struct {
+	ObjectKey    string
+	ImageURL     string
+	ThumbnailURL string
+}
+
+// MediaService 媒体文件服务
+type MediaService struct {
+	storage tools.Storage
+	repo    *databases.MediaRepo
+	maxSize int64
+	options tools.ImageOptions
+}
+
+// 0. NewMediaService 构造函数
+func NewMediaService(storage tools.Storage, repo *databases.MediaRepo) *MediaService {
+	return &MediaService{
+		storage: storage,
+		repo:    repo,
+		maxSize: int64(tools.GetEnvInt("UPLOAD_MAX_SIZE_MB", 10)) << 20,
+		options: tools.ImageOptions{
+			MaxDimension:   tools.GetEnvInt("UPLOAD_MAX_DIMENSION", 2560),
+			ThumbDimension: tools.GetEnvInt("UPLOAD_THUMB_DIMENSION", 400),
+			JPEGQuality:    tools.GetEnvInt("UPLOAD_JPEG_QUALITY", 85),
+		},
+	}
+}
+
+// MaxSize 返回单个文件大小上限（字节）
+func (s *MediaService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// 1. UploadImage 上传图片
+func (s *MediaService) UploadImage(ctx context.Context, userID uint, data []byte) (*models.UploadImageResponse, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, tools.ErrFileTooLarge
+	}
+
+	// 按原始内容哈希去重
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	existing, err := s.repo.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return s.toUploadResponse(existing, true), nil
+	}
+
+	// 校验并处理图片
+	processed, err := tools.ProcessImage(data, s.options)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("images/%s/%s/%s", hash[:2], hash[2:4], hash)
+	media := &models.MediaObject{
+		Hash:         hash,
+		ObjectKey:    prefix + "." + processed.Ext,
+		ThumbnailKey: prefix + "_thumb." + processed.Ext,
+		ContentType:  processed.ContentType,
+		Size:         int64(len(processed.Data)),
+		Width:        processed.Width,
+		Height:       processed.Height,
+		UploaderID:   userID,
+	}
+
+	if err := s.storage.Put(ctx, media.ObjectKey, processed.Data, processed.ContentType); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, media.ThumbnailKey, processed.Thumbnail, processed.ContentType); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, media); err != nil {
+		return nil, err
+	}
+	// 并发上传同一文件时记录已被其他请求创建
+	if media.ID == 0 {
+		existing, err := s.repo.FindByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return s.toUploadResponse(existing, true), nil
+		}
+	}
+
+	return s.toUploadResponse(media, false), nil
+}
+
+// 2. ResolveImages 将已上传的对象键和外部URL 转换为图片来源列表（对象键在前）
+func (s *MediaService) ResolveImages(ctx context.Context, keys []string, urls []string) ([]ImageSource, error) {
+	sources := make([]ImageSource, 0, len(keys)+len(urls))
+
+	if len(keys) > 0 {
+		media, err := s.repo.FindByKeys(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		byKey := make(map[string]models.MediaObject, len(media))
+		for _, m := range media {
+			byKey[m.ObjectKey] = m
+		}
+
+		for _, key := range keys {
+			m, ok := byKey[key]
+			if !ok {
+				return nil, tools.WrapError(400, "unknown image key: "+key, tools.ErrInvalidInput)
+			}
+			source := ImageSource{
+				ObjectKey: m.ObjectKey,
+				ImageURL:  s.storage.URL(m.ObjectKey),
+			}
+			if m.ThumbnailKey != "" {
+				source.ThumbnailURL = s.storage.URL(m.ThumbnailKey)
+			}
+			sources = append(sources, source)
+		}
+	}
+
+	for _, url := range urls {
+		sources = append(sources, ImageSource{ImageURL: url})
+	}
+
+	return sources, nil
+}
+
+// 3. ResolveImage 解析单张图片来源（对象键优先）
+func (s *MediaService) ResolveImage(ctx context.Context, key string, url string) (ImageSource, error) {
+	var keys, urls []string
+	if key != "" {
+		keys = []string{key}
+	} else {
+		urls = []string{url}
+	}
+	sources, err := s.ResolveImages(ctx, keys, urls)
+	if err != nil {
+		return ImageSource{}, err
+	}
+	return sources[0], nil
+}
+
+// toUploadResponse 转换为上传响应
+func (s *MediaService) toUploadResponse(media *models.MediaObject, deduplicated bool) *models.UploadImageResponse {
+	resp := &models.UploadImageResponse{
+		ObjectKey:    media.ObjectKey,
+		URL:          s.storage.URL(media.ObjectKey),
+		ThumbnailKey: media.ThumbnailKey,
+		ContentType:  media.ContentType,
+		Size:         media.Size,
+		Width:        media.Width,
+		Height:       media.Height,
+		Hash:         media.Hash,
+		Deduplicated: deduplicated,
+	}
+	if media.ThumbnailKey != "" {
+		resp.ThumbnailURL = s.storage.URL(media.ThumbnailKey)
+	}
+	return resp
+}
+
+// ============ 图片管理通用校验 ============
+
+// maxListingImages 单个实体图片数量上限
+const maxListingImages = 20
+
+// checkImageCount 校验是否还能添加图片
+func checkImageCount(current int) error {
+	if current >= maxListingImages {
+		return tools.WrapError(400, fmt.Sprintf("too many images, at most %d allowed", maxListingImages), tools.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkImageTotal 校验一次提交的图片总数（创建/覆盖更新图片时）
+func checkImageTotal(total int) error {
+	if total > maxListingImages {
+		return tools.WrapError(400, fmt.Sprintf("too many images, at most %d allowed", maxListingImages), tools.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkImageType 校验图片类型取值
+func checkImageType(imageType string, allowed ...string) error {
+	for _, t := range allowed {
+		if imageType == t {
+			return nil
+		}
+	}
+	return tools.WrapError(400, "invalid image_type: "+imageType, tools.ErrInvalidInput)
+}
+
+// checkImageOrder 校验排序请求恰好包含全部图片ID（不多不少、无重复）
+func checkImageOrder(currentIDs []uint, imageIDs []uint) error {
+	invalid := tools.WrapError(400, "image_ids must contain every image exactly once", tools.ErrInvalidInput)
+	if len(currentIDs) != len(imageIDs) {
+		return invalid
+	}
+	current := make(map[uint]bool, len(currentIDs))
+	for _, id := range currentIDs {
+		current[id] = true
+	}
+	for _, id := range imageIDs {
+		if !current[id] {
+			return invalid
+		}
+		delete(current, id) // 防止重复
+	}
+	return nil
+}
+
+// coverFirstOrder 将封面图移到首位，其余保持原顺序
+func coverFirstOrder(currentIDs []uint, coverID uint) []uint {
+	order := make([]uint, 0, len(currentIDs))
+	order = append(order, coverID)
+	for _, id := range currentIDs {
+		if id != coverID {
+			order = append(order, id)
+		}
+	}
+	return order
+}
@@ -0,0 +1,631 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"gorm.io/gorm"
+)
+
+// ModerationService Methods:
+// 0. NewModerationService(...) -> 注入依赖
+// 1. SubmitProperty(ctx context.Context, property *models.Property) -> 房产提交审核（自动检查 + 进入审核队列）
+// 2. SubmitFurniture(ctx context.Context, furniture *models.Furniture) -> 家具提交审核
+// 3. RecheckProperty(ctx context.Context, property *models.Property) -> 房产内容修改后重新检查
+// 4. RecheckFurniture(ctx context.Context, furniture *models.Furniture) -> 家具内容修改后重新检查
+// 5. ListReviews(ctx context.Context, req *models.ListReviewsRequest) -> 审核队列（管理员）
+// 6. ApproveReview(ctx context.Context, reviewID uint, adminID uint) -> 审核通过并发布（管理员）
+// 7. RejectReview(ctx context.Context, reviewID uint, adminID uint, reason string) -> 审核拒绝（管理员）
+// 8. GetListingReviews(ctx context.Context, entityType string, entityID uint, userID uint, userType string) -> 刊登审核历史（发布者或管理员）
+
+// ModerationPolicy 内容审核规则
+type ModerationPolicy struct {
+	BannedWords      []string // 违禁词（命中直接拒绝）
+	AutoApproveClean bool     // 未命中任何检查时是否自动发布
+	PriceLowPercent  int      // 低于地区均价该百分比视为价格异常
+	PriceHighPercent int      // 高于地区均价该百分比视为价格异常
+	PriceMinSamples  int      // 计算均价所需的最少样本数
+}
+
+// LoadModerationPolicy 从环境变量加载审核规则
+func LoadModerationPolicy() *ModerationPolicy {
+	var words []string
+	for _, w := range strings.Split(tools.GetEnv("MODERATION_BANNED_WORDS", ""), ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			words = append(words, strings.ToLower(w))
+		}
+	}
+	return &ModerationPolicy{
+		BannedWords:      words,
+		AutoApproveClean: tools.GetEnv("MODERATION_AUTO_APPROVE", "false") == "true",
+		PriceLowPercent:  tools.GetEnvInt("MODERATION_PRICE_LOW_PERCENT", 30),
+		PriceHighPercent: tools.GetEnvInt("MODERATION_PRICE_HIGH_PERCENT", 300),
+		PriceMinSamples:  tools.GetEnvInt("MODERATION_PRICE_MIN_SAMPLES", 5),
+	}
+}
+
+// phonePattern 香港电话号码（可选 +852 前缀，8位，首位 2-9，允许空格或连字符分隔）
+var phonePattern = regexp.MustCompile(`(?:\+?852[\s-]?)?\b[2-9]\d{3}[\s-]?\d{4}\b`)
+
+// ModerationService 刊登审核服务
+type ModerationService struct {
+	reviewRepo       *databases.ListingReviewRepo
+	propertyRepo     *databases.PropertyRepo
+	furnitureRepo    *databases.FurnitureRepo
+	notificationRepo *databases.NotificationRepo
+	renewalPolicy    *RenewalPolicy
+	policy           *ModerationPolicy
+	auditService     *AuditService
+}
+
+// 0. NewModerationService 构造函数
+func NewModerationService(
+	reviewRepo *databases.ListingReviewRepo,
+	propertyRepo *databases.PropertyRepo,
+	furnitureRepo *databases.FurnitureRepo,
+	notificationRepo *databases.NotificationRepo,
+	renewalPolicy *RenewalPolicy,
+	policy *ModerationPolicy,
+	auditService *AuditService,
+) *ModerationService {
+	return &ModerationService{
+		reviewRepo:       reviewRepo,
+		propertyRepo:     propertyRepo,
+		furnitureRepo:    furnitureRepo,
+		notificationRepo: notificationRepo,
+		renewalPolicy:    renewalPolicy,
+		policy:           policy,
+		auditService:     auditService,
+	}
+}
+
+// 1. SubmitProperty 房产提交审核（调用方负责已保存房产）
+func (s *ModerationService) SubmitProperty(ctx context.Context, property *models.Property) error {
+	flags, err := s.checkProperty(ctx, property)
+	if err != nil {
+		return err
+	}
+	return s.submitProperty(ctx, property, flags)
+}
+
+// 2. SubmitFurniture 家具提交审核（调用方负责已保存家具）
+func (s *ModerationService) SubmitFurniture(ctx context.Context, furniture *models.Furniture) error {
+	flags, err := s.checkFurniture(ctx, furniture)
+	if err != nil {
+		return err
+	}
+	return s.submitFurniture(ctx, furniture, flags)
+}
+
+// 3. RecheckProperty 房产内容修改后重新检查：
+// 已发布或等待定时发布的房产命中检查时重新进入审核；待审核的房产刷新检查结果
+func (s *ModerationService) RecheckProperty(ctx context.Context, property *models.Property) error {
+	approved := property.Status == models.ListingStatusPublished || property.Status == models.ListingStatusScheduled
+	if !approved && property.Status != models.ListingStatusPendingReview {
+		return nil
+	}
+	flags, err := s.checkProperty(ctx, property)
+	if err != nil {
+		return err
+	}
+	if approved && len(flags) == 0 {
+		return nil
+	}
+	return s.submitProperty(ctx, property, flags)
+}
+
+// 4. RecheckFurniture 家具内容修改后重新检查
+func (s *ModerationService) RecheckFurniture(ctx context.Context, furniture *models.Furniture) error {
+	if furniture.Status != models.ListingStatusPublished && furniture.Status != models.ListingStatusPendingReview {
+		return nil
+	}
+	flags, err := s.checkFurniture(ctx, furniture)
+	if err != nil {
+		return err
+	}
+	if furniture.Status == models.ListingStatusPublished && len(flags) == 0 {
+		return nil
+	}
+	return s.submitFurniture(ctx, furniture, flags)
+}
+
+// 5. ListReviews 审核队列
+func (s *ModerationService) ListReviews(ctx context.Context, req *models.ListReviewsRequest) (*models.PaginatedListingReviewsResponse, error) {
+	reviews, total, err := s.reviewRepo.FindAll(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.toReviewResponses(ctx, reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / req.PageSize
+	if int(total)%req.PageSize > 0 {
+		totalPages++
+	}
+
+	return &models.PaginatedListingReviewsResponse{
+		Data:       data,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// 6. ApproveReview 审核通过：发布刊登并重新计算到期时间（已发布过的刊登沿用原到期时间）
+// 先完成审核记录（并发审核时只有一个请求生效），再更新刊登
+func (s *ModerationService) ApproveReview(ctx context.Context, reviewID uint, adminID uint) (*models.ListingReview, error) {
+	review, err := s.findPendingReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch review.EntityType {
+	case "property":
+		property, err := s.propertyRepo.FindByID(ctx, review.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.resolve(ctx, review, models.ReviewStatusApproved, adminID, "", now); err != nil {
+			return nil, err
+		}
+		before := *property
+		s.publishProperty(property, now, review.WasPublished)
+		if err := s.propertyRepo.Update(ctx, property); err != nil {
+			return nil, err
+		}
+		s.auditService.Record(ctx, "property", property.ID, models.AuditActionReview, &before, property)
+		content := fmt.Sprintf("您的房源「%s」已通过审核并发布。", property.Title)
+		if property.Status == models.ListingStatusScheduled {
+			content = fmt.Sprintf("您的房源「%s」已通过审核，将于 %s 发布。", property.Title, property.PublishedAt.Format("2006-01-02 15:04"))
+		}
+		s.notify(ctx, review, "listing_approved", "房源审核通过", content)
+	case "furniture":
+		furniture, err := s.furnitureRepo.FindByID(ctx, review.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.resolve(ctx, review, models.ReviewStatusApproved, adminID, "", now); err != nil {
+			return nil, err
+		}
+		before := *furniture
+		s.publishFurniture(furniture, now, review.WasPublished)
+		if err := s.furnitureRepo.Update(ctx, furniture); err != nil {
+			return nil, err
+		}
+		s.auditService.Record(ctx, "furniture", furniture.ID, models.AuditActionReview, &before, furniture)
+		s.notify(ctx, review, "listing_approved", "家具刊登审核通过",
+			fmt.Sprintf("您的家具「%s」已通过审核并发布。", furniture.Title))
+	default:
+		return s.resolve(ctx, review, models.ReviewStatusApproved, adminID, "", now)
+	}
+	return review, nil
+}
+
+// 7. RejectReview 审核拒绝：刊登改为 rejected，发布者可修改后重新提交
+// 先完成审核记录（并发审核时只有一个请求生效），再更新刊登
+func (s *ModerationService) RejectReview(ctx context.Context, reviewID uint, adminID uint, reason string) (*models.ListingReview, error) {
+	review, err := s.findPendingReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch review.EntityType {
+	case "property":
+		property, err := s.propertyRepo.FindByID(ctx, review.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.resolve(ctx, review, models.ReviewStatusRejected, adminID, reason, now); err != nil {
+			return nil, err
+		}
+		before := *property
+		property.Status = models.ListingStatusRejected
+		if err := s.propertyRepo.Update(ctx, property); err != nil {
+			return nil, err
+		}
+		s.auditService.Record(ctx, "property", property.ID, models.AuditActionReview, &before, property)
+		s.notify(ctx, review, "listing_rejected", "房源审核未通过",
+			fmt.Sprintf("您的房源「%s」未通过审核：%s", property.Title, reason))
+	case "furniture":
+		furniture, err := s.furnitureRepo.FindByID(ctx, review.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.resolve(ctx, review, models.ReviewStatusRejected, adminID, reason, now); err != nil {
+			return nil, err
+		}
+		before := *furniture
+		furniture.Status = models.ListingStatusRejected
+		if err := s.furnitureRepo.Update(ctx, furniture); err != nil {
+			return nil, err
+		}
+		s.auditService.Record(ctx, "furniture", furniture.ID, models.AuditActionReview, &before, furniture)
+		s.notify(ctx, review, "listing_rejected", "家具刊登审核未通过",
+			fmt.Sprintf("您的家具「%s」未通过审核：%s", furniture.Title, reason))
+	default:
+		return s.resolve(ctx, review, models.ReviewStatusRejected, adminID, reason, now)
+	}
+	return review, nil
+}
+
+// 8. GetListingReviews 刊登审核历史（含拒绝原因）
+func (s *ModerationService) GetListingReviews(ctx context.Context, entityType string, entityID uint, userID uint, userType string) ([]models.ListingReview, error) {
+	var publisherID uint
+	switch entityType {
+	case "property":
+		property, err := s.propertyRepo.FindByID(ctx, entityID)
+		if err != nil {
+			if err.Error() == "property not found" {
+				return nil, tools.ErrNotFound
+			}
+			return nil, err
+		}
+		publisherID = property.PublisherID
+	case "furniture":
+		furniture, err := s.furnitureRepo.FindByID(ctx, entityID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, tools.ErrNotFound
+			}
+			return nil, err
+		}
+		publisherID = furniture.PublisherID
+	default:
+		return nil, tools.ErrInvalidInput
+	}
+
+	if publisherID != userID && userType != "admin" {
+		return nil, tools.ErrForbidden
+	}
+	return s.reviewRepo.FindByEntity(ctx, entityType, entityID)
+}
+
+// ============ 提交与审核 ============
+
+// submitProperty 根据检查结果决定房产状态并记录审核
+func (s *ModerationService) submitProperty(ctx context.Context, property *models.Property, flags []models.ModerationFlag) error {
+	now := time.Now()
+	before := *property
+	wasPublished, err := s.wasPublished(ctx, "property", property.ID, property.Status)
+	if err != nil {
+		return err
+	}
+	status, reason := s.decide(flags)
+	switch status {
+	case models.ReviewStatusApproved:
+		s.publishProperty(property, now, wasPublished)
+	case models.ReviewStatusRejected:
+		property.Status = models.ListingStatusRejected
+	default:
+		property.Status = models.ListingStatusPendingReview
+	}
+	if err := s.propertyRepo.Update(ctx, property); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "property", property.ID, models.AuditActionSubmit, &before, property)
+
+	review, err := s.recordSubmission(ctx, "property", property.ID, property.PublisherID, flags, status, reason, wasPublished, now)
+	if err != nil {
+		return err
+	}
+	if status == models.ReviewStatusRejected {
+		s.notify(ctx, review, "listing_rejected", "房源审核未通过",
+			fmt.Sprintf("您的房源「%s」未通过自动审核：%s", property.Title, reason))
+	}
+	return nil
+}
+
+// submitFurniture 根据检查结果决定家具状态并记录审核
+func (s *ModerationService) submitFurniture(ctx context.Context, furniture *models.Furniture, flags []models.ModerationFlag) error {
+	now := time.Now()
+	before := *furniture
+	wasPublished, err := s.wasPublished(ctx, "furniture", furniture.ID, furniture.Status)
+	if err != nil {
+		return err
+	}
+	status, reason := s.decide(flags)
+	switch status {
+	case models.ReviewStatusApproved:
+		s.publishFurniture(furniture, now, wasPublished)
+	case models.ReviewStatusRejected:
+		furniture.Status = models.ListingStatusRejected
+	default:
+		furniture.Status = models.ListingStatusPendingReview
+	}
+	if err := s.furnitureRepo.Update(ctx, furniture); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "furniture", furniture.ID, models.AuditActionSubmit, &before, furniture)
+
+	review, err := s.recordSubmission(ctx, "furniture", furniture.ID, furniture.PublisherID, flags, status, reason, wasPublished, now)
+	if err != nil {
+		return err
+	}
+	if status == models.ReviewStatusRejected {
+		s.notify(ctx, review, "listing_rejected", "家具刊登审核未通过",
+			fmt.Sprintf("您的家具「%s」未通过自动审核：%s", furniture.Title, reason))
+	}
+	return nil
+}
+
+// decide 根据检查结果决定审核结果：命中 block 直接拒绝；无命中且开启自动发布则通过；否则进入人工审核
+func (s *ModerationService) decide(flags []models.ModerationFlag) (string, string) {
+	var blocked []string
+	for _, f := range flags {
+		if f.Severity == models.FlagSeverityBlock {
+			blocked = append(blocked, f.Detail)
+		}
+	}
+	if len(blocked) > 0 {
+		return models.ReviewStatusRejected, strings.Join(blocked, "；")
+	}
+	if len(flags) == 0 && s.policy.AutoApproveClean {
+		return models.ReviewStatusApproved, ""
+	}
+	return models.ReviewStatusPending, ""
+}
+
+// wasPublished 刊登是否已发布过：当前为发布状态，或曾审核通过（被拒后重新提交）
+func (s *ModerationService) wasPublished(ctx context.Context, entityType string, entityID uint, status string) (bool, error) {
+	if status == models.ListingStatusPublished {
+		return true, nil
+	}
+	return s.reviewRepo.HasApproved(ctx, entityType, entityID)
+}
+
+// recordSubmission 记录审核：已有待审核记录时刷新检查结果，否则新建
+func (s *ModerationService) recordSubmission(ctx context.Context, entityType string, entityID, publisherID uint, flags []models.ModerationFlag, status, reason string, wasPublished bool, now time.Time) (*models.ListingReview, error) {
+	review, err := s.reviewRepo.FindPendingByEntity(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		review = &models.ListingReview{
+			EntityType:  entityType,
+			EntityID:    entityID,
+			PublisherID: publisherID,
+		}
+	}
+
+	review.Flags = flags
+	review.Status = status
+	review.WasPublished = review.WasPublished || wasPublished
+	review.Reason = reason
+	if status != models.ReviewStatusPending {
+		review.ReviewedAt = &now // 自动审核，无审核人
+	}
+
+	if review.ID == 0 {
+		return review, s.reviewRepo.Create(ctx, review)
+	}
+	return review, s.reviewRepo.Update(ctx, review)
+}
+
+// findPendingReview 查询待审核记录
+func (s *ModerationService) findPendingReview(ctx context.Context, reviewID uint) (*models.ListingReview, error) {
+	review, err := s.reviewRepo.FindByID(ctx, reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+	if review.Status != models.ReviewStatusPending {
+		return nil, tools.ErrReviewResolved
+	}
+	return review, nil
+}
+
+// resolve 完成审核记录（并发审核时只有一个请求生效）
+func (s *ModerationService) resolve(ctx context.Context, review *models.ListingReview, status string, adminID uint, reason string, now time.Time) (*models.ListingReview, error) {
+	affected, err := s.reviewRepo.Resolve(ctx, review.ID, status, adminID, reason, now)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, tools.ErrReviewResolved
+	}
+	review.Status = status
+	review.Reason = reason
+	review.ReviewerID = &adminID
+	review.ReviewedAt = &now
+	return review, nil
+}
+
+// publishProperty 发布房产（发布时间与到期时间从审核通过起算）
+// 发布时间晚于当前时间时进入定时发布，到期时间从计划发布时间起算；
+// 已发布过的房产沿用原发布与到期时间（重新审核不等于续期）
+func (s *ModerationService) publishProperty(property *models.Property, now time.Time, wasPublished bool) {
+	if wasPublished && property.ExpiredAt != nil {
+		property.Status = models.ListingStatusPublished
+		return
+	}
+	publishAt := now
+	property.Status = models.ListingStatusPublished
+	if property.PublishedAt != nil && property.PublishedAt.After(now) {
+		publishAt = *property.PublishedAt
+		property.Status = models.ListingStatusScheduled
+	}
+	expiredAt := publishAt.AddDate(0, s.renewalPolicy.PropertyMonths, 0)
+	property.PublishedAt = &publishAt
+	property.ExpiredAt = &expiredAt
+	property.ReminderSentAt = nil
+}
+
+// publishFurniture 发布家具（已发布过的家具沿用原发布与到期时间）
+func (s *ModerationService) publishFurniture(furniture *models.Furniture, now time.Time, wasPublished bool) {
+	furniture.Status = models.ListingStatusPublished
+	if wasPublished {
+		return
+	}
+	furniture.PublishedAt = now
+	furniture.ExpiresAt = now.AddDate(0, 0, s.renewalPolicy.FurnitureDays)
+	furniture.ReminderSentAt = nil
+}
+
+// notify 通知发布者（失败不影响主流程）
+func (s *ModerationService) notify(ctx context.Context, review *models.ListingReview, notificationType, title, content string) {
+	_ = s.notificationRepo.Create(ctx, &models.Notification{
+		UserID:     review.PublisherID,
+		Type:       notificationType,
+		Title:      title,
+		Content:    content,
+		EntityType: review.EntityType,
+		EntityID:   review.EntityID,
+	})
+}
+
+// toReviewResponses 附加刊登摘要
+func (s *ModerationService) toReviewResponses(ctx context.Context, reviews []models.ListingReview) ([]models.ListingReviewResponse, error) {
+	var propertyIDs, furnitureIDs []uint
+	for _, r := range reviews {
+		if r.EntityType == "property" {
+			propertyIDs = append(propertyIDs, r.EntityID)
+		} else {
+			furnitureIDs = append(furnitureIDs, r.EntityID)
+		}
+	}
+
+	properties, err := s.propertyRepo.FindByIDs(ctx, propertyIDs)
+	if err != nil {
+		return nil, err
+	}
+	furniture, err := s.furnitureRepo.FindByIDs(ctx, furnitureIDs)
+	if err != nil {
+		return nil, err
+	}
+	propertyByID := make(map[uint]models.Property, len(properties))
+	for _, p := range properties {
+		propertyByID[p.ID] = p
+	}
+	furnitureByID := make(map[uint]models.Furniture, len(furniture))
+	for _, f := range furniture {
+		furnitureByID[f.ID] = f
+	}
+
+	data := make([]models.ListingReviewResponse, len(reviews))
+	for i, r := range reviews {
+		data[i] = models.ListingReviewResponse{ListingReview: r}
+		if r.EntityType == "property" {
+			if p, ok := propertyByID[r.EntityID]; ok {
+				data[i].ListingTitle, data[i].ListingPrice, data[i].ListingStatus = p.Title, p.Price, p.Status
+			}
+		} else if f, ok := furnitureByID[r.EntityID]; ok {
+			data[i].ListingTitle, data[i].ListingPrice, data[i].ListingStatus = f.Title, f.Price, f.Status
+		}
+	}
+	return data, nil
+}
+
+// ============ 自动检查 ============
+
+// checkProperty 房产自动检查：违禁词、电话号码、重复刊登、呎价异常
+func (s *ModerationService) checkProperty(ctx context.Context, property *models.Property) ([]models.ModerationFlag, error) {
+	flags := s.checkText(property.Title, property.Description)
+
+	duplicates, err := s.propertyRepo.CountDuplicates(ctx, property)
+	if err != nil {
+		return nil, err
+	}
+	if duplicates > 0 {
+		flags = append(flags, models.ModerationFlag{
+			Code:     "duplicate",
+			Severity: models.FlagSeverityReview,
+			Detail:   fmt.Sprintf("发布者已有 %d 个相同标题或相同单位的刊登", duplicates),
+		})
+	}
+
+	if property.Area > 0 {
+		avg, samples, err := s.propertyRepo.GetDistrictUnitPrice(ctx, property.DistrictID, property.ListingType, property.ID)
+		if err != nil {
+			return nil, err
+		}
+		if flag := s.checkPrice(property.Price/property.Area, avg, samples, "呎价"); flag != nil {
+			flags = append(flags, *flag)
+		}
+	}
+
+	return flags, nil
+}
+
+// checkFurniture 家具自动检查：违禁词、电话号码、重复刊登、价格异常（同地区同分类）
+func (s *ModerationService) checkFurniture(ctx context.Context, furniture *models.Furniture) ([]models.ModerationFlag, error) {
+	flags := s.checkText(furniture.Title, furniture.Description)
+
+	duplicates, err := s.furnitureRepo.CountDuplicates(ctx, furniture)
+	if err != nil {
+		return nil, err
+	}
+	if duplicates > 0 {
+		flags = append(flags, models.ModerationFlag{
+			Code:     "duplicate",
+			Severity: models.FlagSeverityReview,
+			Detail:   fmt.Sprintf("发布者已有 %d 个相同标题的刊登", duplicates),
+		})
+	}
+
+	avg, samples, err := s.furnitureRepo.GetDistrictCategoryPrice(ctx, furniture.DeliveryDistrictID, furniture.CategoryID, furniture.ID)
+	if err != nil {
+		return nil, err
+	}
+	if flag := s.checkPrice(furniture.Price, avg, samples, "价格"); flag != nil {
+		flags = append(flags, *flag)
+	}
+
+	return flags, nil
+}
+
+// checkText 检查标题和描述中的违禁词与电话号码
+func (s *ModerationService) checkText(title, description string) []models.ModerationFlag {
+	var flags []models.ModerationFlag
+
+	text := strings.ToLower(title + "\n" + description)
+	for _, word := range s.policy.BannedWords {
+		if strings.Contains(text, word) {
+			flags = append(flags, models.ModerationFlag{
+				Code:     "banned_word",
+				Severity: models.FlagSeverityBlock,
+				Detail:   fmt.Sprintf("包含违禁词「%s」", word),
+			})
+		}
+	}
+
+	if phone := phonePattern.FindString(description); phone != "" {
+		flags = append(flags, models.ModerationFlag{
+			Code:     "phone_number",
+			Severity: models.FlagSeverityReview,
+			Detail:   fmt.Sprintf("描述中包含电话号码「%s」，请使用平台联系方式", phone),
+		})
+	}
+
+	return flags
+}
+
+// checkPrice 与地区均价比较，样本不足时跳过
+func (s *ModerationService) checkPrice(price, avg float64, samples int64, label string) *models.ModerationFlag {
+	if samples < int64(s.policy.PriceMinSamples) || avg <= 0 {
+		return nil
+	}
+	ratio := price / avg * 100
+	if ratio >= float64(s.policy.PriceLowPercent) && ratio <= float64(s.policy.PriceHighPercent) {
+		return nil
+	}
+	return &models.ModerationFlag{
+		Code:     "price_outlier",
+		Severity: models.FlagSeverityReview,
+		Detail:   fmt.Sprintf("%s %.0f 为地区均价 %.0f 的 %.0f%%", label, price, avg, ratio),
+	}
+}
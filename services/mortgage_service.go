@@ -0,0 +1,302 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// MortgageService Methods:
+// 0. NewMortgageService(policy *MortgagePolicy) -> 注入按揭规则
+// 1. Calculate(req *models.MortgageRequest) -> 按揭计算（每月供款、总利息、供款表及供款能力测试）
+// 2. Estimate(price float64) -> 房产详情中的按揭估算（按默认规则，停用时返回 nil）
+
+// MortgageTier 按揭成数级别（按楼价由低至高排列）
+type MortgageTier struct {
+	MaxPrice float64 // 适用楼价上限，0 表示不限
+	LTV      float64 // 最高按揭成数 (%)
+	MaxLoan  float64 // 贷款额上限，0 表示不限
+}
+
+// MortgagePolicy 按揭规则
+type MortgagePolicy struct {
+	DefaultRate        float64 // 默认年利率 (%)
+	DefaultTenureYears int     // 默认还款年期
+
+	StandardTiers           []MortgageTier // 一般按揭成数上限（自住、受薪、无其他按揭）
+	InsuranceTiers          []MortgageTier // 按揭保险计划成数上限
+	InsuranceFirstTimeTiers []MortgageTier // 按揭保险计划成数上限（首次置业）
+	ExistingMortgageCut     float64        // 已有其他按揭时成数下调 (%)
+	NonLocalIncomeCut       float64        // 主要收入来自香港以外时成数下调 (%)
+
+	DSRLimit         float64 // 供款与入息比率上限 (%)
+	StressAddOn      float64 // 压力测试加息幅度 (%)
+	StressedDSRLimit float64 // 压力测试下供款与入息比率上限 (%)
+
+	EmbedEstimate bool // 出售房源详情是否附带按揭估算
+}
+
+// maxMortgageTenureYears 还款年期上限（与请求校验一致）
+const maxMortgageTenureYears = 30
+
+// LoadMortgagePolicy 从环境变量加载按揭规则（成数级别参考金管局逆周期措施及按揭证券公司按揭保险计划）
+func LoadMortgagePolicy() *MortgagePolicy {
+	tenure := tools.GetEnvInt("MORTGAGE_DEFAULT_TENURE_YEARS", maxMortgageTenureYears)
+	if tenure < 1 || tenure > maxMortgageTenureYears {
+		log.Printf("⚠️  Invalid MORTGAGE_DEFAULT_TENURE_YEARS (%d), must be 1-%d, using default %d", tenure, maxMortgageTenureYears, maxMortgageTenureYears)
+		tenure = maxMortgageTenureYears
+	}
+
+	return &MortgagePolicy{
+		DefaultRate:        tools.GetEnvFloat("MORTGAGE_DEFAULT_RATE", 3.5),
+		DefaultTenureYears: tenure,
+		StandardTiers: []MortgageTier{
+			{MaxPrice: 30_000_000, LTV: 70},
+			{LTV: 60},
+		},
+		InsuranceTiers: []MortgageTier{
+			{MaxPrice: 15_000_000, LTV: 80},
+		},
+		InsuranceFirstTimeTiers: []MortgageTier{
+			{MaxPrice: 10_000_000, LTV: 90},
+			{MaxPrice: 11_250_000, LTV: 90, MaxLoan: 9_000_000},
+			{MaxPrice: 15_000_000, LTV: 80},
+		},
+		ExistingMortgageCut: 10,
+		NonLocalIncomeCut:   10,
+		DSRLimit:            tools.GetEnvFloat("MORTGAGE_DSR_LIMIT", 50),
+		StressAddOn:         tools.GetEnvFloat("MORTGAGE_STRESS_ADD_ON", 2),
+		StressedDSRLimit:    tools.GetEnvFloat("MORTGAGE_STRESSED_DSR_LIMIT", 60),
+		EmbedEstimate:       tools.GetEnvBool("MORTGAGE_EMBED_ESTIMATE", true),
+	}
+}
+
+// MortgageService 按揭计算服务
+type MortgageService struct {
+	policy *MortgagePolicy
+}
+
+// 0. NewMortgageService 构造函数
+func NewMortgageService(policy *MortgagePolicy) *MortgageService {
+	return &MortgageService{policy: policy}
+}
+
+// 1. Calculate 按揭计算
+func (s *MortgageService) Calculate(req *models.MortgageRequest) (*models.MortgageResponse, error) {
+	scheme, maxLTV, maxLoan, notes, err := s.loanLimit(req)
+	if err != nil {
+		return nil, err
+	}
+
+	loan := maxLoan
+	switch {
+	case req.LoanAmount > 0:
+		loan = req.LoanAmount
+	case req.DownPayment > 0:
+		loan = req.Price - req.DownPayment
+	case req.LTV > 0:
+		loan = req.Price * req.LTV / 100
+	}
+	if loan <= 0 || loan > req.Price {
+		return nil, tools.WrapError(400, "loan amount must be between 0 and the property price", tools.ErrInvalidInput)
+	}
+	if loan > maxLoan+0.005 {
+		return nil, tools.WrapError(400, fmt.Sprintf("loan amount exceeds the maximum of %.0f (%.0f%% LTV)", maxLoan, maxLTV), tools.ErrInvalidInput)
+	}
+
+	rate := s.policy.DefaultRate
+	if req.AnnualRate != nil {
+		rate = *req.AnnualRate
+	}
+	tenure := s.policy.DefaultTenureYears
+	if req.TenureYears > 0 {
+		tenure = req.TenureYears
+	}
+	months := tenure * 12
+
+	payment := monthlyPayment(loan, rate, months)
+	stressedRate := rate + s.policy.StressAddOn
+	stressedPayment := monthlyPayment(loan, stressedRate, months)
+
+	result := &models.MortgageResponse{
+		Price:          req.Price,
+		Scheme:         scheme,
+		MaxLTV:         maxLTV,
+		MaxLoanAmount:  round2(maxLoan),
+		LoanAmount:     round2(loan),
+		DownPayment:    round2(req.Price - loan),
+		LTV:            round2(loan / req.Price * 100),
+		AnnualRate:     rate,
+		TenureYears:    tenure,
+		MonthlyPayment: round2(payment),
+		TotalPayment:   round2(payment * float64(months)),
+		TotalInterest:  round2(payment*float64(months) - loan),
+		Affordability:  s.affordability(payment, stressedRate, stressedPayment, req.MonthlyIncome, req.OtherMonthlyDebt),
+		Notes:          notes,
+	}
+
+	granularity := req.Schedule
+	if granularity == "" {
+		granularity = models.MortgageScheduleYearly
+	}
+	if granularity != models.MortgageScheduleNone {
+		result.Schedule = amortize(loan, rate, months, payment, granularity == models.MortgageScheduleYearly)
+	}
+
+	return result, nil
+}
+
+// 2. Estimate 按默认利率、年期及一般按揭最高成数估算（停用或楼价无效时返回 nil）
+func (s *MortgageService) Estimate(price float64) *models.MortgageEstimate {
+	if !s.policy.EmbedEstimate || price <= 0 {
+		return nil
+	}
+
+	result, err := s.Calculate(&models.MortgageRequest{Price: price, Schedule: models.MortgageScheduleNone})
+	if err != nil {
+		return nil
+	}
+
+	return &models.MortgageEstimate{
+		LoanAmount:            result.LoanAmount,
+		DownPayment:           result.DownPayment,
+		LTV:                   result.LTV,
+		AnnualRate:            result.AnnualRate,
+		TenureYears:           result.TenureYears,
+		MonthlyPayment:        result.MonthlyPayment,
+		TotalInterest:         result.TotalInterest,
+		RequiredMonthlyIncome: result.Affordability.RequiredMonthlyIncome,
+	}
+}
+
+// loanLimit 计算适用方案的最高按揭成数及贷款额
+func (s *MortgageService) loanLimit(req *models.MortgageRequest) (string, float64, float64, []string, error) {
+	var notes []string
+
+	if req.MortgageInsurance {
+		tiers := s.policy.InsuranceTiers
+		if req.FirstTimeBuyer {
+			tiers = s.policy.InsuranceFirstTimeTiers
+		}
+		tier, ok := findMortgageTier(tiers, req.Price)
+		if !ok {
+			return "", 0, 0, nil, tools.WrapError(400, "property price exceeds the mortgage insurance programme limit", tools.ErrInvalidInput)
+		}
+		maxLoan := req.Price * tier.LTV / 100
+		if tier.MaxLoan > 0 && maxLoan > tier.MaxLoan {
+			maxLoan = tier.MaxLoan
+			notes = append(notes, fmt.Sprintf("mortgage insurance loan capped at %.0f", tier.MaxLoan))
+		}
+		notes = append(notes, "mortgage insurance premium is not included")
+		return models.MortgageSchemeInsurance, tier.LTV, maxLoan, notes, nil
+	}
+
+	tier, _ := findMortgageTier(s.policy.StandardTiers, req.Price)
+	ltv := tier.LTV
+	if req.HasExistingMortgage {
+		ltv -= s.policy.ExistingMortgageCut
+		notes = append(notes, fmt.Sprintf("LTV reduced by %.0f%% for borrowers with an existing mortgage", s.policy.ExistingMortgageCut))
+	}
+	if req.IncomeOutsideHK {
+		ltv -= s.policy.NonLocalIncomeCut
+		notes = append(notes, fmt.Sprintf("LTV reduced by %.0f%% for income derived mainly outside Hong Kong", s.policy.NonLocalIncomeCut))
+	}
+	maxLoan := req.Price * ltv / 100
+	if tier.MaxLoan > 0 && maxLoan > tier.MaxLoan {
+		maxLoan = tier.MaxLoan
+	}
+	return models.MortgageSchemeStandard, ltv, maxLoan, notes, nil
+}
+
+// affordability 供款与入息比率及压力测试
+func (s *MortgageService) affordability(payment, stressedRate, stressedPayment, income, otherDebt float64) models.MortgageAffordability {
+	required := math.Max(
+		(payment+otherDebt)/(s.policy.DSRLimit/100),
+		(stressedPayment+otherDebt)/(s.policy.StressedDSRLimit/100),
+	)
+
+	result := models.MortgageAffordability{
+		DSRLimit:              s.policy.DSRLimit,
+		StressedRate:          stressedRate,
+		StressedPayment:       round2(stressedPayment),
+		StressedDSRLimit:      s.policy.StressedDSRLimit,
+		RequiredMonthlyIncome: math.Ceil(required),
+	}
+	if income > 0 {
+		dsr := round2((payment + otherDebt) / income * 100)
+		stressedDSR := round2((stressedPayment + otherDebt) / income * 100)
+		passed := dsr <= s.policy.DSRLimit && stressedDSR <= s.policy.StressedDSRLimit
+		result.MonthlyIncome = income
+		result.DSR = &dsr
+		result.StressedDSR = &stressedDSR
+		result.Passed = &passed
+	}
+	return result
+}
+
+// findMortgageTier 查找楼价适用的成数级别
+func findMortgageTier(tiers []MortgageTier, price float64) (MortgageTier, bool) {
+	for _, tier := range tiers {
+		if tier.MaxPrice == 0 || price <= tier.MaxPrice {
+			return tier, true
+		}
+	}
+	return MortgageTier{}, false
+}
+
+// monthlyPayment 等额本息每月供款
+func monthlyPayment(loan, annualRate float64, months int) float64 {
+	r := annualRate / 100 / 12
+	if r == 0 {
+		return loan / float64(months)
+	}
+	return loan * r / (1 - math.Pow(1+r, -float64(months)))
+}
+
+// amortize 生成供款表（yearly 为按年汇总）
+func amortize(loan, annualRate float64, months int, payment float64, yearly bool) []models.MortgageSchedulePeriod {
+	r := annualRate / 100 / 12
+	balance := loan
+	periods := make([]models.MortgageSchedulePeriod, 0, months)
+
+	var current models.MortgageSchedulePeriod
+	for m := 1; m <= months; m++ {
+		interest := balance * r
+		principal := payment - interest
+		if m == months {
+			principal = balance
+		}
+		balance -= principal
+
+		current.Payment += principal + interest
+		current.Principal += principal
+		current.Interest += interest
+		if !yearly || m%12 == 0 || m == months {
+			current.Period = m
+			if yearly {
+				current.Period = (m + 11) / 12
+			}
+			current.Balance = math.Max(balance, 0)
+			periods = append(periods, roundSchedulePeriod(current))
+			current = models.MortgageSchedulePeriod{}
+		}
+	}
+	return periods
+}
+
+// roundSchedulePeriod 供款表金额保留两位小数
+func roundSchedulePeriod(p models.MortgageSchedulePeriod) models.MortgageSchedulePeriod {
+	p.Payment = round2(p.Payment)
+	p.Principal = round2(p.Principal)
+	p.Interest = round2(p.Interest)
+	p.Balance = round2(p.Balance)
+	return p
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
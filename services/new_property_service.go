@@ -2,9 +2,13 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
 )
 
 // NewDevelopmentService 新盘服务
@@ -12,13 +16,31 @@ import (
 // 1. ListNewProperties(ctx context.Context, filter *models.ListNewPropertiesRequest) -> 获取新盘列表
 // 2. GetNewProperty(ctx context.Context, id uint) -> 获取新盘详情
 // 3. GetNewPropertyLayouts(ctx context.Context, newPropertyID uint) -> 获取新盘户型列表
+// 4. GetNewPropertyImages(ctx context.Context, newPropertyID uint) -> 获取新盘图片列表
+// 5. AddNewPropertyImage(ctx context.Context, newPropertyID uint, req *models.AddImageRequest) -> 添加新盘图片（管理员）
+// 6. UpdateNewPropertyImage(ctx context.Context, newPropertyID uint, imageID uint, req *models.UpdateImageRequest) -> 更新新盘图片信息（管理员）
+// 7. DeleteNewPropertyImage(ctx context.Context, newPropertyID uint, imageID uint) -> 删除新盘图片（管理员）
+// 8. ReorderNewPropertyImages(ctx context.Context, newPropertyID uint, req *models.ReorderImagesRequest) -> 批量调整新盘图片顺序（管理员）
+// 9. SetNewPropertyCoverImage(ctx context.Context, newPropertyID uint, imageID uint) -> 设置新盘封面图（管理员）
+// 10. CreateNewProperty(ctx context.Context, req *models.CreateNewPropertyRequest) -> 创建新盘（管理员）
+// 11. UpdateNewProperty(ctx context.Context, id uint, req *models.UpdateNewPropertyRequest) -> 更新新盘（管理员，含销售状态及售出单位）
+// 12. DeleteNewProperty(ctx context.Context, id uint) -> 删除新盘（管理员）
+// 13. CreateNewPropertyLayout(ctx context.Context, newPropertyID uint, req *models.CreateNewPropertyLayoutRequest) -> 添加新盘户型（管理员）
+// 14. UpdateNewPropertyLayout(ctx context.Context, newPropertyID uint, layoutID uint, req *models.UpdateNewPropertyLayoutRequest) -> 更新新盘户型（管理员）
+// 15. DeleteNewPropertyLayout(ctx context.Context, newPropertyID uint, layoutID uint) -> 删除新盘户型（管理员）
+// 16. ListTransactions(ctx context.Context, newPropertyID uint, req *models.ListNewPropertyTransactionsRequest) -> 成交纪录册
+// 17. RecordTransaction(ctx context.Context, newPropertyID uint, req *models.RecordNewPropertyTransactionRequest) -> 登记一手成交（管理员）
+// 18. UpdateTransaction(ctx context.Context, newPropertyID uint, transactionID uint, req *models.UpdateNewPropertyTransactionRequest) -> 更新或终止一手成交（管理员）
 type NewDevelopmentService struct {
-	repo *databases.NewDevelopmentRepo
+	repo            *databases.NewDevelopmentRepo
+	transactionRepo *databases.NewPropertyTransactionRepo
+	mediaService    *MediaService
+	auditService    *AuditService
 }
 
 // NewNewDevelopmentService 创建新盘服务
-func NewNewDevelopmentService(repo *databases.NewDevelopmentRepo) *NewDevelopmentService {
-	return &NewDevelopmentService{repo: repo}
+func NewNewDevelopmentService(repo *databases.NewDevelopmentRepo, transactionRepo *databases.NewPropertyTransactionRepo, mediaService *MediaService, auditService *AuditService) *NewDevelopmentService {
+	return &NewDevelopmentService{repo: repo, transactionRepo: transactionRepo, mediaService: mediaService, auditService: auditService}
 }
 
 // ListNewProperties 获取新盘列表
@@ -33,6 +55,9 @@ func (s *NewDevelopmentService) ListNewProperties(ctx context.Context, filter *m
 	if filter.PageSize > 100 {
 		filter.PageSize = 100
 	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, tools.WrapError(400, "min_price must not exceed max_price", tools.ErrInvalidInput)
+	}
 
 	newProperties, total, err := s.repo.FindAll(ctx, filter)
 	if err != nil {
@@ -43,6 +68,9 @@ func (s *NewDevelopmentService) ListNewProperties(ctx context.Context, filter *m
 	items := make([]models.NewPropertyResponse, len(newProperties))
 	for i, np := range newProperties {
 		items[i] = *np.ToNewPropertyResponse()
+		if filter.HasLayoutFilter() {
+			items[i].MatchingLayouts = np.Layouts
+		}
 	}
 
 	// 计算总页数
@@ -60,22 +88,34 @@ func (s *NewDevelopmentService) ListNewProperties(ctx context.Context, filter *m
 	}, nil
 }
 
-// GetNewProperty 获取新盘详情
+// GetNewProperty 获取新盘详情（含户型成交概况及销售进度）
 func (s *NewDevelopmentService) GetNewProperty(ctx context.Context, id uint) (*models.NewPropertyDetailResponse, error) {
 	newProperty, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	transactions, err := s.transactionRepo.FindActive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	releaseDates, err := s.transactionRepo.FindUnitReleaseDates(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	attachLayoutSalesSummaries(newProperty.Layouts, transactions)
+
 	// 异步增加浏览次数
 	go func() {
 		_ = s.repo.IncrementViewCount(context.Background(), id)
 	}()
 
-	return newProperty.ToNewPropertyDetailResponse(), nil
+	resp := newProperty.ToNewPropertyDetailResponse()
+	resp.SalesProgress = buildSalesProgress(newProperty.TotalUnits, releaseDates, transactions, time.Now().In(hongKongTime))
+	return resp, nil
 }
 
-// GetNewPropertyLayouts 获取新盘户型列表
+// GetNewPropertyLayouts 获取新盘户型列表（含成交概况）
 func (s *NewDevelopmentService) GetNewPropertyLayouts(ctx context.Context, newPropertyID uint) ([]models.NewPropertyLayout, error) {
 	// 先检查新盘是否存在
 	_, err := s.repo.FindByID(ctx, newPropertyID)
@@ -83,5 +123,695 @@ func (s *NewDevelopmentService) GetNewPropertyLayouts(ctx context.Context, newPr
 		return nil, err
 	}
 
-	return s.repo.FindLayouts(ctx, newPropertyID)
+	layouts, err := s.repo.FindLayouts(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepo.FindActive(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	attachLayoutSalesSummaries(layouts, transactions)
+	return layouts, nil
+}
+
+// newPropertyImageTypes 新盘图片类型
+var newPropertyImageTypes = []string{"exterior", "interior", "facilities", "floorplan", "location"}
+
+// GetNewPropertyImages 获取新盘图片列表
+func (s *NewDevelopmentService) GetNewPropertyImages(ctx context.Context, newPropertyID uint) ([]models.NewPropertyImage, error) {
+	// 先检查新盘是否存在
+	_, err := s.repo.FindByID(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindImages(ctx, newPropertyID)
+}
+
+// AddNewPropertyImage 添加新盘图片（追加到末尾，第一张自动作为封面）
+func (s *NewDevelopmentService) AddNewPropertyImage(ctx context.Context, newPropertyID uint, req *models.AddImageRequest) (*models.NewPropertyImage, error) {
+	images, err := s.GetNewPropertyImages(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImageCount(len(images)); err != nil {
+		return nil, err
+	}
+
+	imageType := req.ImageType
+	if imageType == "" {
+		imageType = "exterior"
+	}
+	if err := checkImageType(imageType, newPropertyImageTypes...); err != nil {
+		return nil, err
+	}
+
+	source, err := s.mediaService.ResolveImage(ctx, req.ImageKey, req.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.NewPropertyImage{
+		NewPropertyID: newPropertyID,
+		ImageURL:      source.ImageURL,
+		ObjectKey:     source.ObjectKey,
+		ThumbnailURL:  source.ThumbnailURL,
+		ImageType:     imageType,
+		Title:         req.Title,
+		IsCover:       len(images) == 0,
+		SortOrder:     len(images),
+	}
+	if err := s.repo.CreateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property_image", image.ID, models.AuditActionCreate, nil, image)
+	return image, nil
+}
+
+// UpdateNewPropertyImage 更新新盘图片类型/标题
+func (s *NewDevelopmentService) UpdateNewPropertyImage(ctx context.Context, newPropertyID uint, imageID uint, req *models.UpdateImageRequest) (*models.NewPropertyImage, error) {
+	image, err := s.repo.FindImage(ctx, newPropertyID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	before := *image
+
+	if req.ImageType != nil {
+		if err := checkImageType(*req.ImageType, newPropertyImageTypes...); err != nil {
+			return nil, err
+		}
+		image.ImageType = *req.ImageType
+	}
+	if req.Title != nil {
+		image.Title = *req.Title
+	}
+
+	if err := s.repo.UpdateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property_image", imageID, models.AuditActionUpdate, &before, image)
+	return image, nil
+}
+
+// DeleteNewPropertyImage 删除新盘图片
+func (s *NewDevelopmentService) DeleteNewPropertyImage(ctx context.Context, newPropertyID uint, imageID uint) error {
+	image, err := s.repo.FindImage(ctx, newPropertyID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteImage(ctx, newPropertyID, imageID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "new_property_image", imageID, models.AuditActionDelete, image, nil)
+	return nil
+}
+
+// ReorderNewPropertyImages 批量调整新盘图片顺序
+func (s *NewDevelopmentService) ReorderNewPropertyImages(ctx context.Context, newPropertyID uint, req *models.ReorderImagesRequest) ([]models.NewPropertyImage, error) {
+	images, err := s.GetNewPropertyImages(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(images))
+	for i, img := range images {
+		currentIDs[i] = img.ID
+	}
+	if err := checkImageOrder(currentIDs, req.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderImages(ctx, newPropertyID, req.ImageIDs); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, newPropertyID, images)
+}
+
+// SetNewPropertyCoverImage 设置新盘封面图（新封面移到首位）
+func (s *NewDevelopmentService) SetNewPropertyCoverImage(ctx context.Context, newPropertyID uint, imageID uint) ([]models.NewPropertyImage, error) {
+	images, err := s.GetNewPropertyImages(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindImage(ctx, newPropertyID, imageID); err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(images))
+	for i, img := range images {
+		currentIDs[i] = img.ID
+	}
+	if err := s.repo.SetCoverImage(ctx, newPropertyID, imageID, coverFirstOrder(currentIDs, imageID)); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, newPropertyID, images)
+}
+
+// imagesAfterChange 重新查询新盘图片，并逐张记录排序及封面变更
+func (s *NewDevelopmentService) imagesAfterChange(ctx context.Context, newPropertyID uint, before []models.NewPropertyImage) ([]models.NewPropertyImage, error) {
+	images, err := s.repo.FindImages(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.RecordEach(ctx, "new_property_image", models.AuditActionUpdate, before, images)
+	return images, nil
+}
+
+// CreateNewProperty 创建新盘
+func (s *NewDevelopmentService) CreateNewProperty(ctx context.Context, req *models.CreateNewPropertyRequest) (*models.NewPropertyDetailResponse, error) {
+	expectedCompletion, err := parseNewPropertyDate("expected_completion", req.ExpectedCompletion)
+	if err != nil {
+		return nil, err
+	}
+	occupationDate, err := parseNewPropertyDate("occupation_date", req.OccupationDate)
+	if err != nil {
+		return nil, err
+	}
+
+	newProperty := &models.NewProperty{
+		Name:               req.Name,
+		NameEn:             req.NameEn,
+		Address:            req.Address,
+		DistrictID:         req.DistrictID,
+		Status:             req.Status,
+		UnitsForSale:       req.UnitsForSale,
+		UnitsSold:          req.UnitsSold,
+		Developer:          req.Developer,
+		ManagementCompany:  req.ManagementCompany,
+		TotalUnits:         req.TotalUnits,
+		TotalBlocks:        req.TotalBlocks,
+		MaxFloors:          req.MaxFloors,
+		PrimarySchoolNet:   req.PrimarySchoolNet,
+		SecondarySchoolNet: req.SecondarySchoolNet,
+		WebsiteURL:         req.WebsiteURL,
+		SalesOfficeAddress: req.SalesOfficeAddress,
+		SalesPhone:         req.SalesPhone,
+		ExpectedCompletion: expectedCompletion,
+		OccupationDate:     occupationDate,
+		Description:        req.Description,
+		SortOrder:          req.SortOrder,
+		IsFeatured:         req.IsFeatured,
+	}
+	if err := checkNewPropertyUnits(newProperty, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, newProperty); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property", newProperty.ID, models.AuditActionCreate, nil, newProperty)
+
+	created, err := s.repo.FindByID(ctx, newProperty.ID)
+	if err != nil {
+		return nil, err
+	}
+	return created.ToNewPropertyDetailResponse(), nil
+}
+
+// checkUnitCountsEditable 已公布价单的新盘，已售/在售单位数由单位销售状态汇总，不可手动修改
+func (s *NewDevelopmentService) checkUnitCountsEditable(ctx context.Context, newProperty *models.NewProperty, req *models.UpdateNewPropertyRequest) error {
+	changed := (req.UnitsForSale != nil && *req.UnitsForSale != newProperty.UnitsForSale) ||
+		(req.UnitsSold != nil && *req.UnitsSold != newProperty.UnitsSold)
+	if !changed {
+		return nil
+	}
+	hasPriceList, err := s.repo.HasPriceList(ctx, newProperty.ID)
+	if err != nil {
+		return err
+	}
+	if hasPriceList {
+		return tools.WrapError(400, "units_for_sale and units_sold are maintained from price list units once a price list is published", tools.ErrInvalidInput)
+	}
+	return nil
+}
+
+// UpdateNewProperty 更新新盘（单位数及状态变更后重新校验与户型的一致性）
+func (s *NewDevelopmentService) UpdateNewProperty(ctx context.Context, id uint, req *models.UpdateNewPropertyRequest) (*models.NewPropertyDetailResponse, error) {
+	newProperty, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *newProperty
+
+	if req.Name != nil {
+		newProperty.Name = *req.Name
+	}
+	if req.NameEn != nil {
+		newProperty.NameEn = *req.NameEn
+	}
+	if req.Address != nil {
+		newProperty.Address = *req.Address
+	}
+	if req.DistrictID != nil {
+		newProperty.DistrictID = *req.DistrictID
+		newProperty.District = nil
+	}
+	if req.Status != nil {
+		newProperty.Status = *req.Status
+	}
+	if err := s.checkUnitCountsEditable(ctx, newProperty, req); err != nil {
+		return nil, err
+	}
+	if req.UnitsForSale != nil {
+		newProperty.UnitsForSale = *req.UnitsForSale
+	}
+	if req.UnitsSold != nil {
+		newProperty.UnitsSold = *req.UnitsSold
+	}
+	if req.Developer != nil {
+		newProperty.Developer = *req.Developer
+	}
+	if req.ManagementCompany != nil {
+		newProperty.ManagementCompany = *req.ManagementCompany
+	}
+	if req.TotalUnits != nil {
+		newProperty.TotalUnits = *req.TotalUnits
+	}
+	if req.TotalBlocks != nil {
+		newProperty.TotalBlocks = *req.TotalBlocks
+	}
+	if req.MaxFloors != nil {
+		newProperty.MaxFloors = *req.MaxFloors
+	}
+	if req.PrimarySchoolNet != nil {
+		newProperty.PrimarySchoolNet = *req.PrimarySchoolNet
+	}
+	if req.SecondarySchoolNet != nil {
+		newProperty.SecondarySchoolNet = *req.SecondarySchoolNet
+	}
+	if req.WebsiteURL != nil {
+		newProperty.WebsiteURL = *req.WebsiteURL
+	}
+	if req.SalesOfficeAddress != nil {
+		newProperty.SalesOfficeAddress = *req.SalesOfficeAddress
+	}
+	if req.SalesPhone != nil {
+		newProperty.SalesPhone = *req.SalesPhone
+	}
+	if req.ExpectedCompletion != nil {
+		if newProperty.ExpectedCompletion, err = parseNewPropertyDate("expected_completion", *req.ExpectedCompletion); err != nil {
+			return nil, err
+		}
+	}
+	if req.OccupationDate != nil {
+		if newProperty.OccupationDate, err = parseNewPropertyDate("occupation_date", *req.OccupationDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		newProperty.Description = *req.Description
+	}
+	if req.SortOrder != nil {
+		newProperty.SortOrder = *req.SortOrder
+	}
+	if req.IsFeatured != nil {
+		newProperty.IsFeatured = *req.IsFeatured
+	}
+
+	if err := checkNewPropertyUnits(newProperty, newProperty.Layouts); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, newProperty); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property", id, models.AuditActionUpdate, &before, newProperty)
+
+	updated, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToNewPropertyDetailResponse(), nil
+}
+
+// DeleteNewProperty 删除新盘（软删除，图片和户型保留以便恢复）
+func (s *NewDevelopmentService) DeleteNewProperty(ctx context.Context, id uint) error {
+	newProperty, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "new_property", id, models.AuditActionDelete, newProperty, nil)
+	return nil
+}
+
+// CreateNewPropertyLayout 添加新盘户型
+func (s *NewDevelopmentService) CreateNewPropertyLayout(ctx context.Context, newPropertyID uint, req *models.CreateNewPropertyLayoutRequest) (*models.NewPropertyLayout, error) {
+	newProperty, err := s.repo.FindByID(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &models.NewPropertyLayout{
+		NewPropertyID:  newPropertyID,
+		UnitType:       req.UnitType,
+		Bedrooms:       req.Bedrooms,
+		Bathrooms:      req.Bathrooms,
+		SaleableArea:   req.SaleableArea,
+		GrossArea:      req.GrossArea,
+		MinPrice:       req.MinPrice,
+		MaxPrice:       req.MaxPrice,
+		PricePerSqft:   req.PricePerSqft,
+		AvailableUnits: req.AvailableUnits,
+		FloorplanURL:   req.FloorplanURL,
+	}
+	if err := checkNewPropertyLayout(layout); err != nil {
+		return nil, err
+	}
+	if err := checkNewPropertyUnits(newProperty, append(newProperty.Layouts, *layout)); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateLayout(ctx, layout); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property_layout", layout.ID, models.AuditActionCreate, nil, layout)
+	return layout, nil
+}
+
+// UpdateNewPropertyLayout 更新新盘户型（未指定呎价时随售价或面积变化重新计算）
+func (s *NewDevelopmentService) UpdateNewPropertyLayout(ctx context.Context, newPropertyID uint, layoutID uint, req *models.UpdateNewPropertyLayoutRequest) (*models.NewPropertyLayout, error) {
+	newProperty, err := s.repo.FindByID(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := s.repo.FindLayout(ctx, newPropertyID, layoutID)
+	if err != nil {
+		return nil, err
+	}
+	before := *layout
+
+	if req.UnitType != nil {
+		layout.UnitType = *req.UnitType
+	}
+	if req.Bedrooms != nil {
+		layout.Bedrooms = *req.Bedrooms
+	}
+	if req.Bathrooms != nil {
+		layout.Bathrooms = *req.Bathrooms
+	}
+	if req.SaleableArea != nil {
+		layout.SaleableArea = *req.SaleableArea
+	}
+	if req.GrossArea != nil {
+		layout.GrossArea = *req.GrossArea
+	}
+	if req.MinPrice != nil {
+		layout.MinPrice = *req.MinPrice
+	}
+	if req.MaxPrice != nil {
+		layout.MaxPrice = *req.MaxPrice
+	}
+	if req.PricePerSqft != nil {
+		layout.PricePerSqft = *req.PricePerSqft
+	} else if req.MinPrice != nil || req.SaleableArea != nil {
+		layout.PricePerSqft = 0
+	}
+	if req.AvailableUnits != nil {
+		layout.AvailableUnits = *req.AvailableUnits
+	}
+	if req.FloorplanURL != nil {
+		layout.FloorplanURL = *req.FloorplanURL
+	}
+
+	if err := checkNewPropertyLayout(layout); err != nil {
+		return nil, err
+	}
+	layouts := make([]models.NewPropertyLayout, 0, len(newProperty.Layouts))
+	for _, l := range newProperty.Layouts {
+		if l.ID == layoutID {
+			l = *layout
+		}
+		layouts = append(layouts, l)
+	}
+	if err := checkNewPropertyUnits(newProperty, layouts); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateLayout(ctx, layout); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property_layout", layoutID, models.AuditActionUpdate, &before, layout)
+	return layout, nil
+}
+
+// DeleteNewPropertyLayout 删除新盘户型
+func (s *NewDevelopmentService) DeleteNewPropertyLayout(ctx context.Context, newPropertyID uint, layoutID uint) error {
+	layout, err := s.repo.FindLayout(ctx, newPropertyID, layoutID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteLayout(ctx, newPropertyID, layoutID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "new_property_layout", layoutID, models.AuditActionDelete, layout, nil)
+	return nil
+}
+
+// ListTransactions 成交纪录册
+func (s *NewDevelopmentService) ListTransactions(ctx context.Context, newPropertyID uint, req *models.ListNewPropertyTransactionsRequest) (*models.PaginatedNewPropertyTransactionsResponse, error) {
+	if _, err := s.repo.FindByID(ctx, newPropertyID); err != nil {
+		return nil, err
+	}
+
+	transactions, total, err := s.transactionRepo.FindAll(ctx, newPropertyID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedNewPropertyTransactionsResponse{
+		Data:       transactions,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// RecordTransaction 登记一手成交（单位须已出现在价单、未售出且没有未终止的成交，登记时锁定单位检查），单位按成交日期标记为已售
+func (s *NewDevelopmentService) RecordTransaction(ctx context.Context, newPropertyID uint, req *models.RecordNewPropertyTransactionRequest) (*models.NewPropertyTransaction, error) {
+	if _, err := s.repo.FindByID(ctx, newPropertyID); err != nil {
+		return nil, err
+	}
+	unit, err := s.transactionRepo.FindUnit(ctx, newPropertyID, req.UnitID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionDate, err := time.ParseInLocation("2006-01-02", req.TransactionDate, hongKongTime)
+	if err != nil {
+		return nil, tools.WrapError(400, "invalid transaction_date, expected YYYY-MM-DD", tools.ErrInvalidInput)
+	}
+	if transactionDate.After(time.Now()) {
+		return nil, tools.WrapError(400, "transaction_date must not be in the future", tools.ErrInvalidInput)
+	}
+
+	status := req.Status
+	if status == "" {
+		status = models.NewPropertyTxPASP
+	}
+	transaction := &models.NewPropertyTransaction{
+		NewPropertyID:    newPropertyID,
+		UnitID:           unit.ID,
+		LayoutID:         unit.LayoutID,
+		Block:            unit.Block,
+		Floor:            unit.Floor,
+		Flat:             unit.Flat,
+		SaleableArea:     unit.SaleableArea,
+		ListPrice:        unit.ListPrice,
+		TransactionPrice: req.TransactionPrice,
+		PricePerSqft:     math.Round(req.TransactionPrice / unit.SaleableArea),
+		PaymentPlan:      req.PaymentPlan,
+		TransactionDate:  transactionDate,
+		Status:           status,
+		Remarks:          req.Remarks,
+	}
+	if err := s.transactionRepo.Record(ctx, transaction); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property_transaction", transaction.ID, models.AuditActionCreate, nil, transaction)
+	return transaction, nil
+}
+
+// UpdateTransaction 更新成交状态、付款办法或备注；终止后单位恢复可售，已终止的成交不可再修改
+func (s *NewDevelopmentService) UpdateTransaction(ctx context.Context, newPropertyID uint, transactionID uint, req *models.UpdateNewPropertyTransactionRequest) (*models.NewPropertyTransaction, error) {
+	transaction, err := s.transactionRepo.FindByID(ctx, newPropertyID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status == models.NewPropertyTxTerminated {
+		return nil, tools.WrapError(400, "terminated transaction cannot be modified", tools.ErrInvalidInput)
+	}
+	before := *transaction
+
+	if req.Status != nil {
+		if *req.Status == models.NewPropertyTxPASP && transaction.Status == models.NewPropertyTxASP {
+			return nil, tools.WrapError(400, "cannot revert an asp transaction to pasp", tools.ErrInvalidInput)
+		}
+		transaction.Status = *req.Status
+		if transaction.Status == models.NewPropertyTxTerminated {
+			now := time.Now()
+			transaction.TerminatedAt = &now
+		}
+	}
+	if req.PaymentPlan != nil {
+		transaction.PaymentPlan = *req.PaymentPlan
+	}
+	if req.Remarks != nil {
+		transaction.Remarks = *req.Remarks
+	}
+
+	if err := s.transactionRepo.Update(ctx, transaction); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "new_property_transaction", transactionID, models.AuditActionUpdate, &before, transaction)
+	return transaction, nil
+}
+
+// attachLayoutSalesSummaries 按成交纪录计算各户型平均成交价及相对价单售价
+func attachLayoutSalesSummaries(layouts []models.NewPropertyLayout, transactions []models.NewPropertyTransaction) {
+	byLayout := make(map[uint][]models.NewPropertyTransaction)
+	for _, t := range transactions {
+		if t.LayoutID != nil {
+			byLayout[*t.LayoutID] = append(byLayout[*t.LayoutID], t)
+		}
+	}
+
+	for i := range layouts {
+		sales := byLayout[layouts[i].ID]
+		if len(sales) == 0 {
+			continue
+		}
+		var totalPrice, totalList, totalPsf float64
+		for _, t := range sales {
+			totalPrice += t.TransactionPrice
+			totalList += t.ListPrice
+			totalPsf += t.PricePerSqft
+		}
+		n := float64(len(sales))
+		summary := &models.LayoutSalesSummary{
+			TransactionCount:    len(sales),
+			AvgTransactionPrice: math.Round(totalPrice / n),
+			AvgListPrice:        math.Round(totalList / n),
+			AvgPricePerSqft:     math.Round(totalPsf / n),
+		}
+		if totalList > 0 {
+			summary.PriceRealised = round2(totalPrice / totalList * 100)
+		}
+		layouts[i].SalesSummary = summary
+	}
+}
+
+// salesProgressMaxMonths 销售进度最多返回的月数（取最近的月份）
+const salesProgressMaxMonths = 120
+
+// buildSalesProgress 自首份价单或首宗成交所在月份起，按月统计已公布单位、成交宗数及累计去化率
+// releaseDates 与 transactions 均按日期升序
+func buildSalesProgress(totalUnits int, releaseDates []time.Time, transactions []models.NewPropertyTransaction, now time.Time) *models.NewPropertySalesProgress {
+	progress := &models.NewPropertySalesProgress{
+		TotalUnits:    totalUnits,
+		ReleasedUnits: len(releaseDates),
+		UnitsSold:     len(transactions),
+		Timeline:      []models.SalesProgressPoint{},
+	}
+	if totalUnits > 0 {
+		progress.SellThroughRate = round2(float64(len(transactions)) / float64(totalUnits) * 100)
+	}
+
+	var launch time.Time
+	if len(releaseDates) > 0 {
+		launch = releaseDates[0]
+	}
+	if len(transactions) > 0 && (launch.IsZero() || transactions[0].TransactionDate.Before(launch)) {
+		launch = transactions[0].TransactionDate
+	}
+	if launch.IsZero() {
+		return progress
+	}
+	launch = launch.In(hongKongTime)
+	progress.LaunchDate = &launch
+
+	month := time.Date(launch.Year(), launch.Month(), 1, 0, 0, 0, 0, hongKongTime)
+	released, sold := 0, 0
+	for !month.After(now) {
+		next := month.AddDate(0, 1, 0)
+		for released < len(releaseDates) && releaseDates[released].Before(next) {
+			released++
+		}
+		inMonth := 0
+		for sold < len(transactions) && transactions[sold].TransactionDate.Before(next) {
+			sold++
+			inMonth++
+		}
+
+		point := models.SalesProgressPoint{
+			Month:          month.Format("2006-01"),
+			ReleasedUnits:  released,
+			UnitsSold:      inMonth,
+			CumulativeSold: sold,
+		}
+		if totalUnits > 0 {
+			point.SellThroughRate = round2(float64(sold) / float64(totalUnits) * 100)
+		}
+		if released > 0 {
+			point.ReleasedSellThroughRate = round2(float64(sold) / float64(released) * 100)
+		}
+		progress.Timeline = append(progress.Timeline, point)
+		month = next
+	}
+
+	if len(progress.Timeline) > salesProgressMaxMonths {
+		progress.Timeline = progress.Timeline[len(progress.Timeline)-salesProgressMaxMonths:]
+	}
+	return progress
+}
+
+// checkNewPropertyUnits 校验单位数一致性：已售 + 在售不超过总伙数，即将推出的新盘不应有已售单位，各户型可售单位合计不超过总伙数
+func checkNewPropertyUnits(newProperty *models.NewProperty, layouts []models.NewPropertyLayout) error {
+	if newProperty.UnitsSold+newProperty.UnitsForSale > newProperty.TotalUnits {
+		return tools.WrapError(400, fmt.Sprintf("units_sold (%d) + units_for_sale (%d) exceeds total_units (%d)", newProperty.UnitsSold, newProperty.UnitsForSale, newProperty.TotalUnits), tools.ErrInvalidInput)
+	}
+	if newProperty.Status == "upcoming" && newProperty.UnitsSold > 0 {
+		return tools.WrapError(400, "upcoming development cannot have units sold", tools.ErrInvalidInput)
+	}
+
+	available := 0
+	for _, layout := range layouts {
+		available += layout.AvailableUnits
+	}
+	if available > newProperty.TotalUnits {
+		return tools.WrapError(400, fmt.Sprintf("layout available_units total (%d) exceeds total_units (%d)", available, newProperty.TotalUnits), tools.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkNewPropertyLayout 校验户型售价及面积，并在未指定时按最低售价 / 实用面积计算呎价
+func checkNewPropertyLayout(layout *models.NewPropertyLayout) error {
+	if layout.MaxPrice > 0 && layout.MaxPrice < layout.MinPrice {
+		return tools.WrapError(400, "max_price must not be less than min_price", tools.ErrInvalidInput)
+	}
+	if layout.GrossArea > 0 && layout.GrossArea < layout.SaleableArea {
+		return tools.WrapError(400, "gross_area must not be less than saleable_area", tools.ErrInvalidInput)
+	}
+	if layout.PricePerSqft == 0 && layout.SaleableArea > 0 {
+		layout.PricePerSqft = math.Round(layout.MinPrice / layout.SaleableArea)
+	}
+	return nil
+}
+
+// parseNewPropertyDate 解析 YYYY-MM-DD 日期（空字符串返回 nil）
+func parseNewPropertyDate(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, tools.WrapError(400, fmt.Sprintf("invalid %s, expected YYYY-MM-DD", field), tools.ErrInvalidInput)
+	}
+	return &t, nil
 }
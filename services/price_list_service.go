@@ -0,0 +1,520 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// PriceListService Methods:
+// 0. NewPriceListService(repo *databases.PriceListRepo, newDevelopmentRepo *databases.NewDevelopmentRepo, auditService *AuditService) -> 注入依赖
+// 1. ImportPriceList(ctx context.Context, newPropertyID uint, req *models.ImportPriceListRequest, fileName string, data []byte) -> 导入 CSV/XLSX 价单并汇总单位数（管理员）
+// 2. ListPriceLists(ctx context.Context, newPropertyID uint) -> 价单列表
+// 3. GetPriceList(ctx context.Context, newPropertyID uint, priceListID uint) -> 价单详情（含单位行）
+// 4. ListUnits(ctx context.Context, newPropertyID uint, req *models.ListNewPropertyUnitsRequest) -> 单位列表及销售状态
+// 5. UpdateUnitStatus(ctx context.Context, newPropertyID uint, req *models.UpdateUnitStatusRequest) -> 批量更新单位销售状态并汇总（管理员）
+// 6. ListSalesArrangements(ctx context.Context, newPropertyID uint) -> 销售安排列表
+// 7. CreateSalesArrangement(ctx context.Context, newPropertyID uint, req *models.SalesArrangementRequest) -> 创建销售安排（管理员）
+// 8. UpdateSalesArrangement(ctx context.Context, newPropertyID uint, arrangementID uint, req *models.SalesArrangementRequest) -> 更新销售安排（管理员）
+// 9. DeleteSalesArrangement(ctx context.Context, newPropertyID uint, arrangementID uint) -> 删除销售安排（管理员）
+// 10. Template() -> 价单导入模板（CSV 表头及示例行）
+
+// priceListColumn 价单导入列定义
+type priceListColumn struct {
+	Name     string
+	Required bool
+	Example  string
+}
+
+// priceListColumns 价单文件列（status 不填时新单位为 available，已有单位保持原状态）
+var priceListColumns = []priceListColumn{
+	{Name: "block", Example: "1座"},
+	{Name: "floor", Required: true, Example: "23"},
+	{Name: "flat", Required: true, Example: "A"},
+	{Name: "unit_type", Example: "2房"},
+	{Name: "bedrooms", Example: "2"},
+	{Name: "saleable_area", Required: true, Example: "452"},
+	{Name: "list_price", Required: true, Example: "8,638,000"},
+	{Name: "price_per_sqft", Example: "19111"},
+	{Name: "status", Example: "available"},
+}
+
+// priceListMaxRowErrors 导入失败时最多返回的行错误数
+const priceListMaxRowErrors = 20
+
+// PriceListService 新盘价单及销售安排服务
+type PriceListService struct {
+	repo               *databases.PriceListRepo
+	newDevelopmentRepo *databases.NewDevelopmentRepo
+	auditService       *AuditService
+	maxSize            int64 // 单个文件最大字节数
+}
+
+// 0. NewPriceListService 构造函数
+func NewPriceListService(repo *databases.PriceListRepo, newDevelopmentRepo *databases.NewDevelopmentRepo, auditService *AuditService) *PriceListService {
+	return &PriceListService{
+		repo:               repo,
+		newDevelopmentRepo: newDevelopmentRepo,
+		auditService:       auditService,
+		maxSize:            int64(tools.GetEnvInt("IMPORT_MAX_SIZE_MB", 10)) << 20,
+	}
+}
+
+// MaxSize 单个价单文件最大字节数
+func (s *PriceListService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// priceListRow 价单文件中的一行
+type priceListRow struct {
+	block        string
+	floor        string
+	flat         string
+	unitType     string
+	bedrooms     *int
+	saleableArea float64
+	listPrice    float64
+	pricePerSqft float64
+	status       string
+}
+
+// 1. ImportPriceList 导入价单：整份文件校验通过才写入；按座数/楼层/单位新增或更新单位，随后汇总已售/在售单位数及户型可售单位数
+func (s *PriceListService) ImportPriceList(ctx context.Context, newPropertyID uint, req *models.ImportPriceListRequest, fileName string, data []byte) (*models.PriceListImportResponse, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, tools.ErrFileTooLarge
+	}
+	format := tools.SpreadsheetFormat(fileName)
+	if format == "" {
+		return nil, tools.ErrUnsupportedSpreadsheet
+	}
+	releaseDate, err := time.ParseInLocation("2006-01-02", req.ReleaseDate, hongKongTime)
+	if err != nil {
+		return nil, tools.WrapError(400, "invalid release_date, expected YYYY-MM-DD", tools.ErrInvalidInput)
+	}
+
+	newProperty, err := s.newDevelopmentRepo.FindByID(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.repo.ExistsPriceListNo(ctx, newPropertyID, req.PriceListNo)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, tools.WrapError(400, fmt.Sprintf("price list %s already exists", req.PriceListNo), tools.ErrInvalidInput)
+	}
+
+	rows, err := tools.ReadSpreadsheet(format, data)
+	if err != nil {
+		return nil, tools.WrapError(400, err.Error(), tools.ErrInvalidInput)
+	}
+	parsed, err := parsePriceListRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindAllUnits(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*models.NewPropertyUnit, len(existing))
+	for i := range existing {
+		byKey[unitKey(existing[i].Block, existing[i].Floor, existing[i].Flat)] = &existing[i]
+	}
+
+	units := make([]*models.NewPropertyUnit, 0, len(parsed))
+	items := make([]models.PriceListItem, 0, len(parsed))
+	created, updated := 0, 0
+	now := time.Now()
+	for _, row := range parsed {
+		unit, ok := byKey[unitKey(row.block, row.floor, row.flat)]
+		if ok {
+			updated++
+		} else {
+			created++
+			unit = &models.NewPropertyUnit{
+				NewPropertyID: newPropertyID,
+				Block:         row.block,
+				Floor:         row.floor,
+				Flat:          row.flat,
+				Status:        models.UnitStatusAvailable,
+			}
+		}
+
+		if row.unitType != "" {
+			unit.UnitType = row.unitType
+		}
+		if row.bedrooms != nil {
+			unit.Bedrooms = *row.bedrooms
+		}
+		unit.LayoutID = matchUnitLayout(newProperty.Layouts, unit.UnitType, unit.Bedrooms, row.bedrooms != nil || ok)
+		unit.SaleableArea = row.saleableArea
+		unit.ListPrice = row.listPrice
+		unit.PricePerSqft = row.pricePerSqft
+		if row.status != "" && row.status != unit.Status {
+			unit.Status = row.status
+			unit.SoldAt = nil
+			if row.status == models.UnitStatusSold {
+				unit.SoldAt = &now
+			}
+		}
+
+		units = append(units, unit)
+		items = append(items, models.PriceListItem{
+			Block:        row.block,
+			Floor:        row.floor,
+			Flat:         row.flat,
+			SaleableArea: row.saleableArea,
+			ListPrice:    row.listPrice,
+			PricePerSqft: row.pricePerSqft,
+		})
+	}
+
+	if total := len(existing) + created; total > newProperty.TotalUnits {
+		return nil, tools.WrapError(400, fmt.Sprintf("price lists would contain %d units, exceeding total_units (%d)", total, newProperty.TotalUnits), tools.ErrInvalidInput)
+	}
+
+	priceList := &models.PriceList{
+		NewPropertyID: newPropertyID,
+		PriceListNo:   req.PriceListNo,
+		ReleaseDate:   releaseDate,
+		Remarks:       req.Remarks,
+		FileName:      fileName,
+		UnitCount:     len(items),
+	}
+	sold, forSale, err := s.repo.ImportPriceList(ctx, priceList, units, items)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "price_list", priceList.ID, models.AuditActionCreate, nil, priceList)
+
+	return &models.PriceListImportResponse{
+		PriceList:    *priceList,
+		CreatedUnits: created,
+		UpdatedUnits: updated,
+		UnitsForSale: forSale,
+		UnitsSold:    sold,
+	}, nil
+}
+
+// 2. ListPriceLists 价单列表
+func (s *PriceListService) ListPriceLists(ctx context.Context, newPropertyID uint) ([]models.PriceList, error) {
+	if _, err := s.newDevelopmentRepo.FindByID(ctx, newPropertyID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindPriceLists(ctx, newPropertyID)
+}
+
+// 3. GetPriceList 价单详情
+func (s *PriceListService) GetPriceList(ctx context.Context, newPropertyID uint, priceListID uint) (*models.PriceList, error) {
+	return s.repo.FindPriceList(ctx, newPropertyID, priceListID)
+}
+
+// 4. ListUnits 单位列表
+func (s *PriceListService) ListUnits(ctx context.Context, newPropertyID uint, req *models.ListNewPropertyUnitsRequest) (*models.PaginatedNewPropertyUnitsResponse, error) {
+	if _, err := s.newDevelopmentRepo.FindByID(ctx, newPropertyID); err != nil {
+		return nil, err
+	}
+
+	units, total, err := s.repo.FindUnits(ctx, newPropertyID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedNewPropertyUnitsResponse{
+		Data:       units,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// 5. UpdateUnitStatus 批量更新单位销售状态（所有单位须属于该新盘），随后汇总单位数
+func (s *PriceListService) UpdateUnitStatus(ctx context.Context, newPropertyID uint, req *models.UpdateUnitStatusRequest) (*models.UnitStatusResponse, error) {
+	before, err := s.newDevelopmentRepo.FindByID(ctx, newPropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(req.UnitIDs))
+	unitIDs := make([]uint, 0, len(req.UnitIDs))
+	for _, id := range req.UnitIDs {
+		if !seen[id] {
+			seen[id] = true
+			unitIDs = append(unitIDs, id)
+		}
+	}
+	count, err := s.repo.CountUnitsByIDs(ctx, newPropertyID, unitIDs)
+	if err != nil {
+		return nil, err
+	}
+	if int(count) != len(unitIDs) {
+		return nil, tools.WrapError(400, "some units do not belong to this new property", tools.ErrInvalidInput)
+	}
+
+	updated, sold, forSale, err := s.repo.UpdateUnitStatus(ctx, newPropertyID, unitIDs, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	after := *before
+	after.UnitsSold, after.UnitsForSale = sold, forSale
+	s.auditService.Record(ctx, "new_property", newPropertyID, models.AuditActionUpdate, before, &after)
+
+	return &models.UnitStatusResponse{Updated: updated, UnitsForSale: forSale, UnitsSold: sold}, nil
+}
+
+// 6. ListSalesArrangements 销售安排列表
+func (s *PriceListService) ListSalesArrangements(ctx context.Context, newPropertyID uint) ([]models.SalesArrangement, error) {
+	if _, err := s.newDevelopmentRepo.FindByID(ctx, newPropertyID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindSalesArrangements(ctx, newPropertyID)
+}
+
+// 7. CreateSalesArrangement 创建销售安排
+func (s *PriceListService) CreateSalesArrangement(ctx context.Context, newPropertyID uint, req *models.SalesArrangementRequest) (*models.SalesArrangement, error) {
+	if _, err := s.newDevelopmentRepo.FindByID(ctx, newPropertyID); err != nil {
+		return nil, err
+	}
+
+	arrangement := &models.SalesArrangement{NewPropertyID: newPropertyID}
+	if err := s.applySalesArrangement(ctx, arrangement, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateSalesArrangement(ctx, arrangement); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "sales_arrangement", arrangement.ID, models.AuditActionCreate, nil, arrangement)
+	return arrangement, nil
+}
+
+// 8. UpdateSalesArrangement 更新销售安排（整体覆盖）
+func (s *PriceListService) UpdateSalesArrangement(ctx context.Context, newPropertyID uint, arrangementID uint, req *models.SalesArrangementRequest) (*models.SalesArrangement, error) {
+	arrangement, err := s.repo.FindSalesArrangement(ctx, newPropertyID, arrangementID)
+	if err != nil {
+		return nil, err
+	}
+	before := *arrangement
+
+	if err := s.applySalesArrangement(ctx, arrangement, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateSalesArrangement(ctx, arrangement); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "sales_arrangement", arrangementID, models.AuditActionUpdate, &before, arrangement)
+	return arrangement, nil
+}
+
+// 9. DeleteSalesArrangement 删除销售安排
+func (s *PriceListService) DeleteSalesArrangement(ctx context.Context, newPropertyID uint, arrangementID uint) error {
+	arrangement, err := s.repo.FindSalesArrangement(ctx, newPropertyID, arrangementID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteSalesArrangement(ctx, newPropertyID, arrangementID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "sales_arrangement", arrangementID, models.AuditActionDelete, arrangement, nil)
+	return nil
+}
+
+// 10. Template 价单导入模板：全部列名及一行示例（UTF-8 BOM，Excel 可直接打开）
+func (s *PriceListService) Template() ([]byte, error) {
+	header := make([]string, len(priceListColumns))
+	example := make([]string, len(priceListColumns))
+	for i, col := range priceListColumns {
+		header[i] = col.Name
+		example[i] = col.Example
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf")
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll([][]string{header, example}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// applySalesArrangement 校验并写入销售安排字段（关联价单须属于同一新盘）
+func (s *PriceListService) applySalesArrangement(ctx context.Context, arrangement *models.SalesArrangement, req *models.SalesArrangementRequest) error {
+	if req.SaleEndAt != nil && req.SaleEndAt.Before(req.SaleStartAt) {
+		return tools.WrapError(400, "sale_end_at must not be before sale_start_at", tools.ErrInvalidInput)
+	}
+	if req.PriceListID != nil {
+		if _, err := s.repo.FindPriceList(ctx, arrangement.NewPropertyID, *req.PriceListID); err != nil {
+			return err
+		}
+	}
+
+	arrangement.PriceListID = req.PriceListID
+	arrangement.SaleStartAt = req.SaleStartAt
+	arrangement.SaleEndAt = req.SaleEndAt
+	arrangement.Location = req.Location
+	arrangement.UnitsOffered = req.UnitsOffered
+	arrangement.UnitsDetail = req.UnitsDetail
+	arrangement.SellingMethod = req.SellingMethod
+	return nil
+}
+
+// ============ 价单解析 ============
+
+// parsePriceListRows 校验表头并解析全部数据行，有任何行错误时整体失败
+func parsePriceListRows(rows [][]string) ([]priceListRow, error) {
+	if len(rows) == 0 {
+		return nil, tools.WrapError(400, "file is empty", tools.ErrInvalidInput)
+	}
+
+	known := make(map[string]bool, len(priceListColumns))
+	for _, col := range priceListColumns {
+		known[col.Name] = true
+	}
+	index := make(map[string]int, len(rows[0]))
+	var unknown []string
+	for i, name := range rows[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		if _, dup := index[name]; dup {
+			return nil, tools.WrapError(400, "duplicate column: "+name, tools.ErrInvalidInput)
+		}
+		index[name] = i
+	}
+	if len(unknown) > 0 {
+		return nil, tools.WrapError(400, "unknown columns: "+strings.Join(unknown, ", "), tools.ErrInvalidInput)
+	}
+	var missing []string
+	for _, col := range priceListColumns {
+		if _, ok := index[col.Name]; col.Required && !ok {
+			missing = append(missing, col.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, tools.WrapError(400, "missing required columns: "+strings.Join(missing, ", "), tools.ErrInvalidInput)
+	}
+
+	var parsed []priceListRow
+	var rowErrors []string
+	seen := make(map[string]int)
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		rowNo := i + 2
+		cell := func(name string) string {
+			if j, ok := index[name]; ok && j < len(row) {
+				return strings.TrimSpace(row[j])
+			}
+			return ""
+		}
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, fmt.Sprintf("row %d %s: %s", rowNo, field, message))
+		}
+
+		r := priceListRow{
+			block:    cell("block"),
+			floor:    strings.ToUpper(cell("floor")),
+			flat:     strings.ToUpper(cell("flat")),
+			unitType: cell("unit_type"),
+			status:   strings.ToLower(cell("status")),
+		}
+		if r.floor == "" {
+			fail("floor", "is required")
+		}
+		if r.flat == "" {
+			fail("flat", "is required")
+		}
+		if err := parseImportFloat(cell("saleable_area"), &r.saleableArea); err != nil || r.saleableArea <= 0 {
+			fail("saleable_area", "must be a positive number")
+		}
+		if err := parseImportFloat(cell("list_price"), &r.listPrice); err != nil || r.listPrice <= 0 {
+			fail("list_price", "must be a positive number")
+		}
+		if v := cell("bedrooms"); v != "" {
+			var bedrooms int
+			if err := parseImportInt(v, &bedrooms); err != nil || bedrooms < 0 {
+				fail("bedrooms", "must be a non-negative integer")
+			} else {
+				r.bedrooms = &bedrooms
+			}
+		}
+		if v := cell("price_per_sqft"); v != "" {
+			if err := parseImportFloat(v, &r.pricePerSqft); err != nil || r.pricePerSqft <= 0 {
+				fail("price_per_sqft", "must be a positive number")
+			}
+		} else if r.saleableArea > 0 {
+			r.pricePerSqft = math.Round(r.listPrice / r.saleableArea)
+		}
+		switch r.status {
+		case "", models.UnitStatusAvailable, models.UnitStatusReserved, models.UnitStatusSold:
+		default:
+			fail("status", "must be one of: available reserved sold")
+		}
+
+		key := unitKey(r.block, r.floor, r.flat)
+		if first, dup := seen[key]; dup {
+			fail("flat", fmt.Sprintf("duplicates row %d", first))
+		} else {
+			seen[key] = rowNo
+		}
+		parsed = append(parsed, r)
+	}
+
+	if len(rowErrors) > 0 {
+		if len(rowErrors) > priceListMaxRowErrors {
+			rowErrors = append(rowErrors[:priceListMaxRowErrors], fmt.Sprintf("and %d more", len(rowErrors)-priceListMaxRowErrors))
+		}
+		return nil, tools.WrapError(400, "invalid price list: "+strings.Join(rowErrors, "; "), tools.ErrInvalidInput)
+	}
+	if len(parsed) == 0 {
+		return nil, tools.WrapError(400, "file has no data rows", tools.ErrInvalidInput)
+	}
+	return parsed, nil
+}
+
+// unitKey 单位唯一键（座数/楼层/单位，不区分大小写）
+func unitKey(block, floor, flat string) string {
+	return strings.ToUpper(block) + "|" + strings.ToUpper(floor) + "|" + strings.ToUpper(flat)
+}
+
+// matchUnitLayout 匹配单位所属户型：优先按户型名称，其次按房间数（该房间数只有一个户型时）
+func matchUnitLayout(layouts []models.NewPropertyLayout, unitType string, bedrooms int, hasBedrooms bool) *uint {
+	if unitType != "" {
+		for i := range layouts {
+			if strings.EqualFold(strings.TrimSpace(layouts[i].UnitType), unitType) {
+				return &layouts[i].ID
+			}
+		}
+	}
+	if !hasBedrooms {
+		return nil
+	}
+
+	var match *uint
+	for i := range layouts {
+		if layouts[i].Bedrooms == bedrooms {
+			if match != nil {
+				return nil
+			}
+			match = &layouts[i].ID
+		}
+	}
+	return match
+}
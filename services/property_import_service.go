@@ -0,0 +1,619 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// PropertyImportService Methods:
+// 0. NewPropertyImportService(importRepo, propertyRepo, propertyService, moderationService, auditService) -> 注入依赖
+// 1. StartImport(ctx context.Context, userID uint, userType, fileName string, data []byte, dryRun bool) -> 解析文件并创建异步导入任务
+// 2. GetImport(ctx context.Context, id uint, userID uint) -> 查询导入进度与结果
+// 3. ListImports(ctx context.Context, userID uint, req *models.ListPropertyImportsRequest) -> 导入任务列表
+// 4. Template() -> 导入模板（CSV 表头及示例行）
+// 5. ProcessPending(ctx context.Context) -> 执行排队中及中断的导入任务（定时任务）
+
+const (
+	importProgressInterval = 50               // 每处理多少行保存一次进度
+	importMaxRowErrors     = 500              // 最多保留的行错误数
+	importStaleAfter       = 10 * time.Minute // 处理中任务超过该时间无心跳视为中断
+)
+
+// importColumn 导入列定义（列名与 CreatePropertyRequest 的 JSON 字段一致）
+type importColumn struct {
+	Name     string
+	Required bool   // 表头必须包含
+	Example  string // 模板示例值
+	set      func(req *models.CreatePropertyRequest, value string) error
+}
+
+// propertyImportColumns 导入文件列定义（image_urls 多个地址用 | 分隔，图片只在新建时导入）
+var propertyImportColumns = []importColumn{
+	{Name: "estate_no", Required: true, Example: "AGY-0001", set: func(r *models.CreatePropertyRequest, v string) error { r.EstateNo = v; return nil }},
+	{Name: "listing_type", Required: true, Example: "sale", set: func(r *models.CreatePropertyRequest, v string) error { r.ListingType = v; return nil }},
+	{Name: "title", Required: true, Example: "太古城 3房 海景", set: func(r *models.CreatePropertyRequest, v string) error { r.Title = v; return nil }},
+	{Name: "description", Example: "", set: func(r *models.CreatePropertyRequest, v string) error { r.Description = v; return nil }},
+	{Name: "area", Required: true, Example: "850", set: func(r *models.CreatePropertyRequest, v string) error { return parseImportFloat(v, &r.Area) }},
+	{Name: "price", Required: true, Example: "12800000", set: func(r *models.CreatePropertyRequest, v string) error { return parseImportFloat(v, &r.Price) }},
+	{Name: "address", Required: true, Example: "太古城道 18 號", set: func(r *models.CreatePropertyRequest, v string) error { r.Address = v; return nil }},
+	{Name: "district_id", Required: true, Example: "1", set: func(r *models.CreatePropertyRequest, v string) error { return parseImportUint(v, &r.DistrictID) }},
+	{Name: "building_name", Example: "太古城", set: func(r *models.CreatePropertyRequest, v string) error { r.BuildingName = v; return nil }},
+	{Name: "floor", Example: "12/F", set: func(r *models.CreatePropertyRequest, v string) error { r.Floor = v; return nil }},
+	{Name: "orientation", Example: "南", set: func(r *models.CreatePropertyRequest, v string) error { r.Orientation = v; return nil }},
+	{Name: "bedrooms", Required: true, Example: "3", set: func(r *models.CreatePropertyRequest, v string) error { return parseImportInt(v, &r.Bedrooms) }},
+	{Name: "bathrooms", Example: "2", set: func(r *models.CreatePropertyRequest, v string) error { return parseImportInt(v, &r.Bathrooms) }},
+	{Name: "primary_school_net", Example: "14", set: func(r *models.CreatePropertyRequest, v string) error { r.PrimarySchool = v; return nil }},
+	{Name: "secondary_school_net", Example: "", set: func(r *models.CreatePropertyRequest, v string) error { r.SecondarySchool = v; return nil }},
+	{Name: "property_type", Required: true, Example: "apartment", set: func(r *models.CreatePropertyRequest, v string) error { r.PropertyType = v; return nil }},
+	{Name: "agent_id", Example: "", set: func(r *models.CreatePropertyRequest, v string) error {
+		var id uint
+		if err := parseImportUint(v, &id); err != nil {
+			return err
+		}
+		r.AgentID = &id
+		return nil
+	}},
+	{Name: "image_urls", Example: "https://example.com/1.jpg|https://example.com/2.jpg", set: func(r *models.CreatePropertyRequest, v string) error {
+		for _, u := range strings.Split(v, "|") {
+			if u = strings.TrimSpace(u); u != "" {
+				r.ImageURLs = append(r.ImageURLs, u)
+			}
+		}
+		return nil
+	}},
+	{Name: "save_as_draft", Example: "false", set: func(r *models.CreatePropertyRequest, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		r.SaveAsDraft = b
+		return nil
+	}},
+	{Name: "publish_at", Example: "", set: func(r *models.CreatePropertyRequest, v string) error {
+		t, err := parseImportTime(v)
+		if err != nil {
+			return err
+		}
+		r.PublishAt = &t
+		return nil
+	}},
+}
+
+// PropertyImportService 房产批量导入服务
+type PropertyImportService struct {
+	importRepo        *databases.PropertyImportRepo
+	propertyRepo      *databases.PropertyRepo
+	propertyService   *PropertyService
+	moderationService *ModerationService
+	auditService      *AuditService
+	maxRows           int   // 单个文件最大数据行数
+	maxSize           int64 // 单个文件最大字节数
+}
+
+// 0. NewPropertyImportService 构造函数
+func NewPropertyImportService(
+	importRepo *databases.PropertyImportRepo,
+	propertyRepo *databases.PropertyRepo,
+	propertyService *PropertyService,
+	moderationService *ModerationService,
+	auditService *AuditService,
+) *PropertyImportService {
+	return &PropertyImportService{
+		importRepo:        importRepo,
+		propertyRepo:      propertyRepo,
+		propertyService:   propertyService,
+		moderationService: moderationService,
+		auditService:      auditService,
+		maxRows:           tools.GetEnvInt("IMPORT_MAX_ROWS", 5000),
+		maxSize:           int64(tools.GetEnvInt("IMPORT_MAX_SIZE_MB", 10)) << 20,
+	}
+}
+
+// MaxSize 单个导入文件最大字节数
+func (s *PropertyImportService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// 1. StartImport 解析文件、校验表头并创建导入任务，随后在后台执行
+// 同一发布者重复上传尚未完成的相同文件时返回已有任务
+func (s *PropertyImportService) StartImport(ctx context.Context, userID uint, userType, fileName string, data []byte, dryRun bool) (*models.PropertyImportResponse, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, tools.ErrFileTooLarge
+	}
+	format := tools.SpreadsheetFormat(fileName)
+	if format == "" {
+		return nil, tools.ErrUnsupportedSpreadsheet
+	}
+
+	rows, err := tools.ReadSpreadsheet(format, data)
+	if err != nil {
+		return nil, tools.WrapError(400, err.Error(), tools.ErrInvalidInput)
+	}
+	header, dataRows, err := s.splitRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	fileHash := hex.EncodeToString(sum[:])
+	existing, err := s.importRepo.FindActiveByHash(ctx, userID, fileHash, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing.ToPropertyImportResponse(), nil
+	}
+
+	imp := &models.PropertyImport{
+		PublisherID:   userID,
+		PublisherType: userType,
+		FileName:      fileName,
+		FileHash:      fileHash,
+		Format:        format,
+		DryRun:        dryRun,
+		Status:        models.ImportStatusQueued,
+		TotalRows:     len(dataRows),
+		RowErrors:     []models.ImportRowError{},
+		RequestID:     tools.RequestIDFromContext(ctx),
+		Header:        header,
+		Rows:          dataRows,
+	}
+	if err := s.importRepo.Create(ctx, imp); err != nil {
+		return nil, err
+	}
+
+	// 后台执行（进程中断时由定时任务重新执行）
+	go func(id uint) {
+		if err := s.run(context.Background(), id); err != nil {
+			log.Printf("⚠️  Property import %d failed: %v", id, err)
+		}
+	}(imp.ID)
+
+	return imp.ToPropertyImportResponse(), nil
+}
+
+// 2. GetImport 查询导入进度与结果（只能查询自己的任务）
+func (s *PropertyImportService) GetImport(ctx context.Context, id uint, userID uint) (*models.PropertyImportResponse, error) {
+	imp, err := s.importRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tools.ErrNotFound
+		}
+		return nil, err
+	}
+	if imp.PublisherID != userID {
+		return nil, tools.ErrForbidden
+	}
+	return imp.ToPropertyImportResponse(), nil
+}
+
+// 3. ListImports 导入任务列表
+func (s *PropertyImportService) ListImports(ctx context.Context, userID uint, req *models.ListPropertyImportsRequest) (*models.PaginatedPropertyImportsResponse, error) {
+	imports, total, err := s.importRepo.FindByPublisher(ctx, userID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]models.PropertyImportResponse, len(imports))
+	for i := range imports {
+		data[i] = *imports[i].ToPropertyImportResponse()
+	}
+
+	return &models.PaginatedPropertyImportsResponse{
+		Data:       data,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// 4. Template 导入模板：全部列名及一行示例（UTF-8 BOM，Excel 可直接打开）
+func (s *PropertyImportService) Template() ([]byte, error) {
+	header := make([]string, len(propertyImportColumns))
+	example := make([]string, len(propertyImportColumns))
+	for i, col := range propertyImportColumns {
+		header[i] = col.Name
+		example[i] = col.Example
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf")
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll([][]string{header, example}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 5. ProcessPending 重新排队中断的任务并执行排队中的任务
+func (s *PropertyImportService) ProcessPending(ctx context.Context) error {
+	requeued, err := s.importRepo.RequeueStale(ctx, time.Now().Add(-importStaleAfter))
+	if err != nil {
+		return err
+	}
+	if requeued > 0 {
+		log.Printf("🔁 Property import: %d interrupted imports requeued", requeued)
+	}
+
+	ids, err := s.importRepo.FindQueuedIDs(ctx, 20)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.run(ctx, id); err != nil {
+			log.Printf("⚠️  Property import %d failed: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// splitRows 校验表头并拆分数据行（跳过空行）
+func (s *PropertyImportService) splitRows(rows [][]string) ([]string, [][]string, error) {
+	if len(rows) == 0 {
+		return nil, nil, tools.WrapError(400, "file is empty", tools.ErrInvalidInput)
+	}
+
+	known := make(map[string]bool, len(propertyImportColumns))
+	for _, col := range propertyImportColumns {
+		known[col.Name] = true
+	}
+	header := make([]string, len(rows[0]))
+	seen := make(map[string]bool, len(rows[0]))
+	var unknown []string
+	for i, name := range rows[0] {
+		name = strings.ToLower(name)
+		header[i] = name
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		if seen[name] {
+			return nil, nil, tools.WrapError(400, "duplicate column: "+name, tools.ErrInvalidInput)
+		}
+		seen[name] = true
+	}
+	if len(unknown) > 0 {
+		return nil, nil, tools.WrapError(400, "unknown columns: "+strings.Join(unknown, ", "), tools.ErrInvalidInput)
+	}
+	var missing []string
+	for _, col := range propertyImportColumns {
+		if col.Required && !seen[col.Name] {
+			missing = append(missing, col.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, tools.WrapError(400, "missing required columns: "+strings.Join(missing, ", "), tools.ErrInvalidInput)
+	}
+
+	dataRows := make([][]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		if !isBlankRow(row) {
+			dataRows = append(dataRows, row)
+		}
+	}
+	if len(dataRows) == 0 {
+		return nil, nil, tools.WrapError(400, "file has no data rows", tools.ErrInvalidInput)
+	}
+	if len(dataRows) > s.maxRows {
+		return nil, nil, tools.WrapError(400, fmt.Sprintf("too many rows, at most %d allowed", s.maxRows), tools.ErrInvalidInput)
+	}
+	return header, dataRows, nil
+}
+
+// ============ 执行导入 ============
+
+// run 执行导入任务：按 estate_no 新建或更新，逐行记录错误，定期保存进度
+// 任务已被其他执行者领取时直接返回；重新执行时从头开始（按外部编号更新，结果幂等）
+func (s *PropertyImportService) run(ctx context.Context, id uint) (runErr error) {
+	claimed, err := s.importRepo.Claim(ctx, id, time.Now())
+	if err != nil || !claimed {
+		return err
+	}
+	imp, err := s.importRepo.FindWithRows(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 以上传者身份记录审计日志
+	ctx = tools.WithRequestID(ctx, imp.RequestID)
+	ctx = tools.WithActor(ctx, tools.Actor{UserID: imp.PublisherID, UserType: imp.PublisherType})
+
+	imp.ProcessedRows, imp.CreatedCount, imp.UpdatedCount, imp.UnchangedCount, imp.FailedCount = 0, 0, 0, 0, 0
+	imp.RowErrors = []models.ImportRowError{}
+	imp.Error = ""
+
+	defer func() {
+		if r := recover(); r != nil {
+			runErr = fmt.Errorf("panic: %v", r)
+		}
+		now := time.Now()
+		imp.Status = models.ImportStatusCompleted
+		imp.FinishedAt = &now
+		if runErr != nil {
+			imp.Status = models.ImportStatusFailed
+			imp.Error = runErr.Error()
+		}
+		if err := s.importRepo.SaveProgress(context.Background(), imp); err != nil && runErr == nil {
+			runErr = err
+		}
+	}()
+
+	// 预先查询已存在的房产（同一编号有多条时取最早的一条）
+	requests := make([]*models.CreatePropertyRequest, len(imp.Rows))
+	rowErrors := make([][]models.ImportRowError, len(imp.Rows))
+	estateNos := make([]string, 0, len(imp.Rows))
+	for i, row := range imp.Rows {
+		requests[i], rowErrors[i] = parseImportRow(imp.Header, row, i+2)
+		if requests[i] != nil {
+			estateNos = append(estateNos, requests[i].EstateNo)
+		}
+	}
+	existing, err := s.propertyRepo.FindByPublisherEstateNos(ctx, imp.PublisherID, estateNos)
+	if err != nil {
+		return err
+	}
+	byEstateNo := make(map[string]*models.Property, len(existing))
+	for i := range existing {
+		if _, ok := byEstateNo[existing[i].EstateNo]; !ok {
+			byEstateNo[existing[i].EstateNo] = &existing[i]
+		}
+	}
+
+	firstRow := make(map[string]int, len(imp.Rows))
+	for i, req := range requests {
+		rowNo := i + 2
+		if req != nil {
+			if first, ok := firstRow[req.EstateNo]; ok {
+				rowErrors[i] = append(rowErrors[i], models.ImportRowError{
+					Row: rowNo, EstateNo: req.EstateNo, Field: "estate_no",
+					Message: fmt.Sprintf("duplicate estate_no, already used in row %d", first),
+				})
+			} else {
+				firstRow[req.EstateNo] = rowNo
+			}
+		}
+
+		if len(rowErrors[i]) == 0 {
+			if err := s.importRow(ctx, imp, req, byEstateNo); err != nil {
+				rowErrors[i] = append(rowErrors[i], models.ImportRowError{Row: rowNo, EstateNo: req.EstateNo, Message: err.Error()})
+			}
+		}
+		if len(rowErrors[i]) > 0 {
+			imp.FailedCount++
+			for _, e := range rowErrors[i] {
+				if len(imp.RowErrors) < importMaxRowErrors {
+					imp.RowErrors = append(imp.RowErrors, e)
+				}
+			}
+		}
+
+		imp.ProcessedRows++
+		if imp.ProcessedRows%importProgressInterval == 0 {
+			if err := s.importRepo.SaveProgress(ctx, imp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importRow 导入单行：不存在则创建（含图片与提交审核），存在则更新有变化的字段
+func (s *PropertyImportService) importRow(ctx context.Context, imp *models.PropertyImport, req *models.CreatePropertyRequest, byEstateNo map[string]*models.Property) error {
+	property, ok := byEstateNo[req.EstateNo]
+	if !ok {
+		if req.PublishAt != nil && !req.PublishAt.After(time.Now()) {
+			return errors.New("publish_at must be in the future")
+		}
+		if !imp.DryRun {
+			if _, err := s.propertyService.CreateProperty(ctx, imp.PublisherID, imp.PublisherType, req); err != nil {
+				return err
+			}
+		}
+		imp.CreatedCount++
+		return nil
+	}
+
+	before := *property
+	applyImportRow(property, req)
+	if len(diffFields(&before, property)) == 0 {
+		imp.UnchangedCount++
+		return nil
+	}
+	if !imp.DryRun {
+		if err := s.propertyRepo.Update(ctx, property); err != nil {
+			*property = before
+			return err
+		}
+		s.auditService.Record(ctx, "property", property.ID, models.AuditActionUpdate, &before, property)
+		if err := s.moderationService.RecheckProperty(ctx, property); err != nil {
+			return err
+		}
+	} else {
+		*property = before
+	}
+	imp.UpdatedCount++
+	return nil
+}
+
+// applyImportRow 将导入行写入已存在的房产（状态、发布时间和图片不变）
+func applyImportRow(p *models.Property, req *models.CreatePropertyRequest) {
+	p.ListingType = req.ListingType
+	p.Title = req.Title
+	p.Description = req.Description
+	p.Area = req.Area
+	p.Price = req.Price
+	p.Address = req.Address
+	p.DistrictID = req.DistrictID
+	p.BuildingName = req.BuildingName
+	p.Floor = req.Floor
+	p.Orientation = req.Orientation
+	p.Bedrooms = req.Bedrooms
+	p.Bathrooms = req.Bathrooms
+	p.PrimarySchool = req.PrimarySchool
+	p.SecondarySchool = req.SecondarySchool
+	p.PropertyType = req.PropertyType
+	p.AgentID = req.AgentID
+	p.Fingerprint = propertyFingerprint(p)
+}
+
+// ============ 行解析 ============
+
+// parseImportRow 将一行转换为创建请求并按 CreatePropertyRequest 的规则校验，有错误时返回 nil 请求
+func parseImportRow(header []string, row []string, rowNo int) (*models.CreatePropertyRequest, []models.ImportRowError) {
+	req := &models.CreatePropertyRequest{}
+	var rowErrors []models.ImportRowError
+	for i, name := range header {
+		if name == "" || i >= len(row) || row[i] == "" {
+			continue
+		}
+		col, ok := findImportColumn(name)
+		if !ok {
+			rowErrors = append(rowErrors, models.ImportRowError{Row: rowNo, Field: name, Message: "unknown column " + name})
+			continue
+		}
+		if err := col.set(req, row[i]); err != nil {
+			rowErrors = append(rowErrors, models.ImportRowError{Row: rowNo, Field: name, Message: err.Error()})
+		}
+	}
+
+	if req.EstateNo == "" {
+		rowErrors = append(rowErrors, models.ImportRowError{Row: rowNo, Field: "estate_no", Message: "estate_no is required"})
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			rowErrors = append(rowErrors, models.ImportRowError{Row: rowNo, Message: err.Error()})
+		}
+		for _, fe := range validationErrors {
+			field := importFieldName(fe.StructField())
+			rowErrors = append(rowErrors, models.ImportRowError{
+				Row:     rowNo,
+				Field:   field,
+				Message: importValidationMessage(field, fe),
+			})
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		for i := range rowErrors {
+			rowErrors[i].EstateNo = req.EstateNo
+		}
+		return nil, rowErrors
+	}
+	return req, nil
+}
+
+// findImportColumn 按列名查找列定义，未知列返回 false
+func findImportColumn(name string) (importColumn, bool) {
+	for _, col := range propertyImportColumns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return importColumn{}, false
+}
+
+// importFieldName 结构体字段名转换为列名（JSON 字段名）
+func importFieldName(structField string) string {
+	field, ok := reflect.TypeOf(models.CreatePropertyRequest{}).FieldByName(structField)
+	if !ok {
+		return structField
+	}
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+// importValidationMessage 校验错误提示
+func importValidationMessage(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without":
+		return field + " is required"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, fe.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "url":
+		return field + " must contain valid URLs"
+	}
+	return fmt.Sprintf("%s failed on '%s' validation", field, fe.Tag())
+}
+
+// isBlankRow 是否为空行
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImportFloat 解析数字（允许千位分隔符）
+func parseImportFloat(value string, dst *float64) error {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	if err != nil {
+		return errors.New("must be a number")
+	}
+	*dst = f
+	return nil
+}
+
+// parseImportInt 解析整数（XLSX 中的整数可能带 .0）
+func parseImportInt(value string, dst *int) error {
+	var f float64
+	if err := parseImportFloat(value, &f); err != nil || f != float64(int(f)) {
+		return errors.New("must be an integer")
+	}
+	*dst = int(f)
+	return nil
+}
+
+// parseImportUint 解析非负整数
+func parseImportUint(value string, dst *uint) error {
+	var n int
+	if err := parseImportInt(value, &n); err != nil || n < 0 {
+		return errors.New("must be a non-negative integer")
+	}
+	*dst = uint(n)
+	return nil
+}
+
+// importTimeLayouts 支持的时间格式（无时区的按服务器本地时区）
+var importTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// parseImportTime 解析时间，支持常见文本格式及 XLSX 日期序列号
+func parseImportTime(value string) (time.Time, error) {
+	for _, layout := range importTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		return tools.ExcelSerialTime(serial, time.Local), nil
+	}
+	return time.Time{}, errors.New("must be a date such as 2006-01-02 15:04")
+}
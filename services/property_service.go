@@ -3,21 +3,39 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
 )
 
 // PropertyService 房产服务
 type PropertyService struct {
-	propertyRepo *databases.PropertyRepo
+	propertyRepo       *databases.PropertyRepo
+	renewalPolicy      *RenewalPolicy
+	mediaService       *MediaService
+	moderationService  *ModerationService
+	auditService       *AuditService
+	mortgageService    *MortgageService
+	stampDutyService   *StampDutyService
+	estateBlockService *EstateBlockService
 }
 
 // NewPropertyService 创建房产服务
-func NewPropertyService(propertyRepo *databases.PropertyRepo) *PropertyService {
+func NewPropertyService(propertyRepo *databases.PropertyRepo, renewalPolicy *RenewalPolicy, mediaService *MediaService, moderationService *ModerationService, auditService *AuditService, mortgageService *MortgageService, stampDutyService *StampDutyService, estateBlockService *EstateBlockService) *PropertyService {
 	return &PropertyService{
-		propertyRepo: propertyRepo,
+		propertyRepo:       propertyRepo,
+		renewalPolicy:      renewalPolicy,
+		mediaService:       mediaService,
+		moderationService:  moderationService,
+		auditService:       auditService,
+		mortgageService:    mortgageService,
+		stampDutyService:   stampDutyService,
+		estateBlockService: estateBlockService,
 	}
 }
 
@@ -34,6 +52,25 @@ func (s *PropertyService) ListProperties(ctx context.Context, filter *models.Lis
 		data[i] = *p.ToPropertyResponse()
 	}
 
+	// 折叠重复时返回同一单位的其他刊登数
+	if filter.CollapseDuplicates {
+		fingerprints := make([]string, 0, len(properties))
+		for _, p := range properties {
+			if p.Fingerprint != "" {
+				fingerprints = append(fingerprints, p.Fingerprint)
+			}
+		}
+		counts, err := s.propertyRepo.CountByFingerprints(ctx, fingerprints)
+		if err != nil {
+			return nil, err
+		}
+		for i, p := range properties {
+			if count := counts[p.Fingerprint]; count > 1 {
+				data[i].DuplicateCount = int(count - 1)
+			}
+		}
+	}
+
 	return &models.PaginatedPropertiesResponse{
 		Data:       data,
 		Total:      total,
@@ -43,29 +80,54 @@ func (s *PropertyService) ListProperties(ctx context.Context, filter *models.Lis
 	}, nil
 }
 
-// GetProperty 获取房产详情
-func (s *PropertyService) GetProperty(ctx context.Context, id uint) (*models.PropertyDetailResponse, error) {
+// GetProperty 获取房产详情（未发布的房产仅发布者和管理员可见）
+func (s *PropertyService) GetProperty(ctx context.Context, id uint, viewerID uint, viewerType string) (*models.PropertyDetailResponse, error) {
 	property, err := s.propertyRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if isUnderModeration(property.Status) && property.PublisherID != viewerID && viewerType != "admin" {
+		return nil, errors.New("property not found")
+	}
 
 	// 增加浏览次数（异步执行，不影响主流程）
 	go s.propertyRepo.IncrementViewCount(context.Background(), id)
 
-	return property.ToPropertyDetailResponse(), nil
+	result := property.ToPropertyDetailResponse()
+	if property.ListingType == "sale" {
+		result.MortgageEstimate = s.mortgageService.Estimate(property.Price)
+		result.StampDutyEstimate = s.stampDutyService.Estimate(property.Price)
+	}
+	return result, nil
 }
 
 // CreateProperty 创建房产
 func (s *PropertyService) CreateProperty(ctx context.Context, userID uint, userType string, req *models.CreatePropertyRequest) (*models.PropertyDetailResponse, error) {
+	// 解析图片（已上传对象键在前，外部URL 在后）
+	sources, err := s.mediaService.ResolveImages(ctx, req.ImageKeys, req.ImageURLs)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) > 20 {
+		return nil, tools.WrapError(400, "too many images, at most 20 allowed", tools.ErrInvalidInput)
+	}
+
 	// 生成房产编号
 	propertyNo, err := s.propertyRepo.GeneratePropertyNo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// 定时发布：审核通过后到发布时间才上架，到期时间从发布时间起算
 	now := time.Now()
-	expiredAt := now.AddDate(0, 3, 0) // 默认3个月后过期
+	publishedAt := now
+	if req.PublishAt != nil {
+		if !req.PublishAt.After(now) {
+			return nil, tools.WrapError(400, "publish_at must be in the future", tools.ErrInvalidInput)
+		}
+		publishedAt = *req.PublishAt
+	}
+	expiredAt := publishedAt.AddDate(0, s.renewalPolicy.PropertyMonths, 0) // 默认3个月后过期
 
 	property := &models.Property{
 		PropertyNo:      propertyNo,
@@ -85,34 +147,43 @@ func (s *PropertyService) CreateProperty(ctx context.Context, userID uint, userT
 		PrimarySchool:   req.PrimarySchool,
 		SecondarySchool: req.SecondarySchool,
 		PropertyType:    req.PropertyType,
-		Status:          "available",
+		Status:          models.ListingStatusDraft, // 先保存为草稿，提交审核后再决定状态
 		PublisherID:     userID,
 		PublisherType:   userType,
 		AgentID:         req.AgentID,
 		ViewCount:       0,
 		FavoriteCount:   0,
-		PublishedAt:     &now,
+		PublishedAt:     &publishedAt,
 		ExpiredAt:       &expiredAt,
 	}
+	if req.UnitID != nil {
+		if err := s.applyEstateUnit(ctx, property, *req.UnitID); err != nil {
+			return nil, err
+		}
+	}
+	property.Fingerprint = propertyFingerprint(property)
 
 	// 创建房产
 	if err := s.propertyRepo.Create(ctx, property); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "property", property.ID, models.AuditActionCreate, nil, property)
 
 	// 创建图片
-	if len(req.ImageURLs) > 0 {
-		images := make([]models.PropertyImage, len(req.ImageURLs))
-		for i, url := range req.ImageURLs {
+	if len(sources) > 0 {
+		images := make([]models.PropertyImage, len(sources))
+		for i, src := range sources {
 			imageType := "interior"
 			if i == 0 {
 				imageType = "cover" // 第一张作为封面
 			}
 			images[i] = models.PropertyImage{
-				PropertyID: property.ID,
-				ImageURL:   url,
-				ImageType:  imageType,
-				SortOrder:  i,
+				PropertyID:   property.ID,
+				ImageURL:     src.ImageURL,
+				ObjectKey:    src.ObjectKey,
+				ThumbnailURL: src.ThumbnailURL,
+				ImageType:    imageType,
+				SortOrder:    i,
 			}
 		}
 		if err := s.propertyRepo.CreateImages(ctx, images); err != nil {
@@ -120,8 +191,83 @@ func (s *PropertyService) CreateProperty(ctx context.Context, userID uint, userT
 		}
 	}
 
+	// 提交审核（保存为草稿时跳过）
+	if !req.SaveAsDraft {
+		if err := s.moderationService.SubmitProperty(ctx, property); err != nil {
+			return nil, err
+		}
+	}
+
 	// 重新查询完整信息
-	return s.GetProperty(ctx, property.ID)
+	return s.GetProperty(ctx, property.ID, userID, userType)
+}
+
+// unitAreaTolerance 房源面积与单位目录面积（实用或建筑）允许的相对误差
+const unitAreaTolerance = 0.05
+
+// applyEstateUnit 按屋苑单位目录填写留空的面积、座向及楼层，已填写的须与单位一致
+// 房源的地区及大厦名称（已填写时）须与单位所属屋苑一致
+func (s *PropertyService) applyEstateUnit(ctx context.Context, property *models.Property, unitID uint) error {
+	unit, estate, err := s.estateBlockService.GetUnit(ctx, unitID)
+	if err != nil {
+		if err.Error() == "estate unit not found" {
+			return tools.WrapError(400, "estate unit not found", tools.ErrInvalidInput)
+		}
+		return err
+	}
+	if property.DistrictID != estate.DistrictID {
+		return tools.WrapError(400, fmt.Sprintf("district does not match the unit's estate (%s)", estate.Name), tools.ErrInvalidInput)
+	}
+	if property.BuildingName != "" && !matchUnitBuilding(property.BuildingName, estate, unit.Block) {
+		return tools.WrapError(400, fmt.Sprintf("building_name %s does not match the unit's estate (%s)", property.BuildingName, estate.Name), tools.ErrInvalidInput)
+	}
+	property.UnitID = &unit.ID
+
+	if property.Area == 0 {
+		property.Area = unit.SaleableArea
+		if property.Area == 0 {
+			property.Area = unit.GrossArea
+		}
+		if property.Area == 0 {
+			return tools.WrapError(400, "area is required, the unit has no area on record", tools.ErrInvalidInput)
+		}
+	} else if unit.SaleableArea > 0 || unit.GrossArea > 0 {
+		matchArea := func(area float64) bool {
+			return area > 0 && math.Abs(property.Area-area) <= area*unitAreaTolerance
+		}
+		if !matchArea(unit.SaleableArea) && !matchArea(unit.GrossArea) {
+			return tools.WrapError(400, fmt.Sprintf("area %.0f does not match the unit (saleable %.0f, gross %.0f sq ft)", property.Area, unit.SaleableArea, unit.GrossArea), tools.ErrInvalidInput)
+		}
+	}
+
+	if property.Orientation == "" {
+		property.Orientation = unit.Orientation
+	} else if unit.Orientation != "" && !strings.EqualFold(strings.TrimSpace(property.Orientation), unit.Orientation) {
+		return tools.WrapError(400, fmt.Sprintf("orientation %s does not match the unit (%s)", property.Orientation, unit.Orientation), tools.ErrInvalidInput)
+	}
+
+	if property.Floor == "" {
+		property.Floor = unit.Floor
+	} else if normalizeFloor(property.Floor) != normalizeFloor(unit.Floor) {
+		return tools.WrapError(400, fmt.Sprintf("floor %s does not match the unit (%s)", property.Floor, unit.Floor), tools.ErrInvalidInput)
+	}
+	return nil
+}
+
+// matchUnitBuilding 大厦名称是否指向单位所属屋苑或座数（规范化后互相包含即视为一致，如「太古城第1座」与「太古城」）
+func matchUnitBuilding(buildingName string, estate *models.Estate, block *models.EstateBlock) bool {
+	name := normalizeAddress(buildingName)
+	candidates := []string{estate.Name, estate.NameEn}
+	if block != nil {
+		candidates = append(candidates, block.Name, block.NameEn)
+	}
+	for _, c := range candidates {
+		c = normalizeAddress(c)
+		if c != "" && (strings.Contains(name, c) || strings.Contains(c, name)) {
+			return true
+		}
+	}
+	return false
 }
 
 // UpdateProperty 更新房产
@@ -136,6 +282,7 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, id uint, userID ui
 	if property.PublisherID != userID {
 		return nil, errors.New("permission denied")
 	}
+	before := *property
 
 	// 更新字段
 	if req.Title != nil {
@@ -160,19 +307,41 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, id uint, userID ui
 		property.Bathrooms = *req.Bathrooms
 	}
 	if req.Status != nil {
+		// 草稿、待审核、被拒绝的房产须通过审核发布，不能直接修改状态
+		if isUnderModeration(property.Status) {
+			return nil, tools.WrapError(400, "status cannot be changed before the property is published", tools.ErrInvalidInput)
+		}
+		if isLeavingExpired(property.Status, *req.Status) {
+			return nil, tools.WrapError(400, "expired property must be renewed via POST /api/v1/properties/:id/renew", tools.ErrInvalidInput)
+		}
 		property.Status = *req.Status
 	}
 	if req.AgentID != nil {
 		property.AgentID = req.AgentID
 	}
+	// 已关联屋苑单位的房源修改面积、楼层或座向时，须与单位目录保持一致
+	if property.UnitID != nil && (req.Area != nil || req.Floor != nil || req.Orientation != nil) {
+		if err := s.applyEstateUnit(ctx, property, *property.UnitID); err != nil {
+			return nil, err
+		}
+	}
+	property.Fingerprint = propertyFingerprint(property)
 
 	// 保存更新
 	if err := s.propertyRepo.Update(ctx, property); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "property", id, models.AuditActionUpdate, &before, property)
+
+	// 内容修改后重新检查（命中检查的已发布房产将重新进入审核）
+	if req.Title != nil || req.Description != nil || req.Price != nil || req.Area != nil || req.Floor != nil {
+		if err := s.moderationService.RecheckProperty(ctx, property); err != nil {
+			return nil, err
+		}
+	}
 
 	// 重新查询完整信息
-	return s.GetProperty(ctx, id)
+	return s.GetProperty(ctx, id, userID, "")
 }
 
 // DeleteProperty 删除房产
@@ -188,7 +357,156 @@ func (s *PropertyService) DeleteProperty(ctx context.Context, id uint, userID ui
 		return errors.New("permission denied")
 	}
 
-	return s.propertyRepo.Delete(ctx, id)
+	if err := s.propertyRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "property", id, models.AuditActionDelete, property, nil)
+	return nil
+}
+
+// SubmitProperty 提交房产审核（草稿或被拒绝的房产）
+func (s *PropertyService) SubmitProperty(ctx context.Context, id uint, userID uint) (*models.PropertyDetailResponse, error) {
+	// 查找房产
+	property, err := s.propertyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 权限检查：只能提交自己发布的房产
+	if property.PublisherID != userID {
+		return nil, errors.New("permission denied")
+	}
+
+	if property.Status != models.ListingStatusDraft && property.Status != models.ListingStatusRejected {
+		return nil, tools.ErrNotSubmittable
+	}
+
+	if err := s.moderationService.SubmitProperty(ctx, property); err != nil {
+		return nil, err
+	}
+
+	return property.ToPropertyDetailResponse(), nil
+}
+
+// PublishProperty 发布房产：publish_at 为空时立即发布，否则在指定时间定时发布
+// 草稿或被拒绝的房产提交审核，审核通过后按发布时间上架；待审核的房产更新计划发布时间；
+// 已定时的房产可改期或立即上架（已通过审核，无需重新审核）
+func (s *PropertyService) PublishProperty(ctx context.Context, id uint, userID uint, req *models.PublishPropertyRequest) (*models.PropertyDetailResponse, error) {
+	// 查找房产
+	property, err := s.propertyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 权限检查：只能发布自己的房产
+	if property.PublisherID != userID {
+		return nil, errors.New("permission denied")
+	}
+
+	now := time.Now()
+	publishAt := now
+	if req.PublishAt != nil {
+		if !req.PublishAt.After(now) {
+			return nil, tools.WrapError(400, "publish_at must be in the future", tools.ErrInvalidInput)
+		}
+		publishAt = *req.PublishAt
+	}
+	before := *property
+	action := models.AuditActionUpdate
+
+	switch property.Status {
+	case models.ListingStatusDraft, models.ListingStatusRejected:
+		property.PublishedAt = &publishAt
+		if err := s.moderationService.SubmitProperty(ctx, property); err != nil {
+			return nil, err
+		}
+		return property.ToPropertyDetailResponse(), nil
+	case models.ListingStatusPendingReview:
+		property.PublishedAt = &publishAt
+	case models.ListingStatusScheduled:
+		expiredAt := publishAt.AddDate(0, s.renewalPolicy.PropertyMonths, 0)
+		property.PublishedAt = &publishAt
+		property.ExpiredAt = &expiredAt
+		if req.PublishAt == nil {
+			property.Status = models.ListingStatusPublished
+			action = models.AuditActionPublish
+		}
+	default:
+		return nil, tools.ErrNotPublishable
+	}
+
+	if err := s.propertyRepo.Update(ctx, property); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "property", id, action, &before, property)
+
+	return property.ToPropertyDetailResponse(), nil
+}
+
+// CancelScheduledPublish 取消定时发布：房产退回草稿，重新发布需再次提交审核
+func (s *PropertyService) CancelScheduledPublish(ctx context.Context, id uint, userID uint) (*models.PropertyDetailResponse, error) {
+	// 查找房产
+	property, err := s.propertyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 权限检查：只能取消自己的房产
+	if property.PublisherID != userID {
+		return nil, errors.New("permission denied")
+	}
+
+	if property.Status != models.ListingStatusScheduled {
+		return nil, tools.ErrNotScheduled
+	}
+	before := *property
+
+	property.Status = models.ListingStatusDraft
+	property.PublishedAt = nil
+	if err := s.propertyRepo.Update(ctx, property); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "property", id, models.AuditActionUpdate, &before, property)
+
+	return property.ToPropertyDetailResponse(), nil
+}
+
+// RenewProperty 续期房产
+func (s *PropertyService) RenewProperty(ctx context.Context, id uint, userID uint) (*models.PropertyDetailResponse, error) {
+	// 查找房产
+	property, err := s.propertyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 权限检查：只能续期自己发布的房产
+	if property.PublisherID != userID {
+		return nil, errors.New("permission denied")
+	}
+
+	// 只有在架或已过期的房产可以续期
+	if property.Status != "available" && property.Status != "expired" {
+		return nil, tools.ErrNotRenewable
+	}
+
+	// 检查续期次数上限（按发布者类型）
+	if !s.renewalPolicy.CanRenew(property.PublisherType, property.RenewalCount) {
+		return nil, tools.ErrRenewalLimitReached
+	}
+	before := *property
+
+	expiredAt := renewFrom(property.ExpiredAt, time.Now()).AddDate(0, s.renewalPolicy.PropertyMonths, 0)
+	property.ExpiredAt = &expiredAt
+	property.Status = "available"
+	property.RenewalCount++
+	property.ReminderSentAt = nil
+
+	if err := s.propertyRepo.Update(ctx, property); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "property", id, models.AuditActionRenew, &before, property)
+
+	return property.ToPropertyDetailResponse(), nil
 }
 
 // GetSimilarProperties 获取相似房源
@@ -245,3 +563,177 @@ func (s *PropertyService) GetHotProperties(ctx context.Context, limit int) ([]mo
 
 	return data, nil
 }
+
+// isUnderModeration 是否处于未公开的状态（草稿、待审核、被拒绝、等待定时发布）
+func isUnderModeration(status string) bool {
+	return status == models.ListingStatusDraft ||
+		status == models.ListingStatusPendingReview ||
+		status == models.ListingStatusRejected ||
+		status == models.ListingStatusScheduled
+}
+
+// isLeavingExpired 是否将已过期的刊登改为其他状态（过期刊登只能通过续期接口恢复，受续期次数限制）
+func isLeavingExpired(from, to string) bool {
+	return from == "expired" && to != from
+}
+
+// ============ 图片管理 ============
+
+// propertyImageTypes 可手动设置的房产图片类型（封面通过 SetPropertyCoverImage 设置）
+var propertyImageTypes = []string{"interior", "exterior", "floorplan"}
+
+// findManagedProperty 查询房产并校验管理权限（发布者或管理员）
+func (s *PropertyService) findManagedProperty(ctx context.Context, id uint, userID uint, userType string) (*models.Property, error) {
+	property, err := s.propertyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if property.PublisherID != userID && userType != "admin" {
+		return nil, errors.New("permission denied")
+	}
+	return property, nil
+}
+
+// GetPropertyImages 获取房产图片
+func (s *PropertyService) GetPropertyImages(ctx context.Context, id uint) ([]models.PropertyImage, error) {
+	if _, err := s.propertyRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.propertyRepo.FindImagesByPropertyID(ctx, id)
+}
+
+// AddPropertyImage 添加房产图片（追加到末尾，第一张自动作为封面）
+func (s *PropertyService) AddPropertyImage(ctx context.Context, id uint, userID uint, userType string, req *models.AddImageRequest) (*models.PropertyImage, error) {
+	property, err := s.findManagedProperty(ctx, id, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImageCount(len(property.Images)); err != nil {
+		return nil, err
+	}
+
+	imageType := req.ImageType
+	if imageType == "" {
+		imageType = "interior"
+	}
+	if err := checkImageType(imageType, propertyImageTypes...); err != nil {
+		return nil, err
+	}
+	if len(property.Images) == 0 {
+		imageType = "cover"
+	}
+
+	source, err := s.mediaService.ResolveImage(ctx, req.ImageKey, req.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.PropertyImage{
+		PropertyID:   id,
+		ImageURL:     source.ImageURL,
+		ObjectKey:    source.ObjectKey,
+		ThumbnailURL: source.ThumbnailURL,
+		ImageType:    imageType,
+		SortOrder:    len(property.Images),
+	}
+	if err := s.propertyRepo.CreateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "property_image", image.ID, models.AuditActionCreate, nil, image)
+	return image, nil
+}
+
+// UpdatePropertyImage 更新房产图片类型
+func (s *PropertyService) UpdatePropertyImage(ctx context.Context, id uint, imageID uint, userID uint, userType string, req *models.UpdateImageRequest) (*models.PropertyImage, error) {
+	if _, err := s.findManagedProperty(ctx, id, userID, userType); err != nil {
+		return nil, err
+	}
+	image, err := s.propertyRepo.FindImage(ctx, id, imageID)
+	if err != nil {
+		return nil, err
+	}
+	before := *image
+
+	if req.ImageType != nil {
+		if image.ImageType == "cover" {
+			return nil, tools.WrapError(400, "cannot change the type of the cover image, set another cover first", tools.ErrInvalidInput)
+		}
+		if err := checkImageType(*req.ImageType, propertyImageTypes...); err != nil {
+			return nil, err
+		}
+		image.ImageType = *req.ImageType
+	}
+
+	if err := s.propertyRepo.UpdateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "property_image", imageID, models.AuditActionUpdate, &before, image)
+	return image, nil
+}
+
+// DeletePropertyImage 删除房产图片
+func (s *PropertyService) DeletePropertyImage(ctx context.Context, id uint, imageID uint, userID uint, userType string) error {
+	if _, err := s.findManagedProperty(ctx, id, userID, userType); err != nil {
+		return err
+	}
+	image, err := s.propertyRepo.FindImage(ctx, id, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.propertyRepo.DeleteImage(ctx, id, imageID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "property_image", imageID, models.AuditActionDelete, image, nil)
+	return nil
+}
+
+// ReorderPropertyImages 批量调整房产图片顺序
+func (s *PropertyService) ReorderPropertyImages(ctx context.Context, id uint, userID uint, userType string, req *models.ReorderImagesRequest) ([]models.PropertyImage, error) {
+	property, err := s.findManagedProperty(ctx, id, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(property.Images))
+	for i, img := range property.Images {
+		currentIDs[i] = img.ID
+	}
+	if err := checkImageOrder(currentIDs, req.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.propertyRepo.ReorderImages(ctx, id, req.ImageIDs); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, id, property.Images)
+}
+
+// SetPropertyCoverImage 设置房产封面图（原封面改为室内图，新封面移到首位）
+func (s *PropertyService) SetPropertyCoverImage(ctx context.Context, id uint, imageID uint, userID uint, userType string) ([]models.PropertyImage, error) {
+	property, err := s.findManagedProperty(ctx, id, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.propertyRepo.FindImage(ctx, id, imageID); err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(property.Images))
+	for i, img := range property.Images {
+		currentIDs[i] = img.ID
+	}
+	if err := s.propertyRepo.SetCoverImage(ctx, id, imageID, coverFirstOrder(currentIDs, imageID)); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, id, property.Images)
+}
+
+// imagesAfterChange 重新查询房产图片，并逐张记录排序及类型变更
+func (s *PropertyService) imagesAfterChange(ctx context.Context, id uint, before []models.PropertyImage) ([]models.PropertyImage, error) {
+	images, err := s.propertyRepo.FindImagesByPropertyID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.RecordEach(ctx, "property_image", models.AuditActionUpdate, before, images)
+	return images, nil
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// ServicedApartmentBookingService Methods:
+// 0. NewServicedApartmentBookingService(repo, apartmentRepo, notificationRepo, auditService, holdHours) -> 注入依赖
+// 1. GetAvailability(ctx context.Context, apartmentID uint, req *models.GetAvailabilityRequest) -> 房型房态日历
+// 2. CreateBooking(ctx context.Context, apartmentID uint, userID uint, req *models.CreateBookingRequest) -> 提交预订申请（占用房量直至处理或保留到期）
+// 3. ListBookings(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.ListBookingsRequest) -> 住宅预订列表（所属公司或管理员）
+// 4. ListMyBookings(ctx context.Context, userID uint, req *models.ListBookingsRequest) -> 我的预订
+// 5. ConfirmBooking(ctx context.Context, apartmentID uint, bookingID uint, userID uint, userType string, req *models.RespondBookingRequest) -> 确认预订（所属公司或管理员）
+// 6. DeclineBooking(ctx context.Context, apartmentID uint, bookingID uint, userID uint, userType string, req *models.RespondBookingRequest) -> 拒绝预订并释放房量（所属公司或管理员）
+// 7. CancelBooking(ctx context.Context, apartmentID uint, bookingID uint, userID uint, userType string, req *models.RespondBookingRequest) -> 取消预订并释放房量（申请人、所属公司或管理员）
+// 8. ExpireHolds(ctx context.Context) -> 释放保留到期仍未确认的预订（定时任务）
+
+const (
+	availabilityDefaultDays = 30  // 房态日历默认天数
+	availabilityMaxDays     = 92  // 房态日历最多天数
+	bookingMaxNights        = 365 // 单次预订最多晚数
+)
+
+// ServicedApartmentBookingService 服务式住宅预订服务
+type ServicedApartmentBookingService struct {
+	repo             *databases.ServicedApartmentBookingRepo
+	apartmentRepo    *databases.ServicedApartmentRepo
+	notificationRepo *databases.NotificationRepo
+	auditService     *AuditService
+	holdDuration     time.Duration
+}
+
+// 0. NewServicedApartmentBookingService 构造函数（holdHours 为待确认预订占用房量的时长）
+func NewServicedApartmentBookingService(
+	repo *databases.ServicedApartmentBookingRepo,
+	apartmentRepo *databases.ServicedApartmentRepo,
+	notificationRepo *databases.NotificationRepo,
+	auditService *AuditService,
+	holdHours int,
+) *ServicedApartmentBookingService {
+	return &ServicedApartmentBookingService{
+		repo:             repo,
+		apartmentRepo:    apartmentRepo,
+		notificationRepo: notificationRepo,
+		auditService:     auditService,
+		holdDuration:     time.Duration(holdHours) * time.Hour,
+	}
+}
+
+// 1. GetAvailability 房型房态日历（可预订 = 房型单位数 - 待确认及已确认预订占用）
+func (s *ServicedApartmentBookingService) GetAvailability(ctx context.Context, apartmentID uint, req *models.GetAvailabilityRequest) (*models.AvailabilityCalendarResponse, error) {
+	apartment, err := s.apartmentRepo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := bookingToday()
+	if req.StartDate != "" {
+		start, _ = time.Parse("2006-01-02", req.StartDate)
+	}
+	end := start.AddDate(0, 0, availabilityDefaultDays)
+	if req.EndDate != "" {
+		end, _ = time.Parse("2006-01-02", req.EndDate)
+	}
+	if !end.After(start) {
+		return nil, tools.WrapError(400, "end_date must be after start_date", tools.ErrInvalidInput)
+	}
+	if end.After(start.AddDate(0, 0, availabilityMaxDays)) {
+		return nil, tools.WrapError(400, fmt.Sprintf("date range must not exceed %d days", availabilityMaxDays), tools.ErrInvalidInput)
+	}
+
+	units := apartment.Units
+	if req.UnitID != nil {
+		units = nil
+		for _, unit := range apartment.Units {
+			if unit.ID == *req.UnitID {
+				units = append(units, unit)
+			}
+		}
+		if len(units) == 0 {
+			return nil, errors.New("unit not found")
+		}
+	}
+
+	unitIDs := make([]uint, len(units))
+	for i, unit := range units {
+		unitIDs[i] = unit.ID
+	}
+	inventory, err := s.repo.FindInventory(ctx, unitIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+	booked := make(map[uint]map[string]int, len(units))
+	for _, row := range inventory {
+		if booked[row.UnitID] == nil {
+			booked[row.UnitID] = make(map[string]int)
+		}
+		booked[row.UnitID][row.Date.Format("2006-01-02")] = row.Booked
+	}
+
+	resp := &models.AvailabilityCalendarResponse{
+		ServicedApartmentID: apartment.ID,
+		StartDate:           start.Format("2006-01-02"),
+		EndDate:             end.Format("2006-01-02"),
+		MinStayDays:         apartment.MinStayDays,
+		Units:               make([]models.UnitAvailability, 0, len(units)),
+	}
+	for _, unit := range units {
+		calendar := models.UnitAvailability{
+			UnitID:       unit.ID,
+			UnitType:     unit.UnitType,
+			TotalUnits:   unit.AvailableUnits,
+			MinAvailable: unit.AvailableUnits,
+		}
+		for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+			date := d.Format("2006-01-02")
+			day := models.AvailabilityDay{Date: date, Booked: booked[unit.ID][date]}
+			day.Available = unit.AvailableUnits - day.Booked
+			if day.Available < 0 {
+				day.Available = 0
+			}
+			if day.Available < calendar.MinAvailable {
+				calendar.MinAvailable = day.Available
+			}
+			calendar.Days = append(calendar.Days, day)
+		}
+		resp.Units = append(resp.Units, calendar)
+	}
+
+	return resp, nil
+}
+
+// 2. CreateBooking 提交预订申请：校验最少入住天数及入住人数，占用入住期间每晚一个单位
+func (s *ServicedApartmentBookingService) CreateBooking(ctx context.Context, apartmentID uint, userID uint, req *models.CreateBookingRequest) (*models.ServicedApartmentBooking, error) {
+	apartment, err := s.apartmentRepo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if apartment.Status != "active" {
+		return nil, tools.WrapError(400, "serviced apartment is not accepting bookings", tools.ErrInvalidInput)
+	}
+	unit, err := s.apartmentRepo.FindUnit(ctx, apartmentID, req.UnitID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkIn, _ := time.Parse("2006-01-02", req.CheckInDate)
+	checkOut, _ := time.Parse("2006-01-02", req.CheckOutDate)
+	if checkIn.Before(bookingToday()) {
+		return nil, tools.WrapError(400, "check_in_date must not be in the past", tools.ErrInvalidInput)
+	}
+	if !checkOut.After(checkIn) {
+		return nil, tools.WrapError(400, "check_out_date must be after check_in_date", tools.ErrInvalidInput)
+	}
+	nights := int(checkOut.Sub(checkIn).Hours() / 24)
+	if apartment.MinStayDays > 0 && nights < apartment.MinStayDays {
+		return nil, tools.WrapError(400, fmt.Sprintf("minimum stay is %d nights", apartment.MinStayDays), tools.ErrInvalidInput)
+	}
+	if nights > bookingMaxNights {
+		return nil, tools.WrapError(400, fmt.Sprintf("stay must not exceed %d nights", bookingMaxNights), tools.ErrInvalidInput)
+	}
+	if unit.MaxOccupancy > 0 && req.Guests > unit.MaxOccupancy {
+		return nil, tools.WrapError(400, fmt.Sprintf("unit allows at most %d guests", unit.MaxOccupancy), tools.ErrInvalidInput)
+	}
+
+	holdExpiresAt := time.Now().Add(s.holdDuration)
+	booking := &models.ServicedApartmentBooking{
+		ServicedApartmentID: apartmentID,
+		UnitID:              unit.ID,
+		UserID:              userID,
+		CheckInDate:         checkIn,
+		CheckOutDate:        checkOut,
+		Nights:              nights,
+		Guests:              req.Guests,
+		ContactName:         req.ContactName,
+		ContactPhone:        req.ContactPhone,
+		ContactEmail:        req.ContactEmail,
+		Message:             req.Message,
+		Status:              models.BookingStatusPending,
+		HoldExpiresAt:       &holdExpiresAt,
+	}
+	if err := s.repo.CreateWithHold(ctx, booking, unit.AvailableUnits); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_booking", booking.ID, models.AuditActionCreate, nil, booking)
+	booking.Unit = unit
+
+	s.notify(ctx, apartment.CompanyID, "booking_requested", booking.ID,
+		"新预订申请",
+		fmt.Sprintf("「%s」%s 收到 %s 至 %s 的预订申请，请于 %s 前处理。", apartment.Name, unit.UnitType,
+			req.CheckInDate, req.CheckOutDate, holdExpiresAt.In(hongKongTime).Format("2006-01-02 15:04")))
+
+	return booking, nil
+}
+
+// 3. ListBookings 住宅预订列表（所属公司或管理员）
+func (s *ServicedApartmentBookingService) ListBookings(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.ListBookingsRequest) (*models.PaginatedBookingsResponse, error) {
+	if _, err := s.findManagedApartment(ctx, apartmentID, userID, userType); err != nil {
+		return nil, err
+	}
+
+	bookings, total, err := s.repo.FindAll(ctx, apartmentID, req)
+	if err != nil {
+		return nil, err
+	}
+	return paginatedBookings(bookings, total, req), nil
+}
+
+// 4. ListMyBookings 我的预订
+func (s *ServicedApartmentBookingService) ListMyBookings(ctx context.Context, userID uint, req *models.ListBookingsRequest) (*models.PaginatedBookingsResponse, error) {
+	bookings, total, err := s.repo.FindByUser(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	return paginatedBookings(bookings, total, req), nil
+}
+
+// 5. ConfirmBooking 确认待处理预订（保留到期后不可再确认）
+func (s *ServicedApartmentBookingService) ConfirmBooking(ctx context.Context, apartmentID uint, bookingID uint, userID uint, userType string, req *models.RespondBookingRequest) (*models.ServicedApartmentBooking, error) {
+	apartment, err := s.findManagedApartment(ctx, apartmentID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.repo.FindByID(ctx, apartmentID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != models.BookingStatusPending {
+		return nil, tools.WrapError(400, "only pending bookings can be confirmed", tools.ErrInvalidInput)
+	}
+	now := time.Now()
+	if booking.HoldExpiresAt != nil && !booking.HoldExpiresAt.After(now) {
+		return nil, tools.WrapError(400, "booking hold has expired", tools.ErrInvalidInput)
+	}
+	before := *booking
+
+	booking.Status = models.BookingStatusConfirmed
+	booking.HoldExpiresAt = nil
+	booking.OperatorNote = req.Note
+	booking.RespondedAt = &now
+	if err := s.repo.Transition(ctx, booking, models.BookingStatusPending, false); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_booking", booking.ID, models.AuditActionUpdate, &before, booking)
+
+	s.notify(ctx, booking.UserID, "booking_confirmed", booking.ID,
+		"预订已确认",
+		fmt.Sprintf("您于「%s」%s 至 %s 的预订已获确认。", apartment.Name,
+			booking.CheckInDate.Format("2006-01-02"), booking.CheckOutDate.Format("2006-01-02")))
+	return booking, nil
+}
+
+// 6. DeclineBooking 拒绝待处理预订并释放房量
+func (s *ServicedApartmentBookingService) DeclineBooking(ctx context.Context, apartmentID uint, bookingID uint, userID uint, userType string, req *models.RespondBookingRequest) (*models.ServicedApartmentBooking, error) {
+	apartment, err := s.findManagedApartment(ctx, apartmentID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.repo.FindByID(ctx, apartmentID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != models.BookingStatusPending {
+		return nil, tools.WrapError(400, "only pending bookings can be declined", tools.ErrInvalidInput)
+	}
+
+	before := *booking
+	now := time.Now()
+	booking.Status = models.BookingStatusDeclined
+	booking.HoldExpiresAt = nil
+	booking.OperatorNote = req.Note
+	booking.RespondedAt = &now
+	if err := s.repo.Transition(ctx, booking, models.BookingStatusPending, true); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_booking", booking.ID, models.AuditActionUpdate, &before, booking)
+
+	s.notify(ctx, booking.UserID, "booking_declined", booking.ID,
+		"预订未获接纳",
+		fmt.Sprintf("很抱歉，您于「%s」%s 至 %s 的预订未获接纳。%s", apartment.Name,
+			booking.CheckInDate.Format("2006-01-02"), booking.CheckOutDate.Format("2006-01-02"), req.Note))
+	return booking, nil
+}
+
+// 7. CancelBooking 取消预订并释放房量：待确认预订可随时取消，已确认预订须于入住日前取消
+func (s *ServicedApartmentBookingService) CancelBooking(ctx context.Context, apartmentID uint, bookingID uint, userID uint, userType string, req *models.RespondBookingRequest) (*models.ServicedApartmentBooking, error) {
+	apartment, err := s.apartmentRepo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.repo.FindByID(ctx, apartmentID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	isOperator := apartment.CompanyID == userID || userType == "admin"
+	if booking.UserID != userID && !isOperator {
+		return nil, errors.New("permission denied")
+	}
+
+	fromStatus := booking.Status
+	switch fromStatus {
+	case models.BookingStatusPending:
+	case models.BookingStatusConfirmed:
+		if !bookingToday().Before(booking.CheckInDate) {
+			return nil, tools.WrapError(400, "confirmed bookings can only be cancelled before the check-in date", tools.ErrInvalidInput)
+		}
+	default:
+		return nil, tools.WrapError(400, "only pending or confirmed bookings can be cancelled", tools.ErrInvalidInput)
+	}
+
+	before := *booking
+	now := time.Now()
+	booking.Status = models.BookingStatusCancelled
+	booking.HoldExpiresAt = nil
+	booking.CancelledAt = &now
+	if isOperator && booking.UserID != userID {
+		booking.OperatorNote = req.Note
+	}
+	if err := s.repo.Transition(ctx, booking, fromStatus, true); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_booking", booking.ID, models.AuditActionUpdate, &before, booking)
+
+	stay := fmt.Sprintf("%s 至 %s", booking.CheckInDate.Format("2006-01-02"), booking.CheckOutDate.Format("2006-01-02"))
+	if booking.UserID == userID {
+		s.notify(ctx, apartment.CompanyID, "booking_cancelled", booking.ID,
+			"预订已取消",
+			fmt.Sprintf("「%s」%s 的预订已由申请人取消。", apartment.Name, stay))
+	} else {
+		s.notify(ctx, booking.UserID, "booking_cancelled", booking.ID,
+			"预订已取消",
+			fmt.Sprintf("您于「%s」%s 的预订已由营运商取消。%s", apartment.Name, stay, req.Note))
+	}
+	return booking, nil
+}
+
+// 8. ExpireHolds 释放保留到期仍未确认的预订并通知申请人
+func (s *ServicedApartmentBookingService) ExpireHolds(ctx context.Context) error {
+	bookings, err := s.repo.FindExpiredHolds(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range bookings {
+		booking := &bookings[i]
+		before := *booking
+		booking.Status = models.BookingStatusExpired
+		booking.HoldExpiresAt = nil
+		if err := s.repo.Transition(ctx, booking, models.BookingStatusPending, true); err != nil {
+			// 已被营运商或申请人同时处理，跳过
+			if errors.Is(err, databases.ErrBookingStatusChanged) {
+				continue
+			}
+			return err
+		}
+		s.auditService.Record(ctx, "serviced_apartment_booking", booking.ID, models.AuditActionExpire, &before, booking)
+		s.notify(ctx, booking.UserID, "booking_expired", booking.ID,
+			"预订申请已逾时",
+			fmt.Sprintf("您 %s 至 %s 的预订申请未在保留期内获确认，房量已释放，可重新提交申请。",
+				booking.CheckInDate.Format("2006-01-02"), booking.CheckOutDate.Format("2006-01-02")))
+	}
+
+	return nil
+}
+
+// findManagedApartment 校验管理权限（所属公司或管理员）
+func (s *ServicedApartmentBookingService) findManagedApartment(ctx context.Context, apartmentID uint, userID uint, userType string) (*models.ServicedApartment, error) {
+	apartment, err := s.apartmentRepo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if apartment.CompanyID != userID && userType != "admin" {
+		return nil, errors.New("permission denied")
+	}
+	return apartment, nil
+}
+
+// notify 创建站内通知（失败不影响预订流程）
+func (s *ServicedApartmentBookingService) notify(ctx context.Context, userID uint, notificationType string, bookingID uint, title, content string) {
+	_ = s.notificationRepo.Create(ctx, &models.Notification{
+		UserID:     userID,
+		Type:       notificationType,
+		Title:      title,
+		Content:    content,
+		EntityType: "serviced_apartment_booking",
+		EntityID:   bookingID,
+	})
+}
+
+// paginatedBookings 组装分页预订响应
+func paginatedBookings(bookings []models.ServicedApartmentBooking, total int64, req *models.ListBookingsRequest) *models.PaginatedBookingsResponse {
+	return &models.PaginatedBookingsResponse{
+		Data:       bookings,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}
+}
+
+// bookingToday 香港时间的今天（日期按 UTC 零点表示，与 date 字段一致）
+func bookingToday() time.Time {
+	today, _ := time.Parse("2006-01-02", time.Now().In(hongKongTime).Format("2006-01-02"))
+	return today
+}
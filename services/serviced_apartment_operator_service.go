@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// ServicedApartmentOperatorService Methods:
+// 0. NewServicedApartmentOperatorService(apartmentRepo, bookingRepo, mediaService, auditService) -> 注入依赖
+// 1. CreateUnit(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.CreateServicedApartmentUnitRequest) -> 新增房型（所属公司或管理员）
+// 2. UpdateUnit(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string, req *models.UpdateServicedApartmentUnitRequest) -> 更新房型
+// 3. DeleteUnit(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string) -> 删除房型（有未退房预订时不可删除）
+// 4. GetUnitImages(ctx context.Context, apartmentID uint, unitID uint) -> 房型图片列表
+// 5. AddUnitImage(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string, req *models.AddImageRequest) -> 添加房型图片
+// 6. UpdateUnitImage(ctx context.Context, apartmentID uint, unitID uint, imageID uint, userID uint, userType string, req *models.UpdateImageRequest) -> 更新房型图片信息
+// 7. DeleteUnitImage(ctx context.Context, apartmentID uint, unitID uint, imageID uint, userID uint, userType string) -> 删除房型图片
+// 8. ReorderUnitImages(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string, req *models.ReorderImagesRequest) -> 批量调整房型图片顺序
+// 9. GetDashboard(ctx context.Context, userID uint, userType string, req *models.GetOperatorDashboardRequest) -> 营运商概览（浏览、预订申请及入住率）
+
+// operatorDashboardDays 营运商概览统计期天数
+const operatorDashboardDays = 30
+
+// servicedApartmentUnitImageTypes 房型图片类型
+var servicedApartmentUnitImageTypes = []string{"room", "bathroom", "facilities"}
+
+// ServicedApartmentOperatorService 服务式住宅营运商管理服务
+type ServicedApartmentOperatorService struct {
+	apartmentRepo *databases.ServicedApartmentRepo
+	bookingRepo   *databases.ServicedApartmentBookingRepo
+	mediaService  *MediaService
+	auditService  *AuditService
+}
+
+// 0. NewServicedApartmentOperatorService 构造函数
+func NewServicedApartmentOperatorService(
+	apartmentRepo *databases.ServicedApartmentRepo,
+	bookingRepo *databases.ServicedApartmentBookingRepo,
+	mediaService *MediaService,
+	auditService *AuditService,
+) *ServicedApartmentOperatorService {
+	return &ServicedApartmentOperatorService{
+		apartmentRepo: apartmentRepo,
+		bookingRepo:   bookingRepo,
+		mediaService:  mediaService,
+		auditService:  auditService,
+	}
+}
+
+// 1. CreateUnit 新增房型（未指定排序时排在最后）
+func (s *ServicedApartmentOperatorService) CreateUnit(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.CreateServicedApartmentUnitRequest) (*models.ServicedApartmentUnit, error) {
+	apartment, err := s.findManagedApartment(ctx, apartmentID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := &models.ServicedApartmentUnit{
+		ServicedApartmentID: apartmentID,
+		UnitType:            req.UnitType,
+		Bedrooms:            req.Bedrooms,
+		Bathrooms:           req.Bathrooms,
+		Area:                req.Area,
+		MaxOccupancy:        req.MaxOccupancy,
+		DailyPrice:          req.DailyPrice,
+		WeeklyPrice:         req.WeeklyPrice,
+		MonthlyPrice:        req.MonthlyPrice,
+		AvailableUnits:      req.AvailableUnits,
+		Description:         req.Description,
+		SortOrder:           len(apartment.Units),
+	}
+	if req.SortOrder != nil {
+		unit.SortOrder = *req.SortOrder
+	}
+
+	if err := s.apartmentRepo.CreateUnit(ctx, unit); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_unit", unit.ID, models.AuditActionCreate, nil, unit)
+	return unit, nil
+}
+
+// 2. UpdateUnit 更新房型（单位数不可低于未来任一晚已占用的单位数）
+func (s *ServicedApartmentOperatorService) UpdateUnit(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string, req *models.UpdateServicedApartmentUnitRequest) (*models.ServicedApartmentUnit, error) {
+	unit, err := s.findManagedUnit(ctx, apartmentID, unitID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AvailableUnits != nil && *req.AvailableUnits < unit.AvailableUnits {
+		booked, err := s.bookingRepo.FindMaxBooked(ctx, unit.ID, bookingToday())
+		if err != nil {
+			return nil, err
+		}
+		if *req.AvailableUnits < booked {
+			return nil, tools.WrapError(400, fmt.Sprintf("available_units must not be lower than the %d units already booked", booked), tools.ErrInvalidInput)
+		}
+	}
+	before := *unit
+
+	if req.UnitType != nil {
+		unit.UnitType = *req.UnitType
+	}
+	if req.Bedrooms != nil {
+		unit.Bedrooms = *req.Bedrooms
+	}
+	if req.Bathrooms != nil {
+		unit.Bathrooms = *req.Bathrooms
+	}
+	if req.Area != nil {
+		unit.Area = *req.Area
+	}
+	if req.MaxOccupancy != nil {
+		unit.MaxOccupancy = *req.MaxOccupancy
+	}
+	if req.DailyPrice != nil {
+		unit.DailyPrice = *req.DailyPrice
+	}
+	if req.WeeklyPrice != nil {
+		unit.WeeklyPrice = *req.WeeklyPrice
+	}
+	if req.MonthlyPrice != nil {
+		unit.MonthlyPrice = *req.MonthlyPrice
+	}
+	if req.AvailableUnits != nil {
+		unit.AvailableUnits = *req.AvailableUnits
+	}
+	if req.Description != nil {
+		unit.Description = *req.Description
+	}
+	if req.SortOrder != nil {
+		unit.SortOrder = *req.SortOrder
+	}
+
+	if err := s.apartmentRepo.UpdateUnit(ctx, unit); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_unit", unit.ID, models.AuditActionUpdate, &before, unit)
+	return unit, nil
+}
+
+// 3. DeleteUnit 删除房型及其图片（有待确认或已确认且未退房的预订时不可删除）
+func (s *ServicedApartmentOperatorService) DeleteUnit(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string) error {
+	unit, err := s.findManagedUnit(ctx, apartmentID, unitID, userID, userType)
+	if err != nil {
+		return err
+	}
+
+	active, err := s.bookingRepo.CountActiveBookings(ctx, unit.ID, bookingToday())
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return tools.WrapError(400, fmt.Sprintf("unit has %d active bookings", active), tools.ErrInvalidInput)
+	}
+
+	if err := s.apartmentRepo.DeleteUnit(ctx, apartmentID, unit.ID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_unit", unit.ID, models.AuditActionDelete, unit, nil)
+	return nil
+}
+
+// 4. GetUnitImages 房型图片列表
+func (s *ServicedApartmentOperatorService) GetUnitImages(ctx context.Context, apartmentID uint, unitID uint) ([]models.ServicedApartmentImage, error) {
+	if _, err := s.apartmentRepo.FindUnit(ctx, apartmentID, unitID); err != nil {
+		return nil, err
+	}
+	return s.apartmentRepo.FindUnitImages(ctx, unitID)
+}
+
+// 5. AddUnitImage 添加房型图片（追加到末尾，第一张自动作为封面）
+func (s *ServicedApartmentOperatorService) AddUnitImage(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string, req *models.AddImageRequest) (*models.ServicedApartmentImage, error) {
+	unit, err := s.findManagedUnit(ctx, apartmentID, unitID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	images, err := s.apartmentRepo.FindUnitImages(ctx, unit.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImageCount(len(images)); err != nil {
+		return nil, err
+	}
+
+	imageType := req.ImageType
+	if imageType == "" {
+		imageType = "room"
+	}
+	if err := checkImageType(imageType, servicedApartmentUnitImageTypes...); err != nil {
+		return nil, err
+	}
+
+	source, err := s.mediaService.ResolveImage(ctx, req.ImageKey, req.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.ServicedApartmentImage{
+		UnitID:       &unit.ID,
+		ImageURL:     source.ImageURL,
+		ObjectKey:    source.ObjectKey,
+		ThumbnailURL: source.ThumbnailURL,
+		ImageType:    imageType,
+		Title:        req.Title,
+		IsCover:      len(images) == 0,
+		SortOrder:    len(images),
+	}
+	if err := s.apartmentRepo.CreateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_image", image.ID, models.AuditActionCreate, nil, image)
+	return image, nil
+}
+
+// 6. UpdateUnitImage 更新房型图片类型/标题
+func (s *ServicedApartmentOperatorService) UpdateUnitImage(ctx context.Context, apartmentID uint, unitID uint, imageID uint, userID uint, userType string, req *models.UpdateImageRequest) (*models.ServicedApartmentImage, error) {
+	unit, err := s.findManagedUnit(ctx, apartmentID, unitID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	image, err := s.apartmentRepo.FindUnitImage(ctx, unit.ID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	before := *image
+
+	if req.ImageType != nil {
+		if err := checkImageType(*req.ImageType, servicedApartmentUnitImageTypes...); err != nil {
+			return nil, err
+		}
+		image.ImageType = *req.ImageType
+	}
+	if req.Title != nil {
+		image.Title = *req.Title
+	}
+
+	if err := s.apartmentRepo.UpdateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_image", imageID, models.AuditActionUpdate, &before, image)
+	return image, nil
+}
+
+// 7. DeleteUnitImage 删除房型图片
+func (s *ServicedApartmentOperatorService) DeleteUnitImage(ctx context.Context, apartmentID uint, unitID uint, imageID uint, userID uint, userType string) error {
+	unit, err := s.findManagedUnit(ctx, apartmentID, unitID, userID, userType)
+	if err != nil {
+		return err
+	}
+	image, err := s.apartmentRepo.FindUnitImage(ctx, unit.ID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.apartmentRepo.DeleteUnitImage(ctx, unit.ID, imageID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_image", imageID, models.AuditActionDelete, image, nil)
+	return nil
+}
+
+// 8. ReorderUnitImages 批量调整房型图片顺序（需包含全部图片）
+func (s *ServicedApartmentOperatorService) ReorderUnitImages(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string, req *models.ReorderImagesRequest) ([]models.ServicedApartmentImage, error) {
+	unit, err := s.findManagedUnit(ctx, apartmentID, unitID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	images, err := s.apartmentRepo.FindUnitImages(ctx, unit.ID)
+	if err != nil {
+		return nil, err
+	}
+	currentIDs := make([]uint, len(images))
+	for i, img := range images {
+		currentIDs[i] = img.ID
+	}
+	if err := checkImageOrder(currentIDs, req.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.apartmentRepo.ReorderUnitImages(ctx, unit.ID, req.ImageIDs); err != nil {
+		return nil, err
+	}
+	reordered, err := s.apartmentRepo.FindUnitImages(ctx, unit.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.RecordEach(ctx, "serviced_apartment_image", models.AuditActionUpdate, images, reordered)
+	return reordered, nil
+}
+
+// 9. GetDashboard 营运商概览：各住宅浏览量、过去统计期的预订申请、待处理预订及未来统计期入住率
+func (s *ServicedApartmentOperatorService) GetDashboard(ctx context.Context, userID uint, userType string, req *models.GetOperatorDashboardRequest) (*models.OperatorDashboardResponse, error) {
+	companyID := userID
+	if req.CompanyID != nil {
+		if userType != "admin" && *req.CompanyID != userID {
+			return nil, errors.New("permission denied")
+		}
+		companyID = *req.CompanyID
+	}
+
+	apartments, err := s.apartmentRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := bookingToday()
+	windowEnd := today.AddDate(0, 0, operatorDashboardDays)
+	apartmentIDs := make([]uint, 0, len(apartments))
+	var unitIDs []uint
+	for _, apartment := range apartments {
+		apartmentIDs = append(apartmentIDs, apartment.ID)
+		for _, unit := range apartment.Units {
+			unitIDs = append(unitIDs, unit.ID)
+		}
+	}
+
+	stats, err := s.bookingRepo.FindBookingStats(ctx, apartmentIDs, today.AddDate(0, 0, -operatorDashboardDays), today)
+	if err != nil {
+		return nil, err
+	}
+	statsByApartment := make(map[uint]models.ServicedApartmentBookingStats, len(stats))
+	for _, row := range stats {
+		statsByApartment[row.ServicedApartmentID] = row
+	}
+
+	inventory, err := s.bookingRepo.FindInventory(ctx, unitIDs, today, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+	bookedNights := make(map[uint]int)
+	bookedTonight := make(map[uint]int)
+	for _, row := range inventory {
+		bookedNights[row.UnitID] += row.Booked
+		if row.Date.Equal(today) {
+			bookedTonight[row.UnitID] = row.Booked
+		}
+	}
+
+	resp := &models.OperatorDashboardResponse{
+		CompanyID:  companyID,
+		WindowDays: operatorDashboardDays,
+		Properties: make([]models.OperatorDashboardItem, 0, len(apartments)),
+	}
+	var totalBooked, totalCapacity int
+	for _, apartment := range apartments {
+		item := models.OperatorDashboardItem{
+			ID:                            apartment.ID,
+			Name:                          apartment.Name,
+			Status:                        apartment.Status,
+			District:                      apartment.District,
+			ViewCount:                     apartment.ViewCount,
+			FavoriteCount:                 apartment.FavoriteCount,
+			UnitTypes:                     len(apartment.Units),
+			ServicedApartmentBookingStats: statsByApartment[apartment.ID],
+		}
+		var booked int
+		for _, unit := range apartment.Units {
+			item.TotalUnits += unit.AvailableUnits
+			item.OccupiedTonight += bookedTonight[unit.ID]
+			booked += bookedNights[unit.ID]
+		}
+		capacity := item.TotalUnits * operatorDashboardDays
+		if capacity > 0 {
+			item.OccupancyRate = round2(float64(booked) / float64(capacity) * 100)
+		}
+		totalBooked += booked
+		totalCapacity += capacity
+
+		resp.TotalViews += item.ViewCount
+		resp.TotalEnquiries += item.Enquiries
+		resp.PendingBookings += item.PendingBookings
+		resp.Properties = append(resp.Properties, item)
+	}
+	if totalCapacity > 0 {
+		resp.OccupancyRate = round2(float64(totalBooked) / float64(totalCapacity) * 100)
+	}
+
+	return resp, nil
+}
+
+// findManagedApartment 校验管理权限（所属公司或管理员）
+func (s *ServicedApartmentOperatorService) findManagedApartment(ctx context.Context, apartmentID uint, userID uint, userType string) (*models.ServicedApartment, error) {
+	apartment, err := s.apartmentRepo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if apartment.CompanyID != userID && userType != "admin" {
+		return nil, errors.New("permission denied")
+	}
+	return apartment, nil
+}
+
+// findManagedUnit 校验管理权限并返回住宅下的房型
+func (s *ServicedApartmentOperatorService) findManagedUnit(ctx context.Context, apartmentID uint, unitID uint, userID uint, userType string) (*models.ServicedApartmentUnit, error) {
+	if _, err := s.findManagedApartment(ctx, apartmentID, userID, userType); err != nil {
+		return nil, err
+	}
+	return s.apartmentRepo.FindUnit(ctx, apartmentID, unitID)
+}
@@ -0,0 +1,483 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// ServicedApartmentPricingService Methods:
+// 0. NewServicedApartmentPricingService(repo, apartmentRepo, bookingRepo, auditService) -> 注入依赖
+// 1. GetPricingRules(ctx context.Context, apartmentID uint) -> 住宅季节性调价及长住折扣
+// 2. UpdatePricingRules(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.UpdatePricingRulesRequest) -> 整体替换调价及折扣（所属公司或管理员）
+// 3. GetQuote(ctx context.Context, apartmentID uint, req *models.GetStayQuoteRequest) -> 入住报价（各房型最低租金组合及明细）
+// 4. QuoteApartments(ctx context.Context, apartments []models.ServicedApartment, checkIn, checkOut string, guests *int) -> 批量报价（列表按入住日期筛选）
+
+// 计价方案（周租按 7 晚、月租按 30 晚计；不足一周/一月亦可按整周/整月计价，取较低者）
+var stayRatePlans = []struct {
+	itemType string
+	nights   int
+	label    string
+	price    func(unit *models.ServicedApartmentUnit) float64
+}{
+	{models.QuoteItemDaily, 1, "日租", func(unit *models.ServicedApartmentUnit) float64 { return unit.DailyPrice }},
+	{models.QuoteItemWeekly, 7, "周租", func(unit *models.ServicedApartmentUnit) float64 { return unit.WeeklyPrice }},
+	{models.QuoteItemMonthly, 30, "月租", func(unit *models.ServicedApartmentUnit) float64 { return unit.MonthlyPrice }},
+}
+
+// ServicedApartmentPricingService 服务式住宅报价服务
+type ServicedApartmentPricingService struct {
+	repo          *databases.ServicedApartmentPricingRepo
+	apartmentRepo *databases.ServicedApartmentRepo
+	bookingRepo   *databases.ServicedApartmentBookingRepo
+	auditService  *AuditService
+}
+
+// 0. NewServicedApartmentPricingService 构造函数
+func NewServicedApartmentPricingService(
+	repo *databases.ServicedApartmentPricingRepo,
+	apartmentRepo *databases.ServicedApartmentRepo,
+	bookingRepo *databases.ServicedApartmentBookingRepo,
+	auditService *AuditService,
+) *ServicedApartmentPricingService {
+	return &ServicedApartmentPricingService{
+		repo:          repo,
+		apartmentRepo: apartmentRepo,
+		bookingRepo:   bookingRepo,
+		auditService:  auditService,
+	}
+}
+
+// 1. GetPricingRules 住宅季节性调价及长住折扣
+func (s *ServicedApartmentPricingService) GetPricingRules(ctx context.Context, apartmentID uint) (*models.PricingRulesResponse, error) {
+	if _, err := s.apartmentRepo.FindByID(ctx, apartmentID); err != nil {
+		return nil, err
+	}
+	return s.findRules(ctx, apartmentID)
+}
+
+// 2. UpdatePricingRules 整体替换调价及折扣：同一房型（或全住宅）的季节不可重叠，折扣档的最少晚数不可重复
+func (s *ServicedApartmentPricingService) UpdatePricingRules(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.UpdatePricingRulesRequest) (*models.PricingRulesResponse, error) {
+	apartment, err := s.apartmentRepo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if apartment.CompanyID != userID && userType != "admin" {
+		return nil, errors.New("permission denied")
+	}
+
+	unitIDs := make(map[uint]bool, len(apartment.Units))
+	for _, unit := range apartment.Units {
+		unitIDs[unit.ID] = true
+	}
+
+	rates := make([]models.ServicedApartmentSeasonalRate, 0, len(req.SeasonalRates))
+	for _, input := range req.SeasonalRates {
+		if input.UnitID != nil && !unitIDs[*input.UnitID] {
+			return nil, errors.New("unit not found")
+		}
+		start, _ := time.Parse("2006-01-02", input.StartDate)
+		end, _ := time.Parse("2006-01-02", input.EndDate)
+		if end.Before(start) {
+			return nil, tools.WrapError(400, fmt.Sprintf("seasonal rate %q ends before it starts", input.Name), tools.ErrInvalidInput)
+		}
+		for _, existing := range rates {
+			if sameRateScope(existing.UnitID, input.UnitID) && !start.After(existing.EndDate) && !end.Before(existing.StartDate) {
+				return nil, tools.WrapError(400, fmt.Sprintf("seasonal rates %q and %q overlap", existing.Name, input.Name), tools.ErrInvalidInput)
+			}
+		}
+		rates = append(rates, models.ServicedApartmentSeasonalRate{
+			ServicedApartmentID: apartmentID,
+			UnitID:              input.UnitID,
+			Name:                input.Name,
+			StartDate:           start,
+			EndDate:             end,
+			AdjustmentPercent:   input.AdjustmentPercent,
+		})
+	}
+
+	discounts := make([]models.ServicedApartmentStayDiscount, 0, len(req.StayDiscounts))
+	minNights := make(map[int]bool, len(req.StayDiscounts))
+	for _, input := range req.StayDiscounts {
+		if minNights[input.MinNights] {
+			return nil, tools.WrapError(400, fmt.Sprintf("duplicate stay discount for %d nights", input.MinNights), tools.ErrInvalidInput)
+		}
+		minNights[input.MinNights] = true
+		discounts = append(discounts, models.ServicedApartmentStayDiscount{
+			ServicedApartmentID: apartmentID,
+			MinNights:           input.MinNights,
+			DiscountPercent:     input.DiscountPercent,
+		})
+	}
+
+	before, err := s.findRules(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRules(ctx, apartmentID, rates, discounts); err != nil {
+		return nil, err
+	}
+	after, err := s.findRules(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordRuleChanges(ctx, before, after)
+	return after, nil
+}
+
+// recordRuleChanges 记录调价及折扣的整体替换（原规则记为删除，新规则记为新增）
+func (s *ServicedApartmentPricingService) recordRuleChanges(ctx context.Context, before, after *models.PricingRulesResponse) {
+	for i := range before.SeasonalRates {
+		rate := &before.SeasonalRates[i]
+		s.auditService.Record(ctx, "serviced_apartment_seasonal_rate", rate.ID, models.AuditActionDelete, rate, nil)
+	}
+	for i := range before.StayDiscounts {
+		discount := &before.StayDiscounts[i]
+		s.auditService.Record(ctx, "serviced_apartment_stay_discount", discount.ID, models.AuditActionDelete, discount, nil)
+	}
+	for i := range after.SeasonalRates {
+		rate := &after.SeasonalRates[i]
+		s.auditService.Record(ctx, "serviced_apartment_seasonal_rate", rate.ID, models.AuditActionCreate, nil, rate)
+	}
+	for i := range after.StayDiscounts {
+		discount := &after.StayDiscounts[i]
+		s.auditService.Record(ctx, "serviced_apartment_stay_discount", discount.ID, models.AuditActionCreate, nil, discount)
+	}
+}
+
+// 3. GetQuote 入住报价：各房型按最低租金组合计价，按入住晚套用季节性调价，再按总晚数套用长住折扣
+func (s *ServicedApartmentPricingService) GetQuote(ctx context.Context, apartmentID uint, req *models.GetStayQuoteRequest) (*models.StayQuoteResponse, error) {
+	apartment, err := s.apartmentRepo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	checkIn, checkOut, nights, err := parseStayDates(req.CheckIn, req.CheckOut)
+	if err != nil {
+		return nil, err
+	}
+	if apartment.MinStayDays > 0 && nights < apartment.MinStayDays {
+		return nil, tools.WrapError(400, fmt.Sprintf("minimum stay is %d nights", apartment.MinStayDays), tools.ErrInvalidInput)
+	}
+
+	if req.UnitID != nil {
+		units := apartment.Units[:0]
+		for _, unit := range apartment.Units {
+			if unit.ID == *req.UnitID {
+				units = append(units, unit)
+			}
+		}
+		if len(units) == 0 {
+			return nil, errors.New("unit not found")
+		}
+		apartment.Units = units
+	}
+
+	quotes, err := s.quote(ctx, []models.ServicedApartment{*apartment}, checkIn, checkOut, req.Guests)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StayQuoteResponse{
+		ServicedApartmentID: apartment.ID,
+		CheckIn:             req.CheckIn,
+		CheckOut:            req.CheckOut,
+		Nights:              nights,
+		MinStayDays:         apartment.MinStayDays,
+		Units:               quotes[apartment.ID],
+	}, nil
+}
+
+// 4. QuoteApartments 批量报价（住宅ID -> 各房型报价）；不满足最少入住天数的住宅所有房型均视为不可订
+func (s *ServicedApartmentPricingService) QuoteApartments(ctx context.Context, apartments []models.ServicedApartment, checkIn, checkOut string, guests *int) (map[uint][]models.UnitQuote, error) {
+	start, end, _, err := parseStayDates(checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	return s.quote(ctx, apartments, start, end, guests)
+}
+
+// findRules 查询住宅的全部调价及折扣设置
+func (s *ServicedApartmentPricingService) findRules(ctx context.Context, apartmentID uint) (*models.PricingRulesResponse, error) {
+	rates, err := s.repo.FindSeasonalRates(ctx, []uint{apartmentID}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := s.repo.FindStayDiscounts(ctx, []uint{apartmentID})
+	if err != nil {
+		return nil, err
+	}
+	return &models.PricingRulesResponse{SeasonalRates: rates, StayDiscounts: discounts}, nil
+}
+
+// quote 为住宅的各房型计算 [checkIn, checkOut) 的报价及房量（每个住宅的房型按可订、总额升序排列）
+func (s *ServicedApartmentPricingService) quote(ctx context.Context, apartments []models.ServicedApartment, checkIn, checkOut time.Time, guests *int) (map[uint][]models.UnitQuote, error) {
+	apartmentIDs := make([]uint, 0, len(apartments))
+	var unitIDs []uint
+	for _, apartment := range apartments {
+		apartmentIDs = append(apartmentIDs, apartment.ID)
+		for _, unit := range apartment.Units {
+			unitIDs = append(unitIDs, unit.ID)
+		}
+	}
+
+	rates, err := s.repo.FindSeasonalRates(ctx, apartmentIDs, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := s.repo.FindStayDiscounts(ctx, apartmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := s.bookingRepo.FindInventory(ctx, unitIDs, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+
+	ratesByApartment := make(map[uint][]models.ServicedApartmentSeasonalRate)
+	for _, rate := range rates {
+		ratesByApartment[rate.ServicedApartmentID] = append(ratesByApartment[rate.ServicedApartmentID], rate)
+	}
+	discountsByApartment := make(map[uint][]models.ServicedApartmentStayDiscount)
+	for _, discount := range discounts {
+		discountsByApartment[discount.ServicedApartmentID] = append(discountsByApartment[discount.ServicedApartmentID], discount)
+	}
+	maxBooked := make(map[uint]int)
+	for _, row := range inventory {
+		if row.Booked > maxBooked[row.UnitID] {
+			maxBooked[row.UnitID] = row.Booked
+		}
+	}
+
+	nights := int(checkOut.Sub(checkIn).Hours() / 24)
+	result := make(map[uint][]models.UnitQuote, len(apartments))
+	for _, apartment := range apartments {
+		meetsMinStay := apartment.MinStayDays <= 0 || nights >= apartment.MinStayDays
+		quotes := make([]models.UnitQuote, 0, len(apartment.Units))
+		for i := range apartment.Units {
+			unit := &apartment.Units[i]
+			q := quoteUnit(unit, checkIn, nights, ratesByApartment[apartment.ID], discountsByApartment[apartment.ID])
+			q.AvailableUnits = unit.AvailableUnits - maxBooked[unit.ID]
+			if q.AvailableUnits < 0 {
+				q.AvailableUnits = 0
+			}
+			q.Available = meetsMinStay && !q.Unquotable && q.AvailableUnits > 0 &&
+				(guests == nil || unit.MaxOccupancy <= 0 || *guests <= unit.MaxOccupancy)
+			quotes = append(quotes, q)
+		}
+		sortUnitQuotes(quotes)
+		result[apartment.ID] = quotes
+	}
+	return result, nil
+}
+
+// stayBlock 计价区块：自第 start 晚起按某计价方案覆盖 nights 晚
+type stayBlock struct {
+	plan   int
+	start  int
+	nights int
+}
+
+// quoteUnit 计算单个房型的报价：动态规划求覆盖全部入住晚的最低租金组合
+func quoteUnit(unit *models.ServicedApartmentUnit, checkIn time.Time, nights int, rates []models.ServicedApartmentSeasonalRate, discounts []models.ServicedApartmentStayDiscount) models.UnitQuote {
+	q := models.UnitQuote{
+		UnitID:       unit.ID,
+		UnitType:     unit.UnitType,
+		MaxOccupancy: unit.MaxOccupancy,
+		LineItems:    []models.QuoteLineItem{},
+	}
+
+	// 每晚适用的季节性调价（房型专属设置优先）
+	nightRates := make([]*models.ServicedApartmentSeasonalRate, nights)
+	factorSum := make([]float64, nights+1)
+	for n := 0; n < nights; n++ {
+		nightRates[n] = seasonalRateFor(rates, unit.ID, checkIn.AddDate(0, 0, n))
+		factor := 1.0
+		if nightRates[n] != nil {
+			factor += nightRates[n].AdjustmentPercent / 100
+		}
+		factorSum[n+1] = factorSum[n] + factor
+	}
+	blockFactor := func(b stayBlock) float64 {
+		return (factorSum[b.start+b.nights] - factorSum[b.start]) / float64(b.nights)
+	}
+
+	best := make([]float64, nights+1)
+	prev := make([]stayBlock, nights+1)
+	for n := 1; n <= nights; n++ {
+		best[n] = math.Inf(1)
+	}
+	for n := 0; n < nights; n++ {
+		if math.IsInf(best[n], 1) {
+			continue
+		}
+		for p, plan := range stayRatePlans {
+			price := plan.price(unit)
+			if price <= 0 {
+				continue
+			}
+			b := stayBlock{plan: p, start: n, nights: plan.nights}
+			if n+b.nights > nights {
+				b.nights = nights - n
+			}
+			cost := best[n] + round2(price*blockFactor(b))
+			if cost < best[n+b.nights]-0.005 {
+				best[n+b.nights] = cost
+				prev[n+b.nights] = b
+			}
+		}
+	}
+	if nights == 0 || math.IsInf(best[nights], 1) {
+		q.Unquotable = true
+		return q
+	}
+
+	var blocks []stayBlock
+	for n := nights; n > 0; n = prev[n].start {
+		blocks = append([]stayBlock{prev[n]}, blocks...)
+	}
+
+	// 相邻的相同方案、相同单价及季节的区块合并为一行明细
+	for _, b := range blocks {
+		plan := stayRatePlans[b.plan]
+		unitPrice := round2(plan.price(unit) * blockFactor(b))
+		description := plan.label
+		if b.nights < plan.nights {
+			description = fmt.Sprintf("%s（%d 晚按整%s计）", plan.label, b.nights, strings.TrimSuffix(plan.label, "租"))
+		}
+		if names := seasonNames(nightRates[b.start : b.start+b.nights]); names != "" {
+			description += " · " + names
+		}
+
+		if last := len(q.LineItems) - 1; last >= 0 && q.LineItems[last].Type == plan.itemType &&
+			q.LineItems[last].UnitPrice == unitPrice && q.LineItems[last].Description == description {
+			item := &q.LineItems[last]
+			item.Quantity++
+			item.Nights += b.nights
+			item.EndDate = checkIn.AddDate(0, 0, b.start+b.nights-1).Format("2006-01-02")
+			item.Amount = round2(item.UnitPrice * float64(item.Quantity))
+			continue
+		}
+		q.LineItems = append(q.LineItems, models.QuoteLineItem{
+			Type:              plan.itemType,
+			Description:       description,
+			StartDate:         checkIn.AddDate(0, 0, b.start).Format("2006-01-02"),
+			EndDate:           checkIn.AddDate(0, 0, b.start+b.nights-1).Format("2006-01-02"),
+			Nights:            b.nights,
+			Quantity:          1,
+			UnitPrice:         unitPrice,
+			AdjustmentPercent: round2((blockFactor(b) - 1) * 100),
+			Amount:            unitPrice,
+		})
+	}
+	for _, item := range q.LineItems {
+		q.Subtotal += item.Amount
+	}
+	q.Subtotal = round2(q.Subtotal)
+
+	// 长住折扣取最高适用档
+	var applied *models.ServicedApartmentStayDiscount
+	for i := range discounts {
+		if discounts[i].MinNights <= nights && (applied == nil || discounts[i].MinNights > applied.MinNights) {
+			applied = &discounts[i]
+		}
+	}
+	if applied != nil {
+		q.Discount = round2(q.Subtotal * applied.DiscountPercent / 100)
+		q.LineItems = append(q.LineItems, models.QuoteLineItem{
+			Type:        models.QuoteItemDiscount,
+			Description: fmt.Sprintf("长住折扣（满 %d 晚减 %g%%）", applied.MinNights, applied.DiscountPercent),
+			Quantity:    1,
+			UnitPrice:   -q.Discount,
+			Amount:      -q.Discount,
+		})
+	}
+
+	q.Total = round2(q.Subtotal - q.Discount)
+	q.NightlyAverage = round2(q.Total / float64(nights))
+	return q
+}
+
+// seasonalRateFor 查找某晚适用的季节性调价（房型专属设置优先于全住宅设置）
+func seasonalRateFor(rates []models.ServicedApartmentSeasonalRate, unitID uint, night time.Time) *models.ServicedApartmentSeasonalRate {
+	var apartmentWide *models.ServicedApartmentSeasonalRate
+	for i := range rates {
+		rate := &rates[i]
+		if night.Before(rate.StartDate) || night.After(rate.EndDate) {
+			continue
+		}
+		if rate.UnitID == nil {
+			if apartmentWide == nil {
+				apartmentWide = rate
+			}
+		} else if *rate.UnitID == unitID {
+			return rate
+		}
+	}
+	return apartmentWide
+}
+
+// seasonNames 区块内各晚适用季节名称及调整百分比（按出现顺序去重）
+func seasonNames(nightRates []*models.ServicedApartmentSeasonalRate) string {
+	var names []string
+	seen := make(map[uint]bool)
+	for _, rate := range nightRates {
+		if rate == nil || seen[rate.ID] {
+			continue
+		}
+		seen[rate.ID] = true
+		names = append(names, fmt.Sprintf("%s %+g%%", rate.Name, rate.AdjustmentPercent))
+	}
+	return strings.Join(names, "、")
+}
+
+// sameRateScope 两条季节性调价是否适用于同一范围（同一房型或同为全住宅）
+func sameRateScope(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// sortUnitQuotes 房型报价排序：可订优先，再按总额升序，未设置租金的排最后
+func sortUnitQuotes(quotes []models.UnitQuote) {
+	sort.SliceStable(quotes, func(i, j int) bool {
+		if quotes[i].Unquotable != quotes[j].Unquotable {
+			return !quotes[i].Unquotable
+		}
+		if quotes[i].Available != quotes[j].Available {
+			return quotes[i].Available
+		}
+		return quotes[i].Total < quotes[j].Total
+	})
+}
+
+// parseStayDates 解析入住、退房日期并校验（入住不早于今天，最多 bookingMaxNights 晚）
+func parseStayDates(checkInDate, checkOutDate string) (time.Time, time.Time, int, error) {
+	checkIn, err := time.Parse("2006-01-02", checkInDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, tools.WrapError(400, "invalid check_in", tools.ErrInvalidInput)
+	}
+	checkOut, err := time.Parse("2006-01-02", checkOutDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, tools.WrapError(400, "invalid check_out", tools.ErrInvalidInput)
+	}
+	if checkIn.Before(bookingToday()) {
+		return time.Time{}, time.Time{}, 0, tools.WrapError(400, "check_in must not be in the past", tools.ErrInvalidInput)
+	}
+	if !checkOut.After(checkIn) {
+		return time.Time{}, time.Time{}, 0, tools.WrapError(400, "check_out must be after check_in", tools.ErrInvalidInput)
+	}
+	nights := int(checkOut.Sub(checkIn).Hours() / 24)
+	if nights > bookingMaxNights {
+		return time.Time{}, time.Time{}, 0, tools.WrapError(400, fmt.Sprintf("stay must not exceed %d nights", bookingMaxNights), tools.ErrInvalidInput)
+	}
+	return checkIn, checkOut, nights, nil
+}
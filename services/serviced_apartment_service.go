@@ -3,27 +3,40 @@ package services
 import (
 	"context"
 	"errors"
+	"sort"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
 )
 
 // ServicedApartmentService 服务式住宅服务
 // Methods:
-// 1. ListServicedApartments(ctx context.Context, filter *models.ListServicedApartmentsRequest) -> 获取服务式住宅列表
+// 1. ListServicedApartments(ctx context.Context, filter *models.ListServicedApartmentsRequest) -> 获取服务式住宅列表（提供入住日期时只列出有房住宅并按报价排序）
 // 2. GetServicedApartment(ctx context.Context, id uint) -> 获取服务式住宅详情
 // 3. CreateServicedApartment(ctx context.Context, req *models.CreateServicedApartmentRequest, companyID uint) -> 创建服务式住宅
 // 4. UpdateServicedApartment(ctx context.Context, id uint, req *models.UpdateServicedApartmentRequest, companyID uint) -> 更新服务式住宅
 // 5. DeleteServicedApartment(ctx context.Context, id uint, companyID uint) -> 删除服务式住宅
 // 6. GetServicedApartmentUnits(ctx context.Context, apartmentID uint) -> 获取房型列表
 // 7. GetServicedApartmentImages(ctx context.Context, apartmentID uint) -> 获取图片列表
+// 8. AddServicedApartmentImage(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.AddImageRequest) -> 添加图片（所属公司或管理员）
+// 9. UpdateServicedApartmentImage(ctx context.Context, apartmentID uint, imageID uint, userID uint, userType string, req *models.UpdateImageRequest) -> 更新图片信息
+// 10. DeleteServicedApartmentImage(ctx context.Context, apartmentID uint, imageID uint, userID uint, userType string) -> 删除图片
+// 11. ReorderServicedApartmentImages(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.ReorderImagesRequest) -> 批量调整图片顺序
+// 12. SetServicedApartmentCoverImage(ctx context.Context, apartmentID uint, imageID uint, userID uint, userType string) -> 设置封面图
 type ServicedApartmentService struct {
-	repo *databases.ServicedApartmentRepo
+	repo           *databases.ServicedApartmentRepo
+	mediaService   *MediaService
+	pricingService *ServicedApartmentPricingService
+	auditService   *AuditService
 }
 
+// stayListCandidateLimit 按入住日期筛选时参与报价的住宅上限（超出时响应标记 truncated）
+const stayListCandidateLimit = 500
+
 // NewServicedApartmentService 创建服务式住宅服务
-func NewServicedApartmentService(repo *databases.ServicedApartmentRepo) *ServicedApartmentService {
-	return &ServicedApartmentService{repo: repo}
+func NewServicedApartmentService(repo *databases.ServicedApartmentRepo, mediaService *MediaService, pricingService *ServicedApartmentPricingService, auditService *AuditService) *ServicedApartmentService {
+	return &ServicedApartmentService{repo: repo, mediaService: mediaService, pricingService: pricingService, auditService: auditService}
 }
 
 // ListServicedApartments 获取服务式住宅列表
@@ -39,6 +52,11 @@ func (s *ServicedApartmentService) ListServicedApartments(ctx context.Context, f
 		filter.PageSize = 100
 	}
 
+	// 按入住日期筛选
+	if filter.CheckIn != "" || filter.CheckOut != "" {
+		return s.listForStay(ctx, filter)
+	}
+
 	apartments, total, err := s.repo.FindAll(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -65,6 +83,70 @@ func (s *ServicedApartmentService) ListServicedApartments(ctx context.Context, f
 	}, nil
 }
 
+// listForStay 按入住日期筛选：只保留整段期间有可订房型的住宅，按最低报价总额升序分页
+// 只对前 stayListCandidateLimit 间住宅报价；超出时 Total 及排序仅覆盖这部分，响应标记 truncated
+func (s *ServicedApartmentService) listForStay(ctx context.Context, filter *models.ListServicedApartmentsRequest) (*models.PaginatedServicedApartmentsResponse, error) {
+	if filter.CheckIn == "" || filter.CheckOut == "" {
+		return nil, tools.WrapError(400, "check_in and check_out must be provided together", tools.ErrInvalidInput)
+	}
+
+	// 多取一间用于判断是否超出上限
+	apartments, err := s.repo.FindAllForStay(ctx, filter, stayListCandidateLimit+1)
+	if err != nil {
+		return nil, err
+	}
+	truncated := len(apartments) > stayListCandidateLimit
+	if truncated {
+		apartments = apartments[:stayListCandidateLimit]
+	}
+	quotes, err := s.pricingService.QuoteApartments(ctx, apartments, filter.CheckIn, filter.CheckOut, filter.Guests)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.ServicedApartmentResponse, 0, len(apartments))
+	for _, sa := range apartments {
+		var available []models.StayQuoteSummary
+		for _, q := range quotes[sa.ID] {
+			if q.Available {
+				available = append(available, models.StayQuoteSummary{
+					UnitID:   q.UnitID,
+					UnitType: q.UnitType,
+					Total:    q.Total,
+				})
+			}
+		}
+		if len(available) == 0 {
+			continue
+		}
+		item := sa.ToServicedApartmentResponse()
+		item.StayQuotes = available
+		items = append(items, *item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].StayQuotes[0].Total < items[j].StayQuotes[0].Total
+	})
+
+	total := int64(len(items))
+	offset := (filter.Page - 1) * filter.PageSize
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + filter.PageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return &models.PaginatedServicedApartmentsResponse{
+		Data:       items[offset:end],
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, filter.PageSize),
+		Truncated:  truncated,
+	}, nil
+}
+
 // GetServicedApartment 获取服务式住宅详情
 func (s *ServicedApartmentService) GetServicedApartment(ctx context.Context, id uint) (*models.ServicedApartmentDetailResponse, error) {
 	apartment, err := s.repo.FindByID(ctx, id)
@@ -102,16 +184,31 @@ func (s *ServicedApartmentService) CreateServicedApartment(ctx context.Context,
 		ViewCount:     0,
 		FavoriteCount: 0,
 	}
+	// 新建住宅默认不含清洁、允许访客留宿
+	apartment.Inclusions.CleaningFrequency = "none"
+	apartment.HouseRules.OvernightGuestsAllowed = true
+	req.Inclusions.ApplyTo(&apartment.Inclusions)
+	req.HouseRules.ApplyTo(&apartment.HouseRules)
+
+	if err := s.checkFacilities(ctx, req.FacilityIDs); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Create(ctx, apartment); err != nil {
 		return nil, err
 	}
+	if len(req.FacilityIDs) > 0 {
+		if err := s.repo.ReplaceFacilities(ctx, apartment.ID, req.FacilityIDs); err != nil {
+			return nil, err
+		}
+	}
 
 	// 返回完整信息
 	result, err := s.repo.FindByID(ctx, apartment.ID)
 	if err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "serviced_apartment", result.ID, models.AuditActionCreate, nil, result)
 
 	return result.ToServicedApartmentDetailResponse(), nil
 }
@@ -128,6 +225,7 @@ func (s *ServicedApartmentService) UpdateServicedApartment(ctx context.Context,
 	if apartment.CompanyID != companyID {
 		return nil, errors.New("permission denied")
 	}
+	before := *apartment
 
 	// 更新字段
 	if req.Name != nil {
@@ -157,20 +255,54 @@ func (s *ServicedApartmentService) UpdateServicedApartment(ctx context.Context,
 	if req.Status != nil {
 		apartment.Status = *req.Status
 	}
+	req.Inclusions.ApplyTo(&apartment.Inclusions)
+	req.HouseRules.ApplyTo(&apartment.HouseRules)
+
+	if req.FacilityIDs != nil {
+		if err := s.checkFacilities(ctx, req.FacilityIDs); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.repo.Update(ctx, apartment); err != nil {
 		return nil, err
 	}
+	// 提供 facility_ids 时整体替换设施（空数组为清空）
+	if req.FacilityIDs != nil {
+		if err := s.repo.ReplaceFacilities(ctx, apartment.ID, req.FacilityIDs); err != nil {
+			return nil, err
+		}
+	}
 
 	// 返回更新后的信息
 	result, err := s.repo.FindByID(ctx, apartment.ID)
 	if err != nil {
 		return nil, err
 	}
+	s.auditService.Record(ctx, "serviced_apartment", id, models.AuditActionUpdate, &before, result)
 
 	return result.ToServicedApartmentDetailResponse(), nil
 }
 
+// checkFacilities 检查设施均存在于设施字典
+func (s *ServicedApartmentService) checkFacilities(ctx context.Context, facilityIDs []uint) error {
+	if len(facilityIDs) == 0 {
+		return nil
+	}
+	unique := make(map[uint]bool, len(facilityIDs))
+	for _, id := range facilityIDs {
+		unique[id] = true
+	}
+	count, err := s.repo.CountFacilities(ctx, facilityIDs)
+	if err != nil {
+		return err
+	}
+	if int(count) != len(unique) {
+		return tools.WrapError(400, "facility not found", tools.ErrInvalidInput)
+	}
+	return nil
+}
+
 // DeleteServicedApartment 删除服务式住宅
 func (s *ServicedApartmentService) DeleteServicedApartment(ctx context.Context, id uint, companyID uint) error {
 	// 查找现有记录
@@ -184,7 +316,11 @@ func (s *ServicedApartmentService) DeleteServicedApartment(ctx context.Context,
 		return errors.New("permission denied")
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "serviced_apartment", id, models.AuditActionDelete, apartment, nil)
+	return nil
 }
 
 // GetServicedApartmentUnits 获取房型列表
@@ -208,3 +344,153 @@ func (s *ServicedApartmentService) GetServicedApartmentImages(ctx context.Contex
 
 	return s.repo.FindImages(ctx, apartmentID)
 }
+
+// servicedApartmentImageTypes 服务式住宅图片类型
+var servicedApartmentImageTypes = []string{"exterior", "lobby", "room", "bathroom", "facilities"}
+
+// findManagedApartmentImages 校验管理权限（所属公司或管理员）并返回住宅整体图片
+func (s *ServicedApartmentService) findManagedApartmentImages(ctx context.Context, apartmentID uint, userID uint, userType string) ([]models.ServicedApartmentImage, error) {
+	apartment, err := s.repo.FindByID(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if apartment.CompanyID != userID && userType != "admin" {
+		return nil, errors.New("permission denied")
+	}
+	return s.repo.FindImages(ctx, apartmentID)
+}
+
+// AddServicedApartmentImage 添加住宅图片（追加到末尾，第一张自动作为封面）
+func (s *ServicedApartmentService) AddServicedApartmentImage(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.AddImageRequest) (*models.ServicedApartmentImage, error) {
+	images, err := s.findManagedApartmentImages(ctx, apartmentID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImageCount(len(images)); err != nil {
+		return nil, err
+	}
+
+	imageType := req.ImageType
+	if imageType == "" {
+		imageType = "exterior"
+	}
+	if err := checkImageType(imageType, servicedApartmentImageTypes...); err != nil {
+		return nil, err
+	}
+
+	source, err := s.mediaService.ResolveImage(ctx, req.ImageKey, req.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.ServicedApartmentImage{
+		ServicedApartmentID: &apartmentID,
+		ImageURL:            source.ImageURL,
+		ObjectKey:           source.ObjectKey,
+		ThumbnailURL:        source.ThumbnailURL,
+		ImageType:           imageType,
+		Title:               req.Title,
+		IsCover:             len(images) == 0,
+		SortOrder:           len(images),
+	}
+	if err := s.repo.CreateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_image", image.ID, models.AuditActionCreate, nil, image)
+	return image, nil
+}
+
+// UpdateServicedApartmentImage 更新住宅图片类型/标题
+func (s *ServicedApartmentService) UpdateServicedApartmentImage(ctx context.Context, apartmentID uint, imageID uint, userID uint, userType string, req *models.UpdateImageRequest) (*models.ServicedApartmentImage, error) {
+	if _, err := s.findManagedApartmentImages(ctx, apartmentID, userID, userType); err != nil {
+		return nil, err
+	}
+	image, err := s.repo.FindImage(ctx, apartmentID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	before := *image
+
+	if req.ImageType != nil {
+		if err := checkImageType(*req.ImageType, servicedApartmentImageTypes...); err != nil {
+			return nil, err
+		}
+		image.ImageType = *req.ImageType
+	}
+	if req.Title != nil {
+		image.Title = *req.Title
+	}
+
+	if err := s.repo.UpdateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_image", imageID, models.AuditActionUpdate, &before, image)
+	return image, nil
+}
+
+// DeleteServicedApartmentImage 删除住宅图片
+func (s *ServicedApartmentService) DeleteServicedApartmentImage(ctx context.Context, apartmentID uint, imageID uint, userID uint, userType string) error {
+	if _, err := s.findManagedApartmentImages(ctx, apartmentID, userID, userType); err != nil {
+		return err
+	}
+	image, err := s.repo.FindImage(ctx, apartmentID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteImage(ctx, apartmentID, imageID); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, "serviced_apartment_image", imageID, models.AuditActionDelete, image, nil)
+	return nil
+}
+
+// ReorderServicedApartmentImages 批量调整住宅图片顺序
+func (s *ServicedApartmentService) ReorderServicedApartmentImages(ctx context.Context, apartmentID uint, userID uint, userType string, req *models.ReorderImagesRequest) ([]models.ServicedApartmentImage, error) {
+	images, err := s.findManagedApartmentImages(ctx, apartmentID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(images))
+	for i, img := range images {
+		currentIDs[i] = img.ID
+	}
+	if err := checkImageOrder(currentIDs, req.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderImages(ctx, apartmentID, req.ImageIDs); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, apartmentID, images)
+}
+
+// SetServicedApartmentCoverImage 设置住宅封面图（新封面移到首位）
+func (s *ServicedApartmentService) SetServicedApartmentCoverImage(ctx context.Context, apartmentID uint, imageID uint, userID uint, userType string) ([]models.ServicedApartmentImage, error) {
+	images, err := s.findManagedApartmentImages(ctx, apartmentID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindImage(ctx, apartmentID, imageID); err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]uint, len(images))
+	for i, img := range images {
+		currentIDs[i] = img.ID
+	}
+	if err := s.repo.SetCoverImage(ctx, apartmentID, imageID, coverFirstOrder(currentIDs, imageID)); err != nil {
+		return nil, err
+	}
+	return s.imagesAfterChange(ctx, apartmentID, images)
+}
+
+// imagesAfterChange 重新查询住宅图片，并逐张记录排序及封面变更
+func (s *ServicedApartmentService) imagesAfterChange(ctx context.Context, apartmentID uint, before []models.ServicedApartmentImage) ([]models.ServicedApartmentImage, error) {
+	images, err := s.repo.FindImages(ctx, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.RecordEach(ctx, "serviced_apartment_image", models.AuditActionUpdate, before, images)
+	return images, nil
+}
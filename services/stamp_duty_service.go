@@ -0,0 +1,233 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+	"github.com/clutchtechnology/hk_ajoliving_app_go/tools"
+)
+
+// StampDutyService Methods:
+// 0. NewStampDutyService(policy *StampDutyPolicy) -> 注入交易费用规则
+// 1. Calculate(req *models.StampDutyRequest) -> 印花税及交易费用估算（按成交日期选用税率表）
+// 2. Estimate(price float64) -> 房产详情中的估算（默认买家类型，停用时返回 nil）
+
+// StampDutyBand 第 2 标准税率级别：Rate 大于 0 时按成交价乘以税率，否则按 Base 加上超出 MarginalFrom 部分乘以 MarginalRate（边际宽减）
+type StampDutyBand struct {
+	MaxAmount    float64 // 适用成交价上限，0 表示不限
+	Rate         float64 // 税率 (%)
+	Base         float64 // 定额税款
+	MarginalFrom float64 // 边际宽减起点
+	MarginalRate float64 // 边际税率 (%)
+}
+
+// StampDutyRateTable 印花税税率表（按生效日期选用）
+type StampDutyRateTable struct {
+	Version       string
+	EffectiveFrom string          // 生效日期（含），YYYY-MM-DD
+	Description   string          // 说明
+	Scale2        []StampDutyBand // 从价印花税第 2 标准税率
+	Scale1Rate    float64         // 住宅物业第 1 标准税率 (%)，0 表示所有买家均按第 2 标准
+	BSDRate       float64         // 买家印花税税率 (%)，0 表示不征收
+}
+
+// scale2Bands2023 2023-24 财政预算案调整后的第 2 标准税率
+var scale2Bands2023 = []StampDutyBand{
+	{MaxAmount: 3_000_000, Base: 100},
+	{MaxAmount: 3_528_240, Base: 100, MarginalFrom: 3_000_000, MarginalRate: 10},
+	{MaxAmount: 4_500_000, Rate: 1.5},
+	{MaxAmount: 4_935_480, Base: 67_500, MarginalFrom: 4_500_000, MarginalRate: 10},
+	{MaxAmount: 6_000_000, Rate: 2.25},
+	{MaxAmount: 6_642_860, Base: 135_000, MarginalFrom: 6_000_000, MarginalRate: 10},
+	{MaxAmount: 9_000_000, Rate: 3},
+	{MaxAmount: 10_080_000, Base: 270_000, MarginalFrom: 9_000_000, MarginalRate: 10},
+	{MaxAmount: 20_000_000, Rate: 3.75},
+	{MaxAmount: 21_739_120, Base: 750_000, MarginalFrom: 20_000_000, MarginalRate: 10},
+	{Rate: 4.25},
+}
+
+// scale2Bands2025 2025-26 财政预算案调整后的第 2 标准税率（400 万元或以下定额 100 元）
+var scale2Bands2025 = append([]StampDutyBand{
+	{MaxAmount: 4_000_000, Base: 100},
+	{MaxAmount: 4_323_780, Base: 100, MarginalFrom: 4_000_000, MarginalRate: 20},
+}, scale2Bands2023[2:]...)
+
+// stampDutyTables 税率表（按生效日期升序，新增版本追加在末尾）
+var stampDutyTables = []StampDutyRateTable{
+	{
+		Version:       "2023-02",
+		EffectiveFrom: "2023-02-22",
+		Description:   "2023-24 Budget: revised Scale 2 rates; Scale 1 15% and BSD 15% for residential property",
+		Scale2:        scale2Bands2023,
+		Scale1Rate:    15,
+		BSDRate:       15,
+	},
+	{
+		Version:       "2023-10",
+		EffectiveFrom: "2023-10-25",
+		Description:   "2023 Policy Address: Scale 1 and BSD for residential property halved to 7.5%",
+		Scale2:        scale2Bands2023,
+		Scale1Rate:    7.5,
+		BSDRate:       7.5,
+	},
+	{
+		Version:       "2024-02",
+		EffectiveFrom: "2024-02-28",
+		Description:   "2024-25 Budget: demand-side management measures cancelled; all residential buyers on Scale 2",
+		Scale2:        scale2Bands2023,
+	},
+	{
+		Version:       "2025-02",
+		EffectiveFrom: "2025-02-26",
+		Description:   "2025-26 Budget: HK$100 stamp duty extended to property up to HK$4,000,000",
+		Scale2:        scale2Bands2025,
+	},
+}
+
+// hongKongTime 香港时区（固定 UTC+8，无夏令时）
+var hongKongTime = time.FixedZone("HKT", 8*60*60)
+
+// StampDutyPolicy 交易费用规则
+type StampDutyPolicy struct {
+	AgencyCommissionRate float64 // 默认代理佣金 (%)
+	LegalFee             float64 // 默认律师费
+	EmbedEstimate        bool    // 出售房源详情是否附带估算
+}
+
+// LoadStampDutyPolicy 从环境变量加载交易费用规则
+func LoadStampDutyPolicy() *StampDutyPolicy {
+	return &StampDutyPolicy{
+		AgencyCommissionRate: tools.GetEnvFloat("STAMP_DUTY_AGENCY_COMMISSION_RATE", 1),
+		LegalFee:             tools.GetEnvFloat("STAMP_DUTY_LEGAL_FEE", 8000),
+		EmbedEstimate:        tools.GetEnvBool("STAMP_DUTY_EMBED_ESTIMATE", true),
+	}
+}
+
+// StampDutyService 印花税及交易费用估算服务
+type StampDutyService struct {
+	policy *StampDutyPolicy
+}
+
+// 0. NewStampDutyService 构造函数
+func NewStampDutyService(policy *StampDutyPolicy) *StampDutyService {
+	return &StampDutyService{policy: policy}
+}
+
+// 1. Calculate 印花税及交易费用估算
+func (s *StampDutyService) Calculate(req *models.StampDutyRequest) (*models.StampDutyResponse, error) {
+	buyerType := req.BuyerType
+	if buyerType == "" {
+		buyerType = models.BuyerTypeFirstTime
+	}
+	date := req.TransactionDate
+	if date == "" {
+		date = time.Now().In(hongKongTime).Format("2006-01-02")
+	}
+
+	table := findStampDutyTable(date)
+	if table == nil {
+		return nil, tools.WrapError(400, fmt.Sprintf("no stamp duty rate table before %s", stampDutyTables[0].EffectiveFrom), tools.ErrInvalidInput)
+	}
+
+	var duties []models.StampDutyItem
+	if table.Scale1Rate > 0 && buyerType != models.BuyerTypeFirstTime {
+		duties = append(duties, stampDutyItem(models.StampDutyAVD, "scale_1", req.Price, ceilDollar(req.Price*table.Scale1Rate/100)))
+	} else {
+		duties = append(duties, stampDutyItem(models.StampDutyAVD, "scale_2", req.Price, scale2Duty(table.Scale2, req.Price)))
+	}
+	if table.BSDRate > 0 && (buyerType == models.BuyerTypeNonPermanent || buyerType == models.BuyerTypeCompany) {
+		duties = append(duties, stampDutyItem(models.StampDutyBSD, "", req.Price, ceilDollar(req.Price*table.BSDRate/100)))
+	}
+
+	commissionRate := s.policy.AgencyCommissionRate
+	if req.AgencyCommissionRate != nil {
+		commissionRate = *req.AgencyCommissionRate
+	}
+	legalFee := s.policy.LegalFee
+	if req.LegalFee != nil {
+		legalFee = *req.LegalFee
+	}
+
+	result := &models.StampDutyResponse{
+		Price:           req.Price,
+		BuyerType:       buyerType,
+		TransactionDate: date,
+		RateTable: models.StampDutyTable{
+			Version:       table.Version,
+			EffectiveFrom: table.EffectiveFrom,
+			Description:   table.Description,
+		},
+		Duties:           duties,
+		AgencyCommission: round2(req.Price * commissionRate / 100),
+		LegalFee:         legalFee,
+	}
+	for _, duty := range duties {
+		result.TotalStampDuty += duty.Amount
+	}
+	result.TotalCost = round2(result.TotalStampDuty + result.AgencyCommission + result.LegalFee)
+	result.TotalOutlay = round2(req.Price + result.TotalCost)
+
+	return result, nil
+}
+
+// 2. Estimate 按默认买家类型（首次置业香港永久性居民）及默认费用估算（停用或成交价无效时返回 nil）
+func (s *StampDutyService) Estimate(price float64) *models.StampDutyEstimate {
+	if !s.policy.EmbedEstimate || price <= 0 {
+		return nil
+	}
+
+	result, err := s.Calculate(&models.StampDutyRequest{Price: price})
+	if err != nil {
+		return nil
+	}
+
+	return &models.StampDutyEstimate{
+		BuyerType:        result.BuyerType,
+		RateVersion:      result.RateTable.Version,
+		StampDuty:        result.TotalStampDuty,
+		AgencyCommission: result.AgencyCommission,
+		LegalFee:         result.LegalFee,
+		TotalCost:        result.TotalCost,
+	}
+}
+
+// findStampDutyTable 查找成交日期适用的税率表（早于首个版本时返回 nil）
+func findStampDutyTable(date string) *StampDutyRateTable {
+	for i := len(stampDutyTables) - 1; i >= 0; i-- {
+		if date >= stampDutyTables[i].EffectiveFrom {
+			return &stampDutyTables[i]
+		}
+	}
+	return nil
+}
+
+// scale2Duty 按第 2 标准税率计算从价印花税（不足一元作一元计）
+func scale2Duty(bands []StampDutyBand, price float64) float64 {
+	for _, band := range bands {
+		if band.MaxAmount > 0 && price > band.MaxAmount {
+			continue
+		}
+		if band.Rate > 0 {
+			return ceilDollar(price * band.Rate / 100)
+		}
+		return ceilDollar(band.Base + (price-band.MarginalFrom)*band.MarginalRate/100)
+	}
+	return 0
+}
+
+// stampDutyItem 单项印花税及实际税率
+func stampDutyItem(dutyType, scale string, price, amount float64) models.StampDutyItem {
+	return models.StampDutyItem{
+		Type:   dutyType,
+		Scale:  scale,
+		Amount: amount,
+		Rate:   round2(amount / price * 100),
+	}
+}
+
+// ceilDollar 不足一元作一元计（先取整至分，避免浮点误差多进一元）
+func ceilDollar(v float64) float64 {
+	return math.Ceil(round2(v))
+}
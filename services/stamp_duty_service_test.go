@@ -0,0 +1,153 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
+)
+
+func newTestStampDutyService() *StampDutyService {
+	return NewStampDutyService(&StampDutyPolicy{AgencyCommissionRate: 1, LegalFee: 8000, EmbedEstimate: true})
+}
+
+// 税务局公布的计算例子
+func TestStampDutyCalculatePublishedExamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		price   float64
+		date    string
+		version string
+		want    float64
+	}{
+		{name: "HK$5,000,000 at 2.25%", price: 5_000_000, date: "2024-06-01", version: "2024-02", want: 112_500},
+		{name: "HK$4,200,000 marginal relief after 2025-02-26", price: 4_200_000, date: "2025-03-01", version: "2025-02", want: 40_100},
+		{name: "HK$10,000,000 marginal relief in 2024", price: 10_000_000, date: "2024-06-01", version: "2024-02", want: 370_000},
+	}
+	svc := newTestStampDutyService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.Calculate(&models.StampDutyRequest{Price: tt.price, TransactionDate: tt.date})
+			if err != nil {
+				t.Fatalf("Calculate() error: %v", err)
+			}
+			if result.RateTable.Version != tt.version {
+				t.Errorf("rate table = %s, want %s", result.RateTable.Version, tt.version)
+			}
+			if result.TotalStampDuty != tt.want {
+				t.Errorf("stamp duty = %.0f, want %.0f", result.TotalStampDuty, tt.want)
+			}
+		})
+	}
+}
+
+// 边际宽减级别的上下限须与相邻税率衔接
+func TestScale2DutyBandEdges(t *testing.T) {
+	tests := []struct {
+		bands []StampDutyBand
+		price float64
+		want  float64
+	}{
+		{scale2Bands2023, 3_000_000, 100},
+		{scale2Bands2023, 3_000_001, 101},
+		{scale2Bands2023, 3_528_240, 52_924},
+		{scale2Bands2023, 3_528_241, 52_924},
+		{scale2Bands2023, 4_500_000, 67_500},
+		{scale2Bands2023, 4_935_480, 111_048},
+		{scale2Bands2023, 4_935_481, 111_049},
+		{scale2Bands2023, 20_000_000, 750_000},
+		{scale2Bands2023, 21_739_120, 923_912},
+		{scale2Bands2023, 21_739_121, 923_913},
+		{scale2Bands2025, 4_000_000, 100},
+		{scale2Bands2025, 4_323_780, 64_856},
+		{scale2Bands2025, 4_323_781, 64_857},
+	}
+	for _, tt := range tests {
+		if got := scale2Duty(tt.bands, tt.price); got != tt.want {
+			t.Errorf("scale2Duty(%.0f) = %.0f, want %.0f", tt.price, got, tt.want)
+		}
+	}
+}
+
+// 2024-02-28 前非首次置业适用第 1 标准税率，非永久性居民及公司另需缴付买家印花税
+func TestStampDutyCalculateScale1AndBSD(t *testing.T) {
+	tests := []struct {
+		name      string
+		buyerType string
+		date      string
+		price     float64
+		duties    map[string]float64
+		scale     string
+	}{
+		{name: "first time before 2024-02-28", buyerType: models.BuyerTypeFirstTime, date: "2023-11-01", price: 5_000_000,
+			duties: map[string]float64{models.StampDutyAVD: 112_500}, scale: "scale_2"},
+		{name: "existing owner at 7.5%", buyerType: models.BuyerTypeExistingOwner, date: "2023-11-01", price: 5_000_000,
+			duties: map[string]float64{models.StampDutyAVD: 375_000}, scale: "scale_1"},
+		{name: "non-permanent at 7.5% plus BSD", buyerType: models.BuyerTypeNonPermanent, date: "2023-11-01", price: 5_000_000,
+			duties: map[string]float64{models.StampDutyAVD: 375_000, models.StampDutyBSD: 375_000}, scale: "scale_1"},
+		{name: "company at 15% plus BSD", buyerType: models.BuyerTypeCompany, date: "2023-03-01", price: 10_000_000,
+			duties: map[string]float64{models.StampDutyAVD: 1_500_000, models.StampDutyBSD: 1_500_000}, scale: "scale_1"},
+		{name: "last day before measures cancelled", buyerType: models.BuyerTypeNonPermanent, date: "2024-02-27", price: 8_000_000,
+			duties: map[string]float64{models.StampDutyAVD: 600_000, models.StampDutyBSD: 600_000}, scale: "scale_1"},
+		{name: "non-permanent from 2024-02-28", buyerType: models.BuyerTypeNonPermanent, date: "2024-02-28", price: 8_000_000,
+			duties: map[string]float64{models.StampDutyAVD: 240_000}, scale: "scale_2"},
+	}
+	svc := newTestStampDutyService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.Calculate(&models.StampDutyRequest{Price: tt.price, BuyerType: tt.buyerType, TransactionDate: tt.date})
+			if err != nil {
+				t.Fatalf("Calculate() error: %v", err)
+			}
+			if len(result.Duties) != len(tt.duties) {
+				t.Fatalf("duties = %+v, want %v", result.Duties, tt.duties)
+			}
+			var total float64
+			for _, duty := range result.Duties {
+				if duty.Amount != tt.duties[duty.Type] {
+					t.Errorf("%s = %.0f, want %.0f", duty.Type, duty.Amount, tt.duties[duty.Type])
+				}
+				if duty.Type == models.StampDutyAVD && duty.Scale != tt.scale {
+					t.Errorf("AVD scale = %s, want %s", duty.Scale, tt.scale)
+				}
+				total += duty.Amount
+			}
+			if result.TotalStampDuty != total {
+				t.Errorf("total stamp duty = %.0f, want %.0f", result.TotalStampDuty, total)
+			}
+		})
+	}
+}
+
+func TestStampDutyCalculateFees(t *testing.T) {
+	svc := newTestStampDutyService()
+	rate, fee := 0.5, 5000.0
+	result, err := svc.Calculate(&models.StampDutyRequest{Price: 5_000_000, TransactionDate: "2025-03-01", AgencyCommissionRate: &rate, LegalFee: &fee})
+	if err != nil {
+		t.Fatalf("Calculate() error: %v", err)
+	}
+	if result.AgencyCommission != 25_000 || result.LegalFee != 5000 {
+		t.Errorf("commission = %.2f, legal fee = %.2f", result.AgencyCommission, result.LegalFee)
+	}
+	if result.TotalCost != 142_500 || result.TotalOutlay != 5_142_500 {
+		t.Errorf("total cost = %.2f, total outlay = %.2f", result.TotalCost, result.TotalOutlay)
+	}
+
+	if _, err := svc.Calculate(&models.StampDutyRequest{Price: 5_000_000, TransactionDate: "2023-02-21"}); err == nil {
+		t.Error("expected error for a date before the first rate table")
+	}
+}
+
+func TestStampDutyEstimate(t *testing.T) {
+	estimate := newTestStampDutyService().Estimate(5_000_000)
+	if estimate == nil || estimate.BuyerType != models.BuyerTypeFirstTime || estimate.StampDuty != 112_500 {
+		t.Errorf("Estimate() = %+v", estimate)
+	}
+
+	disabled := NewStampDutyService(&StampDutyPolicy{EmbedEstimate: false})
+	if got := disabled.Estimate(5_000_000); got != nil {
+		t.Errorf("Estimate() with embedding disabled = %+v, want nil", got)
+	}
+	if got := newTestStampDutyService().Estimate(0); got != nil {
+		t.Errorf("Estimate(0) = %+v, want nil", got)
+	}
+}
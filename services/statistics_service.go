@@ -110,6 +110,57 @@ func (s *StatisticsService) GetTransactionStatistics(ctx context.Context, req *m
 	return stats, nil
 }
 
+// GetFirstHandStatistics 获取一手成交统计（未指定开始日期时月度趋势取最近12个月）
+func (s *StatisticsService) GetFirstHandStatistics(ctx context.Context, req *models.GetFirstHandStatisticsRequest) (*models.FirstHandStatisticsResponse, error) {
+	var startDate, endDate *time.Time
+
+	// 解析日期（成交日期按香港时间记录）
+	if req.StartDate != nil {
+		t, err := time.ParseInLocation("2006-01-02", *req.StartDate, hongKongTime)
+		if err == nil {
+			startDate = &t
+		}
+	}
+	if req.EndDate != nil {
+		t, err := time.ParseInLocation("2006-01-02", *req.EndDate, hongKongTime)
+		if err == nil {
+			endDate = &t
+		}
+	}
+
+	stats, err := s.repo.GetFirstHandStatistics(ctx, startDate, endDate, req.DistrictID)
+	if err != nil {
+		return nil, err
+	}
+
+	trendStart := startDate
+	if trendStart == nil {
+		now := time.Now().In(hongKongTime)
+		t := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, hongKongTime).AddDate(0, -11, 0)
+		trendStart = &t
+	}
+	transactions, err := s.repo.FindFirstHandTransactions(ctx, trendStart, endDate, req.DistrictID)
+	if err != nil {
+		return nil, err
+	}
+	stats.MonthlyTrend = buildFirstHandMonthlyTrend(transactions)
+
+	stats.AvgPrice = round2(stats.AvgPrice)
+	stats.AvgPricePerSqft = round2(stats.AvgPricePerSqft)
+	stats.PriceRealised = round2(stats.PriceRealised)
+	for i := range stats.Developments {
+		stats.Developments[i].AvgPricePerSqft = round2(stats.Developments[i].AvgPricePerSqft)
+		stats.Developments[i].SellThroughRate = round2(stats.Developments[i].SellThroughRate)
+	}
+
+	// 设置时间范围
+	stats.StartDate = req.StartDate
+	stats.EndDate = req.EndDate
+	stats.DistrictID = req.DistrictID
+
+	return stats, nil
+}
+
 // GetUserStatistics 获取用户统计
 func (s *StatisticsService) GetUserStatistics(ctx context.Context, req *models.GetUserStatisticsRequest) (*models.UserStatisticsResponse, error) {
 	var startDate, endDate *time.Time
@@ -139,3 +190,25 @@ func (s *StatisticsService) GetUserStatistics(ctx context.Context, req *models.G
 
 	return stats, nil
 }
+
+// buildFirstHandMonthlyTrend 按香港时间月份汇总一手成交（月份倒序，与成交统计一致）
+func buildFirstHandMonthlyTrend(transactions []models.NewPropertyTransaction) []models.MonthlyTransactionStat {
+	trend := make([]models.MonthlyTransactionStat, 0)
+	for _, transaction := range transactions {
+		month := transaction.TransactionDate.In(hongKongTime).Format("2006-01")
+		if len(trend) == 0 || trend[len(trend)-1].Month != month {
+			trend = append(trend, models.MonthlyTransactionStat{Month: month})
+		}
+		stat := &trend[len(trend)-1]
+		stat.TransactionCount++
+		stat.TotalValue += transaction.TransactionPrice
+	}
+
+	for i, j := 0, len(trend)-1; i < j; i, j = i+1, j-1 {
+		trend[i], trend[j] = trend[j], trend[i]
+	}
+	for i := range trend {
+		trend[i].AvgPrice = round2(trend[i].TotalValue / float64(trend[i].TransactionCount))
+	}
+	return trend
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
@@ -9,15 +10,17 @@ import (
 
 // UserService 用户服务
 type UserService struct {
-	userRepo     *databases.UserRepo
-	propertyRepo *databases.PropertyRepo
+	userRepo         *databases.UserRepo
+	propertyRepo     *databases.PropertyRepo
+	notificationRepo *databases.NotificationRepo
 }
 
 // NewUserService 创建用户服务
-func NewUserService(userRepo *databases.UserRepo, propertyRepo *databases.PropertyRepo) *UserService {
+func NewUserService(userRepo *databases.UserRepo, propertyRepo *databases.PropertyRepo, notificationRepo *databases.NotificationRepo) *UserService {
 	return &UserService{
-		userRepo:     userRepo,
-		propertyRepo: propertyRepo,
+		userRepo:         userRepo,
+		propertyRepo:     propertyRepo,
+		notificationRepo: notificationRepo,
 	}
 }
 
@@ -69,3 +72,37 @@ func (s *UserService) GetUserListings(ctx context.Context, userID uint, listingT
 		"furniture":  []interface{}{}, // 家具模块后续实现
 	}, nil
 }
+
+// GetNotifications 获取用户站内通知
+func (s *UserService) GetNotifications(ctx context.Context, userID uint, req *models.ListNotificationsRequest) (*models.PaginatedNotificationsResponse, error) {
+	notifications, total, err := s.notificationRepo.FindByUser(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	unreadCount, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedNotificationsResponse{
+		Data:        notifications,
+		UnreadCount: unreadCount,
+		Total:       total,
+		Page:        req.Page,
+		PageSize:    req.PageSize,
+		TotalPages:  databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// MarkNotificationRead 标记通知为已读
+func (s *UserService) MarkNotificationRead(ctx context.Context, userID uint, id uint) error {
+	affected, err := s.notificationRepo.MarkAsRead(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("notification not found")
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/clutchtechnology/hk_ajoliving_app_go/databases"
 	"github.com/clutchtechnology/hk_ajoliving_app_go/models"
@@ -12,12 +14,16 @@ import (
 
 // ValuationService 估价服务
 type ValuationService struct {
-	repo *databases.ValuationRepo
+	repo              *databases.ValuationRepo
+	yieldWindowMonths int // 租金回报率计入多少个月内的成交
 }
 
 // NewValuationService 创建估价服务
 func NewValuationService(repo *databases.ValuationRepo) *ValuationService {
-	return &ValuationService{repo: repo}
+	return &ValuationService{
+		repo:              repo,
+		yieldWindowMonths: tools.GetEnvInt("YIELD_WINDOW_MONTHS", 12),
+	}
 }
 
 // ListValuations 获取屋苑估价列表
@@ -87,3 +93,110 @@ func (s *ValuationService) GetDistrictValuations(ctx context.Context, districtID
 
 	return summary, nil
 }
+
+// GetYieldRanking 屋苑户型租金回报率排行
+func (s *ValuationService) GetYieldRanking(ctx context.Context, req *models.YieldRankingRequest) (*models.PaginatedYieldRankingResponse, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if req.MinSamples == 0 {
+		req.MinSamples = 1
+	}
+
+	items, total, err := s.repo.FindYieldRanking(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedYieldRankingResponse{
+		Data:       items,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: databases.CalculateTotalPages(total, req.PageSize),
+	}, nil
+}
+
+// RecomputeEstateYields 重算各屋苑户型租金回报率（定时任务）
+func (s *ValuationService) RecomputeEstateYields(ctx context.Context) error {
+	estates, err := s.repo.FindEstatesForYield(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, -s.yieldWindowMonths, 0)
+
+	var failed int
+	var lastErr error
+	for _, estate := range estates {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// 单个屋苑失败不影响其他屋苑
+		samples, err := s.repo.FindYieldSamples(ctx, estate.Name, since)
+		if err == nil {
+			yields, overall := matchEstateYields(estate.ID, samples, now)
+			err = s.repo.SaveEstateYields(ctx, estate.ID, yields, overall, now)
+		}
+		if err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d estates failed to update yield, last error: %w", failed, len(estates), lastErr)
+	}
+	return nil
+}
+
+// matchEstateYields 按户型配对出售及出租样本计算毛租金回报率（以每平方尺价格配对，抵消面积差异）；
+// 整体回报率为各户型回报率按样本数（出售与出租较少者）加权平均，无可配对户型时为 0
+func matchEstateYields(estateID uint, samples []models.YieldSample, now time.Time) ([]models.EstateYield, float64) {
+	sales := make(map[int]models.YieldSample)
+	rents := make(map[int]models.YieldSample)
+	for _, sample := range samples {
+		switch sample.ListingType {
+		case "sale":
+			sales[sample.Bedrooms] = sample
+		case "rent":
+			rents[sample.Bedrooms] = sample
+		}
+	}
+
+	var yields []models.EstateYield
+	var weightedSum, totalWeight float64
+	for bedrooms, sale := range sales {
+		rent, ok := rents[bedrooms]
+		if !ok || sale.AvgPricePerSqft <= 0 {
+			continue
+		}
+
+		grossYield := rent.AvgPricePerSqft * 12 / sale.AvgPricePerSqft * 100
+		yields = append(yields, models.EstateYield{
+			EstateID:            estateID,
+			Bedrooms:            bedrooms,
+			AvgSalePrice:        round2(sale.AvgPrice),
+			AvgMonthlyRent:      round2(rent.AvgPrice),
+			AvgSalePricePerSqft: round2(sale.AvgPricePerSqft),
+			AvgRentPerSqft:      round2(rent.AvgPricePerSqft),
+			GrossYield:          round2(grossYield),
+			SaleSamples:         sale.Count,
+			RentSamples:         rent.Count,
+			UpdatedAt:           now,
+		})
+
+		weight := float64(min(sale.Count, rent.Count))
+		weightedSum += grossYield * weight
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return yields, 0
+	}
+	return yields, round2(weightedSum / totalWeight)
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+)
+
+// 请求上下文中携带的信息（供服务层记录审计日志等使用）
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	actorKey
+)
+
+// Actor 操作者
+type Actor struct {
+	UserID   uint
+	UserType string
+}
+
+// WithRequestID 将请求ID写入上下文
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext 从上下文读取请求ID，不存在时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithActor 将操作者写入上下文
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext 从上下文读取操作者，未认证的请求及定时任务返回 false
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey).(Actor)
+	return actor, ok
+}
@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 定时任务调度规则
+type Schedule interface {
+	// Next 返回 t 之后的下一次执行时间
+	Next(t time.Time) time.Time
+	// String 返回调度规则描述
+	String() string
+}
+
+// intervalSchedule 固定间隔调度
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+func (s intervalSchedule) String() string {
+	return "@every " + s.interval.String()
+}
+
+// CronSchedule 标准 5 段 cron 表达式（分 时 日 月 周）
+type CronSchedule struct {
+	spec   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// 日/周 均非 * 时按 cron 惯例取并集
+	domStar bool
+	dowStar bool
+}
+
+// cron 各字段取值范围
+var cronFieldBounds = [5][2]int{
+	{0, 59}, // 分
+	{0, 23}, // 时
+	{1, 31}, // 日
+	{1, 12}, // 月
+	{0, 6},  // 周（0=周日，7 也视为周日）
+}
+
+// cron 预设别名
+var cronAliases = map[string]string{
+	"@yearly":  "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly":  "0 0 * * 0",
+	"@daily":   "0 0 * * *",
+	"@hourly":  "0 * * * *",
+}
+
+// ParseSchedule 解析调度规则，支持 5 段 cron 表达式、@daily 等别名和 "@every 10m"
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval in %q", spec)
+		}
+		return intervalSchedule{interval: interval}, nil
+	}
+	return ParseCron(spec)
+}
+
+// ParseCron 解析 5 段 cron 表达式，每段支持 *、数字、a-b、*/n、a-b/n 和逗号分隔列表
+func ParseCron(spec string) (*CronSchedule, error) {
+	expr := spec
+	if alias, ok := cronAliases[spec]; ok {
+		expr = alias
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron spec %q: expected 5 fields", spec)
+	}
+
+	var bits [5]uint64
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFieldBounds[i][0], cronFieldBounds[i][1], i == 4)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q: %w", spec, err)
+		}
+		bits[i] = b
+	}
+
+	return &CronSchedule{
+		spec:    spec,
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField 解析单个字段为位图
+func parseCronField(field string, min, max int, isDow bool) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo = n
+			hi = n
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		// 周字段允许 7 表示周日
+		if isDow && hi == 7 {
+			if lo == 7 {
+				lo = 0
+				hi = 0
+			} else {
+				hi = 6
+				bits |= 1 // 7 即周日
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range in %q", part)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next 返回 t 之后的下一次执行时间（精确到分钟）
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// 最多向后查找 5 年，防止无法满足的表达式（如 2 月 30 日）死循环
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断日期是否满足 日/周 字段
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// String 返回原始 cron 表达式
+func (s *CronSchedule) String() string {
+	return s.spec
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetEnv 获取环境变量，如果不存在则返回默认值
+func GetEnv(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvInt 获取整数类型环境变量，不存在或格式错误时返回默认值
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvFloat 获取浮点类型环境变量，不存在或格式错误时返回默认值
+func GetEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvBool 获取布尔类型环境变量，不存在或格式错误时返回默认值
+func GetEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -15,6 +15,16 @@ var (
 	ErrInternalServer = errors.New("internal server error")
 	ErrInvalidToken   = errors.New("invalid token")
 	ErrExpiredToken   = errors.New("token expired")
+
+	ErrRenewalLimitReached = errors.New("renewal limit reached")
+	ErrNotRenewable        = errors.New("listing cannot be renewed")
+
+	ErrFileTooLarge = errors.New("file too large")
+
+	ErrNotSubmittable = errors.New("listing cannot be submitted for review")
+	ErrReviewResolved = errors.New("review already resolved")
+	ErrNotPublishable = errors.New("listing cannot be published")
+	ErrNotScheduled   = errors.New("listing is not scheduled for publishing")
 )
 
 // BusinessError 业务错误
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IDFormat 业务编号格式
+// 支持的占位符：
+//
+//	{prefix}  前缀
+//	{yyyy}    四位年份，{yy} 两位年份，{mm} 月，{dd} 日
+//	{seq:N}   序号（至少 N 位，不足补零），必须且只能出现一次
+//	{check}   校验位（Luhn 算法，对其之前的所有数字计算），只能位于末尾
+//
+// 序号按格式中最细的日期粒度分段：含 {dd} 时每日重置，含 {mm} 时每月重置，仅含年份时每年重置
+type IDFormat struct {
+	Prefix  string
+	Pattern string
+
+	head     string // 序号之前的格式片段
+	tail     string // 序号之后的格式片段（不含校验位）
+	check    bool   // 是否带校验位
+	seqWidth int
+	period   string // 序号分段的日期格式（Go layout），为空表示不分段
+}
+
+var (
+	idTokenPattern = regexp.MustCompile(`\{[a-z]+(?::\d+)?\}`)
+	idSeqPattern   = regexp.MustCompile(`\{seq:(\d+)\}`)
+)
+
+// ParseIDFormat 解析编号格式
+func ParseIDFormat(prefix, pattern string) (*IDFormat, error) {
+	f := &IDFormat{Prefix: prefix, Pattern: pattern}
+
+	seqs := idSeqPattern.FindAllStringSubmatchIndex(pattern, -1)
+	if len(seqs) != 1 {
+		return nil, fmt.Errorf("format %q must contain exactly one {seq:N}", pattern)
+	}
+	f.seqWidth, _ = strconv.Atoi(pattern[seqs[0][2]:seqs[0][3]])
+	if f.seqWidth < 1 || f.seqWidth > 18 {
+		return nil, fmt.Errorf("invalid sequence width in %q", pattern)
+	}
+	f.head = pattern[:seqs[0][0]]
+	f.tail = pattern[seqs[0][1]:]
+
+	if strings.Contains(pattern, "{check}") {
+		if !strings.HasSuffix(pattern, "{check}") || strings.Count(pattern, "{check}") > 1 {
+			return nil, fmt.Errorf("{check} must appear once at the end of %q", pattern)
+		}
+		f.check = true
+		f.tail = strings.TrimSuffix(f.tail, "{check}")
+	}
+
+	for _, token := range idTokenPattern.FindAllString(pattern, -1) {
+		switch token {
+		case "{prefix}", "{check}":
+		case "{yyyy}", "{yy}":
+			if f.period == "" {
+				f.period = "2006"
+			}
+		case "{mm}":
+			if f.period != "20060102" {
+				f.period = "200601"
+			}
+		case "{dd}":
+			f.period = "20060102"
+		default:
+			if !idSeqPattern.MatchString(token) {
+				return nil, fmt.Errorf("unknown token %q in %q", token, pattern)
+			}
+		}
+	}
+	return f, nil
+}
+
+// LoadIDFormat 从环境变量加载编号格式（{name}_PREFIX、{name}_FORMAT），格式无效时使用默认格式
+// 默认格式本身无效时返回错误（由启动流程处理）
+func LoadIDFormat(name, defaultPrefix, defaultPattern string) (*IDFormat, error) {
+	prefix := GetEnv(name+"_PREFIX", defaultPrefix)
+	pattern := GetEnv(name+"_FORMAT", defaultPattern)
+
+	f, err := ParseIDFormat(prefix, pattern)
+	if err != nil {
+		log.Printf("⚠️  Invalid %s_FORMAT (%v), using default %q", name, err, defaultPattern)
+		if f, err = ParseIDFormat(prefix, defaultPattern); err != nil {
+			return nil, fmt.Errorf("invalid default %s format: %w", name, err)
+		}
+	}
+	return f, nil
+}
+
+// Period 返回序号分段标识（如 2026、202610、20261018），不分段时为空
+func (f *IDFormat) Period(now time.Time) string {
+	if f.period == "" {
+		return ""
+	}
+	return now.Format(f.period)
+}
+
+// Render 生成编号
+func (f *IDFormat) Render(now time.Time, seq int64) string {
+	id := f.renderPart(f.head, now) + fmt.Sprintf("%0*d", f.seqWidth, seq) + f.renderPart(f.tail, now)
+	if f.check {
+		id += string(LuhnCheckDigit(id))
+	}
+	return id
+}
+
+// SeqPosition 返回该日期分段内编号中序号部分的起始下标、最小位数及编号长度（用于解析已有编号）
+func (f *IDFormat) SeqPosition(now time.Time) (start, width, length int) {
+	start = len(f.renderPart(f.head, now))
+	length = start + f.seqWidth + len(f.renderPart(f.tail, now))
+	if f.check {
+		length++
+	}
+	return start, f.seqWidth, length
+}
+
+// renderPart 渲染不含序号的格式片段
+func (f *IDFormat) renderPart(part string, now time.Time) string {
+	return idTokenPattern.ReplaceAllStringFunc(part, func(token string) string {
+		switch token {
+		case "{prefix}":
+			return f.Prefix
+		case "{yyyy}":
+			return now.Format("2006")
+		case "{yy}":
+			return now.Format("06")
+		case "{mm}":
+			return now.Format("01")
+		case "{dd}":
+			return now.Format("02")
+		}
+		return token
+	})
+}
+
+// LuhnCheckDigit 计算 Luhn 校验位（忽略非数字字符）
+func LuhnCheckDigit(s string) byte {
+	sum := 0
+	double := true // 从右往左，紧邻校验位的数字需要加倍
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
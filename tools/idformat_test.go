@@ -0,0 +1,207 @@
+package tools
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+var idFormatTestDate = time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+func TestParseIDFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		period  string
+		wantErr bool
+	}{
+		{name: "no period", pattern: "{prefix}{seq:8}", period: ""},
+		{name: "yearly", pattern: "{prefix}{yy}{seq:4}", period: "2026"},
+		{name: "monthly", pattern: "{prefix}{yyyy}{mm}{seq:4}", period: "202610"},
+		{name: "daily", pattern: "{prefix}{yyyy}{mm}{dd}{seq:6}", period: "20261018"},
+		{name: "daily before month", pattern: "{dd}{mm}{seq:3}", period: "20261018"},
+		{name: "check digit", pattern: "{prefix}{seq:6}{check}", period: ""},
+		{name: "missing seq", pattern: "{prefix}{yyyy}", wantErr: true},
+		{name: "two seqs", pattern: "{seq:3}{seq:3}", wantErr: true},
+		{name: "zero width", pattern: "{prefix}{seq:0}", wantErr: true},
+		{name: "too wide", pattern: "{prefix}{seq:19}", wantErr: true},
+		{name: "check not at end", pattern: "{prefix}{check}{seq:4}", wantErr: true},
+		{name: "unknown token", pattern: "{prefix}{hh}{seq:4}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseIDFormat("P", tt.pattern)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseIDFormat(%q) expected error", tt.pattern)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIDFormat(%q) error: %v", tt.pattern, err)
+			}
+			if got := f.Period(idFormatTestDate); got != tt.period {
+				t.Errorf("Period() = %q, want %q", got, tt.period)
+			}
+		})
+	}
+}
+
+func TestIDFormatRender(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		pattern string
+		seq     int64
+		want    string
+	}{
+		{prefix: "P", pattern: "{prefix}{seq:8}", seq: 42, want: "P00000042"},
+		{prefix: "FUR", pattern: "{prefix}{yyyy}{mm}{dd}{seq:6}", seq: 7, want: "FUR20261018000007"},
+		{prefix: "A", pattern: "{prefix}-{yy}-{seq:3}", seq: 1234, want: "A-26-1234"},
+		{prefix: "A", pattern: "{prefix}{yy}{seq:4}{check}", seq: 7, want: "A2600070"},
+	}
+	for _, tt := range tests {
+		f, err := ParseIDFormat(tt.prefix, tt.pattern)
+		if err != nil {
+			t.Fatalf("ParseIDFormat(%q) error: %v", tt.pattern, err)
+		}
+		if got := f.Render(idFormatTestDate, tt.seq); got != tt.want {
+			t.Errorf("Render(%q, %d) = %q, want %q", tt.pattern, tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestIDFormatSeqPosition(t *testing.T) {
+	tests := []struct {
+		prefix               string
+		pattern              string
+		start, width, length int
+	}{
+		{prefix: "P", pattern: "{prefix}{seq:8}", start: 1, width: 8, length: 9},
+		{prefix: "FUR", pattern: "{prefix}{yyyy}{mm}{dd}{seq:6}", start: 11, width: 6, length: 17},
+		{prefix: "A", pattern: "{prefix}{yy}{seq:4}{check}", start: 3, width: 4, length: 8},
+		{prefix: "X", pattern: "{prefix}{seq:3}-{yyyy}", start: 1, width: 3, length: 9},
+	}
+	for _, tt := range tests {
+		f, err := ParseIDFormat(tt.prefix, tt.pattern)
+		if err != nil {
+			t.Fatalf("ParseIDFormat(%q) error: %v", tt.pattern, err)
+		}
+		start, width, length := f.SeqPosition(idFormatTestDate)
+		if start != tt.start || width != tt.width || length != tt.length {
+			t.Errorf("SeqPosition(%q) = (%d, %d, %d), want (%d, %d, %d)", tt.pattern, start, width, length, tt.start, tt.width, tt.length)
+		}
+		// 序号部分须能从渲染结果中原样取回
+		id := f.Render(idFormatTestDate, 12)
+		if len(id) != length {
+			t.Errorf("Render(%q) length = %d, want %d", tt.pattern, len(id), length)
+		}
+		if got, want := id[start:start+width], fmt.Sprintf("%0*d", width, 12); got != want {
+			t.Errorf("sequence part of %q = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want byte
+	}{
+		{in: "7992739871", want: '3'},
+		{in: "453914880343646", want: '7'},
+		{in: "0", want: '0'},
+		{in: "", want: '0'},
+		{in: "A-79-927-39871", want: '3'}, // 忽略非数字字符
+	}
+	for _, tt := range tests {
+		if got := LuhnCheckDigit(tt.in); got != tt.want {
+			t.Errorf("LuhnCheckDigit(%q) = %c, want %c", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadIDFormatEnvOverrides(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		f, err := LoadIDFormat("TEST_ID_DEFAULT", "P", "{prefix}{seq:8}")
+		if err != nil {
+			t.Fatalf("LoadIDFormat() error = %v", err)
+		}
+		if got := f.Render(idFormatTestDate, 3); got != "P00000003" {
+			t.Errorf("Render() = %q, want %q", got, "P00000003")
+		}
+	})
+
+	t.Run("prefix and format", func(t *testing.T) {
+		t.Setenv("TEST_ID_CUSTOM_PREFIX", "HK")
+		t.Setenv("TEST_ID_CUSTOM_FORMAT", "{prefix}{yy}{mm}{seq:5}{check}")
+		f, err := LoadIDFormat("TEST_ID_CUSTOM", "P", "{prefix}{seq:8}")
+		if err != nil {
+			t.Fatalf("LoadIDFormat() error = %v", err)
+		}
+		if f.Prefix != "HK" {
+			t.Errorf("Prefix = %q, want %q", f.Prefix, "HK")
+		}
+		if got := f.Period(idFormatTestDate); got != "202610" {
+			t.Errorf("Period() = %q, want %q", got, "202610")
+		}
+		id := f.Render(idFormatTestDate, 9)
+		if id[:len(id)-1] != "HK261000009" {
+			t.Errorf("Render() = %q, want prefix %q", id, "HK261000009")
+		}
+		if id[len(id)-1] != LuhnCheckDigit(id[:len(id)-1]) {
+			t.Errorf("Render() = %q has wrong check digit", id)
+		}
+	})
+
+	t.Run("invalid format falls back to default", func(t *testing.T) {
+		t.Setenv("TEST_ID_BAD_PREFIX", "Q")
+		t.Setenv("TEST_ID_BAD_FORMAT", "{prefix}{yyyy}")
+		f, err := LoadIDFormat("TEST_ID_BAD", "P", "{prefix}{seq:8}")
+		if err != nil {
+			t.Fatalf("LoadIDFormat() error = %v", err)
+		}
+		if got := f.Render(idFormatTestDate, 5); got != "Q00000005" {
+			t.Errorf("Render() = %q, want %q", got, "Q00000005")
+		}
+	})
+
+	t.Run("invalid default format", func(t *testing.T) {
+		if _, err := LoadIDFormat("TEST_ID_NODEFAULT", "P", "{prefix}{yyyy}"); err == nil {
+			t.Error("LoadIDFormat() error = nil, want error")
+		}
+	})
+}
+
+// TestIDFormatRenderConcurrent 同一编号格式被并发请求共享：不同序号渲染结果互不相同
+func TestIDFormatRenderConcurrent(t *testing.T) {
+	f, err := ParseIDFormat("HK", "{prefix}{yyyy}{mm}{seq:6}{check}")
+	if err != nil {
+		t.Fatalf("ParseIDFormat() error = %v", err)
+	}
+
+	const workers, perWorker = 20, 50
+	ids := make(chan string, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				ids <- f.Render(idFormatTestDate, int64(w*perWorker+i+1))
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool, workers*perWorker)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate id %q", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != workers*perWorker {
+		t.Errorf("got %d ids, want %d", len(seen), workers*perWorker)
+	}
+}
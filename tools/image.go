@@ -0,0 +1,291 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+)
+
+// 图片处理错误
+var (
+	ErrUnsupportedImageType = errors.New("unsupported image type, only JPEG and PNG are allowed")
+	ErrInvalidImage         = errors.New("invalid or corrupted image")
+)
+
+// ImageOptions 图片处理参数
+type ImageOptions struct {
+	MaxDimension   int // 原图最长边上限（超出则等比缩小）
+	ThumbDimension int // 缩略图最长边
+	JPEGQuality    int // JPEG 编码质量
+}
+
+// ProcessedImage 处理后的图片
+type ProcessedImage struct {
+	Data        []byte // 处理后的原图（已去除 EXIF 等元数据）
+	Thumbnail   []byte // 缩略图
+	ContentType string
+	Ext         string // 文件扩展名（不含点）
+	Width       int
+	Height      int
+}
+
+// DetectImageType 根据文件内容检测图片 MIME 类型，仅允许 JPEG / PNG
+func DetectImageType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png":
+		return contentType, nil
+	default:
+		return "", ErrUnsupportedImageType
+	}
+}
+
+// ProcessImage 校验并处理上传图片：
+// 1. 按内容检测 MIME 类型
+// 2. 按 EXIF Orientation 旋转后重新编码（丢弃 EXIF/GPS 等全部元数据）
+// 3. 超出尺寸上限时等比缩小，并生成缩略图
+func ProcessImage(data []byte, opts ImageOptions) (*ProcessedImage, error) {
+	contentType, err := DetectImageType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先读取尺寸，防止超大像素图片（解压炸弹）耗尽内存
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > 50_000_000 {
+		return nil, ErrInvalidImage
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	ext := "png"
+	if contentType == "image/jpeg" {
+		ext = "jpg"
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	img = resizeToFit(img, opts.MaxDimension)
+	thumb := resizeToFit(img, opts.ThumbDimension)
+
+	encoded, err := encodeImage(img, contentType, opts.JPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+	thumbEncoded, err := encodeImage(thumb, contentType, opts.JPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	return &ProcessedImage{
+		Data:        encoded,
+		Thumbnail:   thumbEncoded,
+		ContentType: contentType,
+		Ext:         ext,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}, nil
+}
+
+// encodeImage 编码图片（标准库编码器不写入任何元数据）
+func encodeImage(img image.Image, contentType string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeToFit 等比缩小到最长边不超过 maxDim（不放大）
+func resizeToFit(src image.Image, maxDim int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if maxDim <= 0 || (w <= maxDim && h <= maxDim) {
+		return src
+	}
+
+	scale := math.Min(float64(maxDim)/float64(w), float64(maxDim)/float64(h))
+	dw := int(math.Max(1, math.Round(float64(w)*scale)))
+	dh := int(math.Max(1, math.Round(float64(h)*scale)))
+	return boxResize(toRGBA(src), dw, dh)
+}
+
+// toRGBA 转换为 RGBA 以便逐像素处理
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// boxResize 区域平均缩小（每个目标像素取对应源区域的平均值，缩小时无锯齿）
+func boxResize(src *image.RGBA, dw, dh int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for dy := 0; dy < dh; dy++ {
+		y0 := dy * sh / dh
+		y1 := (dy + 1) * sh / dh
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for dx := 0; dx < dw; dx++ {
+			x0 := dx * sw / dw
+			x1 := (dx + 1) * sw / dw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			i := dy*dst.Stride + dx*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// jpegOrientation 读取 JPEG EXIF 中的 Orientation（1-8），读取失败返回 1
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		// SOS 之后是图像数据，不再有 APP 段
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		segLen := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if segLen < 2 || pos+2+segLen > len(data) {
+			return 1
+		}
+		seg := data[pos+4 : pos+2+segLen]
+		if marker == 0xE1 && len(seg) > 6 && string(seg[:6]) == "Exif\x00\x00" {
+			return exifOrientation(seg[6:])
+		}
+		pos += 2 + segLen
+	}
+	return 1
+}
+
+// exifOrientation 解析 TIFF 结构中 IFD0 的 Orientation 标签
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			v := int(order.Uint16(tiff[entry+8:]))
+			if v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation 按 EXIF Orientation 变换图片，使其正向显示
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	rgba := toRGBA(src)
+	w, h := rgba.Rect.Dx(), rgba.Rect.Dy()
+
+	// 5-8 需要交换宽高
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			// 目标坐标 (x, y) 对应的源坐标
+			var sx, sy int
+			switch orientation {
+			case 2: // 水平翻转
+				sx, sy = w-1-x, y
+			case 3: // 旋转 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // 垂直翻转
+				sx, sy = x, h-1-y
+			case 5: // 沿左上-右下对角线翻转
+				sx, sy = y, x
+			case 6: // 顺时针旋转 90°
+				sx, sy = y, h-1-x
+			case 7: // 沿右上-左下对角线翻转
+				sx, sy = w-1-y, h-1-x
+			case 8: // 逆时针旋转 90°
+				sx, sy = w-1-y, x
+			}
+			si := sy*rgba.Stride + sx*4
+			di := y*dst.Stride + x*4
+			copy(dst.Pix[di:di+4], rgba.Pix[si:si+4])
+		}
+	}
+	return dst
+}
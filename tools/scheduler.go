@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// JobFunc 定时任务函数
+type JobFunc func(ctx context.Context) error
+
+// JobLocker 分布式任务锁（多副本部署时保证同一任务只有一个实例在执行）
+type JobLocker interface {
+	// TryLock 尝试获取任务锁，获取成功时返回释放函数
+	TryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
+}
+
+// JobRecorder 任务执行记录器
+type JobRecorder interface {
+	// RecordStart 记录任务开始执行，返回执行记录ID
+	RecordStart(ctx context.Context, name, trigger string, triggeredBy *uint) (uint, error)
+	// RecordFinish 记录任务执行结果
+	RecordFinish(ctx context.Context, runID uint, runErr error) error
+}
+
+// 任务触发方式
+const (
+	JobTriggerSchedule = "schedule" // 定时触发
+	JobTriggerManual   = "manual"   // 手动触发
+)
+
+// ErrJobRunning 任务正在执行中
+var ErrJobRunning = errors.New("job is already running")
+
+// scheduledJob 已注册的定时任务
+type scheduledJob struct {
+	name      string
+	schedule  Schedule
+	fn        JobFunc
+	running   atomic.Bool
+	mu        sync.Mutex
+	nextRunAt time.Time
+	lastRunAt time.Time
+}
+
+// JobInfo 任务状态信息
+type JobInfo struct {
+	Name      string
+	Schedule  string
+	Running   bool
+	NextRunAt *time.Time
+	LastRunAt *time.Time
+}
+
+// Scheduler 进程内定时任务调度器，支持 cron 表达式、分布式锁和执行记录
+type Scheduler struct {
+	jobs     map[string]*scheduledJob
+	locker   JobLocker
+	recorder JobRecorder
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewScheduler 创建定时任务调度器，locker / recorder 可为 nil
+func NewScheduler(locker JobLocker, recorder JobRecorder) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		jobs:     make(map[string]*scheduledJob),
+		locker:   locker,
+		recorder: recorder,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Register 注册定时任务（需在 Start 之前调用），spec 见 ParseSchedule
+func (s *Scheduler) Register(name, spec string, fn JobFunc) error {
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s already registered", name)
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	s.jobs[name] = &scheduledJob{name: name, schedule: schedule, fn: fn}
+	return nil
+}
+
+// MustRegister 注册定时任务，规则无效时 panic（用于启动阶段）
+func (s *Scheduler) MustRegister(name, spec string, fn JobFunc) {
+	if err := s.Register(name, spec, fn); err != nil {
+		panic(err)
+	}
+}
+
+// Start 启动所有任务的调度循环
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+	log.Printf("⏰ Scheduler started with %d job(s)\n", len(s.jobs))
+}
+
+// Stop 停止调度器并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Trigger 手动触发任务：同步获取执行权（本进程及分布式锁）后异步执行，任务已在本进程或其他副本执行时返回 ErrJobRunning
+func (s *Scheduler) Trigger(name string, triggeredBy *uint) error {
+	job, ok := s.jobs[name]
+	if !ok {
+		return ErrNotFound
+	}
+
+	release, err := s.acquire(job)
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer release()
+		s.runLocked(job, JobTriggerManual, triggeredBy)
+	}()
+	return nil
+}
+
+// Jobs 返回所有已注册任务的状态（按名称排序）
+func (s *Scheduler) Jobs() []JobInfo {
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		infos = append(infos, job.info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Job 返回单个任务的状态
+func (s *Scheduler) Job(name string) (*JobInfo, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	info := job.info()
+	return &info, nil
+}
+
+// loop 单个任务的调度循环
+func (s *Scheduler) loop(job *scheduledJob) {
+	defer s.wg.Done()
+
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("⚠️  Job %s has no upcoming run, stop scheduling\n", job.name)
+			return
+		}
+		job.mu.Lock()
+		job.nextRunAt = next
+		job.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.execute(job, JobTriggerSchedule, nil)
+		}
+	}
+}
+
+// execute 定时执行任务：获取执行权失败（正在执行或锁被其他副本持有）时跳过
+func (s *Scheduler) execute(job *scheduledJob, trigger string, triggeredBy *uint) {
+	release, err := s.acquire(job)
+	if err != nil {
+		if errors.Is(err, ErrJobRunning) {
+			log.Printf("⏭️  Job %s is already running, skipped\n", job.name)
+		} else {
+			log.Printf("❌ Job %s failed to acquire lock: %v\n", job.name, err)
+		}
+		return
+	}
+	defer release()
+
+	s.runLocked(job, trigger, triggeredBy)
+}
+
+// acquire 获取任务执行权：本进程去重 → 获取分布式锁，返回释放函数；任务正在执行时返回 ErrJobRunning
+func (s *Scheduler) acquire(job *scheduledJob) (func(), error) {
+	if !job.running.CompareAndSwap(false, true) {
+		return nil, ErrJobRunning
+	}
+	if s.locker == nil {
+		return func() { job.running.Store(false) }, nil
+	}
+
+	unlock, acquired, err := s.locker.TryLock(s.ctx, job.name)
+	if err != nil {
+		job.running.Store(false)
+		return nil, err
+	}
+	if !acquired {
+		job.running.Store(false)
+		return nil, ErrJobRunning
+	}
+	return func() {
+		unlock()
+		job.running.Store(false)
+	}, nil
+}
+
+// runLocked 在已获取执行权的情况下记录执行并运行任务
+func (s *Scheduler) runLocked(job *scheduledJob, trigger string, triggeredBy *uint) {
+	ctx := s.ctx
+
+	var runID uint
+	if s.recorder != nil {
+		id, err := s.recorder.RecordStart(ctx, job.name, trigger, triggeredBy)
+		if err != nil {
+			log.Printf("⚠️  Job %s failed to record start: %v\n", job.name, err)
+		}
+		runID = id
+	}
+
+	start := time.Now()
+	job.mu.Lock()
+	job.lastRunAt = start
+	job.mu.Unlock()
+
+	runErr := s.run(ctx, job)
+	if runErr != nil {
+		log.Printf("❌ Job %s failed: %v\n", job.name, runErr)
+	} else {
+		log.Printf("✅ Job %s finished in %s\n", job.name, time.Since(start))
+	}
+
+	if s.recorder != nil && runID != 0 {
+		// 使用独立 context，确保停机时也能写入结果
+		if err := s.recorder.RecordFinish(context.Background(), runID, runErr); err != nil {
+			log.Printf("⚠️  Job %s failed to record finish: %v\n", job.name, err)
+		}
+	}
+}
+
+// run 运行任务函数，捕获 panic 避免影响其他任务
+func (s *Scheduler) run(ctx context.Context, job *scheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.fn(ctx)
+}
+
+// info 返回任务状态快照
+func (j *scheduledJob) info() JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	info := JobInfo{
+		Name:     j.name,
+		Schedule: j.schedule.String(),
+		Running:  j.running.Load(),
+	}
+	if !j.nextRunAt.IsZero() {
+		next := j.nextRunAt
+		info.NextRunAt = &next
+	}
+	if !j.lastRunAt.IsZero() {
+		last := j.lastRunAt
+		info.LastRunAt = &last
+	}
+	return info
+}
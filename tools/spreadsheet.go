@@ -0,0 +1,265 @@
+package tools
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedSpreadsheet 不支持的表格格式
+var ErrUnsupportedSpreadsheet = errors.New("unsupported spreadsheet format, only csv and xlsx are allowed")
+
+// xlsxMaxPartSize XLSX 内单个 XML 部件解压后的大小上限（防止压缩炸弹）
+const xlsxMaxPartSize = 64 << 20
+
+// SpreadsheetFormat 根据文件名判断表格格式（csv / xlsx），不支持时返回空字符串
+func SpreadsheetFormat(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return "csv"
+	case ".xlsx":
+		return "xlsx"
+	}
+	return ""
+}
+
+// ReadSpreadsheet 读取 CSV 或 XLSX（第一个工作表）的全部行，单元格去除首尾空白
+func ReadSpreadsheet(format string, data []byte) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch format {
+	case "csv":
+		rows, err = readCSV(data)
+	case "xlsx":
+		rows, err = readXLSX(data)
+	default:
+		return nil, ErrUnsupportedSpreadsheet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+	}
+	return rows, nil
+}
+
+// ExcelSerialTime 将 Excel 日期序列号（1900 日期系统）转换为时间
+func ExcelSerialTime(serial float64, loc *time.Location) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, loc)
+	days := int(serial)
+	seconds := int((serial - float64(days)) * 86400)
+	return epoch.AddDate(0, 0, days).Add(time.Duration(seconds) * time.Second)
+}
+
+// readCSV 读取 CSV（兼容 Excel 导出的 UTF-8 BOM）
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	return rows, nil
+}
+
+// ============ XLSX ============
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText 富文本或纯文本单元格内容
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var sb strings.Builder
+	for _, r := range t.Runs {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string   `xml:"r,attr"`
+			T  string   `xml:"t,attr"`
+			V  string   `xml:"v"`
+			IS xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX 读取 XLSX 第一个工作表（只解析单元格值，不计算公式）
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := xlsxFirstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid xlsx: worksheet %s not found", sheetPath)
+	}
+	var sheet xlsxWorksheet
+	if err := decodeXLSXPart(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		// 行号缺省时顺延，中间的空行补齐
+		rowIndex := len(rows)
+		if row.R > 0 {
+			rowIndex = row.R - 1
+		}
+		for len(rows) <= rowIndex {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for _, c := range row.Cells {
+			col := len(cells)
+			if c.R != "" {
+				if idx, ok := xlsxColumnIndex(c.R); ok {
+					col = idx
+				}
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			switch c.T {
+			case "s":
+				idx, err := strconv.Atoi(c.V)
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, fmt.Errorf("invalid xlsx: bad shared string reference in cell %s", c.R)
+				}
+				cells[col] = shared.Items[idx].String()
+			case "inlineStr":
+				cells[col] = c.IS.String()
+			default:
+				cells[col] = c.V
+			}
+		}
+		rows[rowIndex] = cells
+	}
+	return rows, nil
+}
+
+// xlsxFirstSheetPath 通过 workbook.xml 及其关系文件定位第一个工作表
+func xlsxFirstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("invalid xlsx: workbook not found")
+	}
+	var wb xlsxWorkbook
+	if err := decodeXLSXPart(wbFile, &wb); err != nil {
+		return "", err
+	}
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if len(wb.Sheets) == 0 || !ok {
+		return fallback, nil
+	}
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(relsFile, &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// decodeXLSXPart 解码 XLSX 内的 XML 部件
+func decodeXLSXPart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("invalid xlsx: %w", err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, xlsxMaxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("invalid xlsx: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// xlsxColumnIndex 单元格引用（如 "AB12"）转换为从 0 开始的列序号
+func xlsxColumnIndex(ref string) (int, bool) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return col - 1, true
+}
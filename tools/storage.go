@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage 对象存储接口
+type Storage interface {
+	// Put 写入对象（同名对象会被覆盖）
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete 删除对象（对象不存在时不报错）
+	Delete(ctx context.Context, key string) error
+	// Exists 判断对象是否存在
+	Exists(ctx context.Context, key string) (bool, error)
+	// URL 返回对象的公开访问地址
+	URL(key string) string
+}
+
+// NewStorageFromEnv 根据环境变量创建对象存储
+// STORAGE_DRIVER=local（默认）| s3
+func NewStorageFromEnv() (Storage, error) {
+	switch driver := GetEnv("STORAGE_DRIVER", "local"); driver {
+	case "local":
+		return NewLocalStorage(
+			GetEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			GetEnv("STORAGE_PUBLIC_URL", "/uploads"),
+		)
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  GetEnv("S3_ENDPOINT", ""),
+			Region:    GetEnv("S3_REGION", "us-east-1"),
+			Bucket:    GetEnv("S3_BUCKET", ""),
+			AccessKey: GetEnv("S3_ACCESS_KEY", ""),
+			SecretKey: GetEnv("S3_SECRET_KEY", ""),
+			PublicURL: GetEnv("S3_PUBLIC_URL", ""),
+			PathStyle: GetEnv("S3_PATH_STYLE", "true") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", driver)
+	}
+}
+
+// validateObjectKey 校验对象键，禁止绝对路径与目录穿越
+func validateObjectKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid object key: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid object key: %q", key)
+		}
+	}
+	return nil
+}
+
+// ============ 本地磁盘存储 ============
+
+// LocalStorage 本地磁盘存储（开发环境或单机部署使用，由 Gin 静态路由对外提供访问）
+type LocalStorage struct {
+	baseDir   string
+	publicURL string
+}
+
+// NewLocalStorage 创建本地磁盘存储
+func NewLocalStorage(baseDir, publicURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{
+		baseDir:   baseDir,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}, nil
+}
+
+// BaseDir 返回存储根目录
+func (s *LocalStorage) BaseDir() string {
+	return s.baseDir
+}
+
+// PublicURL 返回公开访问地址前缀
+func (s *LocalStorage) PublicURL() string {
+	return s.publicURL
+}
+
+// Put 写入对象（先写临时文件再重命名，避免读到半个文件）
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validateObjectKey(key); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.baseDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Delete 删除对象
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := validateObjectKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.baseDir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Exists 判断对象是否存在
+func (s *LocalStorage) Exists(ctx context.Context, key string) (bool, error) {
+	if err := validateObjectKey(key); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(filepath.Join(s.baseDir, filepath.FromSlash(key)))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// URL 返回对象的公开访问地址
+func (s *LocalStorage) URL(key string) string {
+	return s.publicURL + "/" + key
+}